@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 
 	"github.com/spf13/viper"
 )
@@ -99,7 +100,13 @@ func LoadConfig() (*Connections, error) {
 	viper.AddConfigPath(fc.ConfigPath)
 	viper.SetConfigName(fc.FileName)
 	viper.SetConfigType(fc.Extentsion)
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	viper.AutomaticEnv()
+	for _, key := range envKeys {
+		if err := viper.BindEnv(key); err != nil {
+			return nil, err
+		}
+	}
 
 	err := viper.ReadInConfig()
 	if err != nil {
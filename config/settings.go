@@ -0,0 +1,114 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Tomelin/dashfin-backend-app/pkg/authenticatior"
+	"github.com/Tomelin/dashfin-backend-app/pkg/cache"
+	"github.com/Tomelin/dashfin-backend-app/pkg/database"
+	"github.com/Tomelin/dashfin-backend-app/pkg/http_server"
+	"github.com/Tomelin/dashfin-backend-app/pkg/message_queue"
+	"github.com/go-viper/mapstructure/v2"
+)
+
+// envKeys are bound to their environment variables even when the config file does not set them,
+// so the backends can be chosen without editing the file (e.g. DATABASE_DRIVER=memory).
+var envKeys = []string{
+	"encrypt",
+	"auth.driver",
+	"auth.local.token",
+	"database.driver",
+	"database.sqlite.path",
+	"cache.driver",
+	"message_queue.driver",
+}
+
+// Settings is the typed view of the config file. Each section is decoded into the config of
+// the package that owns it.
+type Settings struct {
+	WebServer    http_server.RestAPIConfig
+	Encrypt      string
+	Firebase     authenticatior.FirebaseConfig
+	Auth         authenticatior.AuthConfig
+	Database     database.DatabaseConfig
+	Cache        cache.CacheConfig
+	MessageQueue message_queue.Config
+}
+
+// Settings decodes and validates the fields of the config file.
+func (c *Connections) Settings() (*Settings, error) {
+	return NewSettings(c.Fields)
+}
+
+// NewSettings decodes the fields of the config file and validates them.
+func NewSettings(fields map[string]interface{}) (*Settings, error) {
+	var s Settings
+
+	if err := mapstructure.Decode(fields["webserver"], &s.WebServer); err != nil {
+		return nil, fmt.Errorf("failed to decode webserver config: %w", err)
+	}
+	if encrypt, ok := fields["encrypt"]; ok && encrypt != nil {
+		s.Encrypt = fmt.Sprintf("%v", encrypt)
+	}
+
+	sections := []struct {
+		key    string
+		target interface{}
+	}{
+		{"firebase", &s.Firebase},
+		{"auth", &s.Auth},
+		{"database", &s.Database},
+		{"cache", &s.Cache},
+		{"message_queue", &s.MessageQueue},
+	}
+	for _, section := range sections {
+		if err := decodeSection(fields[section.key], section.target); err != nil {
+			return nil, fmt.Errorf("failed to decode %s config: %w", section.key, err)
+		}
+	}
+
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Validate fills the defaults of every section and checks the fields the selected backends need.
+func (s *Settings) Validate() error {
+	if s.Encrypt == "" {
+		return errors.New("encrypt is required")
+	}
+
+	validators := []struct {
+		key      string
+		validate func() error
+	}{
+		{"webserver", s.WebServer.Validate},
+		{"auth", s.Auth.Validate},
+		{"database", s.Database.Validate},
+		{"cache", s.Cache.Validate},
+		{"message_queue", s.MessageQueue.Validate},
+	}
+	for _, v := range validators {
+		if err := v.validate(); err != nil {
+			return fmt.Errorf("invalid %s config: %w", v.key, err)
+		}
+	}
+	return nil
+}
+
+// decodeSection round-trips a section through JSON, so the json tags of the target apply.
+// Viper lowercases the keys; encoding/json matches them case-insensitively.
+func decodeSection(section interface{}, target interface{}) error {
+	if section == nil {
+		return nil
+	}
+
+	b, err := json.Marshal(section)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, target)
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSettings(t *testing.T) {
+	fields := map[string]interface{}{
+		"encrypt":       "a2V5",
+		"firebase":      map[string]interface{}{"projectid": "dashfin"},
+		"cache":         map[string]interface{}{"address": "localhost", "port": 6379},
+		"message_queue": map[string]interface{}{"url": "amqp://localhost:5672"},
+	}
+
+	settings, err := NewSettings(fields)
+	require.NoError(t, err)
+	assert.Equal(t, "dashfin", settings.Firebase.ProjectID, "keys lowercased by viper still decode")
+	assert.Equal(t, "a2V5", settings.Encrypt)
+	assert.Equal(t, "8080", settings.WebServer.Port)
+	assert.Equal(t, "firebase", settings.Auth.Driver)
+	assert.Equal(t, "firestore", settings.Database.Driver)
+	assert.Equal(t, "redis", settings.Cache.Driver)
+	assert.Equal(t, "rabbitmq", settings.MessageQueue.Driver)
+
+	tests := []struct {
+		name    string
+		key     string
+		section interface{}
+		err     string
+	}{
+		{"missing encrypt", "encrypt", nil, "encrypt is required"},
+		{"unknown database", "database", map[string]interface{}{"driver": "postgres"}, `invalid database config: unknown database driver "postgres"`},
+		{"redis without address", "cache", map[string]interface{}{}, "invalid cache config: address is required for the redis cache"},
+		{"rabbitmq without url", "message_queue", map[string]interface{}{"driver": "rabbitmq"}, "invalid message_queue config: url is required for rabbitmq"},
+		{"local auth without token", "auth", map[string]interface{}{"driver": "local"}, "invalid auth config: local.token is required for the local authenticator"},
+		{"ssl without certificate", "webserver", map[string]interface{}{"ssl_enabled": true}, "invalid webserver config: certificate_crt is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invalid := make(map[string]interface{}, len(fields))
+			for k, v := range fields {
+				invalid[k] = v
+			}
+			invalid[tt.key] = tt.section
+
+			_, err := NewSettings(invalid)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestLoadConfig_EnvOverrides(t *testing.T) {
+	dir := t.TempDir()
+	file := []byte("encrypt: a2V5\ncache:\n  driver: redis\n  address: localhost\nmessage_queue:\n  url: amqp://localhost:5672\n")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), file, 0o600))
+
+	t.Setenv("PATH_CONFIG", dir)
+	t.Setenv("CACHE_DRIVER", "memory")
+	t.Setenv("DATABASE_DRIVER", "memory")
+	t.Setenv("MESSAGE_QUEUE_DRIVER", "memory")
+	t.Setenv("AUTH_DRIVER", "local")
+	t.Setenv("AUTH_LOCAL_TOKEN", "dev-token")
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	settings, err := cfg.Settings()
+	require.NoError(t, err)
+
+	assert.Equal(t, "memory", settings.Cache.Driver, "a key of the file is overridden")
+	assert.Equal(t, "memory", settings.Database.Driver, "a key missing from the file is set")
+	assert.Equal(t, "memory", settings.MessageQueue.Driver)
+	assert.Equal(t, "local", settings.Auth.Driver)
+	assert.Equal(t, "dev-token", settings.Auth.Local.Token)
+	assert.Equal(t, "localhost", settings.Cache.Address)
+}
@@ -0,0 +1,237 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/Tomelin/dashfin-backend-app/config"
+	repository_dashboard "github.com/Tomelin/dashfin-backend-app/internal/core/repository/dashboard"
+	service_dashboard "github.com/Tomelin/dashfin-backend-app/internal/core/service/dashboard"
+	service_profile "github.com/Tomelin/dashfin-backend-app/internal/core/service/profile"
+	"github.com/Tomelin/dashfin-backend-app/internal/handler/web"
+	web_dashboard "github.com/Tomelin/dashfin-backend-app/internal/handler/web/dashboard"
+	web_finance "github.com/Tomelin/dashfin-backend-app/internal/handler/web/finance"
+	web_finance_expense "github.com/Tomelin/dashfin-backend-app/internal/handler/web/finance/expense"
+	web_finance_income "github.com/Tomelin/dashfin-backend-app/internal/handler/web/finance/income"
+	web_report "github.com/Tomelin/dashfin-backend-app/internal/handler/web/finance/report"
+	web_platform "github.com/Tomelin/dashfin-backend-app/internal/handler/web/platform"
+	"github.com/Tomelin/dashfin-backend-app/pkg/authenticatior"
+	"github.com/Tomelin/dashfin-backend-app/pkg/cache"
+	cryptdata "github.com/Tomelin/dashfin-backend-app/pkg/cryptData"
+	"github.com/Tomelin/dashfin-backend-app/pkg/database"
+	"github.com/Tomelin/dashfin-backend-app/pkg/http_server"
+	"github.com/Tomelin/dashfin-backend-app/pkg/message_queue"
+)
+
+// Container is the composition root: it holds the backends chosen by the settings and the
+// API with every handler registered.
+type Container struct {
+	Settings *config.Settings
+	API      *http_server.RestAPI
+	Crypt    cryptdata.CryptDataInterface
+	Auth     authenticatior.Authenticator
+	DB       database.FirebaseDBInterface
+	Cache    cache.CacheService
+	Queue    message_queue.MessageQueue
+}
+
+// New creates the backends selected by the settings, wires the services on top of them and
+// registers the routes. The backends already created are closed when a later step fails.
+func New(ctx context.Context, settings *config.Settings) (*Container, error) {
+	c := &Container{Settings: settings}
+	if err := c.initialize(ctx); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Container) initialize(ctx context.Context) error {
+	var err error
+	s := c.Settings
+
+	c.API, err = http_server.NewRestApi(s.WebServer)
+	if err != nil {
+		return fmt.Errorf("failed to initialize web server: %w", err)
+	}
+
+	c.Crypt, err = cryptdata.InicializationCryptData(&s.Encrypt)
+	if err != nil {
+		return fmt.Errorf("failed to initialize crypt data: %w", err)
+	}
+
+	log.Printf("Using %s storage, %s cache, %s message queue and %s auth", s.Database.Driver, s.Cache.Driver, s.MessageQueue.Driver, s.Auth.Driver)
+
+	c.Auth, err = authenticatior.Initialize(ctx, s.Auth, &s.Firebase)
+	if err != nil {
+		return fmt.Errorf("failed to initialize auth: %w", err)
+	}
+
+	c.DB, err = database.InitializeDatabase(s.Database, database.FirebaseConfig{
+		ProjectID:             s.Firebase.ProjectID,
+		APIKey:                s.Firebase.APIKey,
+		DatabaseURL:           s.Firebase.DatabaseURL,
+		StorageBucket:         s.Firebase.StorageBucket,
+		AppID:                 s.Firebase.AppID,
+		AuthDomain:            s.Firebase.AuthDomain,
+		MessagingSenderID:     s.Firebase.MessagingSenderID,
+		ServiceAccountKeyPath: s.Firebase.ServiceAccountKeyPath,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+
+	c.Cache, err = cache.InitializeCache(s.Cache)
+	if err != nil {
+		return fmt.Errorf("failed to initialize cache: %w", err)
+	}
+
+	c.Queue, err = message_queue.InitializeMessageQueue(s.MessageQueue)
+	if err != nil {
+		return fmt.Errorf("failed to initialize message queue: %w", err)
+	}
+
+	return c.registerHandlers()
+}
+
+// registerHandlers wires the services and registers their handlers on the API.
+func (c *Container) registerHandlers() error {
+	db, mq, cacheClient := c.DB, c.Queue, c.Cache
+
+	svcProfilePerson, svcProfileProfession, svcProfileGoals, err := initializeProfileServices(db)
+	if err != nil {
+		return err
+	}
+
+	// Reconstruct the aggregate ProfileAllService for handlers that need it
+	svcProfileAll, err := service_profile.InicializeProfileAllService(svcProfilePerson, svcProfileProfession, svcProfileGoals)
+	if err != nil {
+		return fmt.Errorf("failed to initialize ProfileAllService: %w", err)
+	}
+
+	svcSupport, err := initializeSupportServices(db)
+	if err != nil {
+		return err
+	}
+
+	svcFinancialInstitution, err := initializeFinancialInstitution(db)
+	if err != nil {
+		return err
+	}
+
+	svcExpenseRecord, err := initializeExpenseRecordServices(db, mq)
+	if err != nil {
+		return err
+	}
+
+	svcNFCeImport, err := initializeNFCeImportServices(db, svcExpenseRecord, mq)
+	if err != nil {
+		return err
+	}
+
+	svcBankAccount, err := initializeBankAccountServices(db, mq)
+	if err != nil {
+		return err
+	}
+
+	svcCreditCard, err := initializeCreditCardServices(db)
+	if err != nil {
+		return err
+	}
+
+	svcCreditCardInvoice, err := initializeCreditCardInvoiceServices(db, mq)
+	if err != nil {
+		return err
+	}
+
+	svcInstallmentPurchase, err := initializeInstallmentPurchaseServices(db, mq)
+	if err != nil {
+		return err
+	}
+
+	svcTransfer, err := initializeTransferServices(db, mq)
+	if err != nil {
+		return err
+	}
+
+	svcBankAccountBalance, err := initializeBankAccountBalanceServices(db)
+	if err != nil {
+		return err
+	}
+
+	// The bank fee service generates the monthly fees on its own schedule; it has no routes.
+	if _, err := initializeBankFeeServices(db, svcProfilePerson, mq); err != nil {
+		return err
+	}
+
+	// The occurrences of the recurring series are written by a scheduled job as well; the
+	// listings only read them.
+	if _, err := initializeRecurringSeriesServices(db, svcProfilePerson, mq); err != nil {
+		return err
+	}
+
+	svcIncomeRecord, err := initializeIncomeRecordServices(db, mq)
+	if err != nil {
+		return err
+	}
+
+	svcSpendingRecord, err := initializeSpendingPlanServices(db, cacheClient)
+	if err != nil {
+		return err
+	}
+
+	srvDashboard, err := initializeDashboardServices(svcBankAccount, svcExpenseRecord, svcIncomeRecord, svcCreditCardInvoice, svcTransfer, svcBankAccountBalance, svcProfileGoals, svcFinancialInstitution, mq, db)
+	if err != nil {
+		return err
+	}
+
+	svcReport, err := initializeReportServices(svcIncomeRecord, svcExpenseRecord, svcBankAccountBalance, cacheClient, mq)
+	if err != nil {
+		return err
+	}
+
+	// Initialize Financial Repository & Service for PlannedVsActual
+	financialRepo, _ := repository_dashboard.NewFirebaseFinancialRepository(db)
+	financialSvc, _ := service_dashboard.NewFinancialService(financialRepo, cacheClient, svcExpenseRecord, svcSpendingRecord)
+	// No error is returned by NewFinancialService or NewFirebaseFinancialRepository, so no error check needed here.
+
+	api, crypt, authClient := c.API, c.Crypt, c.Auth
+	web_dashboard.InitializeDashboardHandler(srvDashboard, crypt, authClient, api.RouterGroup, api.CorsMiddleware(), api.MiddlewareHeader)
+	web_dashboard.InitializePlannedVsActualHandler(financialSvc, authClient, crypt, api.RouterGroup, api.CorsMiddleware(), api.MiddlewareHeader)
+	web.InicializationProfileHandlerHttp(svcProfileAll, crypt, authClient, api.RouterGroup, api.CorsMiddleware(), api.MiddlewareHeader)
+	web.InicializationSupportHandlerHttp(svcSupport, crypt, authClient, api.RouterGroup, api.CorsMiddleware(), api.MiddlewareHeader)
+	web_platform.NewFinancialInstitutionHandler(svcFinancialInstitution, crypt, authClient, api.RouterGroup, api.CorsMiddleware(), api.MiddlewareHeader)
+	web_finance_expense.InitializeExpenseRecordHandler(svcExpenseRecord, crypt, authClient, api.RouterGroup, api.CorsMiddleware(), api.MiddlewareHeader)
+	web_finance_expense.InitializeNFCeImportHandler(svcNFCeImport, crypt, authClient, api.RouterGroup, api.CorsMiddleware(), api.MiddlewareHeader)
+	web_finance.InitializeBankAccountHandler(svcBankAccount, crypt, authClient, api.RouterGroup, api.CorsMiddleware(), api.MiddlewareHeader)
+	web_finance.InitializeCreditCardHandler(svcCreditCard, crypt, authClient, api.RouterGroup, api.CorsMiddleware(), api.MiddlewareHeader)
+	web_finance.InitializeCreditCardInvoiceHandler(svcCreditCardInvoice, crypt, authClient, api.RouterGroup, api.CorsMiddleware(), api.MiddlewareHeader)
+	web_finance.InitializeInstallmentPurchaseHandler(svcInstallmentPurchase, crypt, authClient, api.RouterGroup, api.CorsMiddleware(), api.MiddlewareHeader)
+	web_finance.InitializeTransferHandler(svcTransfer, crypt, authClient, api.RouterGroup, api.CorsMiddleware(), api.MiddlewareHeader)
+	web_finance.InitializeBankAccountBalanceHandler(svcBankAccountBalance, crypt, authClient, api.RouterGroup, api.CorsMiddleware(), api.MiddlewareHeader)
+	web_finance_income.InitializeIncomeRecordHandler(svcIncomeRecord, crypt, authClient, api.RouterGroup, api.CorsMiddleware(), api.MiddlewareHeader)
+	web_finance.InitializeSpendingPlanHandler(svcSpendingRecord, crypt, authClient, api.RouterGroup, api.CorsMiddleware(), api.MiddlewareHeader)
+	web_report.InitializeReportHandler(svcReport, crypt, authClient, api.RouterGroup, api.CorsMiddleware(), api.MiddlewareHeader)
+
+	return nil
+}
+
+// Run serves the API until it fails.
+func (c *Container) Run() error {
+	return c.API.Run(c.API.Route.Handler())
+}
+
+// Close releases the message queue and the database connection, when the backend holds one.
+func (c *Container) Close() error {
+	var err error
+	if c.Queue != nil {
+		err = c.Queue.Close()
+	}
+	if closer, ok := c.DB.(interface{ Close() error }); ok {
+		if closeErr := closer.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Tomelin/dashfin-backend-app/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testToken = "dev-token"
+
+// offlineFields is a config file that selects the in-process backend of every dependency.
+func offlineFields() map[string]interface{} {
+	return map[string]interface{}{
+		"webserver": map[string]interface{}{"name": "api", "mode": "release"},
+		"encrypt":   base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef")),
+		"auth":      map[string]interface{}{"driver": "local", "local": map[string]interface{}{"token": testToken}},
+		"database":  map[string]interface{}{"driver": "memory"},
+		"cache":     map[string]interface{}{"driver": "memory"},
+		"message_queue": map[string]interface{}{
+			"driver": "memory",
+			"message_queues": []interface{}{
+				map[string]interface{}{
+					"exchange": "dashfin_finance",
+					"type":     "topic",
+					"queues": []interface{}{
+						map[string]interface{}{"name": "income_record", "route_keys": []string{"income.record.*"}},
+						map[string]interface{}{"name": "expense_record", "route_keys": []string{"expense.record.*"}},
+						map[string]interface{}{"name": "credit_card", "route_key": "expense.record.*"},
+						map[string]interface{}{"name": "nfce_import", "route_key": "expense.nfce.import"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestNew_Offline(t *testing.T) {
+	settings, err := config.NewSettings(offlineFields())
+	require.NoError(t, err)
+
+	container, err := New(context.Background(), settings)
+	require.NoError(t, err)
+	defer container.Close()
+
+	request := func(method, path string, payload interface{}, token string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			b, err := json.Marshal(payload)
+			require.NoError(t, err)
+			encrypted, err := container.Crypt.EncryptPayload(b)
+			require.NoError(t, err)
+			require.NoError(t, json.NewEncoder(&body).Encode(map[string]string{"payload": encrypted}))
+		}
+
+		req := httptest.NewRequest(method, path, &body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Userid", "user-1")
+		req.Header.Set("X-Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		container.API.Route.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request(http.MethodPost, "/api/finance/bank-accounts", map[string]interface{}{"accountType": "wallet", "customBankName": "Carteira"}, testToken)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	w = request(http.MethodGet, "/api/finance/bank-accounts", nil, testToken)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response struct {
+		Payload string `json:"payload"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	data, err := container.Crypt.PayloadData(response.Payload)
+	require.NoError(t, err)
+
+	var accounts []map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &accounts))
+	require.Len(t, accounts, 1)
+	assert.Equal(t, "Carteira", accounts[0]["customBankName"])
+
+	w = request(http.MethodGet, "/api/finance/bank-accounts", nil, "wrong-token")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
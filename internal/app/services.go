@@ -0,0 +1,411 @@
+package app
+
+import (
+	"fmt"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	entity_platform "github.com/Tomelin/dashfin-backend-app/internal/core/entity/platform"
+	"github.com/Tomelin/dashfin-backend-app/internal/core/repository"
+	repository_dashboard "github.com/Tomelin/dashfin-backend-app/internal/core/repository/dashboard"
+	repository_finance "github.com/Tomelin/dashfin-backend-app/internal/core/repository/finance"
+	repository_platform "github.com/Tomelin/dashfin-backend-app/internal/core/repository/platform"
+	repository_profile "github.com/Tomelin/dashfin-backend-app/internal/core/repository/profile"
+	"github.com/Tomelin/dashfin-backend-app/internal/core/service"
+	service_dashboard "github.com/Tomelin/dashfin-backend-app/internal/core/service/dashboard"
+	service_finance "github.com/Tomelin/dashfin-backend-app/internal/core/service/finance"
+	service_platform "github.com/Tomelin/dashfin-backend-app/internal/core/service/platform"
+	service_profile "github.com/Tomelin/dashfin-backend-app/internal/core/service/profile"
+	"github.com/Tomelin/dashfin-backend-app/pkg/cache"
+	"github.com/Tomelin/dashfin-backend-app/pkg/database"
+	"github.com/Tomelin/dashfin-backend-app/pkg/message_queue"
+)
+
+func initializeProfileServices(db database.FirebaseDBInterface) (service_profile.ProfilePersonServiceInterface, service_profile.ProfileProfessionServiceInterface, service_profile.ProfileGoalsServiceInterface, error) {
+	repoProfile, err := repository_profile.InicializeProfileRepository(db)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to initialize profile repository: %w", err)
+	}
+
+	svcProfilePerson, err := service_profile.InicializeProfileService(repoProfile)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to initialize profile person service: %w", err)
+	}
+
+	svcProfileProfession, err := service_profile.InicializeProfileProfessionService(repoProfile, svcProfilePerson)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to initialize profile profession service: %w", err)
+	}
+
+	svcProfileGoals, err := service_profile.InicializeProfileGoalsService(repoProfile, svcProfilePerson)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to initialize profile goals service: %w", err)
+	}
+
+	return svcProfilePerson, svcProfileProfession, svcProfileGoals, nil
+}
+
+func initializeSupportServices(db database.FirebaseDBInterface) (service.SupportServiceInterface, error) {
+	repoSupport, err := repository.InicializeSupportRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize support repository: %w", err)
+	}
+
+	return service.InicializeSupportService(repoSupport)
+}
+
+func initializeFinancialInstitution(db database.FirebaseDBInterface) (entity_platform.FinancialInstitutionInterface, error) {
+	repoSupport, err := repository_platform.NewFinancialInstitutionRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize support repository: %w", err)
+	}
+
+	return service_platform.NewFinancialInstitutionService(repoSupport)
+}
+
+func initializeExpenseRecordServices(db database.FirebaseDBInterface, mq message_queue.MessageQueue) (entity_finance.ExpenseRecordServiceInterface, error) {
+	repoExpenseRecord, err := repository_finance.InitializeExpenseRecordRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize expense record repository: %w", err)
+	}
+
+	repoSeries, err := repository_finance.InitializeRecurringSeriesRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize recurring series repository: %w", err)
+	}
+
+	repoCreditCard, err := repository_finance.InitializeCreditCardRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize credit card repository: %w", err)
+	}
+
+	repoBankAccount, err := repository_finance.InitializeBankAccountRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize bank account repository: %w", err)
+	}
+
+	repoInvoice, err := repository_finance.InitializeCreditCardInvoiceRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize credit card invoice repository: %w", err)
+	}
+
+	svcExpenseRecord, err := service_finance.InitializeExpenseRecordService(repoExpenseRecord, repoSeries, repoCreditCard, repoInvoice, repoBankAccount, db, mq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize expense record service: %w", err)
+	}
+	return svcExpenseRecord, nil
+}
+
+func initializeNFCeImportServices(db database.FirebaseDBInterface, expense entity_finance.ExpenseRecordServiceInterface, mq message_queue.MessageQueue) (entity_finance.NFCeImportServiceInterface, error) {
+	repoNFCeImport, err := repository_finance.InitializeNFCeImportJobRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize nfce import repository: %w", err)
+	}
+
+	svcNFCeImport, err := service_finance.InitializeNFCeImportService(repoNFCeImport, expense, mq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize nfce import service: %w", err)
+	}
+	return svcNFCeImport, nil
+}
+
+func initializeBankAccountServices(db database.FirebaseDBInterface, mq message_queue.MessageQueue) (entity_finance.BankAccountServiceInterface, error) {
+	repoBankAccount, err := repository_finance.InitializeBankAccountRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize bank account repository: %w", err)
+	}
+
+	repoIncomeRecord, err := repository_finance.InitializeIncomeRecordRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize income record repository: %w", err)
+	}
+
+	repoExpenseRecord, err := repository_finance.InitializeExpenseRecordRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize expense record repository: %w", err)
+	}
+
+	repoTransfer, err := repository_finance.InitializeTransferRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize transfer repository: %w", err)
+	}
+
+	repoSeries, err := repository_finance.InitializeRecurringSeriesRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize recurring series repository: %w", err)
+	}
+
+	repoBalance, err := repository_finance.InitializeBankAccountBalanceRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize bank account balance repository: %w", err)
+	}
+
+	return service_finance.InitializeBankAccountService(repoBankAccount, repoIncomeRecord, repoExpenseRecord, repoTransfer, repoSeries, repoBalance, db, mq)
+}
+
+func initializeCreditCardServices(db database.FirebaseDBInterface) (entity_finance.CreditCardServiceInterface, error) {
+	repoCreditCard, err := repository_finance.InitializeCreditCardRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize credit card repository: %w", err)
+	}
+
+	repoExpenseRecord, err := repository_finance.InitializeExpenseRecordRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize expense record repository: %w", err)
+	}
+
+	repoInstallmentPurchase, err := repository_finance.InitializeInstallmentPurchaseRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize installment purchase repository: %w", err)
+	}
+
+	repoInvoice, err := repository_finance.InitializeCreditCardInvoiceRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize credit card invoice repository: %w", err)
+	}
+
+	repoSeries, err := repository_finance.InitializeRecurringSeriesRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize recurring series repository: %w", err)
+	}
+
+	return service_finance.InitializeCreditCardService(repoCreditCard, repoExpenseRecord, repoInstallmentPurchase, repoInvoice, repoSeries, db)
+}
+
+func initializeCreditCardInvoiceServices(db database.FirebaseDBInterface, mq message_queue.MessageQueue) (entity_finance.CreditCardInvoiceServiceInterface, error) {
+	repoInvoice, err := repository_finance.InitializeCreditCardInvoiceRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize credit card invoice repository: %w", err)
+	}
+
+	repoCreditCard, err := repository_finance.InitializeCreditCardRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize credit card repository: %w", err)
+	}
+
+	repoExpenseRecord, err := repository_finance.InitializeExpenseRecordRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize expense record repository: %w", err)
+	}
+
+	repoBankAccount, err := repository_finance.InitializeBankAccountRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize bank account repository: %w", err)
+	}
+
+	svcInvoice, err := service_finance.InitializeCreditCardInvoiceService(repoInvoice, repoCreditCard, repoExpenseRecord, repoBankAccount, db, mq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize credit card invoice service: %w", err)
+	}
+	return svcInvoice, nil
+}
+
+func initializeBankFeeServices(db database.FirebaseDBInterface, profiles service_profile.ProfilePersonServiceInterface, mq message_queue.MessageQueue) (entity_finance.BankFeeServiceInterface, error) {
+	repoBankAccount, err := repository_finance.InitializeBankAccountRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize bank account repository: %w", err)
+	}
+
+	repoExpenseRecord, err := repository_finance.InitializeExpenseRecordRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize expense record repository: %w", err)
+	}
+
+	svcBankFee, err := service_finance.InitializeBankFeeService(repoBankAccount, repoExpenseRecord, profiles, mq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize bank fee service: %w", err)
+	}
+	return svcBankFee, nil
+}
+
+func initializeRecurringSeriesServices(db database.FirebaseDBInterface, profiles service_profile.ProfilePersonServiceInterface, mq message_queue.MessageQueue) (entity_finance.RecurringSeriesServiceInterface, error) {
+	repoExpenseRecord, err := repository_finance.InitializeExpenseRecordRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize expense record repository: %w", err)
+	}
+
+	repoIncomeRecord, err := repository_finance.InitializeIncomeRecordRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize income record repository: %w", err)
+	}
+
+	repoSeries, err := repository_finance.InitializeRecurringSeriesRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize recurring series repository: %w", err)
+	}
+
+	repoCreditCard, err := repository_finance.InitializeCreditCardRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize credit card repository: %w", err)
+	}
+
+	repoInvoice, err := repository_finance.InitializeCreditCardInvoiceRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize credit card invoice repository: %w", err)
+	}
+
+	svcRecurringSeries, err := service_finance.InitializeRecurringSeriesService(repoExpenseRecord, repoIncomeRecord, repoSeries, repoCreditCard, repoInvoice, db, profiles, mq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize recurring series service: %w", err)
+	}
+	return svcRecurringSeries, nil
+}
+
+func initializeInstallmentPurchaseServices(db database.FirebaseDBInterface, mq message_queue.MessageQueue) (entity_finance.InstallmentPurchaseServiceInterface, error) {
+	repoInstallmentPurchase, err := repository_finance.InitializeInstallmentPurchaseRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize installment purchase repository: %w", err)
+	}
+
+	repoCreditCard, err := repository_finance.InitializeCreditCardRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize credit card repository: %w", err)
+	}
+
+	repoExpenseRecord, err := repository_finance.InitializeExpenseRecordRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize expense record repository: %w", err)
+	}
+
+	repoInvoice, err := repository_finance.InitializeCreditCardInvoiceRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize credit card invoice repository: %w", err)
+	}
+
+	svcInstallmentPurchase, err := service_finance.InitializeInstallmentPurchaseService(repoInstallmentPurchase, repoCreditCard, repoInvoice, repoExpenseRecord, db, mq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize installment purchase service: %w", err)
+	}
+	return svcInstallmentPurchase, nil
+}
+
+func initializeTransferServices(db database.FirebaseDBInterface, mq message_queue.MessageQueue) (entity_finance.TransferServiceInterface, error) {
+	repoTransfer, err := repository_finance.InitializeTransferRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize transfer repository: %w", err)
+	}
+
+	repoBankAccount, err := repository_finance.InitializeBankAccountRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize bank account repository: %w", err)
+	}
+
+	svcTransfer, err := service_finance.InitializeTransferService(repoTransfer, repoBankAccount, mq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize transfer service: %w", err)
+	}
+	return svcTransfer, nil
+}
+
+func initializeBankAccountBalanceServices(db database.FirebaseDBInterface) (entity_finance.BankAccountBalanceServiceInterface, error) {
+	repoBalance, err := repository_finance.InitializeBankAccountBalanceRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize bank account balance repository: %w", err)
+	}
+
+	repoBankAccount, err := repository_finance.InitializeBankAccountRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize bank account repository: %w", err)
+	}
+
+	repoIncomeRecord, err := repository_finance.InitializeIncomeRecordRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize income record repository: %w", err)
+	}
+
+	repoExpenseRecord, err := repository_finance.InitializeExpenseRecordRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize expense record repository: %w", err)
+	}
+
+	repoTransfer, err := repository_finance.InitializeTransferRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize transfer repository: %w", err)
+	}
+
+	svcBalance, err := service_finance.InitializeBankAccountBalanceService(repoBalance, repoBankAccount, repoIncomeRecord, repoExpenseRecord, repoTransfer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize bank account balance service: %w", err)
+	}
+	return svcBalance, nil
+}
+
+func initializeIncomeRecordServices(db database.FirebaseDBInterface, mq message_queue.MessageQueue) (entity_finance.IncomeRecordServiceInterface, error) {
+	repoIncomeRecord, err := repository_finance.InitializeIncomeRecordRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize income record repository: %w", err)
+	}
+
+	repoSeries, err := repository_finance.InitializeRecurringSeriesRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize recurring series repository: %w", err)
+	}
+
+	repoBankAccount, err := repository_finance.InitializeBankAccountRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize bank account repository: %w", err)
+	}
+
+	svcIncomeRecord, err := service_finance.InitializeIncomeRecordService(repoIncomeRecord, repoSeries, repoBankAccount, db, mq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize income record service: %w", err)
+	}
+	return svcIncomeRecord, nil
+}
+
+func initializeSpendingPlanServices(db database.FirebaseDBInterface, cache cache.CacheService) (entity_finance.SpendingPlanServiceInterface, error) {
+	repoSpendingRecord, err := repository_finance.InitializeSpendingPlanRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize income record repository: %w", err)
+	}
+
+	svcSpendingRecord, err := service_finance.InitializeSpendingPlanService(repoSpendingRecord, cache)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize income record service: %w", err)
+	}
+	return svcSpendingRecord, nil
+}
+
+func initializeReportServices(
+	income entity_finance.IncomeRecordServiceInterface,
+	expense entity_finance.ExpenseRecordServiceInterface,
+	balance entity_finance.BankAccountBalanceServiceInterface,
+	cache cache.CacheService,
+	messageQueue message_queue.MessageQueue,
+) (entity_finance.FinancialReportDataServiceInterface, error) {
+
+	svcReport, err := service_finance.InitializeFinancialReportDataService(income, expense, balance, cache, messageQueue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize report service: %w", err)
+	}
+	return svcReport, nil
+}
+
+func initializeDashboardServices(
+	bankAccountSvc entity_finance.BankAccountServiceInterface,
+	expenseRecordSvc entity_finance.ExpenseRecordServiceInterface,
+	incomeRecordSvc entity_finance.IncomeRecordServiceInterface,
+	cardInvoiceSvc entity_finance.CreditCardInvoiceServiceInterface,
+	transferSvc entity_finance.TransferServiceInterface,
+	balanceSvc entity_finance.BankAccountBalanceServiceInterface,
+	profileGoalsSvc service_profile.ProfileGoalsServiceInterface,
+	platformInst entity_platform.FinancialInstitutionInterface,
+	messageQueue message_queue.MessageQueue,
+	db database.FirebaseDBInterface,
+) (*service_dashboard.DashboardService, error) {
+	repoSpendingRecord := repository_dashboard.NewInMemoryDashboardRepository(db)
+
+	svcSpendingRecord := service_dashboard.NewDashboardService(
+		bankAccountSvc,
+		expenseRecordSvc,
+		incomeRecordSvc,
+		cardInvoiceSvc,
+		transferSvc,
+		balanceSvc,
+		profileGoalsSvc,
+		repoSpendingRecord,
+		messageQueue,
+		platformInst,
+	)
+
+	return svcSpendingRecord, nil
+}
@@ -28,6 +28,24 @@ type AccountBalanceItem struct {
 	BankName    string  `json:"bankName"`
 	Balance     float64 `json:"balance"`
 	UserID      string  `json:"userId"`
+	AccountType string  `json:"accountType,omitempty"` // checking, savings, investment, wallet ou benefit.
+}
+
+// AccountTypeBalanceItem é o saldo somado das contas de um mesmo tipo.
+type AccountTypeBalanceItem struct {
+	AccountType  string  `json:"accountType"`
+	Balance      float64 `json:"balance"`
+	AccountCount int     `json:"accountCount"` // Quantidade de contas do tipo.
+}
+
+// CreditCardLimitItem é o uso do limite de um cartão de crédito.
+type CreditCardLimitItem struct {
+	ID                 string  `json:"id,omitempty"`
+	CardName           string  `json:"cardName"`
+	CreditLimit        float64 `json:"creditLimit"`
+	CommittedAmount    float64 `json:"committedAmount"`    // Faturas em aberto e parcelas futuras (R$).
+	AvailableLimit     float64 `json:"availableLimit"`     // Limite disponível (R$).
+	UtilizationPercent float64 `json:"utilizationPercent"` // Percentual do limite comprometido.
 }
 
 type MonthlyFinancialSummaryItem struct {
@@ -52,6 +70,8 @@ type SummaryCards struct {
 	GoalsProgressDescription     string                        `json:"goalsProgressDescription,omitempty"`
 	UpcomingBillsData            []UpcomingBillData            `json:"upcomingBillsData"`
 	AccountBalances              []AccountBalanceItem          `json:"accountBalances,omitempty"`
+	AccountTypeBalances          []AccountTypeBalanceItem      `json:"accountTypeBalances,omitempty"`
+	CreditCardLimits             []CreditCardLimitItem         `json:"creditCardLimits,omitempty"`
 	MonthlyFinancialSummary      []MonthlyFinancialSummaryItem `json:"monthlyFinancialSummary,omitempty"`
 }
 
@@ -66,6 +86,7 @@ type UpcomingBill struct {
 	BillName string  `json:"billName"` // Nome da conta a pagar.
 	Amount   float64 `json:"amount"`   // Valor da conta.
 	DueDate  string  `json:"dueDate"`  // Data de vencimento (YYYY-MM-DD).
+	Status   string  `json:"status"`   // Situação da conta (pending ou overdue).
 }
 
 // RevenueExpenseChartItem represents a data point for the revenue vs. expenses chart.
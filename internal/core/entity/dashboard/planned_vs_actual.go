@@ -64,7 +64,8 @@ type ExpenseDoc struct {
 	Amount float64 `json:"amount" firestore:"amount"`
 	// PaymentDate is the date and time when the payment for this expense was made.
 	PaymentDate time.Time `json:"payment_date" firestore:"payment_date"`
-	// Status indicates the current state of the expense (e.g., "paid", "pending", "cancelled").
+	// Status is the stored state of the expense ("pending", "paid" or "cancelled"); overdue is derived
+	// from the due date, see entity_finance.ExpenseRecord.EffectiveStatus.
 	Status string `json:"status" firestore:"status"`
 	// Description provides additional details about the expense.
 	Description string `json:"description" firestore:"description"`
@@ -0,0 +1,91 @@
+package entity_finance
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BankAccountReferences are the records that point at a bank account.
+type BankAccountReferences struct {
+	Incomes   []IncomeRecord
+	Expenses  []ExpenseRecord
+	Transfers []Transfer
+	Series    []RecurringSeries
+
+	Checkpoints []BalanceCheckpoint
+	Adjustments []BalanceAdjustment
+}
+
+// InUseError returns the error that blocks deleting the account, or nil when no record
+// points at it.
+func (r BankAccountReferences) InUseError() error {
+	return inUseError("bank account", []referenceCount{
+		{"incomes", len(r.Incomes)},
+		{"expenses", len(r.Expenses)},
+		{"transfers", len(r.Transfers)},
+		{"recurring series", len(r.Series)},
+		{"balance checkpoints", len(r.Checkpoints)},
+		{"balance adjustments", len(r.Adjustments)},
+	})
+}
+
+// CreditCardReferences are the records that point at a credit card.
+type CreditCardReferences struct {
+	Expenses             []ExpenseRecord
+	InstallmentPurchases []InstallmentPurchase
+	Series               []RecurringSeries
+}
+
+// InUseError returns the error that blocks deleting the card, or nil when no record points
+// at it.
+func (r CreditCardReferences) InUseError() error {
+	return inUseError("credit card", []referenceCount{
+		{"expenses", len(r.Expenses)},
+		{"installment purchases", len(r.InstallmentPurchases)},
+		{"recurring series", len(r.Series)},
+	})
+}
+
+type referenceCount struct {
+	name  string
+	count int
+}
+
+func inUseError(what string, counts []referenceCount) error {
+	var parts []string
+	for _, c := range counts {
+		if c.count > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %d", c.name, c.count))
+		}
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s is in use (%s); reassign its records or archive it", what, strings.Join(parts, ", "))
+}
+
+// ReplaceBankAccount moves the transfer from one account to another, on either side.
+func (t *Transfer) ReplaceBankAccount(from, to string) {
+	if t.FromBankAccountID == from {
+		t.FromBankAccountID = to
+	}
+	if t.ToBankAccountID == from {
+		t.ToBankAccountID = to
+	}
+}
+
+// ReferencesBankAccount reports whether the template of the series is received in, or paid
+// from, the account.
+func (s *RecurringSeries) ReferencesBankAccount(id string) bool {
+	return (s.Income != nil && s.Income.BankAccountID == id) || (s.Expense != nil && s.Expense.BankPaidFrom == id)
+}
+
+// ReplaceBankAccount moves the template of the series from one account to another.
+func (s *RecurringSeries) ReplaceBankAccount(from, to string) {
+	if s.Income != nil && s.Income.BankAccountID == from {
+		s.Income.BankAccountID = to
+	}
+	if s.Expense != nil && s.Expense.BankPaidFrom == from {
+		s.Expense.BankPaidFrom = to
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"time"
 )
 
 type BankAccountRepositoryInterface interface {
@@ -22,6 +23,32 @@ type BankAccountServiceInterface interface {
 	GetByFilter(ctx context.Context, data map[string]interface{}) ([]BankAccountRequest, error)
 	UpdateBankAccount(ctx context.Context, data *BankAccountRequest) (*BankAccountRequest, error)
 	DeleteBankAccount(ctx context.Context, id *string) error
+	ReassignBankAccount(ctx context.Context, id, targetID *string) error
+	ArchiveBankAccount(ctx context.Context, id *string) (*BankAccountRequest, error)
+}
+
+// AccountType is the kind of account money is kept in. Only checking and savings accounts
+// have an agency and an account number.
+type AccountType string
+
+const (
+	AccountTypeChecking   AccountType = "checking"
+	AccountTypeSavings    AccountType = "savings"
+	AccountTypeInvestment AccountType = "investment"
+	AccountTypeWallet     AccountType = "wallet"
+	AccountTypeBenefit    AccountType = "benefit"
+)
+
+var accountTypes = []AccountType{AccountTypeChecking, AccountTypeSavings, AccountTypeInvestment, AccountTypeWallet, AccountTypeBenefit}
+
+// IsValid reports whether the account type is one of the known types.
+func (t AccountType) IsValid() bool {
+	for _, accountType := range accountTypes {
+		if t == accountType {
+			return true
+		}
+	}
+	return false
 }
 
 type BankAccount struct {
@@ -31,6 +58,21 @@ type BankAccount struct {
 	Agency         string  `json:"agency" bson:"agency"`
 	AccountNumber  string  `json:"accountNumber" bson:"accountNumber"`
 	MonthlyFee     float64 `json:"monthlyFee" bson:"monthlyFee"`
+
+	// OpeningBalance is the balance of the account at the start of OpeningBalanceDate; the
+	// movements before that date are already in it.
+	OpeningBalance     float64   `json:"openingBalance,omitempty" bson:"openingBalance,omitempty"`
+	OpeningBalanceDate time.Time `json:"openingBalanceDate,omitempty" bson:"openingBalanceDate,omitempty"`
+
+	// AccountType is empty on accounts created before account types existed; they are
+	// checking accounts.
+	AccountType AccountType `json:"accountType,omitempty" bson:"accountType,omitempty"`
+
+	// MonthlyFeeDay is the day of the month the monthly fee is charged; zero is the first.
+	MonthlyFeeDay int `json:"monthlyFeeDay,omitempty" bson:"monthlyFeeDay,omitempty"`
+
+	// Archived accounts stay in the history of their records but can't be picked for new ones.
+	Archived bool `json:"archived,omitempty" bson:"archived,omitempty"`
 }
 
 type BankAccountRequest struct {
@@ -48,9 +90,31 @@ func NewBankAccount(bankCode, agency, accountNumber string) *BankAccount {
 	}
 }
 
+// Type returns the account type, defaulting to checking.
+func (ba *BankAccount) Type() AccountType {
+	if ba.AccountType == "" {
+		return AccountTypeChecking
+	}
+	return ba.AccountType
+}
+
 func (ba *BankAccount) Validate() error {
-	if strings.TrimSpace(ba.BankCode) == "" {
-		return errors.New("bankCode is required")
+	accountType := ba.Type()
+	if !accountType.IsValid() {
+		return errors.New("accountType must be one of checking, savings, investment, wallet, benefit")
+	}
+
+	// Cash wallets and benefit cards are not held at a bank; without a bankCode they are
+	// known by their custom name.
+	switch accountType {
+	case AccountTypeWallet, AccountTypeBenefit:
+		if strings.TrimSpace(ba.BankCode) == "" && strings.TrimSpace(ba.CustomBankName) == "" {
+			return errors.New("customBankName is required when bankCode is not set")
+		}
+	default:
+		if strings.TrimSpace(ba.BankCode) == "" {
+			return errors.New("bankCode is required")
+		}
 	}
 
 	if ba.BankCode == "other" && strings.TrimSpace(ba.CustomBankName) == "" {
@@ -65,25 +129,35 @@ func (ba *BankAccount) Validate() error {
 		return errors.New("description must not exceed 150 characters")
 	}
 
-	agency := strings.TrimSpace(ba.Agency)
-	if agency == "" {
-		return errors.New("agency is required")
-	}
-	if len(agency) < 3 || len(agency) > 10 {
-		return errors.New("agency must be between 3 and 10 characters")
-	}
+	if accountType == AccountTypeChecking || accountType == AccountTypeSavings {
+		agency := strings.TrimSpace(ba.Agency)
+		if agency == "" {
+			return errors.New("agency is required")
+		}
+		if len(agency) < 3 || len(agency) > 10 {
+			return errors.New("agency must be between 3 and 10 characters")
+		}
 
-	accountNumber := strings.TrimSpace(ba.AccountNumber)
-	if accountNumber == "" {
-		return errors.New("accountNumber is required")
-	}
-	if len(accountNumber) < 3 || len(accountNumber) > 20 {
-		return errors.New("accountNumber must be between 3 and 20 characters")
+		accountNumber := strings.TrimSpace(ba.AccountNumber)
+		if accountNumber == "" {
+			return errors.New("accountNumber is required")
+		}
+		if len(accountNumber) < 3 || len(accountNumber) > 20 {
+			return errors.New("accountNumber must be between 3 and 20 characters")
+		}
 	}
 
 	if ba.MonthlyFee < 0 {
 		return errors.New("monthlyFee must be greater than or equal to 0")
 	}
 
+	if ba.MonthlyFeeDay < 0 || ba.MonthlyFeeDay > 31 {
+		return errors.New("monthlyFeeDay must be between 1 and 31")
+	}
+
+	if ba.OpeningBalance != 0 && ba.OpeningBalanceDate.IsZero() {
+		return errors.New("openingBalanceDate is required when openingBalance is set")
+	}
+
 	return nil
 }
@@ -0,0 +1,206 @@
+package entity_finance
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// BankAccountBalanceRepositoryInterface stores the statement checkpoints of the bank accounts
+// and the adjustments posted when reconciling them.
+type BankAccountBalanceRepositoryInterface interface {
+	SaveCheckpoint(ctx context.Context, data *BalanceCheckpoint) (*BalanceCheckpoint, error)
+	GetCheckpoints(ctx context.Context, bankAccountID string) ([]BalanceCheckpoint, error)
+	DeleteCheckpoint(ctx context.Context, id string) error
+	SaveAdjustment(ctx context.Context, data *BalanceAdjustment) (*BalanceAdjustment, error)
+	GetAdjustments(ctx context.Context) ([]BalanceAdjustment, error)
+	DeleteAdjustment(ctx context.Context, id string) error
+}
+
+// BankAccountBalanceServiceInterface computes the balances of the bank accounts and
+// reconciles them with the bank statements.
+type BankAccountBalanceServiceInterface interface {
+	GetBalances(ctx context.Context, date time.Time) ([]BankAccountBalance, error)
+	GetAdjustments(ctx context.Context) ([]BalanceAdjustment, error)
+	CreateCheckpoint(ctx context.Context, data *BalanceCheckpoint) (*BalanceCheckpoint, error)
+	GetCheckpoints(ctx context.Context, bankAccountID string) ([]BalanceCheckpoint, error)
+	Reconcile(ctx context.Context, bankAccountID string, date time.Time) (*AccountReconciliation, error)
+	PostAdjustment(ctx context.Context, bankAccountID string, date time.Time) (*AccountReconciliation, error)
+}
+
+// BalanceCheckpoint is the balance the bank statement shows for an account at the end of a day.
+type BalanceCheckpoint struct {
+	ID               string    `json:"id"`
+	UserID           string    `json:"userId"`
+	BankAccountID    string    `json:"bankAccountId"`
+	Date             time.Time `json:"date"`
+	StatementBalance float64   `json:"statementBalance"`
+	CreatedAt        time.Time `json:"createdAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+}
+
+// Validate checks the BalanceCheckpoint fields for correctness.
+func (c *BalanceCheckpoint) Validate() error {
+	if strings.TrimSpace(c.BankAccountID) == "" {
+		return errors.New("bankAccountId is required")
+	}
+	if c.Date.IsZero() {
+		return errors.New("date is required")
+	}
+	return nil
+}
+
+// BalanceCheckpointID returns the ID of the checkpoint of the account on date; an account
+// has at most one checkpoint a day.
+func BalanceCheckpointID(bankAccountID string, date time.Time) string {
+	return bankAccountID + "_" + date.Format("2006-01-02")
+}
+
+// MoveTo moves the checkpoint to another account; its ID follows the account.
+func (c *BalanceCheckpoint) MoveTo(bankAccountID string) {
+	c.BankAccountID = bankAccountID
+	c.ID = BalanceCheckpointID(bankAccountID, c.Date)
+}
+
+// BalanceAdjustment is posted to make the computed balance of an account match a checkpoint.
+// Like a transfer, it changes the balance but is neither revenue nor expense.
+type BalanceAdjustment struct {
+	ID            string    `json:"id"`
+	UserID        string    `json:"userId"`
+	BankAccountID string    `json:"bankAccountId"`
+	CheckpointID  string    `json:"checkpointId"`
+	Date          time.Time `json:"date"`
+	Amount        float64   `json:"amount"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// MoveTo moves the adjustment to another account together with the checkpoint it reconciles.
+func (a *BalanceAdjustment) MoveTo(bankAccountID string) {
+	a.BankAccountID = bankAccountID
+	a.CheckpointID = BalanceCheckpointID(bankAccountID, a.Date)
+	a.ID = a.CheckpointID
+}
+
+// BankAccountBalance is the computed balance of an account at the end of a day.
+type BankAccountBalance struct {
+	BankAccountID string      `json:"bankAccountId"`
+	AccountType   AccountType `json:"accountType"`
+	Date          time.Time   `json:"date"`
+	Balance       float64     `json:"balance"`
+}
+
+// AccountTypeBalance is the sum of the balances of the accounts of one type.
+type AccountTypeBalance struct {
+	AccountType  AccountType `json:"accountType"`
+	Balance      float64     `json:"balance"`
+	AccountCount int         `json:"accountCount"`
+}
+
+// GroupBalancesByAccountType sums the balances per account type, in the order the types
+// are declared. Types without accounts are left out.
+func GroupBalancesByAccountType(balances []BankAccountBalance) []AccountTypeBalance {
+	totals := make(map[AccountType]*AccountTypeBalance)
+	for _, balance := range balances {
+		accountType := balance.AccountType
+		if accountType == "" {
+			accountType = AccountTypeChecking
+		}
+
+		total, ok := totals[accountType]
+		if !ok {
+			total = &AccountTypeBalance{AccountType: accountType}
+			totals[accountType] = total
+		}
+		total.Balance = roundCents(total.Balance + balance.Balance)
+		total.AccountCount++
+	}
+
+	grouped := make([]AccountTypeBalance, 0, len(totals))
+	for _, accountType := range accountTypes {
+		if total, ok := totals[accountType]; ok {
+			grouped = append(grouped, *total)
+		}
+	}
+	return grouped
+}
+
+// AccountReconciliation compares the computed balance of an account with a checkpoint.
+type AccountReconciliation struct {
+	BankAccountID    string    `json:"bankAccountId"`
+	CheckpointID     string    `json:"checkpointId"`
+	Date             time.Time `json:"date"`
+	ComputedBalance  float64   `json:"computedBalance"`
+	StatementBalance float64   `json:"statementBalance"`
+	// Difference is the statement balance minus the computed balance.
+	Difference   float64 `json:"difference"`
+	AdjustmentID string  `json:"adjustmentId,omitempty"`
+}
+
+// NewAccountReconciliation compares the balance computed for the day of the checkpoint with it.
+func NewAccountReconciliation(checkpoint BalanceCheckpoint, computed float64) AccountReconciliation {
+	return AccountReconciliation{
+		BankAccountID:    checkpoint.BankAccountID,
+		CheckpointID:     checkpoint.ID,
+		Date:             checkpoint.Date,
+		ComputedBalance:  roundCents(computed),
+		StatementBalance: checkpoint.StatementBalance,
+		Difference:       roundCents(checkpoint.StatementBalance - computed),
+	}
+}
+
+// AccountLedger holds the movements that change the balances of the bank accounts.
+type AccountLedger struct {
+	Incomes     []IncomeRecord
+	Expenses    []ExpenseRecord
+	Transfers   []Transfer
+	Adjustments []BalanceAdjustment
+}
+
+// Balance returns the balance of the account at the end of date: the opening balance plus the
+// movements from the opening date on. Only paid expenses leave the account; card purchases
+// leave it through the invoice payment.
+func (l *AccountLedger) Balance(account BankAccountRequest, date time.Time) float64 {
+	from := startOfDay(account.OpeningBalanceDate)
+	until := startOfDay(date).AddDate(0, 0, 1)
+	counts := func(day time.Time) bool {
+		return !day.Before(from) && day.Before(until)
+	}
+
+	balance := account.OpeningBalance
+	for _, income := range l.Incomes {
+		if income.BankAccountID == account.ID && counts(income.ReceiptDate) {
+			balance += income.Amount
+		}
+	}
+	for _, expense := range l.Expenses {
+		if expense.BankPaidFrom != account.ID || expense.IsCardPurchase() || expense.StoredStatus() != ExpenseStatusPaid {
+			continue
+		}
+		paidOn := expense.PaymentDate
+		if paidOn.IsZero() {
+			paidOn = expense.DueDate
+		}
+		if counts(paidOn) {
+			balance -= expense.Amount
+		}
+	}
+	for _, transfer := range l.Transfers {
+		if !counts(transfer.TransferDate) {
+			continue
+		}
+		if transfer.FromBankAccountID == account.ID {
+			balance -= transfer.Amount
+		}
+		if transfer.ToBankAccountID == account.ID {
+			balance += transfer.Amount
+		}
+	}
+	for _, adjustment := range l.Adjustments {
+		if adjustment.BankAccountID == account.ID && counts(adjustment.Date) {
+			balance += adjustment.Amount
+		}
+	}
+	return roundCents(balance)
+}
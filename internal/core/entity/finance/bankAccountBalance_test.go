@@ -0,0 +1,59 @@
+package entity_finance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountLedger_Balance(t *testing.T) {
+	account := BankAccountRequest{ID: "checking", BankAccount: BankAccount{OpeningBalance: 1000, OpeningBalanceDate: date(2025, 3, 1)}}
+	ledger := AccountLedger{
+		Incomes: []IncomeRecord{
+			{BankAccountID: "checking", Amount: 500, ReceiptDate: date(2025, 2, 28)},
+			{BankAccountID: "checking", Amount: 300, ReceiptDate: date(2025, 3, 1)},
+			{BankAccountID: "savings", Amount: 50, ReceiptDate: date(2025, 3, 2)},
+		},
+		Expenses: []ExpenseRecord{
+			{BankPaidFrom: "checking", Amount: 120.5, DueDate: date(2025, 3, 5), PaymentDate: date(2025, 3, 2), Status: ExpenseStatusPaid},
+			{BankPaidFrom: "checking", Amount: 80, DueDate: date(2025, 3, 3), Status: ExpenseStatusPending},
+			{BankPaidFrom: "checking", Amount: 60, DueDate: date(2025, 3, 3), PaymentDate: date(2025, 3, 3), CreditCardID: "card-1"},
+		},
+		Transfers: []Transfer{
+			{FromBankAccountID: "checking", ToBankAccountID: "savings", Amount: 200, TransferDate: date(2025, 3, 4)},
+		},
+		Adjustments: []BalanceAdjustment{
+			{BankAccountID: "checking", Amount: -0.5, Date: date(2025, 3, 4)},
+		},
+	}
+
+	assert.Equal(t, 1300.0, ledger.Balance(account, date(2025, 3, 1)))
+	assert.Equal(t, 1179.5, ledger.Balance(account, date(2025, 3, 3)))
+	assert.Equal(t, 979.0, ledger.Balance(account, date(2025, 3, 4)))
+
+	savings := BankAccountRequest{ID: "savings"}
+	assert.Equal(t, 250.0, ledger.Balance(savings, date(2025, 3, 31)))
+}
+
+func TestNewAccountReconciliation(t *testing.T) {
+	checkpoint := BalanceCheckpoint{ID: BalanceCheckpointID("checking", date(2025, 3, 31)), BankAccountID: "checking", Date: date(2025, 3, 31), StatementBalance: 1000}
+
+	reconciliation := NewAccountReconciliation(checkpoint, 1012.35)
+	assert.Equal(t, "checking_2025-03-31", reconciliation.CheckpointID)
+	assert.Equal(t, -12.35, reconciliation.Difference)
+}
+
+func TestGroupBalancesByAccountType(t *testing.T) {
+	grouped := GroupBalancesByAccountType([]BankAccountBalance{
+		{BankAccountID: "wallet", AccountType: AccountTypeWallet, Balance: 80.3},
+		{BankAccountID: "legacy", Balance: 1000},
+		{BankAccountID: "checking", AccountType: AccountTypeChecking, Balance: -250.1},
+		{BankAccountID: "meal", AccountType: AccountTypeBenefit, Balance: 600},
+	})
+
+	assert.Equal(t, []AccountTypeBalance{
+		{AccountType: AccountTypeChecking, Balance: 749.9, AccountCount: 2},
+		{AccountType: AccountTypeWallet, Balance: 80.3, AccountCount: 1},
+		{AccountType: AccountTypeBenefit, Balance: 600, AccountCount: 1},
+	}, grouped)
+}
@@ -0,0 +1,82 @@
+package entity_finance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBankAccount_ValidateAccountType(t *testing.T) {
+	tests := []struct {
+		name    string
+		account BankAccount
+		wantErr string
+	}{
+		{
+			name:    "legacy account without type is a checking account",
+			account: BankAccount{BankCode: "001", Agency: "1234", AccountNumber: "12345-6"},
+		},
+		{
+			name:    "savings account requires agency",
+			account: BankAccount{AccountType: AccountTypeSavings, BankCode: "001", AccountNumber: "12345-6"},
+			wantErr: "agency is required",
+		},
+		{
+			name:    "investment account without agency and account number",
+			account: BankAccount{AccountType: AccountTypeInvestment, BankCode: "102"},
+		},
+		{
+			name:    "investment account requires bankCode",
+			account: BankAccount{AccountType: AccountTypeInvestment, CustomBankName: "Corretora"},
+			wantErr: "bankCode is required",
+		},
+		{
+			name:    "cash wallet with a name",
+			account: BankAccount{AccountType: AccountTypeWallet, CustomBankName: "Carteira"},
+		},
+		{
+			name:    "benefit card requires a name without bankCode",
+			account: BankAccount{AccountType: AccountTypeBenefit},
+			wantErr: "customBankName is required when bankCode is not set",
+		},
+		{
+			name:    "unknown type",
+			account: BankAccount{AccountType: "loan", BankCode: "001"},
+			wantErr: "accountType must be one of checking, savings, investment, wallet, benefit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.account.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestBankAccountRequest_MonthlyFeeExpense(t *testing.T) {
+	account := BankAccountRequest{ID: "checking", BankAccount: BankAccount{MonthlyFee: 15, MonthlyFeeDay: 31}}
+
+	fee := account.MonthlyFeeExpense("user-1", date(2025, 2, 28))
+	if assert.NotNil(t, fee) {
+		assert.Equal(t, "bankfee_checking_2025-02", fee.ID)
+		assert.Equal(t, date(2025, 2, 28), fee.DueDate, "the fee day falls on the last day of shorter months")
+		assert.Equal(t, 15.0, fee.Amount)
+	}
+
+	assert.Nil(t, account.MonthlyFeeExpense("user-1", date(2025, 3, 30)), "not due yet")
+
+	account.OpeningBalanceDate = date(2025, 3, 15)
+	assert.Nil(t, account.MonthlyFeeExpense("user-1", date(2025, 2, 28)), "already in the opening balance")
+
+	account.Archived = true
+	assert.Nil(t, account.MonthlyFeeExpense("user-1", date(2025, 3, 31)))
+
+	account.Archived = false
+	account.MonthlyFee = 0
+	assert.Nil(t, account.MonthlyFeeExpense("user-1", date(2025, 3, 31)))
+}
@@ -0,0 +1,69 @@
+package entity_finance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BankFeeCategory is the expense category of the monthly fees of the bank accounts.
+const BankFeeCategory = "bank_fees"
+
+type BankFeeServiceInterface interface {
+	GenerateMonthlyFees(ctx context.Context, date time.Time) ([]ExpenseRecord, error)
+}
+
+const bankFeeIDPrefix = "bankfee_"
+
+// BankFeeExpenseID returns the ID of the fee expense of an account in the month of date, so
+// the fee of a month is written once however many times it is generated.
+func BankFeeExpenseID(bankAccountID string, date time.Time) string {
+	return fmt.Sprintf("%s%s_%s", bankFeeIDPrefix, bankAccountID, date.Format("2006-01"))
+}
+
+// IsBankFee reports whether the expense is a monthly fee generated for a bank account. A fee
+// is cancelled rather than deleted: the generator writes the fee of a month whose document
+// is missing, so a deleted fee would be charged again on the next run.
+func (er *ExpenseRecord) IsBankFee() bool {
+	return strings.HasPrefix(er.ID, bankFeeIDPrefix)
+}
+
+// MonthlyFeeDate returns the day the fee of the account is charged in the month of date;
+// days past the end of the month fall on its last day.
+func (ba *BankAccount) MonthlyFeeDate(date time.Time) time.Time {
+	day := ba.MonthlyFeeDay
+	if day == 0 {
+		day = 1
+	}
+	return dayOfMonth(date.Year(), date.Month(), day)
+}
+
+// MonthlyFeeExpense returns the fee of the account in the month of date as an expense paid
+// from the account. It returns nil when the account has no fee or is archived, when the fee
+// is not due by date, or when it was charged before the opening balance and is already in it.
+func (ba *BankAccountRequest) MonthlyFeeExpense(userID string, date time.Time) *ExpenseRecord {
+	if ba.MonthlyFee <= 0 || ba.Archived {
+		return nil
+	}
+
+	feeDate := ba.MonthlyFeeDate(date)
+	if feeDate.After(date) {
+		return nil
+	}
+	if !ba.OpeningBalanceDate.IsZero() && feeDate.Before(startOfDay(ba.OpeningBalanceDate)) {
+		return nil
+	}
+
+	return &ExpenseRecord{
+		ID:           BankFeeExpenseID(ba.ID, date),
+		Category:     BankFeeCategory,
+		Description:  fmt.Sprintf("Monthly fee %s", feeDate.Format("2006-01")),
+		DueDate:      feeDate,
+		PaymentDate:  feeDate,
+		Status:       ExpenseStatusPaid,
+		Amount:       ba.MonthlyFee,
+		BankPaidFrom: ba.ID,
+		UserID:       userID,
+	}
+}
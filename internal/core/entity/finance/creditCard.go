@@ -24,6 +24,8 @@ type CreditCardServiceInterface interface {
 	GetByFilter(ctx context.Context, data map[string]interface{}) ([]CreditCardRequest, error)
 	UpdateCreditCard(ctx context.Context, data *CreditCardRequest) (*CreditCardRequest, error)
 	DeleteCreditCard(ctx context.Context, id *string) error
+	ReassignCreditCard(ctx context.Context, id, targetID *string) error
+	ArchiveCreditCard(ctx context.Context, id *string) (*CreditCardRequest, error)
 }
 
 type CreditCard struct {
@@ -35,6 +37,18 @@ type CreditCard struct {
 	CardExpiryMonth int     `json:"cardExpiryMonth" bson:"cardExpiryMonth"`
 	CardExpiryYear  int     `json:"cardExpiryYear" bson:"cardExpiryYear"`
 	CreditLimit     float64 `json:"creditLimit" bson:"creditLimit"`
+
+	// InvoiceClosingDay is the day purchases stop going to the invoice; zero means 7 days before the due day.
+	InvoiceClosingDay int `json:"invoiceClosingDay,omitempty" bson:"invoiceClosingDay,omitempty"`
+
+	// LimitAlertThresholds are the utilization percentages that publish an alert when crossed.
+	LimitAlertThresholds []float64 `json:"limitAlertThresholds,omitempty" bson:"limitAlertThresholds,omitempty"`
+	// LimitAlertLevel is the threshold last alerted. It is kept by the service and drops when
+	// the utilization goes back under it, so the threshold alerts again.
+	LimitAlertLevel float64 `json:"limitAlertLevel" bson:"limitAlertLevel"`
+
+	// Archived cards stay in the history of their purchases but can't be picked for new ones.
+	Archived bool `json:"archived,omitempty" bson:"archived,omitempty"`
 }
 
 type CreditCardRequest struct {
@@ -105,6 +119,13 @@ func (cc *CreditCard) Validate() error {
 		return errors.New("invoiceDueDate must be between 1 and 31")
 	}
 
+	if cc.InvoiceClosingDay < 0 || cc.InvoiceClosingDay > 31 {
+		return errors.New("invoiceClosingDay must be between 0 and 31, 0 meaning 7 days before invoiceDueDate")
+	}
+	if cc.InvoiceClosingDay == cc.InvoiceDueDate {
+		return errors.New("invoiceClosingDay must be different from invoiceDueDate")
+	}
+
 	if cc.CardExpiryMonth < 1 || cc.CardExpiryMonth > 12 {
 		return errors.New("cardExpiryMonth must be between 1 and 12")
 	}
@@ -128,5 +149,54 @@ func (cc *CreditCard) Validate() error {
 		return errors.New("creditLimit must be greater than or equal to 0")
 	}
 
+	for _, threshold := range cc.LimitAlertThresholds {
+		if threshold <= 0 || threshold > 100 {
+			return errors.New("limitAlertThresholds must be between 0 and 100")
+		}
+	}
+
 	return nil
 }
+
+// defaultInvoiceClosingDays is how many days before the due day an invoice closes by default.
+const defaultInvoiceClosingDays = 7
+
+// ClosingDay returns the day of the month the invoices of the card close.
+func (cc *CreditCard) ClosingDay() int {
+	if cc.InvoiceClosingDay > 0 {
+		return cc.InvoiceClosingDay
+	}
+
+	day := cc.InvoiceDueDate - defaultInvoiceClosingDays
+	if day < 1 {
+		day += 30
+	}
+	return day
+}
+
+// InvoiceMonth returns the month (YYYY-MM) of the invoice a purchase made on date goes to.
+// Purchases made up to the closing day go to the invoice that closes that month.
+func (cc *CreditCard) InvoiceMonth(date time.Time) string {
+	closing := dayOfMonth(date.Year(), date.Month(), cc.ClosingDay())
+	if startOfDay(date).After(closing) {
+		closing = dayOfMonth(date.Year(), date.Month()+1, cc.ClosingDay())
+	}
+
+	due := time.Date(closing.Year(), closing.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if cc.ClosingDay() >= cc.InvoiceDueDate {
+		due = due.AddDate(0, 1, 0)
+	}
+	return due.Format("2006-01")
+}
+
+// InvoiceDates returns the closing and due dates of the invoice of month (YYYY-MM).
+func (cc *CreditCard) InvoiceDates(month time.Time) (closing, due time.Time) {
+	due = dayOfMonth(month.Year(), month.Month(), cc.InvoiceDueDate)
+
+	closingMonth := month.Month()
+	if cc.ClosingDay() >= cc.InvoiceDueDate {
+		closingMonth--
+	}
+	closing = dayOfMonth(month.Year(), closingMonth, cc.ClosingDay())
+	return closing, due
+}
@@ -0,0 +1,122 @@
+package entity_finance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CreditCardInvoiceRepositoryInterface stores the state of the invoices; their totals are
+// always calculated from the card purchases.
+type CreditCardInvoiceRepositoryInterface interface {
+	GetInvoiceByID(ctx context.Context, id string) (*CreditCardInvoice, error)
+	GetInvoicesByCard(ctx context.Context, creditCardID string) ([]CreditCardInvoice, error)
+	SaveInvoice(ctx context.Context, data *CreditCardInvoice) (*CreditCardInvoice, error)
+}
+
+// CreditCardInvoiceServiceInterface defines the service operations for credit card invoices.
+type CreditCardInvoiceServiceInterface interface {
+	GetInvoices(ctx context.Context, creditCardID string, statuses ...CreditCardInvoiceStatus) ([]CreditCardInvoice, error)
+	GetInvoice(ctx context.Context, creditCardID, month string) (*CreditCardInvoice, error)
+	PayInvoice(ctx context.Context, creditCardID, month string, payment *CreditCardInvoicePayment) (*CreditCardInvoice, error)
+	GetCreditCardLimit(ctx context.Context, creditCardID string) (*CreditCardLimit, error)
+	GetCreditCardLimits(ctx context.Context) ([]CreditCardLimit, error)
+}
+
+// CreditCardInvoiceStatus is the stage of an invoice (fatura).
+type CreditCardInvoiceStatus string
+
+const (
+	// CreditCardInvoiceOpen still receives purchases.
+	CreditCardInvoiceOpen CreditCardInvoiceStatus = "open"
+	// CreditCardInvoiceClosed is past its closing date and waits for the payment.
+	CreditCardInvoiceClosed CreditCardInvoiceStatus = "closed"
+	CreditCardInvoicePaid   CreditCardInvoiceStatus = "paid"
+)
+
+// ParseCreditCardInvoiceStatuses parses a comma separated list of invoice statuses.
+func ParseCreditCardInvoiceStatuses(value string) ([]CreditCardInvoiceStatus, error) {
+	statuses := make([]CreditCardInvoiceStatus, 0)
+	for _, part := range strings.Split(value, ",") {
+		status := CreditCardInvoiceStatus(strings.ToLower(strings.TrimSpace(part)))
+		switch status {
+		case "":
+			continue
+		case CreditCardInvoiceOpen, CreditCardInvoiceClosed, CreditCardInvoicePaid:
+			statuses = append(statuses, status)
+		default:
+			return nil, fmt.Errorf("invalid invoice status %q: must be one of open, closed or paid", part)
+		}
+	}
+	return statuses, nil
+}
+
+// CreditCardInvoice is the invoice of a card for a month, identified by the month of its due date.
+type CreditCardInvoice struct {
+	ID           string                  `json:"id"`
+	UserID       string                  `json:"userId"`
+	CreditCardID string                  `json:"creditCardId"`
+	Month        string                  `json:"month"` // YYYY-MM
+	ClosingDate  time.Time               `json:"closingDate"`
+	DueDate      time.Time               `json:"dueDate"`
+	Status       CreditCardInvoiceStatus `json:"status"`
+	Total        float64                 `json:"total"`
+	ExpenseCount int                     `json:"expenseCount"`
+	// Payment of the invoice; the outflow is the expense record PaymentExpenseID.
+	PaidAmount       float64   `json:"paidAmount,omitempty"`
+	PaymentDate      time.Time `json:"paymentDate,omitempty"`
+	BankAccountID    string    `json:"bankAccountId,omitempty"`
+	PaymentExpenseID string    `json:"paymentExpenseId,omitempty"`
+}
+
+// CreditCardInvoiceID returns the ID of the invoice of the card for the month (YYYY-MM).
+func CreditCardInvoiceID(creditCardID, month string) string {
+	return creditCardID + "_" + month
+}
+
+// CreditCardIDOfInvoice returns the card of an invoice ID built by CreditCardInvoiceID.
+func CreditCardIDOfInvoice(invoiceID string) string {
+	if i := strings.LastIndex(invoiceID, "_"); i >= 0 {
+		return invoiceID[:i]
+	}
+	return invoiceID
+}
+
+// ParseInvoiceMonth parses the YYYY-MM month of an invoice.
+func ParseInvoiceMonth(month string) (time.Time, error) {
+	parsed, err := time.Parse("2006-01", month)
+	if err != nil {
+		return time.Time{}, errors.New("invoice month must be in YYYY-MM format")
+	}
+	return parsed, nil
+}
+
+// StatusAt returns the status of an unpaid invoice at now.
+func (i *CreditCardInvoice) StatusAt(now time.Time) CreditCardInvoiceStatus {
+	if i.Status == CreditCardInvoicePaid {
+		return CreditCardInvoicePaid
+	}
+	if startOfDay(now).After(i.ClosingDate) {
+		return CreditCardInvoiceClosed
+	}
+	return CreditCardInvoiceOpen
+}
+
+// CreditCardInvoicePayment is the payment of an invoice from a bank account.
+type CreditCardInvoicePayment struct {
+	BankAccountID string    `json:"bankAccountId"`
+	PaymentDate   time.Time `json:"paymentDate"`
+}
+
+// Validate checks the CreditCardInvoicePayment fields for correctness.
+func (p *CreditCardInvoicePayment) Validate() error {
+	if strings.TrimSpace(p.BankAccountID) == "" {
+		return errors.New("bankAccountId is required")
+	}
+	if p.PaymentDate.IsZero() {
+		return errors.New("paymentDate is required")
+	}
+	return nil
+}
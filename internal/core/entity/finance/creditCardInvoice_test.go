@@ -0,0 +1,72 @@
+package entity_finance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreditCard_ClosingDay(t *testing.T) {
+	assert.Equal(t, 3, (&CreditCard{InvoiceDueDate: 10}).ClosingDay())
+	assert.Equal(t, 28, (&CreditCard{InvoiceDueDate: 5}).ClosingDay())
+	assert.Equal(t, 25, (&CreditCard{InvoiceDueDate: 5, InvoiceClosingDay: 25}).ClosingDay())
+}
+
+func TestCreditCard_ValidateClosingDay(t *testing.T) {
+	card := CreditCard{CardBrand: "visa", LastFourDigits: "1234", InvoiceDueDate: 10, CardExpiryMonth: 12, CardExpiryYear: time.Now().Year() + 1}
+	assert.NoError(t, card.Validate(), "zero is the default closing day")
+
+	card.InvoiceClosingDay = 32
+	assert.EqualError(t, card.Validate(), "invoiceClosingDay must be between 0 and 31, 0 meaning 7 days before invoiceDueDate")
+
+	card.InvoiceClosingDay = 10
+	assert.EqualError(t, card.Validate(), "invoiceClosingDay must be different from invoiceDueDate")
+
+	card.InvoiceClosingDay = 3
+	assert.NoError(t, card.Validate())
+}
+
+func TestCreditCard_InvoiceMonth(t *testing.T) {
+	tests := []struct {
+		name     string
+		card     CreditCard
+		purchase time.Time
+		expected string
+	}{
+		{name: "on the closing day", card: CreditCard{InvoiceDueDate: 10}, purchase: date(2025, 3, 3), expected: "2025-03"},
+		{name: "after the closing day", card: CreditCard{InvoiceDueDate: 10}, purchase: date(2025, 3, 4), expected: "2025-04"},
+		{name: "closing day after the due day", card: CreditCard{InvoiceDueDate: 5, InvoiceClosingDay: 25}, purchase: date(2025, 12, 20), expected: "2026-01"},
+		{name: "year wrap", card: CreditCard{InvoiceDueDate: 5, InvoiceClosingDay: 25}, purchase: date(2025, 12, 26), expected: "2026-02"},
+		{name: "closing day clamped to short months", card: CreditCard{InvoiceDueDate: 10, InvoiceClosingDay: 31}, purchase: date(2025, 2, 28), expected: "2025-03"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.card.InvoiceMonth(tt.purchase))
+		})
+	}
+}
+
+func TestCreditCard_InvoiceDates(t *testing.T) {
+	card := CreditCard{InvoiceDueDate: 5, InvoiceClosingDay: 25}
+
+	closing, due := card.InvoiceDates(date(2026, 1, 1))
+	assert.Equal(t, date(2025, 12, 25), closing)
+	assert.Equal(t, date(2026, 1, 5), due)
+
+	card = CreditCard{InvoiceDueDate: 10}
+	closing, due = card.InvoiceDates(date(2025, 3, 1))
+	assert.Equal(t, date(2025, 3, 3), closing)
+	assert.Equal(t, date(2025, 3, 10), due)
+}
+
+func TestCreditCardInvoice_StatusAt(t *testing.T) {
+	invoice := CreditCardInvoice{ClosingDate: date(2025, 3, 3), DueDate: date(2025, 3, 10)}
+
+	assert.Equal(t, CreditCardInvoiceOpen, invoice.StatusAt(date(2025, 3, 3)))
+	assert.Equal(t, CreditCardInvoiceClosed, invoice.StatusAt(date(2025, 3, 4)))
+
+	invoice.Status = CreditCardInvoicePaid
+	assert.Equal(t, CreditCardInvoicePaid, invoice.StatusAt(date(2025, 3, 4)))
+}
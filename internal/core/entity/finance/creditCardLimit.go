@@ -0,0 +1,79 @@
+package entity_finance
+
+import (
+	"sort"
+	"time"
+)
+
+// CreditCardLimit is how much of the credit limit of a card is committed by purchases
+// whose invoices are not paid yet.
+type CreditCardLimit struct {
+	CreditCardID   string  `json:"creditCardId"`
+	CardBrand      string  `json:"cardBrand"`
+	LastFourDigits string  `json:"lastFourDigits"`
+	CreditLimit    float64 `json:"creditLimit"`
+	// OpenInvoicesAmount is charged on unpaid invoices; FutureInstallmentsAmount are the
+	// installments dated after now, already holding the limit.
+	OpenInvoicesAmount       float64 `json:"openInvoicesAmount"`
+	FutureInstallmentsAmount float64 `json:"futureInstallmentsAmount"`
+	CommittedAmount          float64 `json:"committedAmount"`
+	AvailableLimit           float64 `json:"availableLimit"`
+	UtilizationPercent       float64 `json:"utilizationPercent"`
+}
+
+// CalculateCreditCardLimit sums the purchases of the card that are not on one of the paid
+// invoices. Cancelled purchases and invoice payments do not hold the limit.
+func CalculateCreditCardLimit(card CreditCardRequest, purchases []ExpenseRecord, paidInvoices map[string]bool, now time.Time) CreditCardLimit {
+	limit := CreditCardLimit{
+		CreditCardID:   card.ID,
+		CardBrand:      card.CardBrand,
+		LastFourDigits: card.LastFourDigits,
+		CreditLimit:    card.CreditLimit,
+	}
+
+	for _, purchase := range purchases {
+		if purchase.StoredStatus() == ExpenseStatusCancelled || purchase.IsInvoicePayment() {
+			continue
+		}
+		if paidInvoices[CreditCardInvoiceID(card.ID, card.InvoiceMonth(purchase.DueDate))] {
+			continue
+		}
+
+		if purchase.InstallmentPurchaseID != "" && purchase.DueDate.After(now) {
+			limit.FutureInstallmentsAmount = roundCents(limit.FutureInstallmentsAmount + purchase.Amount)
+		} else {
+			limit.OpenInvoicesAmount = roundCents(limit.OpenInvoicesAmount + purchase.Amount)
+		}
+	}
+
+	limit.CommittedAmount = roundCents(limit.OpenInvoicesAmount + limit.FutureInstallmentsAmount)
+	limit.AvailableLimit = roundCents(limit.CreditLimit - limit.CommittedAmount)
+	if limit.CreditLimit > 0 {
+		limit.UtilizationPercent = roundCents(limit.CommittedAmount / limit.CreditLimit * 100)
+	}
+	return limit
+}
+
+// LimitAlertThreshold returns the highest alert threshold of the card reached by the
+// utilization, or zero when none is.
+func (cc *CreditCard) LimitAlertThreshold(utilization float64) float64 {
+	thresholds := append([]float64(nil), cc.LimitAlertThresholds...)
+	sort.Float64s(thresholds)
+
+	var reached float64
+	for _, threshold := range thresholds {
+		if utilization >= threshold {
+			reached = threshold
+		}
+	}
+	return reached
+}
+
+// CreditCardLimitAlert is the message published when the utilization of a card crosses one
+// of its alert thresholds.
+type CreditCardLimitAlert struct {
+	UserID    string  `json:"userId"`
+	Threshold float64 `json:"threshold"`
+	CreditCardLimit
+	CreatedAt time.Time `json:"createdAt"`
+}
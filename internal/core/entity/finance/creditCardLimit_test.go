@@ -0,0 +1,44 @@
+package entity_finance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateCreditCardLimit(t *testing.T) {
+	card := CreditCardRequest{ID: "card-1", CreditCard: CreditCard{InvoiceDueDate: 10, CreditLimit: 1000}}
+	purchases := []ExpenseRecord{
+		{ID: "paid", CreditCardID: "card-1", DueDate: date(2025, 2, 1), Amount: 500},
+		{ID: "open", CreditCardID: "card-1", DueDate: date(2025, 3, 1), Amount: 100},
+		{ID: "cancelled", CreditCardID: "card-1", DueDate: date(2025, 3, 1), Amount: 40, Status: ExpenseStatusCancelled},
+		{ID: "p_1", CreditCardID: "card-1", DueDate: date(2025, 3, 2), Amount: 50.5, InstallmentPurchaseID: "p"},
+		{ID: "p_2", CreditCardID: "card-1", DueDate: date(2025, 4, 2), Amount: 50.5, InstallmentPurchaseID: "p"},
+		{ID: "p_3", CreditCardID: "card-1", DueDate: date(2025, 5, 2), Amount: 50.5, InstallmentPurchaseID: "p"},
+	}
+
+	limit := CalculateCreditCardLimit(card, purchases, map[string]bool{"card-1_2025-02": true}, date(2025, 3, 5))
+
+	assert.Equal(t, 150.5, limit.OpenInvoicesAmount)
+	assert.Equal(t, 101.0, limit.FutureInstallmentsAmount)
+	assert.Equal(t, 251.5, limit.CommittedAmount)
+	assert.Equal(t, 748.5, limit.AvailableLimit)
+	assert.Equal(t, 25.15, limit.UtilizationPercent)
+
+	card.CreditLimit = 0
+	assert.Zero(t, CalculateCreditCardLimit(card, purchases, nil, date(2025, 3, 5)).UtilizationPercent)
+}
+
+func TestCreditCard_LimitAlertThreshold(t *testing.T) {
+	card := CreditCard{LimitAlertThresholds: []float64{90, 50, 75}}
+
+	assert.Zero(t, card.LimitAlertThreshold(49.99))
+	assert.Equal(t, 50.0, card.LimitAlertThreshold(50))
+	assert.Equal(t, 75.0, card.LimitAlertThreshold(89))
+	assert.Equal(t, 90.0, card.LimitAlertThreshold(120))
+	assert.Zero(t, (&CreditCard{}).LimitAlertThreshold(100))
+}
+
+func TestCreditCardIDOfInvoice(t *testing.T) {
+	assert.Equal(t, "card_with_underscore", CreditCardIDOfInvoice(CreditCardInvoiceID("card_with_underscore", "2025-03")))
+}
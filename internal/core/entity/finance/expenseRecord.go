@@ -3,9 +3,11 @@ package entity_finance
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/url"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // ExpenseRecordRepositoryInterface defines the repository operations for ExpenseRecord.
@@ -13,6 +15,8 @@ type ExpenseRecordRepositoryInterface interface {
 	CreateExpenseRecord(ctx context.Context, data *ExpenseRecord) (*ExpenseRecord, error)
 	GetExpenseRecordByID(ctx context.Context, id string) (*ExpenseRecord, error)
 	GetExpenseRecords(ctx context.Context) ([]ExpenseRecord, error)
+	GetExpenseRecordsByDateRange(ctx context.Context, filter *RecordDateRange) ([]ExpenseRecord, error)
+	GetExpenseRecordsPage(ctx context.Context, filter *ExpenseRecordQueryByDate, page *PageRequest) (*ExpenseRecordPage, error)
 	GetExpenseRecordsByFilter(ctx context.Context, filter map[string]interface{}) ([]ExpenseRecord, error)
 	UpdateExpenseRecord(ctx context.Context, id string, data *ExpenseRecord) (*ExpenseRecord, error)
 	DeleteExpenseRecord(ctx context.Context, id string) error
@@ -23,11 +27,17 @@ type ExpenseRecordServiceInterface interface {
 	CreateExpenseRecord(ctx context.Context, data *ExpenseRecord) (*ExpenseRecord, error)
 	GetExpenseRecordByID(ctx context.Context, id string) (*ExpenseRecord, error)
 	GetExpenseRecords(ctx context.Context) ([]ExpenseRecord, error)
+	GetExpenseRecordsByDateRange(ctx context.Context, filter *RecordDateRange) ([]ExpenseRecord, error)
 	GetExpenseRecordsByFilter(ctx context.Context, filter map[string]interface{}) ([]ExpenseRecord, error)
 	GetExpenseRecordsByDate(ctx context.Context, filter *ExpenseRecordQueryByDate) ([]ExpenseRecord, error)
+	GetExpenseRecordsPage(ctx context.Context, filter *ExpenseRecordQueryByDate, page *PageRequest) (*ExpenseRecordPage, error)
 	UpdateExpenseRecord(ctx context.Context, id string, data *ExpenseRecord) (*ExpenseRecord, error)
 	DeleteExpenseRecord(ctx context.Context, id string) error
-	CreateExpenseByNfceUrl(ctx context.Context, url *ExpenseByNfceUrl) (*ExpenseByNfceUrl, error)
+	UpdateExpenseSeries(ctx context.Context, id string, data *ExpenseRecord, scope SeriesScope) (*ExpenseRecord, error)
+	DeleteExpenseSeries(ctx context.Context, id string, scope SeriesScope) error
+	PayExpenseRecord(ctx context.Context, id string, payment *ExpensePayment) (*ExpenseRecord, error)
+	CancelExpenseRecord(ctx context.Context, id string) (*ExpenseRecord, error)
+	CreateExpenseByNfceUrl(ctx context.Context, url *ExpenseByNfceUrl) (*NFCeImportResult, error)
 }
 
 // ExpenseRecord defines the structure for an expense record.
@@ -37,6 +47,7 @@ type ExpenseRecord struct {
 	Subcategory      string
 	DueDate          time.Time
 	PaymentDate      time.Time
+	Status           ExpenseStatus
 	Amount           float64
 	BankPaidFrom     string
 	CustomBankName   string
@@ -44,9 +55,74 @@ type ExpenseRecord struct {
 	IsRecurring      bool
 	RecurrenceNumber int
 	RecurrenceCount  int
+	SeriesID         string
+	CreditCardID     string // Card the purchase was charged to; it is paid through the invoice.
+	InvoiceID        string // Invoice of the card the purchase belongs to.
+	PaidInvoiceID    string // Set on the bank account outflow that pays an invoice.
+	NfceAccessKey    string
 	CreatedAt        time.Time
 	UpdatedAt        time.Time
 	UserID           string
+	// Recurrence is the rule requested on creation; it is stored on the series, not on the record.
+	Recurrence *RecurrenceRule `json:"-"`
+
+	// Installment N of InstallmentCount of an installment purchase.
+	InstallmentPurchaseID string
+	InstallmentNumber     int
+	InstallmentCount      int
+}
+
+// InstallmentLabel returns the "installment 3/10" label of an installment charge, or "" for
+// other expenses.
+func (er *ExpenseRecord) InstallmentLabel() string {
+	if er.InstallmentPurchaseID == "" {
+		return ""
+	}
+	return fmt.Sprintf("installment %d/%d", er.InstallmentNumber, er.InstallmentCount)
+}
+
+// EffectiveRecurrence returns the rule the record repeats by: the requested one or, for the
+// legacy recurrenceCount, a monthly rule with that count. Nil means the record does not repeat.
+func (er *ExpenseRecord) EffectiveRecurrence() *RecurrenceRule {
+	if er.Recurrence != nil {
+		return er.Recurrence
+	}
+	if er.IsRecurring && er.RecurrenceCount > 0 {
+		return MonthlyRecurrence(er.RecurrenceCount)
+	}
+	return nil
+}
+
+// ApplySeriesChanges copies the fields shared by the occurrences of a series from the
+// edited occurrence. Dates, payment and status belong to each occurrence and are kept.
+func (er *ExpenseRecord) ApplySeriesChanges(from *ExpenseRecord) {
+	er.Category = from.Category
+	er.Subcategory = from.Subcategory
+	er.Amount = from.Amount
+	er.Description = from.Description
+}
+
+// IsCardPurchase reports whether the expense was charged to a credit card. Card purchases
+// count as spending but leave the bank accounts to the invoice payment.
+func (er *ExpenseRecord) IsCardPurchase() bool {
+	return er.CreditCardID != ""
+}
+
+// IsInvoicePayment reports whether the expense is the payment of a credit card invoice. It
+// moves money out of the bank account but is not spending by itself: the purchases are.
+func (er *ExpenseRecord) IsInvoicePayment() bool {
+	return er.PaidInvoiceID != ""
+}
+
+// WithoutInvoicePayments drops the invoice payments, which must not count as spending.
+func WithoutInvoicePayments(records []ExpenseRecord) []ExpenseRecord {
+	filtered := make([]ExpenseRecord, 0, len(records))
+	for _, record := range records {
+		if !record.IsInvoicePayment() {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered
 }
 
 type ExpenseRecordQueryByDate struct {
@@ -54,6 +130,12 @@ type ExpenseRecordQueryByDate struct {
 	EndDate   string `json:"endDate"`
 }
 
+// ExpenseRecordPage is a page of expense records ordered by due date and ID.
+type ExpenseRecordPage struct {
+	Records    []ExpenseRecord
+	NextCursor string
+}
+
 // NewExpenseRecord creates a new ExpenseRecord with default values.
 // Required fields (category, dueDate, amount) must be set separately.
 func NewExpenseRecord(category string, dueDate time.Time, amount float64, userID string) *ExpenseRecord {
@@ -73,22 +155,64 @@ type NfceUrl string
 const (
 	NfceUrlItems NfceUrl = "item-by-item"
 	NfceUrlTotal NfceUrl = "total-value"
+
+	// NfceDefaultCategory is used when the import request does not choose a category.
+	NfceDefaultCategory = "food"
 )
 
+// NfceExpenseID returns the ID of the n-th expense record (from 1) imported from the receipt,
+// so a receipt imported twice writes the same records.
+func NfceExpenseID(accessKey string, n int) string {
+	return fmt.Sprintf("nfce_%s_%d", accessKey, n)
+}
+
 type ExpenseByNfceUrl struct {
-	NfceUrl    string  `json:"nfceUrl" binding:"required"`
-	UserID     string  `json:"userId"`
-	ImportMode NfceUrl `json:"importMode" binding:"required"`
+	NfceUrl     string  `json:"nfceUrl" binding:"required"`
+	UserID      string  `json:"userId"`
+	ImportMode  NfceUrl `json:"importMode" binding:"required"`
+	Category    string  `json:"category,omitempty"`
+	Subcategory string  `json:"subcategory,omitempty"`
+}
+
+// NFCeImportResult summarizes the expense records created from a NFC-e.
+type NFCeImportResult struct {
+	ImportMode   NfceUrl           `json:"importMode"`
+	AccessKey    string            `json:"accessKey"`
+	Duplicate    bool              `json:"duplicate"`
+	SellerCNPJ   string            `json:"sellerCnpj"`
+	CreatedIDs   []string          `json:"createdIds"`
+	TotalAmount  float64           `json:"totalAmount"`
+	SkippedItems []NFCeSkippedItem `json:"skippedItems"`
+}
+
+// NFCeSkippedItem is an item of the receipt that did not become an expense record.
+type NFCeSkippedItem struct {
+	Item   NFCeItem `json:"item"`
+	Reason string   `json:"reason"`
 }
 
 type NFCeItem struct {
 	ItemDescription string  `json:"item_description"`
 	ItemPrice       float64 `json:"item_price"`
+	ItemCode        string  `json:"item_code,omitempty"`
+	Quantity        float64 `json:"item_quantity,omitempty"`
+	Unit            string  `json:"item_unit,omitempty"`
+	UnitPrice       float64 `json:"item_unit_price,omitempty"`
 }
 
 type NFCe struct {
-	Itens    []NFCeItem `json:"itens"`
-	IDSeller string     `json:"id_seller"`
+	Itens        []NFCeItem `json:"itens"`
+	IDSeller     string     `json:"id_seller"`
+	SellerName   string     `json:"seller_name,omitempty"`
+	EmissionDate time.Time  `json:"emission_date,omitempty"`
+	TotalAmount  float64    `json:"total_amount,omitempty"`
+}
+
+// NFCeParser extracts the receipt data from the page returned by a SEFAZ NFC-e consultation URL.
+type NFCeParser interface {
+	// CanParse reports whether the parser recognizes the page layout.
+	CanParse(pageURL string, body []byte) bool
+	Parse(ctx context.Context, pageURL string, body []byte) (*NFCe, error)
 }
 
 func (ex *ExpenseByNfceUrl) Validate() error {
@@ -117,6 +241,10 @@ func (ex *ExpenseByNfceUrl) Validate() error {
 		return errors.New("nfceUrl must use http or https protocol")
 	}
 
+	if _, err := NFCeAccessKeyFromURL(ex.NfceUrl); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -166,6 +294,14 @@ func (er *ExpenseRecord) Validate() error {
 		}
 	}
 
+	if er.Status != "" && !er.Status.IsValid() {
+		return errors.New("status must be one of pending, paid, overdue or cancelled")
+	}
+
+	if er.Status == ExpenseStatusPaid && er.PaymentDate.IsZero() {
+		return errors.New("paymentDate is required when status is paid")
+	}
+
 	if er.Amount <= 0 {
 		return errors.New("amount must be greater than 0")
 	}
@@ -179,14 +315,24 @@ func (er *ExpenseRecord) Validate() error {
 		return errors.New("customBankName must not exceed 100 characters")
 	}
 
-	if er.Description != "" && len(er.Description) > 200 {
+	if utf8.RuneCountInString(er.Description) > 200 {
 		return errors.New("description must not exceed 200 characters")
 	}
 
+	if er.CreditCardID != "" && er.BankPaidFrom != "" {
+		return errors.New("bankPaidFrom must be empty for credit card purchases")
+	}
+
 	if er.IsRecurring && er.RecurrenceCount < 0 {
 		return errors.New("recurrenceCount must be greater than 0 when isRecurring is true")
 	}
 
+	if er.Recurrence != nil {
+		if err := er.Recurrence.Validate(); err != nil {
+			return err
+		}
+	}
+
 	if strings.TrimSpace(er.UserID) == "" {
 		return errors.New("userID is required")
 	}
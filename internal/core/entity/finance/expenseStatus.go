@@ -0,0 +1,157 @@
+package entity_finance
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ExpenseStatus is the lifecycle state of an expense record.
+type ExpenseStatus string
+
+const (
+	ExpenseStatusPending   ExpenseStatus = "pending"
+	ExpenseStatusPaid      ExpenseStatus = "paid"
+	ExpenseStatusOverdue   ExpenseStatus = "overdue"
+	ExpenseStatusCancelled ExpenseStatus = "cancelled"
+)
+
+// expenseStatusTransitions lists the statuses each status can move to. Overdue is never a
+// target: it is derived from DueDate, so an overdue expense moves like a pending one.
+var expenseStatusTransitions = map[ExpenseStatus][]ExpenseStatus{
+	ExpenseStatusPending:   {ExpenseStatusPaid, ExpenseStatusCancelled},
+	ExpenseStatusOverdue:   {ExpenseStatusPaid, ExpenseStatusCancelled},
+	ExpenseStatusPaid:      {ExpenseStatusPending},
+	ExpenseStatusCancelled: {ExpenseStatusPending},
+}
+
+// IsValid reports whether the status is one of the known statuses.
+func (s ExpenseStatus) IsValid() bool {
+	_, ok := expenseStatusTransitions[s]
+	return ok
+}
+
+// CanTransitionTo returns an error when the expense cannot move from s to next.
+func (s ExpenseStatus) CanTransitionTo(next ExpenseStatus) error {
+	if s == next {
+		return nil
+	}
+	for _, allowed := range expenseStatusTransitions[s] {
+		if allowed == next {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid status transition: %s -> %s", s, next)
+}
+
+// ParseExpenseStatuses parses a comma separated list of statuses, e.g. "pending,overdue".
+func ParseExpenseStatuses(value string) ([]ExpenseStatus, error) {
+	statuses := make([]ExpenseStatus, 0)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+		status := ExpenseStatus(part)
+		if !status.IsValid() {
+			return nil, fmt.Errorf("invalid status %q", part)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// NormalizeStatus sets the status that is stored for the record. Overdue is stored as pending
+// and records saved before the status existed are paid when they have a payment date.
+func (er *ExpenseRecord) NormalizeStatus() {
+	if er.Status == ExpenseStatusCancelled || er.Status == ExpenseStatusPaid {
+		return
+	}
+	er.Status = ExpenseStatusPending
+	if !er.PaymentDate.IsZero() {
+		er.Status = ExpenseStatusPaid
+	}
+}
+
+// StoredStatus returns the status the record has once normalized, without changing it.
+func (er *ExpenseRecord) StoredStatus() ExpenseStatus {
+	record := *er
+	record.NormalizeStatus()
+	return record.Status
+}
+
+// EffectiveStatus returns the status at the given moment: a pending expense whose due date
+// is before that day is overdue.
+func (er *ExpenseRecord) EffectiveStatus(now time.Time) ExpenseStatus {
+	status := er.StoredStatus()
+	if status == ExpenseStatusPending && er.DueDate.Before(startOfDay(now)) {
+		return ExpenseStatusOverdue
+	}
+	return status
+}
+
+// HasStatus reports whether the effective status is one of the statuses.
+func (er *ExpenseRecord) HasStatus(now time.Time, statuses ...ExpenseStatus) bool {
+	current := er.EffectiveStatus(now)
+	for _, status := range statuses {
+		if current == status {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterExpenseRecordsByStatus keeps the records whose effective status is one of the statuses.
+// Without statuses every record is kept.
+func FilterExpenseRecordsByStatus(records []ExpenseRecord, now time.Time, statuses ...ExpenseStatus) []ExpenseRecord {
+	if len(statuses) == 0 {
+		return records
+	}
+	filtered := make([]ExpenseRecord, 0, len(records))
+	for _, record := range records {
+		if record.HasStatus(now, statuses...) {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered
+}
+
+// WithoutCancelledExpenses drops the cancelled records, which must not count in totals.
+func WithoutCancelledExpenses(records []ExpenseRecord) []ExpenseRecord {
+	filtered := make([]ExpenseRecord, 0, len(records))
+	for _, record := range records {
+		if record.StoredStatus() != ExpenseStatusCancelled {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered
+}
+
+// ExpensePayment is the payment applied to an expense record by the pay operation.
+type ExpensePayment struct {
+	PaymentDate    time.Time
+	BankPaidFrom   string
+	CustomBankName string
+}
+
+// Validate checks the ExpensePayment fields for correctness.
+func (p *ExpensePayment) Validate() error {
+	if p.PaymentDate.IsZero() {
+		return errors.New("paymentDate is required")
+	}
+	if strings.TrimSpace(p.BankPaidFrom) == "" {
+		return errors.New("bankPaidFrom is required")
+	}
+	if p.BankPaidFrom == "other" && strings.TrimSpace(p.CustomBankName) == "" {
+		return errors.New("customBankName is required when bankPaidFrom is 'other'")
+	}
+	if len(p.CustomBankName) > 100 {
+		return errors.New("customBankName must not exceed 100 characters")
+	}
+	return nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,93 @@
+package entity_finance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpenseRecord_EffectiveStatus(t *testing.T) {
+	now := time.Date(2025, 6, 15, 14, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		record   ExpenseRecord
+		expected ExpenseStatus
+	}{
+		{"due in the future", ExpenseRecord{DueDate: time.Date(2025, 6, 20, 0, 0, 0, 0, time.UTC)}, ExpenseStatusPending},
+		{"due today", ExpenseRecord{DueDate: time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)}, ExpenseStatusPending},
+		{"due yesterday", ExpenseRecord{DueDate: time.Date(2025, 6, 14, 0, 0, 0, 0, time.UTC)}, ExpenseStatusOverdue},
+		{"legacy record with payment date", ExpenseRecord{DueDate: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), PaymentDate: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)}, ExpenseStatusPaid},
+		{"paid", ExpenseRecord{DueDate: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), PaymentDate: time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), Status: ExpenseStatusPaid}, ExpenseStatusPaid},
+		{"cancelled past due", ExpenseRecord{DueDate: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), Status: ExpenseStatusCancelled}, ExpenseStatusCancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.record.EffectiveStatus(now))
+		})
+	}
+}
+
+func TestExpenseRecord_NormalizeStatus(t *testing.T) {
+	record := ExpenseRecord{Status: ExpenseStatusOverdue}
+	record.NormalizeStatus()
+	assert.Equal(t, ExpenseStatusPending, record.Status)
+
+	record = ExpenseRecord{Status: ExpenseStatusPending, PaymentDate: time.Now()}
+	record.NormalizeStatus()
+	assert.Equal(t, ExpenseStatusPaid, record.Status)
+
+	record = ExpenseRecord{Status: ExpenseStatusCancelled}
+	record.NormalizeStatus()
+	assert.Equal(t, ExpenseStatusCancelled, record.Status)
+}
+
+func TestExpenseStatus_CanTransitionTo(t *testing.T) {
+	assert.NoError(t, ExpenseStatusPending.CanTransitionTo(ExpenseStatusPaid))
+	assert.NoError(t, ExpenseStatusOverdue.CanTransitionTo(ExpenseStatusCancelled))
+	assert.NoError(t, ExpenseStatusPaid.CanTransitionTo(ExpenseStatusPending))
+	assert.NoError(t, ExpenseStatusCancelled.CanTransitionTo(ExpenseStatusPending))
+	assert.NoError(t, ExpenseStatusPaid.CanTransitionTo(ExpenseStatusPaid))
+
+	assert.Error(t, ExpenseStatusPaid.CanTransitionTo(ExpenseStatusCancelled))
+	assert.Error(t, ExpenseStatusCancelled.CanTransitionTo(ExpenseStatusPaid))
+	assert.Error(t, ExpenseStatusPending.CanTransitionTo(ExpenseStatusOverdue))
+}
+
+func TestParseExpenseStatuses(t *testing.T) {
+	statuses, err := ParseExpenseStatuses("pending, Overdue,")
+	require.NoError(t, err)
+	assert.Equal(t, []ExpenseStatus{ExpenseStatusPending, ExpenseStatusOverdue}, statuses)
+
+	statuses, err = ParseExpenseStatuses("")
+	require.NoError(t, err)
+	assert.Empty(t, statuses)
+
+	_, err = ParseExpenseStatuses("late")
+	assert.Error(t, err)
+}
+
+func TestFilterExpenseRecordsByStatus(t *testing.T) {
+	now := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
+	records := []ExpenseRecord{
+		{ID: "pending", DueDate: now.AddDate(0, 0, 5)},
+		{ID: "overdue", DueDate: now.AddDate(0, 0, -5)},
+		{ID: "paid", DueDate: now, PaymentDate: now, Status: ExpenseStatusPaid},
+		{ID: "cancelled", DueDate: now, Status: ExpenseStatusCancelled},
+	}
+
+	ids := func(records []ExpenseRecord) []string {
+		result := make([]string, 0, len(records))
+		for _, record := range records {
+			result = append(result, record.ID)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"pending", "overdue"}, ids(FilterExpenseRecordsByStatus(records, now, ExpenseStatusPending, ExpenseStatusOverdue)))
+	assert.Equal(t, []string{"pending", "overdue", "paid", "cancelled"}, ids(FilterExpenseRecordsByStatus(records, now)))
+	assert.Equal(t, []string{"pending", "overdue", "paid"}, ids(WithoutCancelledExpenses(records)))
+}
@@ -14,6 +14,9 @@ type IncomeRecordRepositoryInterface interface {
 	CreateIncomeRecord(ctx context.Context, data *IncomeRecord) (*IncomeRecord, error)
 	GetIncomeRecordByID(ctx context.Context, id string) (*IncomeRecord, error)
 	GetIncomeRecords(ctx context.Context, params *GetIncomeRecordsQueryParameters) ([]IncomeRecord, error)
+	GetIncomeRecordsByDateRange(ctx context.Context, filter *RecordDateRange) ([]IncomeRecord, error)
+	GetIncomeRecordsPage(ctx context.Context, params *GetIncomeRecordsQueryParameters, page *PageRequest) (*IncomeRecordPage, error)
+	GetIncomeRecordsByFilter(ctx context.Context, filter map[string]interface{}) ([]IncomeRecord, error)
 	UpdateIncomeRecord(ctx context.Context, id string, data *IncomeRecord) (*IncomeRecord, error)
 	DeleteIncomeRecord(ctx context.Context, id string) error
 }
@@ -23,8 +26,12 @@ type IncomeRecordServiceInterface interface {
 	CreateIncomeRecord(ctx context.Context, data *IncomeRecord) (*IncomeRecord, error)
 	GetIncomeRecordByID(ctx context.Context, id string) (*IncomeRecord, error)
 	GetIncomeRecords(ctx context.Context, params *GetIncomeRecordsQueryParameters) ([]IncomeRecord, error)
+	GetIncomeRecordsByDateRange(ctx context.Context, filter *RecordDateRange) ([]IncomeRecord, error)
+	GetIncomeRecordsPage(ctx context.Context, params *GetIncomeRecordsQueryParameters, page *PageRequest) (*IncomeRecordPage, error)
 	UpdateIncomeRecord(ctx context.Context, id string, data *IncomeRecord) (*IncomeRecord, error)
 	DeleteIncomeRecord(ctx context.Context, id string) error
+	UpdateIncomeSeries(ctx context.Context, id string, data *IncomeRecord, scope SeriesScope) (*IncomeRecord, error)
+	DeleteIncomeSeries(ctx context.Context, id string, scope SeriesScope) error
 }
 
 // IncomeRecord defines the structure for an income record.
@@ -38,10 +45,35 @@ type IncomeRecord struct {
 	IsRecurring      bool
 	RecurrenceCount  int
 	RecurrenceNumber int
+	SeriesID         string
 	Observations     string
 	UserID           string
 	CreatedAt        time.Time
 	UpdatedAt        time.Time
+	// Recurrence is the rule requested on creation; it is stored on the series, not on the record.
+	Recurrence *RecurrenceRule `json:"-"`
+}
+
+// EffectiveRecurrence returns the rule the record repeats by: the requested one or, for the
+// legacy recurrenceCount, a monthly rule with that count. Nil means the record does not repeat.
+func (ir *IncomeRecord) EffectiveRecurrence() *RecurrenceRule {
+	if ir.Recurrence != nil {
+		return ir.Recurrence
+	}
+	if ir.IsRecurring && ir.RecurrenceCount > 0 {
+		return MonthlyRecurrence(ir.RecurrenceCount)
+	}
+	return nil
+}
+
+// ApplySeriesChanges copies the fields shared by the occurrences of a series from the
+// edited occurrence. The receipt date belongs to each occurrence and is kept.
+func (ir *IncomeRecord) ApplySeriesChanges(from *IncomeRecord) {
+	ir.Category = from.Category
+	ir.Description = from.Description
+	ir.BankAccountID = from.BankAccountID
+	ir.Amount = from.Amount
+	ir.Observations = from.Observations
 }
 
 type IncomeRecordEvent struct {
@@ -140,7 +172,12 @@ func (ir *IncomeRecord) Validate() error {
 		return errors.New("receiptDate is required")
 	}
 
-	if ir.IsRecurring {
+	if ir.Recurrence != nil {
+		if err := ir.Recurrence.Validate(); err != nil {
+			return err
+		}
+	} else if ir.IsRecurring && ir.SeriesID == "" {
+		// Occurrences of a series repeat by the series rule, which may have no count.
 		if ir.RecurrenceCount == 0 {
 			return errors.New("recurrenceCount is required if isRecurring is true")
 		}
@@ -173,6 +210,30 @@ type GetIncomeRecordsQueryParameters struct {
 	SortDirection *string `json:"sortDirection,omitempty"` // "asc", "desc"
 }
 
+// IncomeRecordPage is a page of income records ordered by receipt date and ID.
+type IncomeRecordPage struct {
+	Records    []IncomeRecord
+	NextCursor string
+}
+
+// StartDay returns the startDate parameter as a date; zero when it is not set.
+func (p *GetIncomeRecordsQueryParameters) StartDay() time.Time {
+	return parseQueryDay(p.StartDate)
+}
+
+// EndDay returns the endDate parameter as a date; zero when it is not set.
+func (p *GetIncomeRecordsQueryParameters) EndDay() time.Time {
+	return parseQueryDay(p.EndDate)
+}
+
+func parseQueryDay(value *string) time.Time {
+	if value == nil {
+		return time.Time{}
+	}
+	t, _ := time.Parse("2006-01-02", *value)
+	return t
+}
+
 func (p *GetIncomeRecordsQueryParameters) Validate() error {
 	if p.StartDate != nil {
 		if _, err := time.Parse("2006-01-02", *p.StartDate); err != nil {
@@ -0,0 +1,196 @@
+package entity_finance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// InstallmentPurchaseRepositoryInterface defines the repository operations for installment purchases.
+type InstallmentPurchaseRepositoryInterface interface {
+	CreateInstallmentPurchase(ctx context.Context, data *InstallmentPurchase) (*InstallmentPurchase, error)
+	GetInstallmentPurchaseByID(ctx context.Context, id string) (*InstallmentPurchase, error)
+	GetInstallmentPurchases(ctx context.Context) ([]InstallmentPurchase, error)
+	UpdateInstallmentPurchase(ctx context.Context, data *InstallmentPurchase) (*InstallmentPurchase, error)
+	DeleteInstallmentPurchase(ctx context.Context, id string) error
+}
+
+// InstallmentPurchaseServiceInterface defines the service operations for installment purchases.
+type InstallmentPurchaseServiceInterface interface {
+	CreateInstallmentPurchase(ctx context.Context, data *InstallmentPurchase) (*InstallmentPurchaseSummary, error)
+	GetInstallmentPurchaseByID(ctx context.Context, id string) (*InstallmentPurchaseSummary, error)
+	GetInstallmentPurchases(ctx context.Context) ([]InstallmentPurchaseSummary, error)
+	DeleteInstallmentPurchase(ctx context.Context, id string) error
+}
+
+// maxInstallments is the longest split accepted for a purchase.
+const maxInstallments = 48
+
+// InstallmentPurchase is a card purchase split in monthly installments (parcelamento). Each
+// installment is charged as an expense record on the invoice of its month.
+type InstallmentPurchase struct {
+	ID           string    `json:"id"`
+	UserID       string    `json:"userId"`
+	CreditCardID string    `json:"creditCardId"`
+	Category     string    `json:"category"`
+	Subcategory  string    `json:"subcategory,omitempty"`
+	Description  string    `json:"description,omitempty"`
+	PurchaseDate time.Time `json:"purchaseDate"`
+	TotalAmount  float64   `json:"totalAmount"`
+	Installments int       `json:"installments"`
+	// InterestRate is the monthly rate in percent; zero is "sem juros".
+	InterestRate float64   `json:"interestRate,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// Validate checks the InstallmentPurchase fields for correctness.
+func (p *InstallmentPurchase) Validate() error {
+	if strings.TrimSpace(p.CreditCardID) == "" {
+		return errors.New("creditCardId is required")
+	}
+	if strings.TrimSpace(p.Category) == "" {
+		return errors.New("category is required")
+	}
+	if p.PurchaseDate.IsZero() {
+		return errors.New("purchaseDate is required")
+	}
+	if p.TotalAmount <= 0 {
+		return errors.New("totalAmount must be greater than 0")
+	}
+	if p.Installments < 2 || p.Installments > maxInstallments {
+		return fmt.Errorf("installments must be between 2 and %d", maxInstallments)
+	}
+	if p.InterestRate < 0 {
+		return errors.New("interestRate must be greater than or equal to 0")
+	}
+	if len(p.Description) > 200 {
+		return errors.New("description must not exceed 200 characters")
+	}
+	return nil
+}
+
+// InstallmentAmounts returns the amount of each installment. Without interest the total is
+// split evenly and the cents left over go to the first installment; with interest every
+// installment has the fixed payment of the Price table.
+func (p *InstallmentPurchase) InstallmentAmounts() []float64 {
+	amounts := make([]float64, p.Installments)
+
+	if p.InterestRate == 0 {
+		installment := math.Floor(p.TotalAmount*100/float64(p.Installments)) / 100
+		for i := range amounts {
+			amounts[i] = installment
+		}
+		amounts[0] = roundCents(p.TotalAmount - installment*float64(p.Installments-1))
+		return amounts
+	}
+
+	rate := p.InterestRate / 100
+	payment := roundCents(p.TotalAmount * rate / (1 - math.Pow(1+rate, -float64(p.Installments))))
+	for i := range amounts {
+		amounts[i] = payment
+	}
+	return amounts
+}
+
+// Charges builds the expense record of each installment. The n-th installment is dated n-1
+// months after the purchase, so it lands on the n-th invoice of the card.
+func (p *InstallmentPurchase) Charges() []ExpenseRecord {
+	amounts := p.InstallmentAmounts()
+	charges := make([]ExpenseRecord, 0, len(amounts))
+	for i, amount := range amounts {
+		number := i + 1
+		charges = append(charges, ExpenseRecord{
+			ID:                    fmt.Sprintf("%s_%d", p.ID, number),
+			Category:              p.Category,
+			Subcategory:           p.Subcategory,
+			DueDate:               dayOfMonth(p.PurchaseDate.Year(), p.PurchaseDate.Month()+time.Month(i), p.PurchaseDate.Day()),
+			Status:                ExpenseStatusPending,
+			Amount:                amount,
+			Description:           p.Description,
+			CreditCardID:          p.CreditCardID,
+			InstallmentPurchaseID: p.ID,
+			InstallmentNumber:     number,
+			InstallmentCount:      p.Installments,
+			UserID:                p.UserID,
+			CreatedAt:             p.CreatedAt,
+			UpdatedAt:             p.UpdatedAt,
+		})
+	}
+	return charges
+}
+
+// InstallmentPurchaseSummary is a purchase with what is left to be charged.
+type InstallmentPurchaseSummary struct {
+	InstallmentPurchase
+	InstallmentAmount     float64 `json:"installmentAmount"`
+	TotalWithInterest     float64 `json:"totalWithInterest"`
+	ChargedInstallments   int     `json:"chargedInstallments"`
+	RemainingInstallments int     `json:"remainingInstallments"`
+	RemainingAmount       float64 `json:"remainingAmount"`
+}
+
+// SummarizeInstallmentPurchase returns the purchase with the commitment left at now: the
+// installments dated after now, which no invoice has charged yet.
+func SummarizeInstallmentPurchase(purchase InstallmentPurchase, now time.Time) InstallmentPurchaseSummary {
+	summary := InstallmentPurchaseSummary{InstallmentPurchase: purchase}
+	for _, charge := range purchase.Charges() {
+		summary.InstallmentAmount = charge.Amount
+		summary.TotalWithInterest = roundCents(summary.TotalWithInterest + charge.Amount)
+		if charge.DueDate.After(now) {
+			summary.RemainingInstallments++
+			summary.RemainingAmount = roundCents(summary.RemainingAmount + charge.Amount)
+		} else {
+			summary.ChargedInstallments++
+		}
+	}
+	return summary
+}
+
+// InstallmentCommitments groups the installment charges among the records by purchase, with
+// what remains after now, the largest commitments first.
+func InstallmentCommitments(records []ExpenseRecord, now time.Time) []InstallmentCommitmentItem {
+	byPurchase := make(map[string]*InstallmentCommitmentItem)
+	for _, record := range records {
+		if record.InstallmentPurchaseID == "" {
+			continue
+		}
+
+		item, ok := byPurchase[record.InstallmentPurchaseID]
+		if !ok {
+			item = &InstallmentCommitmentItem{
+				PurchaseID:   record.InstallmentPurchaseID,
+				Description:  record.Description,
+				Category:     record.Category,
+				Installments: record.InstallmentCount,
+			}
+			byPurchase[record.InstallmentPurchaseID] = item
+		}
+
+		item.TotalAmount = roundCents(item.TotalAmount + record.Amount)
+		if record.DueDate.After(now) {
+			item.RemainingInstallments++
+			item.RemainingAmount = roundCents(item.RemainingAmount + record.Amount)
+		}
+	}
+
+	items := make([]InstallmentCommitmentItem, 0, len(byPurchase))
+	for _, item := range byPurchase {
+		items = append(items, *item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].RemainingAmount != items[j].RemainingAmount {
+			return items[i].RemainingAmount > items[j].RemainingAmount
+		}
+		return items[i].PurchaseID < items[j].PurchaseID
+	})
+	return items
+}
+
+func roundCents(value float64) float64 {
+	return math.Round(value*100) / 100
+}
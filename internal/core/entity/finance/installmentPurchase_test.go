@@ -0,0 +1,58 @@
+package entity_finance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallmentPurchase_InstallmentAmounts(t *testing.T) {
+	purchase := InstallmentPurchase{TotalAmount: 1000, Installments: 3}
+	assert.Equal(t, []float64{333.34, 333.33, 333.33}, purchase.InstallmentAmounts())
+
+	purchase = InstallmentPurchase{TotalAmount: 1000, Installments: 10, InterestRate: 2}
+	amounts := purchase.InstallmentAmounts()
+	require.Len(t, amounts, 10)
+	for _, amount := range amounts {
+		assert.Equal(t, 111.33, amount)
+	}
+}
+
+func TestInstallmentPurchase_Charges(t *testing.T) {
+	purchase := InstallmentPurchase{
+		ID:           "purchase-1",
+		CreditCardID: "card-1",
+		Category:     "electronics",
+		PurchaseDate: date(2025, 1, 31),
+		TotalAmount:  300,
+		Installments: 3,
+	}
+
+	charges := purchase.Charges()
+	require.Len(t, charges, 3)
+	assert.Equal(t, "purchase-1_2", charges[1].ID)
+	assert.Equal(t, date(2025, 2, 28), charges[1].DueDate)
+	assert.Equal(t, date(2025, 3, 31), charges[2].DueDate)
+	assert.Equal(t, "card-1", charges[2].CreditCardID)
+	assert.Equal(t, "installment 3/3", charges[2].InstallmentLabel())
+	assert.Empty(t, (&ExpenseRecord{}).InstallmentLabel())
+
+	summary := SummarizeInstallmentPurchase(purchase, date(2025, 2, 28))
+	assert.Equal(t, 2, summary.ChargedInstallments)
+	assert.Equal(t, 1, summary.RemainingInstallments)
+	assert.Equal(t, 100.0, summary.RemainingAmount)
+	assert.Equal(t, 300.0, summary.TotalWithInterest)
+
+	records := append(charges, ExpenseRecord{ID: "other", Amount: 50})
+	commitments := InstallmentCommitments(records, date(2025, 1, 31))
+	require.Len(t, commitments, 1)
+	assert.Equal(t, InstallmentCommitmentItem{
+		PurchaseID:            "purchase-1",
+		Category:              "electronics",
+		Installments:          3,
+		TotalAmount:           300,
+		RemainingInstallments: 2,
+		RemainingAmount:       200,
+	}, commitments[0])
+}
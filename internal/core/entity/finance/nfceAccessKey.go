@@ -0,0 +1,121 @@
+package entity_finance
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	nfceAccessKeyLength = 44
+	// NfceModel is the fiscal document model of a NFC-e (55 is the NF-e).
+	NfceModel = "65"
+)
+
+// NFCeAccessKey is the decoded "chave de acesso" of a NFC-e.
+//
+// Layout: cUF(2) AAMM(4) CNPJ(14) mod(2) serie(3) nNF(9) tpEmis(1) cNF(8) cDV(1).
+type NFCeAccessKey struct {
+	Key          string `json:"key"`
+	UF           string `json:"uf"`
+	Year         int    `json:"year"`
+	Month        int    `json:"month"`
+	CNPJ         string `json:"cnpj"`
+	Model        string `json:"model"`
+	Series       string `json:"series"`
+	Number       string `json:"number"`
+	EmissionType string `json:"emissionType"`
+	Code         string `json:"code"`
+	CheckDigit   int    `json:"checkDigit"`
+}
+
+// ParseNFCeAccessKey decodes the 44-digit access key and validates its check digit.
+func ParseNFCeAccessKey(key string) (*NFCeAccessKey, error) {
+	key = strings.ReplaceAll(strings.TrimSpace(key), " ", "")
+	if len(key) != nfceAccessKeyLength {
+		return nil, fmt.Errorf("access key must have %d digits", nfceAccessKeyLength)
+	}
+	for _, r := range key {
+		if r < '0' || r > '9' {
+			return nil, errors.New("access key must contain only digits")
+		}
+	}
+
+	checkDigit := int(key[43] - '0')
+	if expected := NFCeAccessKeyCheckDigit(key[:43]); checkDigit != expected {
+		return nil, fmt.Errorf("invalid access key check digit: expected %d, got %d", expected, checkDigit)
+	}
+
+	year, _ := strconv.Atoi(key[2:4])
+	month, _ := strconv.Atoi(key[4:6])
+	if month < 1 || month > 12 {
+		return nil, errors.New("invalid access key emission month")
+	}
+
+	accessKey := &NFCeAccessKey{
+		Key:          key,
+		UF:           key[0:2],
+		Year:         2000 + year,
+		Month:        month,
+		CNPJ:         key[6:20],
+		Model:        key[20:22],
+		Series:       key[22:25],
+		Number:       key[25:34],
+		EmissionType: key[34:35],
+		Code:         key[35:43],
+		CheckDigit:   checkDigit,
+	}
+
+	if accessKey.Model != NfceModel {
+		return nil, fmt.Errorf("access key model %s is not a NFC-e", accessKey.Model)
+	}
+
+	return accessKey, nil
+}
+
+// NFCeAccessKeyCheckDigit calculates the mod-11 check digit of the first 43 digits of the key.
+func NFCeAccessKeyCheckDigit(digits string) int {
+	sum := 0
+	weight := 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		sum += int(digits[i]-'0') * weight
+		weight++
+		if weight > 9 {
+			weight = 2
+		}
+	}
+
+	digit := 11 - sum%11
+	if digit >= 10 {
+		return 0
+	}
+	return digit
+}
+
+// NFCeAccessKeyFromURL extracts the access key from the "p" (or "chNFe") parameter
+// of a NFC-e QR code URL, e.g. "?p=<key>|2|1|1|<hash>".
+func NFCeAccessKeyFromURL(rawURL string) (*NFCeAccessKey, error) {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, errors.New("nfceUrl must be a valid URL")
+	}
+
+	query := parsedURL.Query()
+	param := query.Get("p")
+	if param == "" {
+		param = query.Get("chNFe")
+	}
+	if param == "" {
+		return nil, errors.New("nfceUrl does not contain the access key")
+	}
+
+	return ParseNFCeAccessKey(strings.Split(param, "|")[0])
+}
+
+// FormattedCNPJ returns the issuer CNPJ as 00.000.000/0000-00.
+func (k *NFCeAccessKey) FormattedCNPJ() string {
+	c := k.CNPJ
+	return c[0:2] + "." + c[2:5] + "." + c[5:8] + "/" + c[8:12] + "-" + c[12:14]
+}
@@ -0,0 +1,78 @@
+package entity_finance_test
+
+import (
+	"testing"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNFCeAccessKey(t *testing.T) {
+	key, err := entity_finance.ParseNFCeAccessKey("43250400776574163454653020000395661694784220")
+	require.NoError(t, err)
+
+	assert.Equal(t, "43", key.UF)
+	assert.Equal(t, 2025, key.Year)
+	assert.Equal(t, 4, key.Month)
+	assert.Equal(t, "00776574163454", key.CNPJ)
+	assert.Equal(t, "00.776.574/1634-54", key.FormattedCNPJ())
+	assert.Equal(t, "65", key.Model)
+	assert.Equal(t, "302", key.Series)
+	assert.Equal(t, "000039566", key.Number)
+	assert.Equal(t, "1", key.EmissionType)
+	assert.Equal(t, "69478422", key.Code)
+	assert.Equal(t, 0, key.CheckDigit)
+}
+
+func TestParseNFCeAccessKey_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{"too short", "4325040077657416345465302000039566169478422"},
+		{"not digits", "4325040077657416345465302000039566169478422A"},
+		{"wrong check digit", "43250400776574163454653020000395661694784221"},
+		{"NF-e model", "43250400776574163454553020000395661694784228"},
+		{"invalid month", "43251300776574163454653020000395661694784220"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := entity_finance.ParseNFCeAccessKey(tt.key)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNFCeAccessKeyCheckDigit(t *testing.T) {
+	assert.Equal(t, 0, entity_finance.NFCeAccessKeyCheckDigit("4325040077657416345465302000039566169478422"))
+	assert.Equal(t, 8, entity_finance.NFCeAccessKeyCheckDigit("3525056158586500015165001000001234100001234"))
+	assert.Equal(t, 9, entity_finance.NFCeAccessKeyCheckDigit("3125040464137600010565005000482913100482913"))
+}
+
+func TestNFCeAccessKeyFromURL(t *testing.T) {
+	key, err := entity_finance.NFCeAccessKeyFromURL("https://dfe-portal.svrs.rs.gov.br/Dfe/QrCodeNFce?p=43250400776574163454653020000395661694784220%7C2%7C1%7C1%7Cb5bd8ab6f361bea7d94707cdcacfd96b44b4d42b")
+	require.NoError(t, err)
+	assert.Equal(t, "43250400776574163454653020000395661694784220", key.Key)
+
+	key, err = entity_finance.NFCeAccessKeyFromURL("https://www.fazenda.pr.gov.br/nfce/consulta?chNFe=43250400776574163454653020000395661694784220")
+	require.NoError(t, err)
+	assert.Equal(t, "000039566", key.Number)
+
+	_, err = entity_finance.NFCeAccessKeyFromURL("https://dfe-portal.svrs.rs.gov.br/Dfe/QrCodeNFce")
+	assert.Error(t, err)
+}
+
+func TestExpenseByNfceUrl_Validate(t *testing.T) {
+	valid := entity_finance.ExpenseByNfceUrl{
+		NfceUrl:    "https://dfe-portal.svrs.rs.gov.br/Dfe/QrCodeNFce?p=43250400776574163454653020000395661694784220|2|1|1|abc",
+		UserID:     "user-1",
+		ImportMode: entity_finance.NfceUrlItems,
+	}
+	assert.NoError(t, valid.Validate())
+
+	invalid := valid
+	invalid.NfceUrl = "https://dfe-portal.svrs.rs.gov.br/Dfe/QrCodeNFce?p=43250400776574163454653020000395661694784229|2|1|1|abc"
+	assert.Error(t, invalid.Validate())
+}
@@ -0,0 +1,51 @@
+package entity_finance
+
+import (
+	"context"
+	"time"
+)
+
+// NFCeImportJobRepositoryInterface defines the repository operations for NFCeImportJob.
+type NFCeImportJobRepositoryInterface interface {
+	CreateNFCeImportJob(ctx context.Context, data *NFCeImportJob) (*NFCeImportJob, error)
+	GetNFCeImportJobByID(ctx context.Context, id string) (*NFCeImportJob, error)
+	UpdateNFCeImportJob(ctx context.Context, data *NFCeImportJob) (*NFCeImportJob, error)
+}
+
+// NFCeImportServiceInterface defines the service operations for the asynchronous NFC-e import.
+type NFCeImportServiceInterface interface {
+	EnqueueNFCeImport(ctx context.Context, data *ExpenseByNfceUrl) (*NFCeImportJob, error)
+	GetNFCeImportJob(ctx context.Context, id string) (*NFCeImportJob, error)
+}
+
+type NFCeImportStatus string
+
+const (
+	NFCeImportPending   NFCeImportStatus = "pending"
+	NFCeImportRunning   NFCeImportStatus = "running"
+	NFCeImportSucceeded NFCeImportStatus = "succeeded"
+	NFCeImportFailed    NFCeImportStatus = "failed"
+)
+
+// NFCeImportJob tracks an import queued by POST /finance/expenses/process-nfce-url.
+type NFCeImportJob struct {
+	ID        string            `json:"id"`
+	UserID    string            `json:"userId"`
+	Status    NFCeImportStatus  `json:"status"`
+	Request   ExpenseByNfceUrl  `json:"request"`
+	Result    *NFCeImportResult `json:"result,omitempty"`
+	Error     string            `json:"error,omitempty"`
+	CreatedAt time.Time         `json:"createdAt"`
+	UpdatedAt time.Time         `json:"updatedAt"`
+}
+
+// NFCeImportMessage is the message published to the queue for each job.
+type NFCeImportMessage struct {
+	JobID  string `json:"jobId"`
+	UserID string `json:"userId"`
+}
+
+// IsFinished reports whether the job reached a final status.
+func (j *NFCeImportJob) IsFinished() bool {
+	return j.Status == NFCeImportSucceeded || j.Status == NFCeImportFailed
+}
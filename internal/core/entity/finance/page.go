@@ -0,0 +1,39 @@
+package entity_finance
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+// PageRequest asks for one page of a listing. Cursor is the opaque nextCursor returned with
+// the previous page; empty for the first one.
+type PageRequest struct {
+	Limit  int
+	Cursor string
+}
+
+// ParsePageRequest reads the limit and cursor query parameters. It returns nil when neither
+// is set, meaning the whole listing is requested.
+func ParsePageRequest(limit, cursor string) (*PageRequest, error) {
+	limit = strings.TrimSpace(limit)
+	cursor = strings.TrimSpace(cursor)
+	if limit == "" && cursor == "" {
+		return nil, nil
+	}
+
+	page := &PageRequest{Limit: DefaultPageLimit, Cursor: cursor}
+	if limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > MaxPageLimit {
+			return nil, fmt.Errorf("invalid limit %q, expected a number between 1 and %d", limit, MaxPageLimit)
+		}
+		page.Limit = n
+	}
+	return page, nil
+}
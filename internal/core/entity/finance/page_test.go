@@ -0,0 +1,27 @@
+package entity_finance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePageRequest(t *testing.T) {
+	page, err := ParsePageRequest("", "")
+	require.NoError(t, err)
+	assert.Nil(t, page)
+
+	page, err = ParsePageRequest("25", "abc")
+	require.NoError(t, err)
+	assert.Equal(t, &PageRequest{Limit: 25, Cursor: "abc"}, page)
+
+	page, err = ParsePageRequest("", "abc")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultPageLimit, page.Limit)
+
+	for _, limit := range []string{"0", "-1", "ten", "201"} {
+		_, err = ParsePageRequest(limit, "")
+		assert.Error(t, err, limit)
+	}
+}
@@ -0,0 +1,50 @@
+package entity_finance
+
+import (
+	"errors"
+	"time"
+)
+
+// RecordDateRange selects the expense or income records dated from StartDate to EndDate,
+// both days included, optionally narrowed to a bank account and to some categories. A zero
+// date leaves that side of the range open.
+type RecordDateRange struct {
+	StartDate     time.Time
+	EndDate       time.Time
+	BankAccountID string
+	Categories    []string
+}
+
+// Validate checks the range is not inverted.
+func (r *RecordDateRange) Validate() error {
+	if r == nil {
+		return errors.New("date range is nil")
+	}
+	if !r.StartDate.IsZero() && !r.EndDate.IsZero() && r.EndDate.Before(r.StartDate) {
+		return errors.New("validation failed: endDate must not be before startDate")
+	}
+	return nil
+}
+
+// ParseRecordDateRange reads the YYYY-MM-DD bounds of a listing; empty bounds are open.
+func ParseRecordDateRange(startDate, endDate string) (*RecordDateRange, error) {
+	dateRange := &RecordDateRange{}
+
+	if startDate != "" {
+		t, err := time.Parse("2006-01-02", startDate)
+		if err != nil {
+			return nil, errors.New("validation failed: startDate must be in YYYY-MM-DD format")
+		}
+		dateRange.StartDate = t
+	}
+
+	if endDate != "" {
+		t, err := time.Parse("2006-01-02", endDate)
+		if err != nil {
+			return nil, errors.New("validation failed: endDate must be in YYYY-MM-DD format")
+		}
+		dateRange.EndDate = t
+	}
+
+	return dateRange, dateRange.Validate()
+}
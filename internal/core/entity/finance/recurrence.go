@@ -0,0 +1,419 @@
+package entity_finance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RecurringSeriesRepositoryInterface defines the repository operations for RecurringSeries.
+type RecurringSeriesRepositoryInterface interface {
+	CreateRecurringSeries(ctx context.Context, data *RecurringSeries) (*RecurringSeries, error)
+	GetRecurringSeriesByID(ctx context.Context, id string) (*RecurringSeries, error)
+	GetRecurringSeriesByKind(ctx context.Context, kind RecurringSeriesKind) ([]RecurringSeries, error)
+	UpdateRecurringSeries(ctx context.Context, data *RecurringSeries) (*RecurringSeries, error)
+	DeleteRecurringSeries(ctx context.Context, id string) error
+}
+
+// RecurringSeriesServiceInterface writes the occurrences of the recurring series ahead of
+// the listings, which only read the stored records.
+type RecurringSeriesServiceInterface interface {
+	MaterializeSeries(ctx context.Context, until time.Time) error
+}
+
+// RecurrenceFrequency is the base period of a recurrence rule (RRULE FREQ).
+type RecurrenceFrequency string
+
+const (
+	RecurrenceDaily   RecurrenceFrequency = "daily"
+	RecurrenceWeekly  RecurrenceFrequency = "weekly"
+	RecurrenceMonthly RecurrenceFrequency = "monthly"
+	RecurrenceYearly  RecurrenceFrequency = "yearly"
+)
+
+// BusinessDayAdjustment moves the occurrences that fall on a weekend.
+type BusinessDayAdjustment string
+
+const (
+	BusinessDayNone     BusinessDayAdjustment = ""
+	BusinessDayPrevious BusinessDayAdjustment = "previous"
+	BusinessDayNext     BusinessDayAdjustment = "next"
+)
+
+// maxRecurrencePeriods bounds the expansion of open-ended rules.
+const maxRecurrencePeriods = 10000
+
+// RecurrenceRule is an RRULE-like definition of when a series repeats.
+//
+// Examples: every two weeks is {weekly, interval 2}; the last business day of
+// the month is {monthly, byMonthDay -1, businessDay previous}.
+type RecurrenceRule struct {
+	Frequency RecurrenceFrequency `json:"frequency"`
+	// Interval repeats every N periods; zero means 1.
+	Interval int `json:"interval,omitempty"`
+	// ByMonthDay is the day of the month for monthly and yearly rules. Negative values
+	// count from the end of the month (-1 is the last day). Zero keeps the start day.
+	ByMonthDay int `json:"byMonthDay,omitempty"`
+	// BusinessDay moves occurrences on Saturday or Sunday to the previous or next weekday.
+	BusinessDay BusinessDayAdjustment `json:"businessDay,omitempty"`
+	// Count limits the number of occurrences; zero means no limit.
+	Count int `json:"count,omitempty"`
+	// Until is the last day an occurrence may fall on; zero means no limit.
+	Until time.Time `json:"until,omitempty"`
+}
+
+// Validate checks the RecurrenceRule fields for correctness.
+func (r *RecurrenceRule) Validate() error {
+	switch r.Frequency {
+	case RecurrenceDaily, RecurrenceWeekly, RecurrenceMonthly, RecurrenceYearly:
+	default:
+		return errors.New("recurrence frequency must be one of daily, weekly, monthly or yearly")
+	}
+
+	if r.Interval < 0 {
+		return errors.New("recurrence interval must not be negative")
+	}
+
+	if r.ByMonthDay < -31 || r.ByMonthDay > 31 {
+		return errors.New("recurrence byMonthDay must be between -31 and 31")
+	}
+	if r.ByMonthDay != 0 && r.Frequency != RecurrenceMonthly && r.Frequency != RecurrenceYearly {
+		return errors.New("recurrence byMonthDay is only allowed for monthly and yearly rules")
+	}
+
+	switch r.BusinessDay {
+	case BusinessDayNone, BusinessDayPrevious, BusinessDayNext:
+	default:
+		return errors.New("recurrence businessDay must be either 'previous' or 'next'")
+	}
+
+	if r.Count < 0 {
+		return errors.New("recurrence count must not be negative")
+	}
+
+	return nil
+}
+
+// MonthlyRecurrence is the rule of the legacy "recurrenceCount" monthly copies.
+func MonthlyRecurrence(count int) *RecurrenceRule {
+	return &RecurrenceRule{Frequency: RecurrenceMonthly, Interval: 1, Count: count}
+}
+
+// ParseRecurrenceRule parses an RRULE string such as "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=12".
+// The non standard X-BUSINESSDAY=PREVIOUS|NEXT part sets the business day adjustment.
+func ParseRecurrenceRule(value string) (*RecurrenceRule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return nil, errors.New("rrule is empty")
+	}
+
+	rule := &RecurrenceRule{}
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rrule part %q", part)
+		}
+
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Frequency = RecurrenceFrequency(strings.ToLower(val))
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(val)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = strconv.Atoi(val)
+		case "COUNT":
+			rule.Count, err = strconv.Atoi(val)
+		case "UNTIL":
+			rule.Until, err = time.Parse("20060102", val[:min(len(val), 8)])
+		case "X-BUSINESSDAY":
+			rule.BusinessDay = BusinessDayAdjustment(strings.ToLower(val))
+		default:
+			return nil, fmt.Errorf("unsupported rrule part %q", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid rrule %s: %w", key, err)
+		}
+	}
+
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// String returns the rule in RRULE notation.
+func (r RecurrenceRule) String() string {
+	parts := []string{"FREQ=" + strings.ToUpper(string(r.Frequency))}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.ByMonthDay != 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(r.ByMonthDay))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+	}
+	if r.BusinessDay != BusinessDayNone {
+		parts = append(parts, "X-BUSINESSDAY="+strings.ToUpper(string(r.BusinessDay)))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Occurrences returns the dates of a series starting at start, up to and including until.
+func (r RecurrenceRule) Occurrences(start, until time.Time) []time.Time {
+	dates := make([]time.Time, 0)
+	r.each(start, func(date time.Time) bool {
+		if date.After(until) {
+			return false
+		}
+		dates = append(dates, date)
+		return true
+	})
+	return dates
+}
+
+// First returns the first occurrence of a series starting at start.
+func (r RecurrenceRule) First(start time.Time) (time.Time, bool) {
+	var first time.Time
+	r.each(start, func(date time.Time) bool {
+		first = date
+		return false
+	})
+	return first, !first.IsZero()
+}
+
+// each calls yield with every occurrence, in order, until it returns false or the rule ends.
+func (r RecurrenceRule) each(start time.Time, yield func(time.Time) bool) {
+	start = startOfDay(start)
+	count := 0
+	for period := 0; period < maxRecurrencePeriods; period++ {
+		date := r.occurrence(start, period)
+		if date.Before(start) {
+			continue
+		}
+		if !r.Until.IsZero() && date.After(startOfDay(r.Until)) {
+			return
+		}
+		if r.Count > 0 && count >= r.Count {
+			return
+		}
+		count++
+		if !yield(r.adjust(date)) {
+			return
+		}
+	}
+}
+
+// occurrence returns the unadjusted date of the given period of the series.
+func (r RecurrenceRule) occurrence(start time.Time, period int) time.Time {
+	step := period * max(r.Interval, 1)
+	day := r.ByMonthDay
+	if day == 0 {
+		day = start.Day()
+	}
+
+	switch r.Frequency {
+	case RecurrenceDaily:
+		return start.AddDate(0, 0, step)
+	case RecurrenceWeekly:
+		return start.AddDate(0, 0, 7*step)
+	case RecurrenceYearly:
+		return dayOfMonth(start.Year()+step, start.Month(), day)
+	default:
+		return dayOfMonth(start.Year(), start.Month()+time.Month(step), day)
+	}
+}
+
+// adjust applies the business day adjustment.
+func (r RecurrenceRule) adjust(date time.Time) time.Time {
+	switch {
+	case r.BusinessDay == BusinessDayPrevious && date.Weekday() == time.Saturday:
+		return date.AddDate(0, 0, -1)
+	case r.BusinessDay == BusinessDayPrevious && date.Weekday() == time.Sunday:
+		return date.AddDate(0, 0, -2)
+	case r.BusinessDay == BusinessDayNext && date.Weekday() == time.Saturday:
+		return date.AddDate(0, 0, 2)
+	case r.BusinessDay == BusinessDayNext && date.Weekday() == time.Sunday:
+		return date.AddDate(0, 0, 1)
+	}
+	return date
+}
+
+// dayOfMonth returns the day of the month, clamped to its length. Negative days count
+// from the end of the month. The month may overflow, as in time.Date.
+func dayOfMonth(year int, month time.Month, day int) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1).Day()
+	if day < 0 {
+		day = max(last+day+1, 1)
+	}
+	return first.AddDate(0, 0, min(day, last)-1)
+}
+
+// RecurrenceHorizon is how far ahead the occurrences are materialised when a series is created
+// and on every run of the recurring series job: the end of the month after now.
+func RecurrenceHorizon(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month()+2, 0, 0, 0, 0, 0, time.UTC)
+}
+
+// RecurringSeriesKind tells which records a series generates.
+type RecurringSeriesKind string
+
+const (
+	RecurringSeriesExpense RecurringSeriesKind = "expense"
+	RecurringSeriesIncome  RecurringSeriesKind = "income"
+)
+
+// RecurringSeries holds a recurrence rule and the record every occurrence is copied from.
+// Occurrences are written ahead of time, up to the recurrence horizon.
+type RecurringSeries struct {
+	ID        string              `json:"id"`
+	UserID    string              `json:"userId"`
+	Kind      RecurringSeriesKind `json:"kind"`
+	Rule      RecurrenceRule      `json:"rule"`
+	StartDate time.Time           `json:"startDate"`
+	Expense   *ExpenseRecord      `json:"expense,omitempty"`
+	Income    *IncomeRecord       `json:"income,omitempty"`
+	// MaterializedCount is the number of occurrences already written as records.
+	MaterializedCount int       `json:"materializedCount"`
+	CreatedAt         time.Time `json:"createdAt"`
+	UpdatedAt         time.Time `json:"updatedAt"`
+}
+
+// RecurrenceOccurrence is a date of a series with its 1-based position.
+type RecurrenceOccurrence struct {
+	Number int
+	Date   time.Time
+}
+
+// Validate checks the RecurringSeries fields for correctness.
+func (s *RecurringSeries) Validate() error {
+	if strings.TrimSpace(s.UserID) == "" {
+		return errors.New("userID is required")
+	}
+	if s.StartDate.IsZero() {
+		return errors.New("series startDate is required")
+	}
+	if err := s.Rule.Validate(); err != nil {
+		return err
+	}
+	if !s.Rule.Until.IsZero() && s.Rule.Until.Before(startOfDay(s.StartDate)) {
+		return errors.New("recurrence until must not be before the start date")
+	}
+
+	switch s.Kind {
+	case RecurringSeriesExpense:
+		if s.Expense == nil {
+			return errors.New("expense series requires an expense template")
+		}
+	case RecurringSeriesIncome:
+		if s.Income == nil {
+			return errors.New("income series requires an income template")
+		}
+	default:
+		return errors.New("series kind must be either 'expense' or 'income'")
+	}
+	return nil
+}
+
+// PendingOccurrences returns the occurrences up to until that were not materialised yet.
+func (s *RecurringSeries) PendingOccurrences(until time.Time) []RecurrenceOccurrence {
+	dates := s.Rule.Occurrences(s.StartDate, until)
+	if len(dates) <= s.MaterializedCount {
+		return nil
+	}
+
+	occurrences := make([]RecurrenceOccurrence, 0, len(dates)-s.MaterializedCount)
+	for i := s.MaterializedCount; i < len(dates); i++ {
+		occurrences = append(occurrences, RecurrenceOccurrence{Number: i + 1, Date: dates[i]})
+	}
+	return occurrences
+}
+
+// OccurrenceID is the document ID of an occurrence. It is deterministic so writing the
+// same occurrence twice does not duplicate it.
+func (s *RecurringSeries) OccurrenceID(number int) string {
+	return fmt.Sprintf("%s_%d", s.ID, number)
+}
+
+// ExpenseOccurrence builds the expense record of an occurrence. Only the first occurrence
+// keeps the payment of the template; the following ones start pending.
+func (s *RecurringSeries) ExpenseOccurrence(occurrence RecurrenceOccurrence) *ExpenseRecord {
+	record := *s.Expense
+	record.ID = s.OccurrenceID(occurrence.Number)
+	record.SeriesID = s.ID
+	record.UserID = s.UserID
+	record.DueDate = occurrence.Date
+	record.IsRecurring = true
+	record.RecurrenceNumber = occurrence.Number
+	record.RecurrenceCount = s.Rule.Count
+	record.Recurrence = nil
+	if occurrence.Number > 1 {
+		record.Status = ExpenseStatusPending
+		record.PaymentDate = time.Time{}
+		record.BankPaidFrom = ""
+		record.CustomBankName = ""
+	}
+	return &record
+}
+
+// IncomeOccurrence builds the income record of an occurrence.
+func (s *RecurringSeries) IncomeOccurrence(occurrence RecurrenceOccurrence) *IncomeRecord {
+	record := *s.Income
+	record.ID = s.OccurrenceID(occurrence.Number)
+	record.SeriesID = s.ID
+	record.UserID = s.UserID
+	record.ReceiptDate = occurrence.Date
+	record.IsRecurring = true
+	record.RecurrenceNumber = occurrence.Number
+	record.RecurrenceCount = s.Rule.Count
+	record.Recurrence = nil
+	return &record
+}
+
+// EndBefore stops the series so that occurrence number and the later ones are no longer
+// generated. Ending before the first occurrence leaves nothing, so callers delete the series instead.
+func (s *RecurringSeries) EndBefore(number int) {
+	s.Rule.Count = number - 1
+	s.MaterializedCount = min(s.MaterializedCount, s.Rule.Count)
+}
+
+// SeriesScope selects the occurrences of a series an update or a delete applies to.
+type SeriesScope string
+
+const (
+	SeriesScopeSingle    SeriesScope = "single"
+	SeriesScopeFollowing SeriesScope = "following"
+	SeriesScopeAll       SeriesScope = "all"
+)
+
+// ParseSeriesScope parses the scope of an update or delete; empty means single.
+func ParseSeriesScope(value string) (SeriesScope, error) {
+	switch scope := SeriesScope(strings.ToLower(strings.TrimSpace(value))); scope {
+	case "":
+		return SeriesScopeSingle, nil
+	case SeriesScopeSingle, SeriesScopeFollowing, SeriesScopeAll:
+		return scope, nil
+	default:
+		return "", fmt.Errorf("invalid scope %q: must be one of single, following or all", value)
+	}
+}
+
+// Includes reports whether occurrence number is affected when occurrence from is changed.
+func (s SeriesScope) Includes(from, number int) bool {
+	switch s {
+	case SeriesScopeAll:
+		return true
+	case SeriesScopeFollowing:
+		return number >= from
+	default:
+		return number == from
+	}
+}
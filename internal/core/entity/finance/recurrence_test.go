@@ -0,0 +1,150 @@
+package entity_finance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestRecurrenceRule_Occurrences(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     RecurrenceRule
+		start    time.Time
+		until    time.Time
+		expected []time.Time
+	}{
+		{
+			name:     "every two weeks",
+			rule:     RecurrenceRule{Frequency: RecurrenceWeekly, Interval: 2},
+			start:    date(2025, 6, 6),
+			until:    date(2025, 7, 15),
+			expected: []time.Time{date(2025, 6, 6), date(2025, 6, 20), date(2025, 7, 4)},
+		},
+		{
+			name:  "last business day of the month",
+			rule:  RecurrenceRule{Frequency: RecurrenceMonthly, ByMonthDay: -1, BusinessDay: BusinessDayPrevious},
+			start: date(2025, 5, 1),
+			until: date(2025, 8, 31),
+			// May 31 and Aug 31 2025 fall on a weekend.
+			expected: []time.Time{date(2025, 5, 30), date(2025, 6, 30), date(2025, 7, 31), date(2025, 8, 29)},
+		},
+		{
+			name:     "day 31 is clamped to short months",
+			rule:     RecurrenceRule{Frequency: RecurrenceMonthly},
+			start:    date(2025, 1, 31),
+			until:    date(2025, 4, 30),
+			expected: []time.Time{date(2025, 1, 31), date(2025, 2, 28), date(2025, 3, 31), date(2025, 4, 30)},
+		},
+		{
+			name:     "fifth business day moves forward",
+			rule:     RecurrenceRule{Frequency: RecurrenceMonthly, ByMonthDay: 5, BusinessDay: BusinessDayNext},
+			start:    date(2025, 10, 1),
+			until:    date(2025, 11, 30),
+			expected: []time.Time{date(2025, 10, 6), date(2025, 11, 5)},
+		},
+		{
+			name:     "leap day",
+			rule:     RecurrenceRule{Frequency: RecurrenceYearly},
+			start:    date(2024, 2, 29),
+			until:    date(2028, 12, 31),
+			expected: []time.Time{date(2024, 2, 29), date(2025, 2, 28), date(2026, 2, 28), date(2027, 2, 28), date(2028, 2, 29)},
+		},
+		{
+			name:     "count",
+			rule:     RecurrenceRule{Frequency: RecurrenceMonthly, Count: 2},
+			start:    date(2025, 1, 10),
+			until:    date(2025, 12, 31),
+			expected: []time.Time{date(2025, 1, 10), date(2025, 2, 10)},
+		},
+		{
+			name:     "until",
+			rule:     RecurrenceRule{Frequency: RecurrenceDaily, Until: date(2025, 1, 3)},
+			start:    date(2025, 1, 1),
+			until:    date(2025, 12, 31),
+			expected: []time.Time{date(2025, 1, 1), date(2025, 1, 2), date(2025, 1, 3)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.rule.Occurrences(tt.start, tt.until))
+		})
+	}
+}
+
+func TestParseRecurrenceRule(t *testing.T) {
+	rule, err := ParseRecurrenceRule("RRULE:FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=12;X-BUSINESSDAY=PREVIOUS")
+	require.NoError(t, err)
+	assert.Equal(t, RecurrenceRule{Frequency: RecurrenceMonthly, ByMonthDay: -1, Count: 12, BusinessDay: BusinessDayPrevious}, *rule)
+	assert.Equal(t, "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=12;X-BUSINESSDAY=PREVIOUS", rule.String())
+
+	rule, err = ParseRecurrenceRule("FREQ=WEEKLY;INTERVAL=2;UNTIL=20251231T000000Z")
+	require.NoError(t, err)
+	assert.Equal(t, 2, rule.Interval)
+	assert.Equal(t, date(2025, 12, 31), rule.Until)
+
+	for _, value := range []string{"", "FREQ=HOURLY", "FREQ=WEEKLY;BYMONTHDAY=1", "FREQ=MONTHLY;BYDAY=MO", "FREQ=MONTHLY;COUNT=x"} {
+		_, err := ParseRecurrenceRule(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestRecurringSeries_PendingOccurrences(t *testing.T) {
+	series := RecurringSeries{
+		ID:        "series-1",
+		UserID:    "user-1",
+		Kind:      RecurringSeriesExpense,
+		Rule:      RecurrenceRule{Frequency: RecurrenceMonthly},
+		StartDate: date(2025, 1, 10),
+		Expense:   &ExpenseRecord{Category: "housing", Amount: 100, Status: ExpenseStatusPaid, PaymentDate: date(2025, 1, 10)},
+	}
+
+	pending := series.PendingOccurrences(date(2025, 3, 31))
+	require.Len(t, pending, 3)
+	assert.Equal(t, RecurrenceOccurrence{Number: 1, Date: date(2025, 1, 10)}, pending[0])
+
+	series.MaterializedCount = 3
+	assert.Empty(t, series.PendingOccurrences(date(2025, 3, 31)))
+
+	pending = series.PendingOccurrences(date(2025, 4, 30))
+	require.Len(t, pending, 1)
+	assert.Equal(t, 4, pending[0].Number)
+
+	record := series.ExpenseOccurrence(pending[0])
+	assert.Equal(t, "series-1_4", record.ID)
+	assert.Equal(t, "series-1", record.SeriesID)
+	assert.Equal(t, date(2025, 4, 10), record.DueDate)
+	assert.Equal(t, ExpenseStatusPending, record.Status)
+	assert.True(t, record.PaymentDate.IsZero())
+}
+
+func TestRecurrenceHorizon(t *testing.T) {
+	assert.Equal(t, date(2025, 7, 31), RecurrenceHorizon(time.Date(2025, 6, 15, 10, 0, 0, 0, time.UTC)))
+	assert.Equal(t, date(2026, 1, 31), RecurrenceHorizon(date(2025, 12, 1)))
+}
+
+func TestSeriesScope(t *testing.T) {
+	scope, err := ParseSeriesScope("")
+	require.NoError(t, err)
+	assert.Equal(t, SeriesScopeSingle, scope)
+
+	scope, err = ParseSeriesScope("Following")
+	require.NoError(t, err)
+	assert.Equal(t, SeriesScopeFollowing, scope)
+
+	_, err = ParseSeriesScope("previous")
+	assert.Error(t, err)
+
+	assert.True(t, SeriesScopeSingle.Includes(3, 3))
+	assert.False(t, SeriesScopeSingle.Includes(3, 4))
+	assert.False(t, SeriesScopeFollowing.Includes(3, 2))
+	assert.True(t, SeriesScopeFollowing.Includes(3, 5))
+	assert.True(t, SeriesScopeAll.Includes(3, 1))
+}
@@ -36,6 +36,8 @@ type FinancialReportData struct {
 	ExpenseByCategoryLast12Months []CategoryExpenseItem         `json:"expenseByCategoryLast12Months"` // Para o gráfico Treemap (12 meses)
 	NetWorthEvolution             []NetWorthHistoryItem         `json:"netWorthEvolution"`             // Para o gráfico de linha do Patrimônio
 	ExpenseBreakdown              []ExpenseCategoryWithSubItems `json:"expenseBreakdown"`              // Para o gráfico Sunburst/Donut Aninhado
+	InstallmentCommitments        []InstallmentCommitmentItem   `json:"installmentCommitments"`        // Compras parceladas e o que ainda falta pagar
+	BalanceByAccountType          []AccountTypeBalance          `json:"balanceByAccountType"`          // Saldo atual das contas somado por tipo de conta
 }
 
 // ReportSummaryCards contém os dados para os cards de destaque.
@@ -65,3 +67,14 @@ type NetWorthHistoryItem struct {
 	Date  string  `json:"date"`  // Formato "Mês/Ano", ex: "Jan/24"
 	Value float64 `json:"value"` // Valor do patrimônio líquido no final daquela data
 }
+
+// InstallmentCommitmentItem representa uma compra parcelada e o compromisso restante.
+type InstallmentCommitmentItem struct {
+	PurchaseID            string  `json:"purchaseId"`
+	Description           string  `json:"description"`
+	Category              string  `json:"category"`
+	Installments          int     `json:"installments"`          // Número total de parcelas
+	TotalAmount           float64 `json:"totalAmount"`           // Soma de todas as parcelas
+	RemainingInstallments int     `json:"remainingInstallments"` // Parcelas ainda não lançadas em fatura
+	RemainingAmount       float64 `json:"remainingAmount"`       // Valor das parcelas restantes
+}
@@ -0,0 +1,13 @@
+package entity_finance
+
+import "context"
+
+// TransactionRunner commits the writes of an operation spanning several documents
+// all-or-nothing. Repositories take part in it through the context handed to fn.
+type TransactionRunner interface {
+	// RunTransaction runs fn in a transaction. Its reads must come before its writes, and fn
+	// may run more than once, so it must leave side effects such as messages to the caller.
+	RunTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+	// Batch runs fn once and commits its writes together when it returns nil.
+	Batch(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,62 @@
+package entity_finance
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// TransferRepositoryInterface defines the repository operations for transfers.
+type TransferRepositoryInterface interface {
+	CreateTransfer(ctx context.Context, data *Transfer) (*Transfer, error)
+	GetTransferByID(ctx context.Context, id string) (*Transfer, error)
+	GetTransfers(ctx context.Context) ([]Transfer, error)
+	UpdateTransfer(ctx context.Context, data *Transfer) (*Transfer, error)
+	DeleteTransfer(ctx context.Context, id string) error
+}
+
+// TransferServiceInterface defines the service operations for transfers.
+type TransferServiceInterface interface {
+	CreateTransfer(ctx context.Context, data *Transfer) (*Transfer, error)
+	GetTransferByID(ctx context.Context, id string) (*Transfer, error)
+	GetTransfers(ctx context.Context) ([]Transfer, error)
+	DeleteTransfer(ctx context.Context, id string) error
+}
+
+// Transfer moves money between two bank accounts of the user. It changes the balance of
+// both accounts but is neither revenue nor expense.
+type Transfer struct {
+	ID                string    `json:"id"`
+	UserID            string    `json:"userId"`
+	FromBankAccountID string    `json:"fromBankAccountId"`
+	ToBankAccountID   string    `json:"toBankAccountId"`
+	Amount            float64   `json:"amount"`
+	TransferDate      time.Time `json:"transferDate"`
+	Description       string    `json:"description,omitempty"`
+	CreatedAt         time.Time `json:"createdAt"`
+	UpdatedAt         time.Time `json:"updatedAt"`
+}
+
+// Validate checks the Transfer fields for correctness.
+func (t *Transfer) Validate() error {
+	if strings.TrimSpace(t.FromBankAccountID) == "" {
+		return errors.New("fromBankAccountId is required")
+	}
+	if strings.TrimSpace(t.ToBankAccountID) == "" {
+		return errors.New("toBankAccountId is required")
+	}
+	if t.FromBankAccountID == t.ToBankAccountID {
+		return errors.New("fromBankAccountId and toBankAccountId must be different")
+	}
+	if t.Amount <= 0 {
+		return errors.New("amount must be greater than 0")
+	}
+	if t.TransferDate.IsZero() {
+		return errors.New("transferDate is required")
+	}
+	if len(t.Description) > 200 {
+		return errors.New("description must not exceed 200 characters")
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package entity_finance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransfer_Validate(t *testing.T) {
+	valid := Transfer{FromBankAccountID: "checking", ToBankAccountID: "savings", Amount: 100, TransferDate: date(2025, 3, 1)}
+	assert.NoError(t, valid.Validate())
+
+	sameAccount := valid
+	sameAccount.ToBankAccountID = "checking"
+	assert.ErrorContains(t, sameAccount.Validate(), "must be different")
+
+	noAmount := valid
+	noAmount.Amount = 0
+	assert.ErrorContains(t, noAmount.Validate(), "amount")
+
+	noDate := valid
+	noDate.TransferDate = date(1, 1, 1)
+	assert.ErrorContains(t, noDate.Validate(), "transferDate")
+}
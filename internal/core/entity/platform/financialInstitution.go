@@ -12,6 +12,7 @@ type FinancialInstitutionInterface interface {
 
 const (
 	ErrInvalidID = "invalid financial institution ID"
+	ErrNotFound  = "financial institution not found"
 )
 
 type FinancialInstitution struct {
@@ -2,7 +2,6 @@ package dashboard
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"sync"
@@ -154,16 +153,11 @@ func (r *InMemoryDashboardRepository) GetBankAccountBalanceByID(ctx context.Cont
 		"type":     "accountBalance",
 	}
 
-	result, err := r.db.GetByFilter(ctx, filters, *collection)
+	items, err := database.FindAs[dashboardEntity.AccountBalanceItem](ctx, r.db, database.NewQuery(*collection).WhereEqual(filters))
 	if err != nil {
 		return nil, err
 	}
 
-	var items []dashboardEntity.AccountBalanceItem
-	if err := json.Unmarshal(result, &items); err != nil {
-		return nil, err
-	}
-
 	if len(items) == 0 {
 		return nil, errors.New("bank account not found")
 	}
@@ -190,16 +184,11 @@ func (r *InMemoryDashboardRepository) GetBankAccountBalance(ctx context.Context,
 		"type":   "accountBalance",
 	}
 
-	result, err := r.db.GetByFilter(ctx, filters, *collection)
+	items, err := database.FindAs[dashboardEntity.AccountBalanceItem](ctx, r.db, database.NewQuery(*collection).WhereEqual(filters))
 	if err != nil {
 		return nil, err
 	}
 
-	var items []dashboardEntity.AccountBalanceItem
-	if err := json.Unmarshal(result, &items); err != nil {
-		return nil, err
-	}
-
 	if len(items) == 0 {
 		return nil, errors.New("bank account not found")
 	}
@@ -226,16 +215,11 @@ func (r *InMemoryDashboardRepository) GetFinancialSummary(ctx context.Context, u
 		"type":   "financialSummary",
 	}
 
-	result, err := r.db.GetByFilter(ctx, filters, *collection)
+	items, err := database.FindAs[dashboardEntity.MonthlyFinancialSummaryItem](ctx, r.db, database.NewQuery(*collection).WhereEqual(filters))
 	if err != nil {
 		return nil, err
 	}
 
-	var items []dashboardEntity.MonthlyFinancialSummaryItem
-	if err := json.Unmarshal(result, &items); err != nil {
-		return nil, err
-	}
-
 	if len(items) == 0 {
 		return nil, errors.New("bank account not found")
 	}
@@ -6,7 +6,6 @@ import (
 
 	entity_dashboard "github.com/Tomelin/dashfin-backend-app/internal/core/entity/dashboard"
 	"github.com/Tomelin/dashfin-backend-app/pkg/database"
-	"google.golang.org/api/iterator"
 	// "github.com/Tomelin/dashfin-backend-app/pkg/database" // No longer needed directly here
 )
 
@@ -61,24 +60,22 @@ func NewFirebaseFinancialRepository(dbProvider database.FirebaseDBInterface) (Fi
 func (r *FirebaseFinancialRepository) GetExpensePlanning(ctx context.Context, userID string, month, year int) (*entity_dashboard.ExpensePlanningDoc, error) {
 	docID := fmt.Sprintf("%s_%d_%d", userID, year, month)
 
-	_, err := r.dbProvider.Get(ctx, "expenses_planning")
+	query := database.NewQuery(r.collection).
+		Where("userId", database.FilterEquals, userID).
+		Where("month", database.FilterEquals, month).
+		Where("year", database.FilterEquals, year).
+		Limit(1)
+
+	docs, err := database.FindAs[entity_dashboard.ExpensePlanningDoc](ctx, r.dbProvider, query)
 	if err != nil {
-		if err.Error() == "rpc error: code = NotFound desc = document not found" || iterator.Done.Error() == err.Error() || err.Error() == "datastore: no such entity" { // Common ways NotFound is represented
-			// Check if the error indicates "not found". Firestore might return an error with code NotFound.
-			// For GRPC, the error might be something like "rpc error: code = NotFound desc = ..."
-			// It's safer to check specific error codes if available, but string matching is a common fallback.
-			// For now, a simple string check for "not found" or iterator.Done will be used.
-			// A more robust solution would involve checking `status.Code(err) == codes.NotFound`.
-			return nil, nil // Document not found is not treated as an application error here.
-		}
 		return nil, fmt.Errorf("failed to get expense planning document %s: %w", docID, err)
 	}
+	if len(docs) == 0 {
+		return nil, nil // Document not found is not treated as an application error here.
+	}
 
-	var planningDoc entity_dashboard.ExpensePlanningDoc
-	// if err := docSnap.DataTo(&planningDoc); err != nil {
-	// 	return nil, fmt.Errorf("failed to unmarshal expense planning document %s into ExpensePlanningDoc: %w", docID, err)
-	// }
-	// planningDoc.ID = docSnap.Ref.ID // Populate ID from the document reference.
+	planningDoc := docs[0]
+	planningDoc.ID = docID
 	return &planningDoc, nil
 }
 
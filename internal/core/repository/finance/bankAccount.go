@@ -84,16 +84,11 @@ func (r *BankAccountRepository) GetBankAccountByID(ctx context.Context, id *stri
 		return nil, fmt.Errorf("%s collection is empty", r.collection)
 	}
 
-	result, err := r.DB.GetByConditional(ctx, conditional, *collection)
+	bankAccounts, err := database.FindAs[entity.BankAccountRequest](ctx, r.DB, database.NewQuery(*collection).WhereConditionals(conditional...))
 	if err != nil {
 		return nil, err
 	}
 
-	var bankAccounts []entity.BankAccountRequest
-	if err := json.Unmarshal(result, &bankAccounts); err != nil {
-		return nil, err
-	}
-
 	if len(bankAccounts) == 0 {
 		return nil, errors.New("bank account not found")
 	}
@@ -108,16 +103,11 @@ func (r *BankAccountRepository) GetBankAccounts(ctx context.Context) ([]entity.B
 		return nil, err
 	}
 
-	result, err := r.DB.Get(ctx, *collection)
+	bankAccounts, err := database.FindAs[entity.BankAccountRequest](ctx, r.DB, database.NewQuery(*collection))
 	if err != nil {
 		return nil, err
 	}
 
-	var bankAccounts []entity.BankAccountRequest
-	if err := json.Unmarshal(result, &bankAccounts); err != nil {
-		return nil, err
-	}
-
 	return bankAccounts, nil
 }
 
@@ -135,16 +125,11 @@ func (r *BankAccountRepository) GetByFilter(ctx context.Context, data map[string
 		return nil, fmt.Errorf("%s collection is empty", r.collection)
 	}
 
-	result, err := r.DB.GetByFilter(ctx, data, *collection)
+	bankAccounts, err := database.FindAs[entity.BankAccountRequest](ctx, r.DB, database.NewQuery(*collection).WhereEqual(data))
 	if err != nil {
 		return nil, err
 	}
 
-	var bankAccounts []entity.BankAccountRequest
-	if err := json.Unmarshal(result, &bankAccounts); err != nil {
-		return nil, err
-	}
-
 	return bankAccounts, nil
 }
 
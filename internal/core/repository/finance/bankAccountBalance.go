@@ -0,0 +1,135 @@
+package repository_finance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"github.com/Tomelin/dashfin-backend-app/internal/core/repository"
+	"github.com/Tomelin/dashfin-backend-app/pkg/database"
+	"github.com/Tomelin/dashfin-backend-app/pkg/utils"
+)
+
+// BankAccountBalanceRepository handles database operations for balance checkpoints and
+// balance adjustments.
+type BankAccountBalanceRepository struct {
+	DB                    database.FirebaseDBInterface
+	checkpointsCollection string
+	adjustmentsCollection string
+}
+
+// InitializeBankAccountBalanceRepository creates a new BankAccountBalanceRepository.
+func InitializeBankAccountBalanceRepository(db database.FirebaseDBInterface) (entity_finance.BankAccountBalanceRepositoryInterface, error) {
+	if db == nil {
+		return nil, errors.New("database is nil")
+	}
+
+	return &BankAccountBalanceRepository{
+		DB:                    db,
+		checkpointsCollection: "balance-checkpoints",
+		adjustmentsCollection: "balance-adjustments",
+	}, nil
+}
+
+// SaveCheckpoint writes the checkpoint under its deterministic ID (account and day).
+func (r *BankAccountBalanceRepository) SaveCheckpoint(ctx context.Context, data *entity_finance.BalanceCheckpoint) (*entity_finance.BalanceCheckpoint, error) {
+	if data == nil {
+		return nil, errors.New("balance checkpoint is nil")
+	}
+	if data.ID == "" {
+		return nil, errors.New("id is empty")
+	}
+
+	if err := r.save(ctx, r.checkpointsCollection, data.ID, data); err != nil {
+		return nil, fmt.Errorf("failed to save balance checkpoint: %w", err)
+	}
+
+	return data, nil
+}
+
+// GetCheckpoints retrieves the checkpoints of a bank account.
+func (r *BankAccountBalanceRepository) GetCheckpoints(ctx context.Context, bankAccountID string) ([]entity_finance.BalanceCheckpoint, error) {
+	if bankAccountID == "" {
+		return nil, errors.New("bankAccountId is empty")
+	}
+
+	collection, err := repository.SetCollection(ctx, r.checkpointsCollection)
+	if err != nil {
+		return nil, err
+	}
+
+	query := database.NewQuery(*collection).Where("bankAccountId", database.FilterEquals, bankAccountID)
+
+	checkpoints, err := database.FindAs[entity_finance.BalanceCheckpoint](ctx, r.DB, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return checkpoints, nil
+}
+
+// DeleteCheckpoint removes a checkpoint.
+func (r *BankAccountBalanceRepository) DeleteCheckpoint(ctx context.Context, id string) error {
+	return r.delete(ctx, r.checkpointsCollection, id)
+}
+
+// SaveAdjustment writes the adjustment under its ID, the ID of the checkpoint it reconciles.
+func (r *BankAccountBalanceRepository) SaveAdjustment(ctx context.Context, data *entity_finance.BalanceAdjustment) (*entity_finance.BalanceAdjustment, error) {
+	if data == nil {
+		return nil, errors.New("balance adjustment is nil")
+	}
+	if data.ID == "" {
+		return nil, errors.New("id is empty")
+	}
+
+	if err := r.save(ctx, r.adjustmentsCollection, data.ID, data); err != nil {
+		return nil, fmt.Errorf("failed to save balance adjustment: %w", err)
+	}
+
+	return data, nil
+}
+
+// GetAdjustments retrieves all balance adjustments of the user.
+func (r *BankAccountBalanceRepository) GetAdjustments(ctx context.Context) ([]entity_finance.BalanceAdjustment, error) {
+	collection, err := repository.SetCollection(ctx, r.adjustmentsCollection)
+	if err != nil {
+		return nil, err
+	}
+
+	adjustments, err := database.FindAs[entity_finance.BalanceAdjustment](ctx, r.DB, database.NewQuery(*collection))
+	if err != nil {
+		return nil, err
+	}
+
+	return adjustments, nil
+}
+
+// DeleteAdjustment removes an adjustment.
+func (r *BankAccountBalanceRepository) DeleteAdjustment(ctx context.Context, id string) error {
+	return r.delete(ctx, r.adjustmentsCollection, id)
+}
+
+func (r *BankAccountBalanceRepository) save(ctx context.Context, name, id string, data interface{}) error {
+	toMap, _ := utils.StructToMap(data)
+
+	collection, err := repository.SetCollection(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	return r.DB.Update(ctx, id, toMap, *collection)
+}
+
+func (r *BankAccountBalanceRepository) delete(ctx context.Context, name, id string) error {
+	if id == "" {
+		return errors.New("id is empty")
+	}
+
+	collection, err := repository.SetCollection(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	return r.DB.Delete(ctx, id, *collection)
+}
@@ -0,0 +1,65 @@
+package repository_finance
+
+import (
+	"time"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"github.com/Tomelin/dashfin-backend-app/pkg/database"
+)
+
+// dateRangeConditionals filters a date field by the days of a range, both included. Dates are
+// stored as RFC 3339 strings, so the bounds are compared as strings: the start day begins at
+// "T00:00:00Z" and the end day is closed by "T24:00:00Z", which sorts after any time of the day.
+func dateRangeConditionals(field string, startDate, endDate time.Time) []database.Conditional {
+	conditionals := make([]database.Conditional, 0, 2)
+
+	if !startDate.IsZero() {
+		conditionals = append(conditionals, database.Conditional{
+			Field:  field,
+			Value:  startDate.Format("2006-01-02") + "T00:00:00Z",
+			Filter: database.FilterGreaterOrEqual,
+		})
+	}
+
+	if !endDate.IsZero() {
+		conditionals = append(conditionals, database.Conditional{
+			Field:  field,
+			Value:  endDate.Format("2006-01-02") + "T24:00:00Z",
+			Filter: database.FilterLessOrEqual,
+		})
+	}
+
+	return conditionals
+}
+
+// recordDateRangeConditionals translates a date range of records into conditionals on the
+// date field and on the field holding the bank account.
+func recordDateRangeConditionals(dateField, accountField string, filter *entity_finance.RecordDateRange) []database.Conditional {
+	conditionals := dateRangeConditionals(dateField, filter.StartDate, filter.EndDate)
+
+	if filter.BankAccountID != "" {
+		conditionals = append(conditionals, database.Conditional{
+			Field:  accountField,
+			Value:  filter.BankAccountID,
+			Filter: database.FilterEquals,
+		})
+	}
+
+	switch len(filter.Categories) {
+	case 0:
+	case 1:
+		conditionals = append(conditionals, database.Conditional{
+			Field:  "Category",
+			Value:  filter.Categories[0],
+			Filter: database.FilterEquals,
+		})
+	default:
+		conditionals = append(conditionals, database.Conditional{
+			Field:  "Category",
+			Value:  filter.Categories,
+			Filter: database.FilterIn,
+		})
+	}
+
+	return conditionals
+}
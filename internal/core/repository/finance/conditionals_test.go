@@ -0,0 +1,43 @@
+package repository_finance
+
+import (
+	"testing"
+	"time"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"github.com/Tomelin/dashfin-backend-app/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDateRangeConditionals(t *testing.T) {
+	conditionals := dateRangeConditionals("DueDate", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, []database.Conditional{
+		{Field: "DueDate", Value: "2025-03-01T00:00:00Z", Filter: database.FilterGreaterOrEqual},
+		{Field: "DueDate", Value: "2025-03-31T24:00:00Z", Filter: database.FilterLessOrEqual},
+	}, conditionals)
+
+	// Every stored date of the bound days falls inside the range, and the next day does not.
+	assert.GreaterOrEqual(t, "2025-03-01T00:00:00Z", conditionals[0].Value)
+	assert.LessOrEqual(t, "2025-03-31T23:59:59.5Z", conditionals[1].Value)
+	assert.Greater(t, "2025-04-01T00:00:00Z", conditionals[1].Value)
+
+	assert.Empty(t, dateRangeConditionals("DueDate", time.Time{}, time.Time{}))
+}
+
+func TestRecordDateRangeConditionals(t *testing.T) {
+	filter := &entity_finance.RecordDateRange{
+		StartDate:     time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		BankAccountID: "acc-1",
+		Categories:    []string{"housing", "food"},
+	}
+
+	assert.Equal(t, []database.Conditional{
+		{Field: "ReceiptDate", Value: "2025-03-01T00:00:00Z", Filter: database.FilterGreaterOrEqual},
+		{Field: "BankAccountID", Value: "acc-1", Filter: database.FilterEquals},
+		{Field: "Category", Value: []string{"housing", "food"}, Filter: database.FilterIn},
+	}, recordDateRangeConditionals("ReceiptDate", "BankAccountID", filter))
+
+	filter.Categories = []string{"housing"}
+	conditionals := recordDateRangeConditionals("DueDate", "BankPaidFrom", filter)
+	assert.Equal(t, database.Conditional{Field: "Category", Value: "housing", Filter: database.FilterEquals}, conditionals[2])
+}
@@ -76,18 +76,13 @@ func (r *CreditCardRepository) GetCreditCardByID(ctx context.Context, id *string
 		return nil, fmt.Errorf("%s collection is empty", r.collection)
 	}
 
-	result, err := r.DB.GetByFilter(ctx, filters, *collection)
+	CreditCards, err := database.FindAs[entity.CreditCardRequest](ctx, r.DB, database.NewQuery(*collection).WhereEqual(filters))
 	if err != nil {
 		return nil, err
 	}
 
-	var CreditCards []entity.CreditCardRequest
-	if err := json.Unmarshal(result, &CreditCards); err != nil {
-		return nil, err
-	}
-
 	if len(CreditCards) == 0 {
-		return nil, errors.New("bank account not found")
+		return nil, errors.New("credit card not found")
 	}
 
 	return &CreditCards[0], nil
@@ -100,16 +95,11 @@ func (r *CreditCardRepository) GetCreditCards(ctx context.Context) ([]entity.Cre
 		return nil, err
 	}
 
-	result, err := r.DB.Get(ctx, *collection)
+	CreditCards, err := database.FindAs[entity.CreditCardRequest](ctx, r.DB, database.NewQuery(*collection))
 	if err != nil {
 		return nil, err
 	}
 
-	var CreditCards []entity.CreditCardRequest
-	if err := json.Unmarshal(result, &CreditCards); err != nil {
-		return nil, err
-	}
-
 	return CreditCards, nil
 }
 
@@ -127,16 +117,11 @@ func (r *CreditCardRepository) GetByFilter(ctx context.Context, data map[string]
 		return nil, fmt.Errorf("%s collection is empty", r.collection)
 	}
 
-	result, err := r.DB.GetByFilter(ctx, data, *collection)
+	CreditCards, err := database.FindAs[entity.CreditCardRequest](ctx, r.DB, database.NewQuery(*collection).WhereEqual(data))
 	if err != nil {
 		return nil, err
 	}
 
-	var CreditCards []entity.CreditCardRequest
-	if err := json.Unmarshal(result, &CreditCards); err != nil {
-		return nil, err
-	}
-
 	return CreditCards, nil
 }
 
@@ -0,0 +1,94 @@
+package repository_finance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"github.com/Tomelin/dashfin-backend-app/internal/core/repository"
+	"github.com/Tomelin/dashfin-backend-app/pkg/database"
+	"github.com/Tomelin/dashfin-backend-app/pkg/utils"
+)
+
+// CreditCardInvoiceRepository handles database operations for CreditCardInvoice.
+type CreditCardInvoiceRepository struct {
+	DB         database.FirebaseDBInterface
+	collection string
+}
+
+// InitializeCreditCardInvoiceRepository creates a new CreditCardInvoiceRepository.
+func InitializeCreditCardInvoiceRepository(db database.FirebaseDBInterface) (entity_finance.CreditCardInvoiceRepositoryInterface, error) {
+	if db == nil {
+		return nil, errors.New("database is nil")
+	}
+
+	return &CreditCardInvoiceRepository{
+		DB:         db,
+		collection: "credit-card-invoices",
+	}, nil
+}
+
+// GetInvoiceByID retrieves a stored invoice by its ID (cardID_YYYY-MM).
+func (r *CreditCardInvoiceRepository) GetInvoiceByID(ctx context.Context, id string) (*entity_finance.CreditCardInvoice, error) {
+	if id == "" {
+		return nil, errors.New("id is empty")
+	}
+
+	invoices, err := r.getByFilter(ctx, map[string]interface{}{"id": id})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(invoices) == 0 {
+		return nil, errors.New("credit card invoice not found")
+	}
+
+	return &invoices[0], nil
+}
+
+// GetInvoicesByCard retrieves the stored invoices of a card.
+func (r *CreditCardInvoiceRepository) GetInvoicesByCard(ctx context.Context, creditCardID string) ([]entity_finance.CreditCardInvoice, error) {
+	if creditCardID == "" {
+		return nil, errors.New("creditCardId is empty")
+	}
+
+	return r.getByFilter(ctx, map[string]interface{}{"creditCardId": creditCardID})
+}
+
+// SaveInvoice writes the invoice document under its deterministic ID.
+func (r *CreditCardInvoiceRepository) SaveInvoice(ctx context.Context, data *entity_finance.CreditCardInvoice) (*entity_finance.CreditCardInvoice, error) {
+	if data == nil {
+		return nil, errors.New("credit card invoice is nil")
+	}
+	if data.ID == "" {
+		return nil, errors.New("id is empty")
+	}
+
+	toMap, _ := utils.StructToMap(data)
+
+	collection, err := repository.SetCollection(ctx, r.collection)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.DB.Update(ctx, data.ID, toMap, *collection); err != nil {
+		return nil, fmt.Errorf("failed to save credit card invoice: %w", err)
+	}
+
+	return data, nil
+}
+
+func (r *CreditCardInvoiceRepository) getByFilter(ctx context.Context, filter map[string]interface{}) ([]entity_finance.CreditCardInvoice, error) {
+	collection, err := repository.SetCollection(ctx, r.collection)
+	if err != nil {
+		return nil, err
+	}
+
+	invoices, err := database.FindAs[entity_finance.CreditCardInvoice](ctx, r.DB, database.NewQuery(*collection).WhereEqual(filter))
+	if err != nil {
+		return nil, err
+	}
+
+	return invoices, nil
+}
@@ -75,22 +75,37 @@ func (r *ExpenseRecordRepository) GetExpenseRecordByID(ctx context.Context, id s
 		return nil, errors.New("id is empty")
 	}
 
-	filters := map[string]interface{}{
-		"id": id,
+	collection, err := repository.SetCollection(ctx, r.collection)
+	if err != nil {
+		return nil, err
 	}
 
-	collection, err := repository.SetCollection(ctx, r.collection)
+	response, err := database.FindAs[interface{}](ctx, r.DB, database.NewQuery(*collection).WhereID(id))
 	if err != nil {
 		return nil, err
 	}
 
-	docs, err := r.DB.Get(ctx, *collection)
+	responseEntity, err := r.convertToEntity(response)
 	if err != nil {
 		return nil, err
 	}
 
-	var response []interface{}
-	err = json.Unmarshal(docs, &response)
+	if len(responseEntity) == 0 {
+		return nil, errors.New("expense record not found")
+	}
+
+	return &responseEntity[0], nil
+}
+
+// GetExpenseRecords retrieves all expense records for the user in context (or all if no user context).
+func (r *ExpenseRecordRepository) GetExpenseRecords(ctx context.Context) ([]entity_finance.ExpenseRecord, error) {
+
+	collection, err := repository.SetCollection(ctx, r.collection)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := database.FindAs[interface{}](ctx, r.DB, database.NewQuery(*collection))
 	if err != nil {
 		return nil, err
 	}
@@ -100,33 +115,61 @@ func (r *ExpenseRecordRepository) GetExpenseRecordByID(ctx context.Context, id s
 		return nil, err
 	}
 
-	var result *entity_finance.ExpenseRecord
-	for _, v := range responseEntity {
-		if v.ID == filters["id"] {
-			result = &v
-			break
-		}
+	return responseEntity, nil
+}
+
+// GetExpenseRecordsByDateRange retrieves the expense records due within the range.
+func (r *ExpenseRecordRepository) GetExpenseRecordsByDateRange(ctx context.Context, filter *entity_finance.RecordDateRange) ([]entity_finance.ExpenseRecord, error) {
+	if filter == nil {
+		return nil, errors.New("filter data is nil")
 	}
 
-	return result, nil
+	collection, err := repository.SetCollection(ctx, r.collection)
+	if err != nil {
+		return nil, err
+	}
+
+	conditionals := recordDateRangeConditionals("DueDate", "BankPaidFrom", filter)
+
+	response, err := database.FindAs[interface{}](ctx, r.DB, database.NewQuery(*collection).WhereConditionals(conditionals...))
+	if err != nil {
+		return nil, err
+	}
+
+	return r.convertToEntity(response)
 }
 
-// GetExpenseRecords retrieves all expense records for the user in context (or all if no user context).
-func (r *ExpenseRecordRepository) GetExpenseRecords(ctx context.Context) ([]entity_finance.ExpenseRecord, error) {
+// GetExpenseRecordsPage retrieves one page of the expense records, ordered by due date and ID.
+func (r *ExpenseRecordRepository) GetExpenseRecordsPage(ctx context.Context, filter *entity_finance.ExpenseRecordQueryByDate, page *entity_finance.PageRequest) (*entity_finance.ExpenseRecordPage, error) {
+	if page == nil {
+		return nil, errors.New("page request is nil")
+	}
 
 	collection, err := repository.SetCollection(ctx, r.collection)
 	if err != nil {
 		return nil, err
 	}
 
-	result, err := r.DB.Get(ctx, *collection)
+	query := database.NewQuery(*collection).
+		OrderBy("DueDate", database.Ascending).
+		Limit(page.Limit).
+		StartAfter(page.Cursor)
+	if filter != nil {
+		// An empty bound leaves the range open; an invalid one is rejected, not dropped.
+		dateRange, err := entity_finance.ParseRecordDateRange(filter.StartDate, filter.EndDate)
+		if err != nil {
+			return nil, err
+		}
+		query.WhereConditionals(dateRangeConditionals("DueDate", dateRange.StartDate, dateRange.EndDate)...)
+	}
+
+	result, err := r.DB.Find(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
 	var response []interface{}
-	err = json.Unmarshal(result, &response)
-	if err != nil {
+	if err := result.Decode(&response); err != nil {
 		return nil, err
 	}
 
@@ -135,7 +178,10 @@ func (r *ExpenseRecordRepository) GetExpenseRecords(ctx context.Context) ([]enti
 		return nil, err
 	}
 
-	return responseEntity, nil
+	return &entity_finance.ExpenseRecordPage{
+		Records:    responseEntity,
+		NextCursor: result.NextCursor,
+	}, nil
 }
 
 // GetExpenseRecordsByFilter retrieves expense records based on a filter.
@@ -149,13 +195,7 @@ func (r *ExpenseRecordRepository) GetExpenseRecordsByFilter(ctx context.Context,
 		return nil, err
 	}
 
-	result, err := r.DB.GetByFilter(ctx, filter, *collection)
-	if err != nil {
-		return nil, err
-	}
-
-	var response []interface{}
-	err = json.Unmarshal(result, &response)
+	response, err := database.FindAs[interface{}](ctx, r.DB, database.NewQuery(*collection).WhereEqual(filter))
 	if err != nil {
 		return nil, err
 	}
@@ -211,8 +251,9 @@ func (r *ExpenseRecordRepository) DeleteExpenseRecord(ctx context.Context, id st
 
 func (r *ExpenseRecordRepository) convertToEntity(data []interface{}) ([]entity_finance.ExpenseRecord, error) {
 
+	// An empty query result comes back as null; it is not an error.
 	if data == nil {
-		return nil, errors.New("data is nil")
+		return []entity_finance.ExpenseRecord{}, nil
 	}
 
 	var result []entity_finance.ExpenseRecord
@@ -344,8 +385,38 @@ func (r *ExpenseRecordRepository) convertToEntity(data []interface{}) ([]entity_
 				}
 			}
 
+			record.Status = entity_finance.ExpenseStatus(mapString(itemMap, "Status", "status"))
+			record.SeriesID = mapString(itemMap, "SeriesID", "seriesId")
+			record.CreditCardID = mapString(itemMap, "CreditCardID", "creditCardId")
+			record.InvoiceID = mapString(itemMap, "InvoiceID", "invoiceId")
+			record.PaidInvoiceID = mapString(itemMap, "PaidInvoiceID", "paidInvoiceId")
+			record.NfceAccessKey = mapString(itemMap, "NfceAccessKey", "nfceAccessKey")
+			record.InstallmentPurchaseID = mapString(itemMap, "InstallmentPurchaseID", "installmentPurchaseId")
+			record.InstallmentNumber = mapInt(itemMap, "InstallmentNumber", "installmentNumber")
+			record.InstallmentCount = mapInt(itemMap, "InstallmentCount", "installmentCount")
+
 			result = append(result, record)
 		}
 	}
 	return result, nil
 }
+
+// mapString returns the first string value found under one of the keys.
+func mapString(itemMap map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := itemMap[key].(string); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// mapInt returns the first number found under one of the keys.
+func mapInt(itemMap map[string]interface{}, keys ...string) int {
+	for _, key := range keys {
+		if value, ok := itemMap[key].(float64); ok {
+			return int(value)
+		}
+	}
+	return 0
+}
@@ -1,11 +1,186 @@
 package repository_finance
 
 import (
+	"context"
 	"testing"
+	"time"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"github.com/Tomelin/dashfin-backend-app/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-// Basic test structure. Full tests require mocking FirebaseDBInterface.
-func TestExpenseRecordRepository_Placeholder(t *testing.T) {
-	// TODO: Add actual tests with mocks
-	t.Log("Placeholder test for ExpenseRecordRepository. Further tests require DB mocking.")
+func userContext(userID string) context.Context {
+	ctx := context.WithValue(context.Background(), "UserID", userID)
+	return context.WithValue(ctx, "Authorization", "token")
+}
+
+func newExpenseRecordRepository(t *testing.T, db database.FirebaseDBInterface) entity_finance.ExpenseRecordRepositoryInterface {
+	t.Helper()
+	repo, err := InitializeExpenseRecordRepository(db)
+	require.NoError(t, err)
+	return repo
+}
+
+func createExpenseRecords(t *testing.T, ctx context.Context, repo entity_finance.ExpenseRecordRepositoryInterface, records ...entity_finance.ExpenseRecord) []string {
+	t.Helper()
+	ids := make([]string, 0, len(records))
+	for _, record := range records {
+		created, err := repo.CreateExpenseRecord(ctx, &record)
+		require.NoError(t, err)
+		ids = append(ids, created.ID)
+	}
+	return ids
+}
+
+func expenseIDs(records []entity_finance.ExpenseRecord) []string {
+	ids := make([]string, 0, len(records))
+	for _, record := range records {
+		ids = append(ids, record.ID)
+	}
+	return ids
+}
+
+func TestExpenseRecordRepository_CRUD(t *testing.T) {
+	ctx := userContext("user-1")
+	repo := newExpenseRecordRepository(t, database.InitializeMemoryDB())
+
+	created, err := repo.CreateExpenseRecord(ctx, &entity_finance.ExpenseRecord{
+		Category:     "housing",
+		Description:  "Rent",
+		Amount:       1200,
+		DueDate:      time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC),
+		BankPaidFrom: "acc-1",
+		Status:       entity_finance.ExpenseStatusPending,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, created.ID)
+
+	found, err := repo.GetExpenseRecordByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Rent", found.Description)
+	assert.Equal(t, 1200.0, found.Amount)
+	assert.Equal(t, time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC), found.DueDate)
+	assert.Equal(t, entity_finance.ExpenseStatusPending, found.Status)
+
+	found.Amount = 1250
+	_, err = repo.UpdateExpenseRecord(ctx, found.ID, found)
+	require.NoError(t, err)
+
+	found, err = repo.GetExpenseRecordByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1250.0, found.Amount)
+	assert.Equal(t, "acc-1", found.BankPaidFrom)
+
+	// Each user reads only their own collection.
+	_, err = repo.GetExpenseRecordByID(userContext("user-2"), created.ID)
+	assert.EqualError(t, err, "expense record not found")
+
+	require.NoError(t, repo.DeleteExpenseRecord(ctx, created.ID))
+	records, err := repo.GetExpenseRecords(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestExpenseRecordRepository_Queries(t *testing.T) {
+	ctx := userContext("user-1")
+	repo := newExpenseRecordRepository(t, database.InitializeMemoryDB())
+
+	ids := createExpenseRecords(t, ctx, repo,
+		entity_finance.ExpenseRecord{Category: "housing", Amount: 1200, DueDate: time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC), BankPaidFrom: "acc-1"},
+		entity_finance.ExpenseRecord{Category: "food", Amount: 300, DueDate: time.Date(2025, 3, 31, 18, 30, 0, 0, time.UTC), BankPaidFrom: "acc-2"},
+		entity_finance.ExpenseRecord{Category: "food", Amount: 80, DueDate: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), BankPaidFrom: "acc-1"},
+		entity_finance.ExpenseRecord{Category: "leisure", Amount: 50, DueDate: time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC), BankPaidFrom: "acc-1"},
+	)
+
+	t.Run("by filter", func(t *testing.T) {
+		records, err := repo.GetExpenseRecordsByFilter(ctx, map[string]interface{}{"Category": "food", "BankPaidFrom": "acc-1"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[2]}, expenseIDs(records))
+	})
+
+	t.Run("by date range", func(t *testing.T) {
+		records, err := repo.GetExpenseRecordsByDateRange(ctx, &entity_finance.RecordDateRange{
+			StartDate: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+		})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{ids[0], ids[1]}, expenseIDs(records))
+
+		records, err = repo.GetExpenseRecordsByDateRange(ctx, &entity_finance.RecordDateRange{
+			StartDate:     time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			BankAccountID: "acc-1",
+			Categories:    []string{"food", "leisure"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[2]}, expenseIDs(records))
+	})
+
+	t.Run("pages by due date", func(t *testing.T) {
+		var got []string
+		page := &entity_finance.PageRequest{Limit: 3}
+		for {
+			result, err := repo.GetExpenseRecordsPage(ctx, nil, page)
+			require.NoError(t, err)
+			got = append(got, expenseIDs(result.Records)...)
+			if result.NextCursor == "" {
+				break
+			}
+			page.Cursor = result.NextCursor
+		}
+		assert.Equal(t, []string{ids[3], ids[0], ids[1], ids[2]}, got)
+
+		result, err := repo.GetExpenseRecordsPage(ctx, &entity_finance.ExpenseRecordQueryByDate{StartDate: "2025-03-01", EndDate: "2025-03-31"}, &entity_finance.PageRequest{Limit: 1})
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[0]}, expenseIDs(result.Records))
+		assert.NotEmpty(t, result.NextCursor)
+
+		_, err = repo.GetExpenseRecordsPage(ctx, &entity_finance.ExpenseRecordQueryByDate{StartDate: "2024-13-01"}, &entity_finance.PageRequest{Limit: 1})
+		assert.EqualError(t, err, "validation failed: startDate must be in YYYY-MM-DD format")
+	})
+}
+
+func TestIncomeRecordRepository_Queries(t *testing.T) {
+	ctx := userContext("user-1")
+	repo, err := InitializeIncomeRecordRepository(database.InitializeMemoryDB())
+	require.NoError(t, err)
+
+	var ids []string
+	for _, record := range []entity_finance.IncomeRecord{
+		{Category: "salary", Amount: 5000, ReceiptDate: time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC), BankAccountID: "acc-1"},
+		{Category: "freelance", Amount: 800, ReceiptDate: time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC), BankAccountID: "acc-2"},
+		{Category: "salary", Amount: 5000, ReceiptDate: time.Date(2025, 4, 5, 0, 0, 0, 0, time.UTC), BankAccountID: "acc-1"},
+	} {
+		created, err := repo.CreateIncomeRecord(ctx, &record)
+		require.NoError(t, err)
+		ids = append(ids, created.ID)
+	}
+
+	found, err := repo.GetIncomeRecordByID(ctx, ids[1])
+	require.NoError(t, err)
+	assert.Equal(t, "freelance", found.Category)
+	assert.Equal(t, 800.0, found.Amount)
+
+	records, err := repo.GetIncomeRecordsByDateRange(ctx, &entity_finance.RecordDateRange{
+		StartDate:     time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:       time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+		BankAccountID: "acc-1",
+	})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, ids[0], records[0].ID)
+
+	desc := "desc"
+	page, err := repo.GetIncomeRecordsPage(ctx, &entity_finance.GetIncomeRecordsQueryParameters{SortDirection: &desc}, &entity_finance.PageRequest{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Records, 2)
+	assert.Equal(t, ids[2], page.Records[0].ID)
+	assert.Equal(t, ids[1], page.Records[1].ID)
+
+	page, err = repo.GetIncomeRecordsPage(ctx, &entity_finance.GetIncomeRecordsQueryParameters{SortDirection: &desc}, &entity_finance.PageRequest{Limit: 2, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, page.Records, 1)
+	assert.Equal(t, ids[0], page.Records[0].ID)
+	assert.Empty(t, page.NextCursor)
 }
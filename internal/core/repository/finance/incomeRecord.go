@@ -89,13 +89,7 @@ func (r *IncomeRecordRepository) GetIncomeRecordByID(ctx context.Context, id str
 		return nil, err
 	}
 
-	docs, err := r.DB.Get(ctx, *collection)
-	if err != nil {
-		return nil, err
-	}
-
-	var response []interface{}
-	err = json.Unmarshal(docs, &response)
+	response, err := database.FindAs[interface{}](ctx, r.DB, database.NewQuery(*collection))
 	if err != nil {
 		return nil, err
 	}
@@ -112,6 +106,11 @@ func (r *IncomeRecordRepository) GetIncomeRecordByID(ctx context.Context, id str
 			break
 		}
 	}
+
+	if result == nil {
+		return nil, errors.New("income record not found")
+	}
+
 	return result, nil
 }
 
@@ -122,13 +121,12 @@ func (r *IncomeRecordRepository) GetIncomeRecords(ctx context.Context, params *e
 		return nil, err
 	}
 
-	docs, err := r.DB.Get(ctx, *collection)
-	if err != nil {
-		return nil, err
+	query := database.NewQuery(*collection)
+	if params != nil {
+		query.WhereConditionals(dateRangeConditionals("ReceiptDate", params.StartDay(), params.EndDay())...)
 	}
 
-	var response []interface{}
-	err = json.Unmarshal(docs, &response)
+	response, err := database.FindAs[interface{}](ctx, r.DB, query)
 	if err != nil {
 		return nil, err
 	}
@@ -141,8 +139,8 @@ func (r *IncomeRecordRepository) GetIncomeRecords(ctx context.Context, params *e
 	return responseEntity, nil
 }
 
-// GetExpenseRecordsByFilter retrieves expense records based on a filter.
-func (r *IncomeRecordRepository) GetExpenseRecordsByFilter(ctx context.Context, filter map[string]interface{}) ([]entity_finance.IncomeRecord, error) {
+// GetIncomeRecordsByDateRange retrieves the income records received within the range.
+func (r *IncomeRecordRepository) GetIncomeRecordsByDateRange(ctx context.Context, filter *entity_finance.RecordDateRange) ([]entity_finance.IncomeRecord, error) {
 	if filter == nil {
 		return nil, errors.New("filter data is nil")
 	}
@@ -152,13 +150,73 @@ func (r *IncomeRecordRepository) GetExpenseRecordsByFilter(ctx context.Context,
 		return nil, err
 	}
 
-	docs, err := r.DB.GetByFilter(ctx, filter, *collection)
+	conditionals := recordDateRangeConditionals("ReceiptDate", "BankAccountID", filter)
+
+	response, err := database.FindAs[interface{}](ctx, r.DB, database.NewQuery(*collection).WhereConditionals(conditionals...))
+	if err != nil {
+		return nil, err
+	}
+
+	return r.convertToEntity(response)
+}
+
+// GetIncomeRecordsPage retrieves one page of the income records, ordered by receipt date and ID.
+func (r *IncomeRecordRepository) GetIncomeRecordsPage(ctx context.Context, params *entity_finance.GetIncomeRecordsQueryParameters, page *entity_finance.PageRequest) (*entity_finance.IncomeRecordPage, error) {
+	if page == nil {
+		return nil, errors.New("page request is nil")
+	}
+
+	collection, err := repository.SetCollection(ctx, r.collection)
+	if err != nil {
+		return nil, err
+	}
+
+	direction := database.Ascending
+	if params != nil && params.SortDirection != nil && *params.SortDirection == "desc" {
+		direction = database.Descending
+	}
+
+	query := database.NewQuery(*collection).
+		OrderBy("ReceiptDate", direction).
+		Limit(page.Limit).
+		StartAfter(page.Cursor)
+	if params != nil {
+		query.WhereConditionals(dateRangeConditionals("ReceiptDate", params.StartDay(), params.EndDay())...)
+	}
+
+	result, err := r.DB.Find(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
 	var response []interface{}
-	err = json.Unmarshal(docs, &response)
+	if err := result.Decode(&response); err != nil {
+		return nil, err
+	}
+
+	responseEntity, err := r.convertToEntity(response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity_finance.IncomeRecordPage{
+		Records:    responseEntity,
+		NextCursor: result.NextCursor,
+	}, nil
+}
+
+// GetIncomeRecordsByFilter retrieves income records based on a filter.
+func (r *IncomeRecordRepository) GetIncomeRecordsByFilter(ctx context.Context, filter map[string]interface{}) ([]entity_finance.IncomeRecord, error) {
+	if filter == nil {
+		return nil, errors.New("filter data is nil")
+	}
+
+	collection, err := repository.SetCollection(ctx, r.collection)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := database.FindAs[interface{}](ctx, r.DB, database.NewQuery(*collection).WhereEqual(filter))
 	if err != nil {
 		return nil, err
 	}
@@ -217,8 +275,9 @@ func (r *IncomeRecordRepository) DeleteIncomeRecord(ctx context.Context, id stri
 
 func (r *IncomeRecordRepository) convertToEntity(data []interface{}) ([]entity_finance.IncomeRecord, error) {
 
+	// An empty query result comes back as null; it is not an error.
 	if data == nil {
-		return nil, errors.New("data is nil")
+		return []entity_finance.IncomeRecord{}, nil
 	}
 
 	var result []entity_finance.IncomeRecord
@@ -301,6 +360,9 @@ func (r *IncomeRecordRepository) convertToEntity(data []interface{}) ([]entity_f
 			} else {
 				record.RecurrenceNumber = 0 // Default to 0 if not present
 			}
+
+			record.SeriesID = mapString(itemMap, "SeriesID", "seriesId")
+
 			result = append(result, record)
 		}
 	}
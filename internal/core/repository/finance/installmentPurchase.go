@@ -0,0 +1,134 @@
+package repository_finance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"github.com/Tomelin/dashfin-backend-app/internal/core/repository"
+	"github.com/Tomelin/dashfin-backend-app/pkg/database"
+	"github.com/Tomelin/dashfin-backend-app/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// InstallmentPurchaseRepository handles database operations for InstallmentPurchase.
+type InstallmentPurchaseRepository struct {
+	DB         database.FirebaseDBInterface
+	collection string
+}
+
+// InitializeInstallmentPurchaseRepository creates a new InstallmentPurchaseRepository.
+func InitializeInstallmentPurchaseRepository(db database.FirebaseDBInterface) (entity_finance.InstallmentPurchaseRepositoryInterface, error) {
+	if db == nil {
+		return nil, errors.New("database is nil")
+	}
+
+	return &InstallmentPurchaseRepository{
+		DB:         db,
+		collection: "installment-purchases",
+	}, nil
+}
+
+// CreateInstallmentPurchase stores a new purchase. The ID is generated here because the
+// IDs of the installment charges are derived from it.
+func (r *InstallmentPurchaseRepository) CreateInstallmentPurchase(ctx context.Context, data *entity_finance.InstallmentPurchase) (*entity_finance.InstallmentPurchase, error) {
+	if data == nil {
+		return nil, errors.New("installment purchase is nil")
+	}
+
+	data.ID = uuid.NewString()
+	toMap, _ := utils.StructToMap(data)
+
+	collection, err := repository.SetCollection(ctx, r.collection)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.DB.Update(ctx, data.ID, toMap, *collection); err != nil {
+		return nil, fmt.Errorf("failed to save installment purchase: %w", err)
+	}
+
+	return data, nil
+}
+
+// GetInstallmentPurchaseByID retrieves a purchase by its ID.
+func (r *InstallmentPurchaseRepository) GetInstallmentPurchaseByID(ctx context.Context, id string) (*entity_finance.InstallmentPurchase, error) {
+	if id == "" {
+		return nil, errors.New("id is empty")
+	}
+
+	purchases, err := r.getByFilter(ctx, map[string]interface{}{"id": id})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(purchases) == 0 {
+		return nil, errors.New("installment purchase not found")
+	}
+
+	return &purchases[0], nil
+}
+
+// GetInstallmentPurchases retrieves all installment purchases of the user.
+func (r *InstallmentPurchaseRepository) GetInstallmentPurchases(ctx context.Context) ([]entity_finance.InstallmentPurchase, error) {
+	collection, err := repository.SetCollection(ctx, r.collection)
+	if err != nil {
+		return nil, err
+	}
+
+	purchases, err := database.FindAs[entity_finance.InstallmentPurchase](ctx, r.DB, database.NewQuery(*collection))
+	if err != nil {
+		return nil, err
+	}
+
+	return purchases, nil
+}
+
+// UpdateInstallmentPurchase replaces a stored purchase.
+func (r *InstallmentPurchaseRepository) UpdateInstallmentPurchase(ctx context.Context, data *entity_finance.InstallmentPurchase) (*entity_finance.InstallmentPurchase, error) {
+	if data == nil || data.ID == "" {
+		return nil, errors.New("installment purchase id is empty for update")
+	}
+
+	toMap, _ := utils.StructToMap(data)
+
+	collection, err := repository.SetCollection(ctx, r.collection)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.DB.Update(ctx, data.ID, toMap, *collection); err != nil {
+		return nil, fmt.Errorf("failed to save installment purchase: %w", err)
+	}
+
+	return data, nil
+}
+
+// DeleteInstallmentPurchase removes a purchase. Its charges are deleted by the service.
+func (r *InstallmentPurchaseRepository) DeleteInstallmentPurchase(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("id is empty for delete")
+	}
+
+	collection, err := repository.SetCollection(ctx, r.collection)
+	if err != nil {
+		return err
+	}
+
+	return r.DB.Delete(ctx, id, *collection)
+}
+
+func (r *InstallmentPurchaseRepository) getByFilter(ctx context.Context, filter map[string]interface{}) ([]entity_finance.InstallmentPurchase, error) {
+	collection, err := repository.SetCollection(ctx, r.collection)
+	if err != nil {
+		return nil, err
+	}
+
+	purchases, err := database.FindAs[entity_finance.InstallmentPurchase](ctx, r.DB, database.NewQuery(*collection).WhereEqual(filter))
+	if err != nil {
+		return nil, err
+	}
+
+	return purchases, nil
+}
@@ -0,0 +1,88 @@
+package repository_finance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"github.com/Tomelin/dashfin-backend-app/internal/core/repository"
+	"github.com/Tomelin/dashfin-backend-app/pkg/database"
+	"github.com/Tomelin/dashfin-backend-app/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// NFCeImportJobRepository handles database operations for NFCeImportJobs.
+type NFCeImportJobRepository struct {
+	DB         database.FirebaseDBInterface
+	collection string
+}
+
+// InitializeNFCeImportJobRepository creates a new NFCeImportJobRepository.
+func InitializeNFCeImportJobRepository(db database.FirebaseDBInterface) (entity_finance.NFCeImportJobRepositoryInterface, error) {
+	if db == nil {
+		return nil, errors.New("database is nil")
+	}
+
+	return &NFCeImportJobRepository{
+		DB:         db,
+		collection: "nfce-imports",
+	}, nil
+}
+
+// CreateNFCeImportJob stores a new job. The ID is generated here so it is
+// also saved in the document and can be queried.
+func (r *NFCeImportJobRepository) CreateNFCeImportJob(ctx context.Context, data *entity_finance.NFCeImportJob) (*entity_finance.NFCeImportJob, error) {
+	if data == nil {
+		return nil, errors.New("nfce import job is nil")
+	}
+
+	data.ID = uuid.NewString()
+	return r.UpdateNFCeImportJob(ctx, data)
+}
+
+// GetNFCeImportJobByID retrieves a job by its ID.
+func (r *NFCeImportJobRepository) GetNFCeImportJobByID(ctx context.Context, id string) (*entity_finance.NFCeImportJob, error) {
+	if id == "" {
+		return nil, errors.New("id is empty")
+	}
+
+	collection, err := repository.SetCollection(ctx, r.collection)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, err := database.FindAs[entity_finance.NFCeImportJob](ctx, r.DB, database.NewQuery(*collection).Where("id", database.FilterEquals, id))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(jobs) == 0 {
+		return nil, errors.New("nfce import job not found")
+	}
+
+	return &jobs[0], nil
+}
+
+// UpdateNFCeImportJob writes the job document.
+func (r *NFCeImportJobRepository) UpdateNFCeImportJob(ctx context.Context, data *entity_finance.NFCeImportJob) (*entity_finance.NFCeImportJob, error) {
+	if data == nil {
+		return nil, errors.New("nfce import job is nil")
+	}
+	if data.ID == "" {
+		return nil, errors.New("id is empty")
+	}
+
+	toMap, _ := utils.StructToMap(data)
+
+	collection, err := repository.SetCollection(ctx, r.collection)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.DB.Update(ctx, data.ID, toMap, *collection); err != nil {
+		return nil, fmt.Errorf("failed to save nfce import job: %w", err)
+	}
+
+	return data, nil
+}
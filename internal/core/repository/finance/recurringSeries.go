@@ -0,0 +1,116 @@
+package repository_finance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"github.com/Tomelin/dashfin-backend-app/internal/core/repository"
+	"github.com/Tomelin/dashfin-backend-app/pkg/database"
+	"github.com/Tomelin/dashfin-backend-app/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// RecurringSeriesRepository handles database operations for RecurringSeries.
+type RecurringSeriesRepository struct {
+	DB         database.FirebaseDBInterface
+	collection string
+}
+
+// InitializeRecurringSeriesRepository creates a new RecurringSeriesRepository.
+func InitializeRecurringSeriesRepository(db database.FirebaseDBInterface) (entity_finance.RecurringSeriesRepositoryInterface, error) {
+	if db == nil {
+		return nil, errors.New("database is nil")
+	}
+
+	return &RecurringSeriesRepository{
+		DB:         db,
+		collection: "recurring-series",
+	}, nil
+}
+
+// CreateRecurringSeries stores a new series. The ID is generated here because the
+// occurrence IDs are derived from it.
+func (r *RecurringSeriesRepository) CreateRecurringSeries(ctx context.Context, data *entity_finance.RecurringSeries) (*entity_finance.RecurringSeries, error) {
+	if data == nil {
+		return nil, errors.New("recurring series is nil")
+	}
+
+	data.ID = uuid.NewString()
+	return r.UpdateRecurringSeries(ctx, data)
+}
+
+// GetRecurringSeriesByID retrieves a series by its ID.
+func (r *RecurringSeriesRepository) GetRecurringSeriesByID(ctx context.Context, id string) (*entity_finance.RecurringSeries, error) {
+	if id == "" {
+		return nil, errors.New("id is empty")
+	}
+
+	series, err := r.getByFilter(ctx, map[string]interface{}{"id": id})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(series) == 0 {
+		return nil, errors.New("recurring series not found")
+	}
+
+	return &series[0], nil
+}
+
+// GetRecurringSeriesByKind retrieves the user's series that generate the given kind of record.
+func (r *RecurringSeriesRepository) GetRecurringSeriesByKind(ctx context.Context, kind entity_finance.RecurringSeriesKind) ([]entity_finance.RecurringSeries, error) {
+	return r.getByFilter(ctx, map[string]interface{}{"kind": string(kind)})
+}
+
+// UpdateRecurringSeries writes the series document.
+func (r *RecurringSeriesRepository) UpdateRecurringSeries(ctx context.Context, data *entity_finance.RecurringSeries) (*entity_finance.RecurringSeries, error) {
+	if data == nil {
+		return nil, errors.New("recurring series is nil")
+	}
+	if data.ID == "" {
+		return nil, errors.New("id is empty")
+	}
+
+	toMap, _ := utils.StructToMap(data)
+
+	collection, err := repository.SetCollection(ctx, r.collection)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.DB.Update(ctx, data.ID, toMap, *collection); err != nil {
+		return nil, fmt.Errorf("failed to save recurring series: %w", err)
+	}
+
+	return data, nil
+}
+
+// DeleteRecurringSeries removes a series. Its materialised records are kept.
+func (r *RecurringSeriesRepository) DeleteRecurringSeries(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("id is empty for delete")
+	}
+
+	collection, err := repository.SetCollection(ctx, r.collection)
+	if err != nil {
+		return err
+	}
+
+	return r.DB.Delete(ctx, id, *collection)
+}
+
+func (r *RecurringSeriesRepository) getByFilter(ctx context.Context, filter map[string]interface{}) ([]entity_finance.RecurringSeries, error) {
+	collection, err := repository.SetCollection(ctx, r.collection)
+	if err != nil {
+		return nil, err
+	}
+
+	series, err := database.FindAs[entity_finance.RecurringSeries](ctx, r.DB, database.NewQuery(*collection).WhereEqual(filter))
+	if err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
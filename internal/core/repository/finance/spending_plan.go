@@ -45,16 +45,11 @@ func (r *SpendingPlanRepository) GetSpendingPlanByUserID(ctx context.Context, us
 		return nil, err
 	}
 
-	docs, err := r.DB.Get(ctx, *collection)
+	records, err := database.FindAs[entity_finance.SpendingPlan](ctx, r.DB, database.NewQuery(*collection))
 	if err != nil {
 		return nil, err
 	}
 
-	var records []entity_finance.SpendingPlan
-	if err := json.Unmarshal(docs, &records); err != nil {
-		return nil, err
-	}
-
 	var result *entity_finance.SpendingPlan
 	for _, v := range records {
 		if v.UserID == filters["userId"] {
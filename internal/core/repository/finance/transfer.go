@@ -0,0 +1,124 @@
+package repository_finance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"github.com/Tomelin/dashfin-backend-app/internal/core/repository"
+	"github.com/Tomelin/dashfin-backend-app/pkg/database"
+	"github.com/Tomelin/dashfin-backend-app/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// TransferRepository handles database operations for Transfer.
+type TransferRepository struct {
+	DB         database.FirebaseDBInterface
+	collection string
+}
+
+// InitializeTransferRepository creates a new TransferRepository.
+func InitializeTransferRepository(db database.FirebaseDBInterface) (entity_finance.TransferRepositoryInterface, error) {
+	if db == nil {
+		return nil, errors.New("database is nil")
+	}
+
+	return &TransferRepository{
+		DB:         db,
+		collection: "transfers",
+	}, nil
+}
+
+// CreateTransfer stores a new transfer. The ID is kept in the document so it can be filtered on.
+func (r *TransferRepository) CreateTransfer(ctx context.Context, data *entity_finance.Transfer) (*entity_finance.Transfer, error) {
+	if data == nil {
+		return nil, errors.New("transfer is nil")
+	}
+
+	data.ID = uuid.NewString()
+	toMap, _ := utils.StructToMap(data)
+
+	collection, err := repository.SetCollection(ctx, r.collection)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.DB.Update(ctx, data.ID, toMap, *collection); err != nil {
+		return nil, fmt.Errorf("failed to save transfer: %w", err)
+	}
+
+	return data, nil
+}
+
+// GetTransferByID retrieves a transfer by its ID.
+func (r *TransferRepository) GetTransferByID(ctx context.Context, id string) (*entity_finance.Transfer, error) {
+	if id == "" {
+		return nil, errors.New("id is empty")
+	}
+
+	collection, err := repository.SetCollection(ctx, r.collection)
+	if err != nil {
+		return nil, err
+	}
+
+	transfers, err := database.FindAs[entity_finance.Transfer](ctx, r.DB, database.NewQuery(*collection).Where("id", database.FilterEquals, id))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(transfers) == 0 {
+		return nil, errors.New("transfer not found")
+	}
+
+	return &transfers[0], nil
+}
+
+// GetTransfers retrieves all transfers of the user.
+func (r *TransferRepository) GetTransfers(ctx context.Context) ([]entity_finance.Transfer, error) {
+	collection, err := repository.SetCollection(ctx, r.collection)
+	if err != nil {
+		return nil, err
+	}
+
+	transfers, err := database.FindAs[entity_finance.Transfer](ctx, r.DB, database.NewQuery(*collection))
+	if err != nil {
+		return nil, err
+	}
+
+	return transfers, nil
+}
+
+// UpdateTransfer replaces a stored transfer.
+func (r *TransferRepository) UpdateTransfer(ctx context.Context, data *entity_finance.Transfer) (*entity_finance.Transfer, error) {
+	if data == nil || data.ID == "" {
+		return nil, errors.New("transfer id is empty for update")
+	}
+
+	toMap, _ := utils.StructToMap(data)
+
+	collection, err := repository.SetCollection(ctx, r.collection)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.DB.Update(ctx, data.ID, toMap, *collection); err != nil {
+		return nil, fmt.Errorf("failed to save transfer: %w", err)
+	}
+
+	return data, nil
+}
+
+// DeleteTransfer removes a transfer.
+func (r *TransferRepository) DeleteTransfer(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("id is empty for delete")
+	}
+
+	collection, err := repository.SetCollection(ctx, r.collection)
+	if err != nil {
+		return err
+	}
+
+	return r.DB.Delete(ctx, id, *collection)
+}
@@ -2,7 +2,6 @@ package repository_platform
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 
 	entity_platform "github.com/Tomelin/dashfin-backend-app/internal/core/entity/platform"
@@ -30,31 +29,20 @@ func (r *financialInstitutionRepository) GetFinancialInstitutionByID(ctx context
 		return nil, errors.New(entity_platform.ErrInvalidID)
 	}
 
-	query := map[string]interface{}{
-		"id": *id,
-	}
+	query := database.NewQuery(r.collection).Where("id", database.FilterEquals, *id)
 
-	results, err := r.DB.GetByFilter(ctx, query, r.collection)
+	institutions, err := database.FindAs[entity_platform.FinancialInstitution](ctx, r.DB, query)
 	if err != nil {
 		return nil, err
 	}
-
-	var institution entity_platform.FinancialInstitution
-	err = json.Unmarshal(results, &institution)
-	if err != nil {
-		return nil, err
+	if len(institutions) == 0 {
+		return nil, errors.New(entity_platform.ErrNotFound)
 	}
-	return &institution, nil
+	return &institutions[0], nil
 }
 
 func (r *financialInstitutionRepository) GetAllFinancialInstitutions(ctx context.Context) ([]entity_platform.FinancialInstitution, error) {
-	results, err := r.DB.Get(ctx, r.collection)
-	if err != nil {
-		return nil, err
-	}
-
-	var institutions []entity_platform.FinancialInstitution
-	err = json.Unmarshal(results, &institutions)
+	institutions, err := database.FindAs[entity_platform.FinancialInstitution](ctx, r.DB, database.NewQuery(r.collection))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,29 @@
+package repository_platform
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Tomelin/dashfin-backend-app/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFinancialInstitutionRepository_GetFinancialInstitutionByID(t *testing.T) {
+	ctx := context.Background()
+	db := database.InitializeMemoryDB()
+	require.NoError(t, db.Update(ctx, "001", map[string]interface{}{"id": "001", "code": "001", "name": "Banco do Brasil"}, "platform_financial-institution"))
+
+	repo, err := NewFinancialInstitutionRepository(db)
+	require.NoError(t, err)
+
+	id := "001"
+	institution, err := repo.GetFinancialInstitutionByID(ctx, &id)
+	require.NoError(t, err)
+	assert.Equal(t, "Banco do Brasil", institution.Name)
+
+	missing := "999"
+	institution, err = repo.GetFinancialInstitutionByID(ctx, &missing)
+	assert.EqualError(t, err, "financial institution not found")
+	assert.Nil(t, institution)
+}
@@ -103,13 +103,7 @@ func (r *ProfileRepository) GetProfileByID(ctx context.Context, id *string) (*en
 		"userProviderID": *id,
 	}
 
-	results, err := r.DB.GetByFilter(ctx, query, r.collection)
-	if err != nil {
-		return nil, err
-	}
-
-	var profiles []entity_profile.Profile
-	err = json.Unmarshal(results, &profiles)
+	profiles, err := database.FindAs[entity_profile.Profile](ctx, r.DB, database.NewQuery(r.collection).WhereEqual(query))
 	if err != nil {
 		return nil, err
 	}
@@ -127,13 +121,7 @@ func (r *ProfileRepository) GetByFilter(ctx context.Context, data map[string]int
 		return nil, errors.New("data is nil")
 	}
 
-	results, err := r.DB.GetByFilter(ctx, data, r.collection)
-	if err != nil {
-		return nil, err
-	}
-
-	var profile []entity_profile.Profile
-	err = json.Unmarshal(results, &profile)
+	profile, err := database.FindAs[entity_profile.Profile](ctx, r.DB, database.NewQuery(r.collection).WhereEqual(data))
 	if err != nil {
 		return nil, err
 	}
@@ -142,13 +130,7 @@ func (r *ProfileRepository) GetByFilter(ctx context.Context, data map[string]int
 
 func (r *ProfileRepository) GetProfile(ctx context.Context) ([]entity_profile.Profile, error) {
 
-	results, err := r.DB.Get(ctx, r.collection)
-	if err != nil {
-		return nil, err
-	}
-
-	var profile []entity_profile.Profile
-	err = json.Unmarshal(results, &profile)
+	profile, err := database.FindAs[entity_profile.Profile](ctx, r.DB, database.NewQuery(r.collection))
 	if err != nil {
 		return nil, err
 	}
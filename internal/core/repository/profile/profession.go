@@ -2,7 +2,6 @@ package repository
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 
 	entity_profile "github.com/Tomelin/dashfin-backend-app/internal/core/entity/profile"
@@ -95,13 +94,7 @@ func (r *ProfileProfessionRepository) GetByFilter(ctx context.Context, data map[
 		return nil, errors.New("data is nil")
 	}
 
-	results, err := r.DB.GetByFilter(ctx, data, r.collection)
-	if err != nil {
-		return nil, err
-	}
-
-	var profile []entity_profile.Profile
-	err = json.Unmarshal(results, &profile)
+	profile, err := database.FindAs[entity_profile.Profile](ctx, r.DB, database.NewQuery(r.collection).WhereEqual(data))
 	if err != nil {
 		return nil, err
 	}
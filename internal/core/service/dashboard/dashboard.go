@@ -28,6 +28,9 @@ type DashboardService struct {
 	bankAccountService   financeEntity.BankAccountServiceInterface
 	expenseRecordService financeEntity.ExpenseRecordServiceInterface
 	incomeRecordService  financeEntity.IncomeRecordServiceInterface
+	cardInvoiceService   financeEntity.CreditCardInvoiceServiceInterface
+	transferService      financeEntity.TransferServiceInterface
+	balanceService       financeEntity.BankAccountBalanceServiceInterface
 	profileGoalsService  profileEntity.ProfileGoalsServiceInterface
 	dashboardRepository  dashboardEntity.DashboardRepositoryInterface // New dependency
 	messageQueue         message_queue.MessageQueue
@@ -35,6 +38,8 @@ type DashboardService struct {
 	dash                 dashboardEntity.Dashboard
 	incomeRecords        []financeEntity.IncomeRecord
 	expenseRecords       []financeEntity.ExpenseRecord
+	transfers            []financeEntity.Transfer
+	adjustments          []financeEntity.BalanceAdjustment
 }
 
 // NewDashboardService creates a new DashboardService.
@@ -42,6 +47,9 @@ func NewDashboardService(
 	bankAccountSvc financeEntity.BankAccountServiceInterface,
 	expenseRecordSvc financeEntity.ExpenseRecordServiceInterface,
 	incomeRecordSvc financeEntity.IncomeRecordServiceInterface,
+	cardInvoiceSvc financeEntity.CreditCardInvoiceServiceInterface,
+	transferSvc financeEntity.TransferServiceInterface,
+	balanceSvc financeEntity.BankAccountBalanceServiceInterface,
 	profileGoalsSvc profileEntity.ProfileGoalsServiceInterface,
 	dashboardRepo dashboardEntity.DashboardRepositoryInterface, // New dependency
 	messageQueue message_queue.MessageQueue,
@@ -52,6 +60,9 @@ func NewDashboardService(
 		bankAccountService:   bankAccountSvc,
 		expenseRecordService: expenseRecordSvc,
 		incomeRecordService:  incomeRecordSvc,
+		cardInvoiceService:   cardInvoiceSvc,
+		transferService:      transferSvc,
+		balanceService:       balanceSvc,
 		profileGoalsService:  profileGoalsSvc,
 		dashboardRepository:  dashboardRepo, // Store the new dependency
 		messageQueue:         messageQueue,
@@ -101,11 +112,13 @@ func (s *DashboardService) getSummaryCards() error {
 		receiveBalance += income.Amount
 	}
 
+	// Card purchases are spending but leave the balance through the invoice payment, which in
+	// turn is not spending.
 	for _, expense := range s.expenseRecords {
-		if expense.DueDate.After(utils.GetFirstDayOfCurrentMonth()) && expense.DueDate.Before(utils.GetLastDayOfCurrentMonth()) {
+		if !expense.IsInvoicePayment() && expense.DueDate.After(utils.GetFirstDayOfCurrentMonth()) && expense.DueDate.Before(utils.GetLastDayOfCurrentMonth()) {
 			expenseMonth += expense.Amount
 		}
-		if expense.DueDate.Before(utils.GetLastDayOfCurrentMonth()) {
+		if !expense.IsCardPurchase() && expense.DueDate.Before(utils.GetLastDayOfCurrentMonth()) {
 			expenseBalance += expense.Amount
 		}
 	}
@@ -117,7 +130,7 @@ func (s *DashboardService) getSummaryCards() error {
 	}
 
 	for _, expense := range s.expenseRecords {
-		if expense.DueDate.After(utils.GetFirstDayOfLastMonth()) && expense.DueDate.Before(utils.GetLastDayOfLastMonth()) {
+		if !expense.IsInvoicePayment() && expense.DueDate.After(utils.GetFirstDayOfLastMonth()) && expense.DueDate.Before(utils.GetLastDayOfLastMonth()) {
 			expenseLastMonth += expense.Amount
 		}
 	}
@@ -147,23 +160,25 @@ func (s *DashboardService) getIncomeRecords(ctx context.Context) error {
 	return nil
 }
 
-func (s *DashboardService) getIncomeRecordsFromPeriod(startDate, endDate time.Time) ([]financeEntity.IncomeRecord, float64, error) {
-
-	if s.incomeRecords == nil {
-		return nil, 0, errors.New("income records are not initialized")
-	}
+// getIncomeRecordsFromPeriod queries the income records received from startDate to endDate,
+// both days included.
+func (s *DashboardService) getIncomeRecordsFromPeriod(ctx context.Context, startDate, endDate time.Time) ([]financeEntity.IncomeRecord, float64, error) {
 
 	if startDate.IsZero() || endDate.IsZero() {
 		return nil, 0, fmt.Errorf("startDate and endDate must be provided")
 	}
 
+	records, err := s.incomeRecordService.GetIncomeRecordsByDateRange(ctx, &financeEntity.RecordDateRange{
+		StartDate: startDate,
+		EndDate:   endDate,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
 	var amount float64
-	var records []financeEntity.IncomeRecord
-	for _, income := range s.incomeRecords {
-		if income.ReceiptDate.After(startDate) && income.ReceiptDate.Before(endDate) {
-			records = append(records, income)
-			amount += income.Amount
-		}
+	for _, income := range records {
+		amount += income.Amount
 	}
 
 	return records, amount, nil
@@ -176,25 +191,58 @@ func (s *DashboardService) getExpenseRecords(ctx context.Context) error {
 		return fmt.Errorf("error fetching expense records: %w", err)
 	}
 
-	s.expenseRecords = records
+	s.expenseRecords = financeEntity.WithoutCancelledExpenses(records)
 
 	return nil
 }
 
-func (s *DashboardService) getExpenseRecordsFromPeriod(startDate, endDate time.Time) ([]financeEntity.ExpenseRecord, float64, error) {
+// getTransfers loads the transfers between the accounts of the user. They only move balances
+// between accounts, so they are left out of the revenue and expense totals.
+func (s *DashboardService) getTransfers(ctx context.Context) error {
+	transfers, err := s.transferService.GetTransfers(ctx)
+	if err != nil {
+		s.transfers = nil
+		return fmt.Errorf("error fetching transfers: %w", err)
+	}
+
+	s.transfers = transfers
 
-	if s.expenseRecords == nil {
-		return nil, 0, errors.New("expense records are not initialized")
+	return nil
+}
+
+// getBalanceAdjustments loads the adjustments posted when reconciling the accounts.
+func (s *DashboardService) getBalanceAdjustments(ctx context.Context) error {
+	adjustments, err := s.balanceService.GetAdjustments(ctx)
+	if err != nil {
+		s.adjustments = nil
+		return fmt.Errorf("error fetching balance adjustments: %w", err)
 	}
 
+	s.adjustments = adjustments
+
+	return nil
+}
+
+// getExpenseRecordsFromPeriod queries the expense records due from startDate to endDate, both
+// days included. Cancelled expenses and invoice payments are left out.
+func (s *DashboardService) getExpenseRecordsFromPeriod(ctx context.Context, startDate, endDate time.Time) ([]financeEntity.ExpenseRecord, float64, error) {
+
 	if startDate.IsZero() || endDate.IsZero() {
 		return nil, 0, fmt.Errorf("startDate and endDate must be provided")
 	}
 
+	found, err := s.expenseRecordService.GetExpenseRecordsByDateRange(ctx, &financeEntity.RecordDateRange{
+		StartDate: startDate,
+		EndDate:   endDate,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
 	var amount float64
 	var records []financeEntity.ExpenseRecord
-	for _, expense := range s.expenseRecords {
-		if expense.DueDate.After(startDate) && expense.DueDate.Before(endDate) {
+	for _, expense := range financeEntity.WithoutCancelledExpenses(found) {
+		if !expense.IsInvoicePayment() {
 			records = append(records, expense)
 			amount += expense.Amount
 		}
@@ -205,40 +253,64 @@ func (s *DashboardService) getExpenseRecordsFromPeriod(startDate, endDate time.T
 
 func (s *DashboardService) getBankAccountBalance(ctx context.Context, userID *string) {
 
-	balances := make(map[string]float64)
-	for _, income := range s.incomeRecords {
-		balances[income.BankAccountID] += income.Amount
-	}
-	for _, expense := range s.expenseRecords {
-		if expense.DueDate.Before(utils.GetFirstDayOfLastMonth()) {
-			balances[expense.BankPaidFrom] -= expense.Amount
-		}
-	}
-
 	banks, err := s.bankAccountService.GetBankAccounts(ctx)
 	if err != nil {
 		return
 	}
 
-	for bankID := range balances {
+	balances := s.calculateAllAccountBalances(banks, s.incomeRecords, s.expenseRecords)
+	byType := make([]financeEntity.BankAccountBalance, 0, len(banks))
+	for _, bank := range banks {
 
-		if balances[bankID] == 0 {
+		if balances[bank.ID] == 0 {
 			continue
 		}
 
-		for _, bank := range banks {
+		s.dash.SummaryCards.AccountBalances = append(s.dash.SummaryCards.AccountBalances, dashboardEntity.AccountBalanceItem{
+			AccountName: bank.CustomBankName,
+			BankName:    bank.Description,
+			Balance:     balances[bank.ID],
+			UserID:      *userID,
+			ID:          bank.ID,
+			AccountType: string(bank.Type()),
+		})
+		byType = append(byType, financeEntity.BankAccountBalance{
+			BankAccountID: bank.ID,
+			AccountType:   bank.Type(),
+			Balance:       balances[bank.ID],
+		})
+	}
 
-			if bank.ID == bankID {
-				s.dash.SummaryCards.AccountBalances = append(s.dash.SummaryCards.AccountBalances, dashboardEntity.AccountBalanceItem{
-					AccountName: bank.CustomBankName,
-					BankName:    bank.Description,
-					Balance:     balances[bankID],
-					UserID:      *userID,
-					ID:          bank.ID,
-				})
-			}
+	for _, total := range financeEntity.GroupBalancesByAccountType(byType) {
+		s.dash.SummaryCards.AccountTypeBalances = append(s.dash.SummaryCards.AccountTypeBalances, dashboardEntity.AccountTypeBalanceItem{
+			AccountType:  string(total.AccountType),
+			Balance:      total.Balance,
+			AccountCount: total.AccountCount,
+		})
+	}
+}
+
+// getCreditCardLimits sets the limit use of each card with a credit limit.
+func (s *DashboardService) getCreditCardLimits(ctx context.Context, userID *string) {
+	limits, err := s.cardInvoiceService.GetCreditCardLimits(ctx)
+	if err != nil {
+		log.Println(fmt.Errorf("error getting credit card limits for user %s: %w", *userID, err))
+		return
+	}
+
+	for _, limit := range limits {
+		if limit.CreditLimit == 0 {
+			continue
 		}
 
+		s.dash.SummaryCards.CreditCardLimits = append(s.dash.SummaryCards.CreditCardLimits, dashboardEntity.CreditCardLimitItem{
+			ID:                 limit.CreditCardID,
+			CardName:           fmt.Sprintf("%s %s", limit.CardBrand, limit.LastFourDigits),
+			CreditLimit:        limit.CreditLimit,
+			CommittedAmount:    limit.CommittedAmount,
+			AvailableLimit:     limit.AvailableLimit,
+			UtilizationPercent: limit.UtilizationPercent,
+		})
 	}
 }
 
@@ -255,6 +327,15 @@ func (s *DashboardService) generateFreshDashboardData(ctx context.Context, userI
 		log.Println(fmt.Errorf("error fetching expense records: %w", err))
 	}
 
+	err = s.getTransfers(ctx)
+	if err != nil {
+		log.Println(fmt.Errorf("error fetching transfers: %w", err))
+	}
+	err = s.getBalanceAdjustments(ctx)
+	if err != nil {
+		log.Println(fmt.Errorf("error fetching balance adjustments: %w", err))
+	}
+
 	// 6. Goals fetch and set additional data
 	s.formatGoalsProgress(ctx, userID)
 
@@ -271,10 +352,14 @@ func (s *DashboardService) generateFreshDashboardData(ctx context.Context, userI
 	}
 
 	s.getBankAccountBalance(ctx, &userID)
+	s.getCreditCardLimits(ctx, &userID)
 	// 9. Set the income and expense records
 	s.calculateTotalBalance(ctx, userID)
 
-	s.getMonthlyFinancialSummary(&userID)
+	err = s.getMonthlyFinancialSummary(ctx, &userID)
+	if err != nil {
+		log.Println(fmt.Errorf("error getting monthly financial summary: %w", err))
+	}
 
 }
 
@@ -294,22 +379,23 @@ func (s *DashboardService) calculateTotalBalance(ctx context.Context, userID str
 
 }
 
+// calculateAllAccountBalances returns the balance of each account today, from its opening
+// balance and the movements since then.
 func (s *DashboardService) calculateAllAccountBalances(
 	accounts []financeEntity.BankAccountRequest,
 	incomes []financeEntity.IncomeRecord,
 	paidExpenses []financeEntity.ExpenseRecord,
 ) map[string]float64 {
+	ledger := financeEntity.AccountLedger{
+		Incomes:     incomes,
+		Expenses:    paidExpenses,
+		Transfers:   s.transfers,
+		Adjustments: s.adjustments,
+	}
+
 	accountBalances := make(map[string]float64)
 	for _, acc := range accounts {
-		accountBalances[acc.ID] = 0
-	}
-	for _, income := range incomes {
-		accountBalances[income.BankAccountID] += income.Amount
-	}
-	for _, expense := range paidExpenses {
-		if expense.BankPaidFrom != "" {
-			accountBalances[expense.BankPaidFrom] -= expense.Amount
-		}
+		accountBalances[acc.ID] = ledger.Balance(acc, time.Now())
 	}
 	return accountBalances
 }
@@ -329,7 +415,7 @@ func (s *DashboardService) calculateMonthlyExpenses(paidExpenses []financeEntity
 	var totalExpenses float64
 	monthEnd := monthStart.AddDate(0, 1, 0).Add(-time.Nanosecond)
 	for _, expense := range paidExpenses {
-		if !expense.PaymentDate.IsZero() {
+		if expense.StoredStatus() == financeEntity.ExpenseStatusPaid && !expense.IsInvoicePayment() {
 			if !expense.PaymentDate.Before(monthStart) && !expense.PaymentDate.After(monthEnd) {
 				totalExpenses += expense.Amount
 			}
@@ -375,13 +461,15 @@ func (s *DashboardService) formatGoalsProgress(ctx context.Context, userID strin
 
 func (s *DashboardService) getUpcomingBills2() error {
 
+	now := time.Now()
 	bills := make([]dashboardEntity.UpcomingBill, 0)
 	for _, expense := range s.expenseRecords {
-		if expense.PaymentDate.IsZero() {
+		if expense.HasStatus(now, financeEntity.ExpenseStatusPending, financeEntity.ExpenseStatusOverdue) {
 			bills = append(bills, dashboardEntity.UpcomingBill{
 				BillName: fmt.Sprintf("%s - %s", expense.Category, expense.Subcategory),
 				Amount:   expense.Amount,
 				DueDate:  expense.DueDate.Format("2006-01-02"),
+				Status:   string(expense.EffectiveStatus(now)),
 			})
 
 		}
@@ -408,19 +496,18 @@ func (s *DashboardService) getUpcomingBills(
 
 	bills := make([]dashboardEntity.UpcomingBill, 0)
 	for _, exp := range allUserRawExpenses {
-		if exp.PaymentDate.IsZero() {
+		if exp.HasStatus(fromDate, financeEntity.ExpenseStatusPending, financeEntity.ExpenseStatusOverdue) {
 			if !exp.DueDate.Before(upcomingStartDate) && !exp.DueDate.After(upcomingEndDate) {
-				if exp.PaymentDate.IsZero() {
-					billName := exp.Description
-					if billName == "" {
-						billName = exp.Category
-					}
-					bills = append(bills, dashboardEntity.UpcomingBill{
-						BillName: billName,
-						Amount:   exp.Amount,
-						DueDate:  exp.DueDate.Format("2006-01-02"), // Assign the parsed time.Time value
-					})
+				billName := exp.Description
+				if billName == "" {
+					billName = exp.Category
 				}
+				bills = append(bills, dashboardEntity.UpcomingBill{
+					BillName: billName,
+					Amount:   exp.Amount,
+					DueDate:  exp.DueDate.Format("2006-01-02"), // Assign the parsed time.Time value
+					Status:   string(exp.EffectiveStatus(fromDate)),
+				})
 			}
 		}
 	}
@@ -464,7 +551,7 @@ func (s *DashboardService) getExpenseCategoriesForMonth(
 	monthEnd := monthStart.AddDate(0, 1, 0).Add(-time.Nanosecond)
 	categories := make(map[string]float64)
 	for _, exp := range paidExpenses {
-		if !exp.PaymentDate.IsZero() {
+		if exp.StoredStatus() == financeEntity.ExpenseStatusPaid && !exp.IsInvoicePayment() {
 
 			if !exp.PaymentDate.Before(monthStart) && !exp.PaymentDate.After(monthEnd) {
 				categoryName := exp.Category
@@ -490,8 +577,9 @@ func (s *DashboardService) getExpenseCategoriesForMonth(
 }
 
 func (s *DashboardService) accountBalance(ctx context.Context) {
-
-	s.messageQueue.Consumer(ctx, mq_exchange, mq_queue_income, s.processIncomeRecord)
+	if err := s.messageQueue.Consumer(ctx, mq_exchange, mq_queue_income, s.processIncomeRecord); err != nil {
+		log.Printf("dashboard income consumer stopped: %v", err)
+	}
 }
 
 func (s *DashboardService) processIncomeRecord(body []byte, traceID string) error {
@@ -559,33 +647,45 @@ func (s *DashboardService) processIncomeRecord(body []byte, traceID string) erro
 	return nil
 }
 
-func (s *DashboardService) getMonthlyFinancialSummary(userID *string) error {
+// getMonthlyFinancialSummary totals the last 12 months from one query per record type over
+// the whole period.
+func (s *DashboardService) getMonthlyFinancialSummary(ctx context.Context, userID *string) error {
 
 	if userID == nil || *userID == "" {
 		return fmt.Errorf("userID is nil or empty")
 	}
 
-	items := make([]dashboardEntity.MonthlyFinancialSummaryItem, 0)
+	firstMonth := utils.GetFirstDayOfCurrentMonth().AddDate(0, -11, 0)
+	lastDay := utils.GetLastDayOfCurrentMonth()
 
-	for i := 0; i < 12; i++ {
-		startDateThisMonth := utils.GetFirstDayOfCurrentMonth().AddDate(0, -i, 0)
-		endDateThisMonth := utils.GetLastDayOfCurrentMonth().AddDate(0, -i, 0)
-		month := startDateThisMonth.Format("2006-01")
+	incomes, _, err := s.getIncomeRecordsFromPeriod(ctx, firstMonth, lastDay)
+	if err != nil {
+		return fmt.Errorf("error fetching income records from %s: %w", firstMonth.Format("2006-01"), err)
+	}
 
-		_, incomeAmount, err := s.getIncomeRecordsFromPeriod(startDateThisMonth, endDateThisMonth)
-		if err != nil {
-			return fmt.Errorf("error fetching income records for month %s: %w", month, err)
-		}
+	expenses, _, err := s.getExpenseRecordsFromPeriod(ctx, firstMonth, lastDay)
+	if err != nil {
+		return fmt.Errorf("error fetching expense records from %s: %w", firstMonth.Format("2006-01"), err)
+	}
 
-		_, expenseAmount, err := s.getExpenseRecordsFromPeriod(startDateThisMonth, endDateThisMonth)
-		if err != nil {
-			return fmt.Errorf("error fetching expense records for month %s: %w", month, err)
-		}
+	incomeByMonth := make(map[string]float64)
+	for _, income := range incomes {
+		incomeByMonth[income.ReceiptDate.Format("2006-01")] += income.Amount
+	}
+	expenseByMonth := make(map[string]float64)
+	for _, expense := range expenses {
+		expenseByMonth[expense.DueDate.Format("2006-01")] += expense.Amount
+	}
+
+	items := make([]dashboardEntity.MonthlyFinancialSummaryItem, 0)
+
+	for i := 0; i < 12; i++ {
+		month := utils.GetFirstDayOfCurrentMonth().AddDate(0, -i, 0).Format("2006-01")
 
 		items = append(items, dashboardEntity.MonthlyFinancialSummaryItem{
 			Month:         month,
-			TotalIncome:   incomeAmount,
-			TotalExpenses: expenseAmount,
+			TotalIncome:   incomeByMonth[month],
+			TotalExpenses: expenseByMonth[month],
 			UserID:        *userID,
 		})
 	}
@@ -170,7 +170,7 @@ func (s *FinancialService) getExpenses(ctx context.Context, month, year int) []f
 		return expenses
 	}
 
-	for _, v := range queryExpenses {
+	for _, v := range finance_entity.WithoutInvoicePayments(finance_entity.WithoutCancelledExpenses(queryExpenses)) {
 		b, err := s.isInCurrentMonthAndYear(v.DueDate)
 		if err != nil || !b {
 			continue
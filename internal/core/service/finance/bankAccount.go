@@ -2,23 +2,62 @@ package finance
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
+	entity_common "github.com/Tomelin/dashfin-backend-app/internal/core/entity/common"
 	entity "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"github.com/Tomelin/dashfin-backend-app/pkg/message_queue"
 )
 
 type BankAccountService struct {
-	Repo entity.BankAccountRepositoryInterface
+	Repo      entity.BankAccountRepositoryInterface
+	Incomes   entity.IncomeRecordRepositoryInterface
+	Expenses  entity.ExpenseRecordRepositoryInterface
+	Transfers entity.TransferRepositoryInterface
+	Series    entity.RecurringSeriesRepositoryInterface
+	Balances  entity.BankAccountBalanceRepositoryInterface
+	Tx        entity.TransactionRunner
+	mq        message_queue.MessageQueue
 }
 
-func InitializeBankAccountService(repo entity.BankAccountRepositoryInterface) (entity.BankAccountServiceInterface, error) {
+func InitializeBankAccountService(repo entity.BankAccountRepositoryInterface, incomes entity.IncomeRecordRepositoryInterface, expenses entity.ExpenseRecordRepositoryInterface, transfers entity.TransferRepositoryInterface, series entity.RecurringSeriesRepositoryInterface, balances entity.BankAccountBalanceRepositoryInterface, tx entity.TransactionRunner, mq message_queue.MessageQueue) (entity.BankAccountServiceInterface, error) {
 	if repo == nil {
 		return nil, errors.New("repo is nil")
 	}
+	if incomes == nil {
+		return nil, errors.New("income record repository is nil for BankAccountService")
+	}
+	if expenses == nil {
+		return nil, errors.New("expense record repository is nil for BankAccountService")
+	}
+	if transfers == nil {
+		return nil, errors.New("transfer repository is nil for BankAccountService")
+	}
+	if series == nil {
+		return nil, errors.New("recurring series repository is nil for BankAccountService")
+	}
+	if balances == nil {
+		return nil, errors.New("bank account balance repository is nil for BankAccountService")
+	}
+	if tx == nil {
+		return nil, errors.New("transaction runner is nil for BankAccountService")
+	}
+	if mq == nil {
+		return nil, errors.New("message queue is nil for BankAccountService")
+	}
 
 	return &BankAccountService{
-		Repo: repo,
+		Repo:      repo,
+		Incomes:   incomes,
+		Expenses:  expenses,
+		Transfers: transfers,
+		Series:    series,
+		Balances:  balances,
+		Tx:        tx,
+		mq:        mq,
 	}, nil
 }
 
@@ -31,19 +70,23 @@ func (s *BankAccountService) CreateBankAccount(ctx context.Context, bankAccount
 		return nil, err
 	}
 
-	query := map[string]interface{}{
-		"bankCode":      bankAccount.BankCode,
-		"agency":        bankAccount.Agency,
-		"accountNumber": bankAccount.AccountNumber,
-	}
+	// Wallets, benefit cards and brokerage accounts may have no account number to tell
+	// them apart, so only numbered accounts are checked for duplicates.
+	if bankAccount.AccountNumber != "" {
+		query := map[string]interface{}{
+			"bankCode":      bankAccount.BankCode,
+			"agency":        bankAccount.Agency,
+			"accountNumber": bankAccount.AccountNumber,
+		}
 
-	results, err := s.GetByFilter(ctx, query)
-	if err != nil && err.Error() != "bank account not found" {
-		return nil, err
-	}
+		results, err := s.GetByFilter(ctx, query)
+		if err != nil && err.Error() != "bank account not found" {
+			return nil, err
+		}
 
-	if len(results) > 0 {
-		return nil, errors.New("bank account already exists")
+		if len(results) > 0 {
+			return nil, errors.New("bank account already exists")
+		}
 	}
 
 	result, err := s.Repo.CreateBankAccount(ctx, bankAccount)
@@ -113,15 +156,297 @@ func (s *BankAccountService) UpdateBankAccount(ctx context.Context, data *entity
 	return s.Repo.UpdateBankAccount(ctx, data)
 }
 
+// DeleteBankAccount removes an account no record points at. An account in use has to be
+// reassigned or archived instead.
 func (s *BankAccountService) DeleteBankAccount(ctx context.Context, id *string) error {
 	if id == nil || *id == "" {
 		return errors.New("bank account id is empty")
 	}
 
-	// _, err := s.GetBankAccountByID(ctx, id)
-	// if err != nil {
-	// 	return err
-	// }
+	if _, err := s.GetBankAccountByID(ctx, id); err != nil {
+		return err
+	}
+
+	references, err := s.references(ctx, *id)
+	if err != nil {
+		return err
+	}
+
+	if err := references.InUseError(); err != nil {
+		return err
+	}
 
 	return s.Repo.DeleteBankAccount(ctx, id)
 }
+
+// ReassignBankAccount moves every record of the account to the target account and deletes
+// it. Transfers between the two accounts become transfers to itself and are deleted. The
+// balance checkpoints and adjustments move too, unless the target already has one on the
+// same day.
+func (s *BankAccountService) ReassignBankAccount(ctx context.Context, id, targetID *string) error {
+	if id == nil || *id == "" {
+		return errors.New("bank account id is empty")
+	}
+	if targetID == nil || *targetID == "" {
+		return errors.New("validation failed: reassignTo is required")
+	}
+	if *id == *targetID {
+		return errors.New("validation failed: reassignTo must be another bank account")
+	}
+
+	if _, err := s.GetBankAccountByID(ctx, id); err != nil {
+		return err
+	}
+
+	target, err := s.Repo.GetBankAccountByID(ctx, targetID)
+	if err != nil {
+		return fmt.Errorf("validation failed: reassignTo: %w", err)
+	}
+	if target.Archived {
+		return errors.New("validation failed: reassignTo: bank account is archived")
+	}
+
+	references, err := s.references(ctx, *id)
+	if err != nil {
+		return err
+	}
+
+	targetReferences, err := s.balanceReferences(ctx, *targetID)
+	if err != nil {
+		return err
+	}
+	// A checkpoint and its adjustment are stored under the account and the day, so the target
+	// cannot take one for a day it already has.
+	taken := make(map[string]bool)
+	for _, checkpoint := range targetReferences.Checkpoints {
+		taken[checkpoint.ID] = true
+	}
+	for _, adjustment := range targetReferences.Adjustments {
+		taken[adjustment.ID] = true
+	}
+	for _, checkpoint := range references.Checkpoints {
+		checkpoint.MoveTo(*targetID)
+		if taken[checkpoint.ID] {
+			return fmt.Errorf("validation failed: reassignTo: bank account already has a balance checkpoint on %s", checkpoint.Date.Format("2006-01-02"))
+		}
+	}
+	for _, adjustment := range references.Adjustments {
+		adjustment.MoveTo(*targetID)
+		if taken[adjustment.ID] {
+			return fmt.Errorf("validation failed: reassignTo: bank account already has a balance adjustment on %s", adjustment.Date.Format("2006-01-02"))
+		}
+	}
+
+	// The moved records are replaced in the consumers once the batch is committed.
+	before := *references
+	after := entity.BankAccountReferences{
+		Incomes:   make([]entity.IncomeRecord, len(references.Incomes)),
+		Expenses:  make([]entity.ExpenseRecord, len(references.Expenses)),
+		Transfers: make([]entity.Transfer, len(references.Transfers)),
+	}
+	copy(after.Incomes, references.Incomes)
+	copy(after.Expenses, references.Expenses)
+	copy(after.Transfers, references.Transfers)
+
+	// Moved in a single commit, so a failure does not leave records on a deleted account.
+	err = s.Tx.Batch(ctx, func(ctx context.Context) error {
+		for i := range after.Incomes {
+			income := &after.Incomes[i]
+			income.BankAccountID = *targetID
+			if _, err := s.Incomes.UpdateIncomeRecord(ctx, income.ID, income); err != nil {
+				return err
+			}
+		}
+
+		for i := range after.Expenses {
+			expense := &after.Expenses[i]
+			expense.BankPaidFrom = *targetID
+			if _, err := s.Expenses.UpdateExpenseRecord(ctx, expense.ID, expense); err != nil {
+				return err
+			}
+		}
+
+		for i := range after.Transfers {
+			transfer := &after.Transfers[i]
+			transfer.ReplaceBankAccount(*id, *targetID)
+			var err error
+			if transfer.FromBankAccountID == transfer.ToBankAccountID {
+				err = s.Transfers.DeleteTransfer(ctx, transfer.ID)
+			} else {
+				_, err = s.Transfers.UpdateTransfer(ctx, transfer)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, series := range references.Series {
+			series.ReplaceBankAccount(*id, *targetID)
+			if _, err := s.Series.UpdateRecurringSeries(ctx, &series); err != nil {
+				return err
+			}
+		}
+
+		for _, checkpoint := range references.Checkpoints {
+			if err := s.Balances.DeleteCheckpoint(ctx, checkpoint.ID); err != nil {
+				return err
+			}
+			checkpoint.MoveTo(*targetID)
+			if _, err := s.Balances.SaveCheckpoint(ctx, &checkpoint); err != nil {
+				return err
+			}
+		}
+
+		for _, adjustment := range references.Adjustments {
+			if err := s.Balances.DeleteAdjustment(ctx, adjustment.ID); err != nil {
+				return err
+			}
+			adjustment.MoveTo(*targetID)
+			if _, err := s.Balances.SaveAdjustment(ctx, &adjustment); err != nil {
+				return err
+			}
+		}
+
+		return s.Repo.DeleteBankAccount(ctx, id)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.publishReassign(ctx, &before, &after)
+	return nil
+}
+
+// publishReassign replaces the moved records in the consumers with a delete and a create
+// event each. A transfer dropped by the reassignment only gets the delete event.
+func (s *BankAccountService) publishReassign(ctx context.Context, before, after *entity.BankAccountReferences) {
+	for i := range before.Incomes {
+		s.publishIncome(ctx, mq_rk_income_delete, &before.Incomes[i], entity_common.ActionDelete)
+		s.publishIncome(ctx, mq_rk_income_create, &after.Incomes[i], entity_common.ActionCreate)
+	}
+
+	for i := range before.Expenses {
+		old, _ := json.Marshal(before.Expenses[i])
+		new, _ := json.Marshal(after.Expenses[i])
+		s.publishMessage(ctx, mq_rk_expense_delete, old, "")
+		s.publishMessage(ctx, mq_rk_expense_create, new, "")
+	}
+
+	for i := range before.Transfers {
+		old, _ := json.Marshal(before.Transfers[i])
+		s.publishMessage(ctx, mq_rk_transfer_delete, old, "")
+		if after.Transfers[i].FromBankAccountID != after.Transfers[i].ToBankAccountID {
+			new, _ := json.Marshal(after.Transfers[i])
+			s.publishMessage(ctx, mq_rk_transfer_create, new, "")
+		}
+	}
+}
+
+// ArchiveBankAccount keeps the account in the history of its records and stops it from being
+// picked for new ones.
+func (s *BankAccountService) ArchiveBankAccount(ctx context.Context, id *string) (*entity.BankAccountRequest, error) {
+	account, err := s.GetBankAccountByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	account.Archived = true
+	return s.Repo.UpdateBankAccount(ctx, account)
+}
+
+// references gathers the records that point at the account.
+func (s *BankAccountService) references(ctx context.Context, id string) (*entity.BankAccountReferences, error) {
+	incomes, err := s.Incomes.GetIncomeRecordsByFilter(ctx, map[string]interface{}{"BankAccountID": id})
+	if err != nil {
+		return nil, err
+	}
+
+	expenses, err := s.Expenses.GetExpenseRecordsByFilter(ctx, map[string]interface{}{"BankPaidFrom": id})
+	if err != nil {
+		return nil, err
+	}
+
+	references := &entity.BankAccountReferences{Incomes: incomes, Expenses: expenses}
+
+	transfers, err := s.Transfers.GetTransfers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, transfer := range transfers {
+		if transfer.FromBankAccountID == id || transfer.ToBankAccountID == id {
+			references.Transfers = append(references.Transfers, transfer)
+		}
+	}
+
+	for _, kind := range []entity.RecurringSeriesKind{entity.RecurringSeriesIncome, entity.RecurringSeriesExpense} {
+		series, err := s.Series.GetRecurringSeriesByKind(ctx, kind)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range series {
+			if item.ReferencesBankAccount(id) {
+				references.Series = append(references.Series, item)
+			}
+		}
+	}
+
+	balances, err := s.balanceReferences(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	references.Checkpoints = balances.Checkpoints
+	references.Adjustments = balances.Adjustments
+
+	return references, nil
+}
+
+// balanceReferences gathers the balance checkpoints and adjustments of the account.
+func (s *BankAccountService) balanceReferences(ctx context.Context, id string) (*entity.BankAccountReferences, error) {
+	checkpoints, err := s.Balances.GetCheckpoints(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	adjustments, err := s.Balances.GetAdjustments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	references := &entity.BankAccountReferences{Checkpoints: checkpoints}
+	for _, adjustment := range adjustments {
+		if adjustment.BankAccountID == id {
+			references.Adjustments = append(references.Adjustments, adjustment)
+		}
+	}
+	return references, nil
+}
+
+func (s *BankAccountService) publishIncome(ctx context.Context, routeKey string, income *entity.IncomeRecord, action entity_common.ActionEvent) error {
+	body, _ := json.Marshal(entity.IncomeRecordEvent{Action: action, Data: *income})
+	return s.mq.PublisherWithRouteKey(mq_exchange, routeKey, body, "")
+}
+
+func (s *BankAccountService) publishMessage(ctx context.Context, routeKey string, body []byte, trace string) error {
+	return s.mq.PublisherWithRouteKey(mq_exchange, routeKey, body, trace)
+}
+
+// checkBankAccountNotArchived rejects an archived account picked for a new record. Accounts
+// that are not registered, such as "other", are left to the validation of the record.
+func checkBankAccountNotArchived(ctx context.Context, accounts entity.BankAccountRepositoryInterface, field, id string) error {
+	if id == "" || id == "other" {
+		return nil
+	}
+
+	account, err := accounts.GetBankAccountByID(ctx, &id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil
+		}
+		return err
+	}
+
+	if account.Archived {
+		return fmt.Errorf("validation failed: %s: bank account is archived", field)
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+)
+
+// BankAccountBalanceService computes the balances of the bank accounts from their opening
+// balance and movements, and reconciles them with the balances of the bank statements.
+type BankAccountBalanceService struct {
+	Repo      entity_finance.BankAccountBalanceRepositoryInterface
+	Accounts  entity_finance.BankAccountRepositoryInterface
+	Incomes   entity_finance.IncomeRecordRepositoryInterface
+	Expenses  entity_finance.ExpenseRecordRepositoryInterface
+	Transfers entity_finance.TransferRepositoryInterface
+}
+
+// InitializeBankAccountBalanceService creates a new BankAccountBalanceService.
+func InitializeBankAccountBalanceService(repo entity_finance.BankAccountBalanceRepositoryInterface, accounts entity_finance.BankAccountRepositoryInterface, incomes entity_finance.IncomeRecordRepositoryInterface, expenses entity_finance.ExpenseRecordRepositoryInterface, transfers entity_finance.TransferRepositoryInterface) (entity_finance.BankAccountBalanceServiceInterface, error) {
+	if repo == nil {
+		return nil, errors.New("repository is nil for BankAccountBalanceService")
+	}
+	if accounts == nil {
+		return nil, errors.New("bank account repository is nil for BankAccountBalanceService")
+	}
+	if incomes == nil {
+		return nil, errors.New("income record repository is nil for BankAccountBalanceService")
+	}
+	if expenses == nil {
+		return nil, errors.New("expense record repository is nil for BankAccountBalanceService")
+	}
+	if transfers == nil {
+		return nil, errors.New("transfer repository is nil for BankAccountBalanceService")
+	}
+	return &BankAccountBalanceService{
+		Repo:      repo,
+		Accounts:  accounts,
+		Incomes:   incomes,
+		Expenses:  expenses,
+		Transfers: transfers,
+	}, nil
+}
+
+// GetBalances returns the balance of every account of the user at the end of date.
+func (s *BankAccountBalanceService) GetBalances(ctx context.Context, date time.Time) ([]entity_finance.BankAccountBalance, error) {
+	accounts, err := s.Accounts.GetBankAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	incomes, err := s.Incomes.GetIncomeRecords(ctx, &entity_finance.GetIncomeRecordsQueryParameters{})
+	if err != nil {
+		return nil, err
+	}
+
+	expenses, err := s.Expenses.GetExpenseRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ledger, err := s.ledger(ctx, incomes, expenses)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make([]entity_finance.BankAccountBalance, 0, len(accounts))
+	for _, account := range accounts {
+		balances = append(balances, entity_finance.BankAccountBalance{
+			BankAccountID: account.ID,
+			AccountType:   account.Type(),
+			Date:          date,
+			Balance:       ledger.Balance(account, date),
+		})
+	}
+	return balances, nil
+}
+
+// GetAdjustments returns the balance adjustments of the user.
+func (s *BankAccountBalanceService) GetAdjustments(ctx context.Context) ([]entity_finance.BalanceAdjustment, error) {
+	return s.Repo.GetAdjustments(ctx)
+}
+
+// CreateCheckpoint stores the statement balance of an account on a day, replacing the
+// checkpoint the account already had that day.
+func (s *BankAccountBalanceService) CreateCheckpoint(ctx context.Context, data *entity_finance.BalanceCheckpoint) (*entity_finance.BalanceCheckpoint, error) {
+	if data == nil {
+		return nil, errors.New("balance checkpoint data is nil")
+	}
+
+	if err := data.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if _, err := s.Accounts.GetBankAccountByID(ctx, &data.BankAccountID); err != nil {
+		return nil, fmt.Errorf("validation failed: bankAccountId: %w", err)
+	}
+
+	data.ID = entity_finance.BalanceCheckpointID(data.BankAccountID, data.Date)
+	data.UserID, _ = ctx.Value("UserID").(string)
+	data.CreatedAt = time.Now()
+	data.UpdatedAt = time.Now()
+
+	return s.Repo.SaveCheckpoint(ctx, data)
+}
+
+// GetCheckpoints returns the checkpoints of the account, the most recent first.
+func (s *BankAccountBalanceService) GetCheckpoints(ctx context.Context, bankAccountID string) ([]entity_finance.BalanceCheckpoint, error) {
+	if _, err := s.Accounts.GetBankAccountByID(ctx, &bankAccountID); err != nil {
+		return nil, err
+	}
+
+	checkpoints, err := s.Repo.GetCheckpoints(ctx, bankAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(checkpoints, func(i, j int) bool {
+		return checkpoints[i].Date.After(checkpoints[j].Date)
+	})
+	return checkpoints, nil
+}
+
+// Reconcile compares the balance computed for the account at the end of date with the
+// checkpoint of that day.
+func (s *BankAccountBalanceService) Reconcile(ctx context.Context, bankAccountID string, date time.Time) (*entity_finance.AccountReconciliation, error) {
+	reconciliation, _, err := s.reconcile(ctx, bankAccountID, date)
+	return reconciliation, err
+}
+
+// PostAdjustment posts the difference between the checkpoint of the day and the computed
+// balance as an adjustment, so both match. Posting again after new movements updates the
+// same adjustment.
+func (s *BankAccountBalanceService) PostAdjustment(ctx context.Context, bankAccountID string, date time.Time) (*entity_finance.AccountReconciliation, error) {
+	reconciliation, adjustment, err := s.reconcile(ctx, bankAccountID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	if reconciliation.Difference == 0 {
+		return nil, errors.New("validation failed: the computed balance already matches the statement")
+	}
+
+	if adjustment == nil {
+		adjustment = &entity_finance.BalanceAdjustment{
+			ID:            reconciliation.CheckpointID,
+			BankAccountID: bankAccountID,
+			CheckpointID:  reconciliation.CheckpointID,
+			Date:          reconciliation.Date,
+			CreatedAt:     time.Now(),
+		}
+		adjustment.UserID, _ = ctx.Value("UserID").(string)
+	}
+	adjustment.Amount = math.Round((adjustment.Amount+reconciliation.Difference)*100) / 100
+	adjustment.UpdatedAt = time.Now()
+
+	if _, err := s.Repo.SaveAdjustment(ctx, adjustment); err != nil {
+		return nil, err
+	}
+
+	reconciliation.ComputedBalance = reconciliation.StatementBalance
+	reconciliation.Difference = 0
+	reconciliation.AdjustmentID = adjustment.ID
+	return reconciliation, nil
+}
+
+// reconcile returns the reconciliation of the checkpoint of the day and the adjustment
+// already posted for it, if any.
+func (s *BankAccountBalanceService) reconcile(ctx context.Context, bankAccountID string, date time.Time) (*entity_finance.AccountReconciliation, *entity_finance.BalanceAdjustment, error) {
+	account, err := s.Accounts.GetBankAccountByID(ctx, &bankAccountID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	checkpoints, err := s.Repo.GetCheckpoints(ctx, bankAccountID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	checkpointID := entity_finance.BalanceCheckpointID(bankAccountID, date)
+	var checkpoint *entity_finance.BalanceCheckpoint
+	for i := range checkpoints {
+		if checkpoints[i].ID == checkpointID {
+			checkpoint = &checkpoints[i]
+		}
+	}
+	if checkpoint == nil {
+		return nil, nil, errors.New("balance checkpoint not found")
+	}
+
+	incomes, err := s.Incomes.GetIncomeRecordsByFilter(ctx, map[string]interface{}{"BankAccountID": bankAccountID})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	expenses, err := s.Expenses.GetExpenseRecordsByFilter(ctx, map[string]interface{}{"BankPaidFrom": bankAccountID})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ledger, err := s.ledger(ctx, incomes, expenses)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reconciliation := entity_finance.NewAccountReconciliation(*checkpoint, ledger.Balance(*account, checkpoint.Date))
+
+	var adjustment *entity_finance.BalanceAdjustment
+	for i := range ledger.Adjustments {
+		if ledger.Adjustments[i].ID == checkpoint.ID {
+			adjustment = &ledger.Adjustments[i]
+			reconciliation.AdjustmentID = adjustment.ID
+		}
+	}
+	return &reconciliation, adjustment, nil
+}
+
+// ledger completes the incomes and expenses with the transfers and adjustments of the user.
+func (s *BankAccountBalanceService) ledger(ctx context.Context, incomes []entity_finance.IncomeRecord, expenses []entity_finance.ExpenseRecord) (*entity_finance.AccountLedger, error) {
+	transfers, err := s.Transfers.GetTransfers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	adjustments, err := s.Repo.GetAdjustments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity_finance.AccountLedger{
+		Incomes:     incomes,
+		Expenses:    entity_finance.WithoutCancelledExpenses(expenses),
+		Transfers:   transfers,
+		Adjustments: adjustments,
+	}, nil
+}
@@ -0,0 +1,163 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIncomeRepository returns the income records it was built with.
+type fakeIncomeRepository struct {
+	records []entity_finance.IncomeRecord
+}
+
+func (r *fakeIncomeRepository) CreateIncomeRecord(ctx context.Context, data *entity_finance.IncomeRecord) (*entity_finance.IncomeRecord, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *fakeIncomeRepository) GetIncomeRecordByID(ctx context.Context, id string) (*entity_finance.IncomeRecord, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *fakeIncomeRepository) GetIncomeRecords(ctx context.Context, params *entity_finance.GetIncomeRecordsQueryParameters) ([]entity_finance.IncomeRecord, error) {
+	return r.records, nil
+}
+
+func (r *fakeIncomeRepository) GetIncomeRecordsByDateRange(ctx context.Context, filter *entity_finance.RecordDateRange) ([]entity_finance.IncomeRecord, error) {
+	records := make([]entity_finance.IncomeRecord, 0)
+	for _, record := range r.records {
+		if inRecordDateRange(filter, record.ReceiptDate, record.BankAccountID, record.Category) {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func (r *fakeIncomeRepository) GetIncomeRecordsPage(ctx context.Context, params *entity_finance.GetIncomeRecordsQueryParameters, page *entity_finance.PageRequest) (*entity_finance.IncomeRecordPage, error) {
+	return &entity_finance.IncomeRecordPage{Records: r.records}, nil
+}
+
+func (r *fakeIncomeRepository) GetIncomeRecordsByFilter(ctx context.Context, filter map[string]interface{}) ([]entity_finance.IncomeRecord, error) {
+	records := make([]entity_finance.IncomeRecord, 0)
+	for _, record := range r.records {
+		if accountID, ok := filter["BankAccountID"]; ok && record.BankAccountID != accountID {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func (r *fakeIncomeRepository) UpdateIncomeRecord(ctx context.Context, id string, data *entity_finance.IncomeRecord) (*entity_finance.IncomeRecord, error) {
+	for i := range r.records {
+		if r.records[i].ID == id {
+			r.records[i] = *data
+			return data, nil
+		}
+	}
+	return nil, errors.New("income record not found")
+}
+
+func (r *fakeIncomeRepository) DeleteIncomeRecord(ctx context.Context, id string) error {
+	return errors.New("not implemented")
+}
+
+// fakeBankAccountBalanceRepository keeps checkpoints and adjustments in memory.
+type fakeBankAccountBalanceRepository struct {
+	checkpoints map[string]entity_finance.BalanceCheckpoint
+	adjustments map[string]entity_finance.BalanceAdjustment
+}
+
+func (r *fakeBankAccountBalanceRepository) SaveCheckpoint(ctx context.Context, data *entity_finance.BalanceCheckpoint) (*entity_finance.BalanceCheckpoint, error) {
+	r.checkpoints[data.ID] = *data
+	return data, nil
+}
+
+func (r *fakeBankAccountBalanceRepository) GetCheckpoints(ctx context.Context, bankAccountID string) ([]entity_finance.BalanceCheckpoint, error) {
+	checkpoints := make([]entity_finance.BalanceCheckpoint, 0)
+	for _, checkpoint := range r.checkpoints {
+		if checkpoint.BankAccountID == bankAccountID {
+			checkpoints = append(checkpoints, checkpoint)
+		}
+	}
+	return checkpoints, nil
+}
+
+func (r *fakeBankAccountBalanceRepository) DeleteCheckpoint(ctx context.Context, id string) error {
+	delete(r.checkpoints, id)
+	return nil
+}
+
+func (r *fakeBankAccountBalanceRepository) SaveAdjustment(ctx context.Context, data *entity_finance.BalanceAdjustment) (*entity_finance.BalanceAdjustment, error) {
+	r.adjustments[data.ID] = *data
+	return data, nil
+}
+
+func (r *fakeBankAccountBalanceRepository) GetAdjustments(ctx context.Context) ([]entity_finance.BalanceAdjustment, error) {
+	adjustments := make([]entity_finance.BalanceAdjustment, 0, len(r.adjustments))
+	for _, adjustment := range r.adjustments {
+		adjustments = append(adjustments, adjustment)
+	}
+	return adjustments, nil
+}
+
+func (r *fakeBankAccountBalanceRepository) DeleteAdjustment(ctx context.Context, id string) error {
+	delete(r.adjustments, id)
+	return nil
+}
+
+func TestBankAccountBalanceService(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "UserID", "user-1")
+	march := func(day int) time.Time { return time.Date(2025, 3, day, 0, 0, 0, 0, time.UTC) }
+
+	expenses := newFakeExpenseRepository()
+	expenses.records["rent"] = entity_finance.ExpenseRecord{ID: "rent", BankPaidFrom: "checking", Amount: 800, DueDate: march(5), PaymentDate: march(5), Status: entity_finance.ExpenseStatusPaid}
+	expenses.records["power"] = entity_finance.ExpenseRecord{ID: "power", BankPaidFrom: "checking", Amount: 150, DueDate: march(20), Status: entity_finance.ExpenseStatusPending}
+
+	repo := &fakeBankAccountBalanceRepository{checkpoints: make(map[string]entity_finance.BalanceCheckpoint), adjustments: make(map[string]entity_finance.BalanceAdjustment)}
+	s := &BankAccountBalanceService{
+		Repo: repo,
+		Accounts: &fakeBankAccountRepository{accounts: map[string]entity_finance.BankAccountRequest{
+			"checking": {ID: "checking", BankAccount: entity_finance.BankAccount{OpeningBalance: 2000, OpeningBalanceDate: march(1)}},
+		}},
+		Incomes: &fakeIncomeRepository{records: []entity_finance.IncomeRecord{
+			{ID: "salary", BankAccountID: "checking", Amount: 3000, ReceiptDate: march(10)},
+		}},
+		Expenses:  expenses,
+		Transfers: &fakeTransferRepository{transfers: make(map[string]entity_finance.Transfer)},
+	}
+
+	balances, err := s.GetBalances(ctx, march(31))
+	require.NoError(t, err)
+	require.Len(t, balances, 1)
+	assert.Equal(t, 4200.0, balances[0].Balance)
+
+	_, err = s.Reconcile(ctx, "checking", march(31))
+	assert.ErrorContains(t, err, "not found")
+
+	_, err = s.CreateCheckpoint(ctx, &entity_finance.BalanceCheckpoint{BankAccountID: "checking", Date: march(31), StatementBalance: 4187.9})
+	require.NoError(t, err)
+
+	reconciliation, err := s.Reconcile(ctx, "checking", march(31))
+	require.NoError(t, err)
+	assert.Equal(t, 4200.0, reconciliation.ComputedBalance)
+	assert.Equal(t, -12.1, reconciliation.Difference)
+
+	reconciliation, err = s.PostAdjustment(ctx, "checking", march(31))
+	require.NoError(t, err)
+	assert.Zero(t, reconciliation.Difference)
+	assert.Equal(t, -12.1, repo.adjustments[reconciliation.AdjustmentID].Amount)
+
+	reconciliation, err = s.Reconcile(ctx, "checking", march(31))
+	require.NoError(t, err)
+	assert.Zero(t, reconciliation.Difference)
+	assert.Equal(t, 4187.9, reconciliation.ComputedBalance)
+
+	_, err = s.PostAdjustment(ctx, "checking", march(31))
+	assert.ErrorContains(t, err, "validation failed")
+}
@@ -0,0 +1,125 @@
+package finance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBankAccountService_DeleteBankAccount(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "UserID", "user-1")
+
+	march := func(day int) time.Time { return time.Date(2025, 3, day, 0, 0, 0, 0, time.UTC) }
+
+	newService := func() (*BankAccountService, *fakeBankAccountRepository, *fakeExpenseRepository, *fakeTransferRepository, *fakeRecurringSeriesRepository) {
+		accounts := &fakeBankAccountRepository{accounts: map[string]entity_finance.BankAccountRequest{
+			"old":     {ID: "old"},
+			"new":     {ID: "new"},
+			"unused":  {ID: "unused"},
+			"archive": {ID: "archive", BankAccount: entity_finance.BankAccount{Archived: true}},
+		}}
+		expenses := newFakeExpenseRepository()
+		expenses.records["rent"] = entity_finance.ExpenseRecord{ID: "rent", Amount: 900, BankPaidFrom: "old"}
+		transfers := &fakeTransferRepository{transfers: map[string]entity_finance.Transfer{
+			"to-new":     {ID: "to-new", FromBankAccountID: "old", ToBankAccountID: "new", Amount: 100, TransferDate: time.Now()},
+			"to-savings": {ID: "to-savings", FromBankAccountID: "savings", ToBankAccountID: "old", Amount: 50, TransferDate: time.Now()},
+		}}
+		series := newFakeRecurringSeriesRepository()
+		series.series["salary"] = entity_finance.RecurringSeries{ID: "salary", Kind: entity_finance.RecurringSeriesIncome, Income: &entity_finance.IncomeRecord{BankAccountID: "old"}}
+
+		balances := &fakeBankAccountBalanceRepository{checkpoints: map[string]entity_finance.BalanceCheckpoint{}, adjustments: map[string]entity_finance.BalanceAdjustment{}}
+		checkpoint := entity_finance.BalanceCheckpoint{ID: entity_finance.BalanceCheckpointID("old", march(31)), BankAccountID: "old", Date: march(31), StatementBalance: 1200}
+		balances.checkpoints[checkpoint.ID] = checkpoint
+		balances.adjustments[checkpoint.ID] = entity_finance.BalanceAdjustment{ID: checkpoint.ID, CheckpointID: checkpoint.ID, BankAccountID: "old", Date: march(31), Amount: 10}
+
+		s := &BankAccountService{
+			Repo:      accounts,
+			Incomes:   &fakeIncomeRepository{records: []entity_finance.IncomeRecord{{ID: "income-1", BankAccountID: "old", Amount: 3000}}},
+			Expenses:  expenses,
+			Transfers: transfers,
+			Series:    series,
+			Balances:  balances,
+			Tx:        &fakeTransactionRunner{},
+			mq:        &fakeMessageQueue{},
+		}
+		return s, accounts, expenses, transfers, series
+	}
+
+	t.Run("blocked while records point at the account", func(t *testing.T) {
+		s, accounts, _, _, _ := newService()
+
+		id := "old"
+		err := s.DeleteBankAccount(ctx, &id)
+		assert.EqualError(t, err, "bank account is in use (incomes: 1, expenses: 1, transfers: 2, recurring series: 1, balance checkpoints: 1, balance adjustments: 1); reassign its records or archive it")
+		assert.Contains(t, accounts.accounts, "old")
+
+		unused := "unused"
+		require.NoError(t, s.DeleteBankAccount(ctx, &unused))
+		assert.NotContains(t, accounts.accounts, "unused")
+	})
+
+	t.Run("reassign moves the records and deletes the account", func(t *testing.T) {
+		s, accounts, expenses, transfers, series := newService()
+		incomes := s.Incomes.(*fakeIncomeRepository)
+
+		id, target := "old", "new"
+		require.NoError(t, s.ReassignBankAccount(ctx, &id, &target))
+
+		assert.NotContains(t, accounts.accounts, "old")
+		assert.Equal(t, "new", incomes.records[0].BankAccountID)
+		assert.Equal(t, "new", expenses.records["rent"].BankPaidFrom)
+		assert.Equal(t, "new", series.series["salary"].Income.BankAccountID)
+		assert.NotContains(t, transfers.transfers, "to-new", "a transfer between the merged accounts is dropped")
+		assert.Equal(t, "new", transfers.transfers["to-savings"].ToBankAccountID)
+
+		balances := s.Balances.(*fakeBankAccountBalanceRepository)
+		moved := entity_finance.BalanceCheckpointID("new", march(31))
+		assert.Len(t, balances.checkpoints, 1)
+		assert.Equal(t, "new", balances.checkpoints[moved].BankAccountID)
+		assert.Equal(t, "new", balances.adjustments[moved].BankAccountID)
+		assert.Equal(t, moved, balances.adjustments[moved].CheckpointID)
+		assert.Len(t, balances.adjustments, 1)
+
+		// income, expense and the kept transfer are replaced; the dropped transfer is only deleted
+		assert.ElementsMatch(t, []string{
+			mq_rk_income_delete, mq_rk_income_create,
+			mq_rk_expense_delete, mq_rk_expense_create,
+			mq_rk_transfer_delete, mq_rk_transfer_delete, mq_rk_transfer_create,
+		}, s.mq.(*fakeMessageQueue).published)
+	})
+
+	t.Run("reassign to an account with a checkpoint on the same day", func(t *testing.T) {
+		s, accounts, expenses, _, _ := newService()
+		balances := s.Balances.(*fakeBankAccountBalanceRepository)
+		taken := entity_finance.BalanceCheckpointID("new", march(31))
+		balances.checkpoints[taken] = entity_finance.BalanceCheckpoint{ID: taken, BankAccountID: "new", Date: march(31), StatementBalance: 500}
+
+		id, target := "old", "new"
+		assert.ErrorContains(t, s.ReassignBankAccount(ctx, &id, &target), "validation failed: reassignTo: bank account already has a balance checkpoint on 2025-03-31")
+		assert.Contains(t, accounts.accounts, "old")
+		assert.Equal(t, "old", expenses.records["rent"].BankPaidFrom)
+		assert.Equal(t, 500.0, balances.checkpoints[taken].StatementBalance)
+	})
+
+	t.Run("reassign to an archived account", func(t *testing.T) {
+		s, accounts, _, _, _ := newService()
+
+		id, target := "old", "archive"
+		assert.ErrorContains(t, s.ReassignBankAccount(ctx, &id, &target), "validation failed: reassignTo: bank account is archived")
+		assert.Contains(t, accounts.accounts, "old")
+	})
+
+	t.Run("archive", func(t *testing.T) {
+		s, accounts, _, _, _ := newService()
+
+		id := "old"
+		archived, err := s.ArchiveBankAccount(ctx, &id)
+		require.NoError(t, err)
+		assert.True(t, archived.Archived)
+		assert.True(t, accounts.accounts["old"].Archived)
+	})
+}
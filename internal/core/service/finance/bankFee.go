@@ -0,0 +1,143 @@
+package finance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	service_profile "github.com/Tomelin/dashfin-backend-app/internal/core/service/profile"
+	"github.com/Tomelin/dashfin-backend-app/pkg/message_queue"
+)
+
+// BankFeeService charges the monthly fee of the bank accounts as expenses paid from them.
+type BankFeeService struct {
+	Accounts entity_finance.BankAccountRepositoryInterface
+	Expenses entity_finance.ExpenseRecordRepositoryInterface
+	Profiles service_profile.ProfilePersonServiceInterface
+	mq       message_queue.MessageQueue
+}
+
+// InitializeBankFeeService creates a new BankFeeService and starts generating the fees of
+// every user on bankFeeSchedule.
+func InitializeBankFeeService(accounts entity_finance.BankAccountRepositoryInterface, expenses entity_finance.ExpenseRecordRepositoryInterface, profiles service_profile.ProfilePersonServiceInterface, mq message_queue.MessageQueue) (entity_finance.BankFeeServiceInterface, error) {
+	if accounts == nil {
+		return nil, errors.New("bank account repository is nil for BankFeeService")
+	}
+	if expenses == nil {
+		return nil, errors.New("expense record repository is nil for BankFeeService")
+	}
+	if profiles == nil {
+		return nil, errors.New("profile service is nil for BankFeeService")
+	}
+	if mq == nil {
+		return nil, errors.New("message queue is nil for BankFeeService")
+	}
+
+	svc := &BankFeeService{
+		Accounts: accounts,
+		Expenses: expenses,
+		Profiles: profiles,
+		mq:       mq,
+	}
+
+	go svc.schedule(context.Background())
+
+	return svc, nil
+}
+
+// GenerateMonthlyFees writes the fees of the accounts of the user due in the month of date up
+// to date, and returns the ones written by this call. A fee already written that month is
+// left as it is, even if it was edited or cancelled since. Fees cannot be deleted, so a
+// missing fee is one that was never written.
+func (s *BankFeeService) GenerateMonthlyFees(ctx context.Context, date time.Time) ([]entity_finance.ExpenseRecord, error) {
+	userID, _ := ctx.Value("UserID").(string)
+	if userID == "" {
+		return nil, errors.New("user id is empty")
+	}
+
+	accounts, err := s.Accounts.GetBankAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var generated []entity_finance.ExpenseRecord
+	for _, account := range accounts {
+		fee := account.MonthlyFeeExpense(userID, date)
+		if fee == nil {
+			continue
+		}
+
+		// The fee of the month has a deterministic ID, so its document is read directly.
+		_, err := s.Expenses.GetExpenseRecordByID(ctx, fee.ID)
+		if err == nil {
+			continue
+		}
+		if !strings.Contains(err.Error(), "not found") {
+			return nil, err
+		}
+
+		fee.CreatedAt = time.Now()
+		fee.UpdatedAt = fee.CreatedAt
+		result, err := s.Expenses.UpdateExpenseRecord(ctx, fee.ID, fee)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write the monthly fee of bank account %s: %w", account.ID, err)
+		}
+
+		b, _ := json.Marshal(result)
+		s.publishMessage(ctx, mq_rk_expense_create, b, "")
+
+		generated = append(generated, *result)
+	}
+
+	return generated, nil
+}
+
+func (s *BankFeeService) schedule(ctx context.Context) {
+	ticker := time.NewTicker(bankFeeSchedule)
+	defer ticker.Stop()
+
+	for {
+		s.generateAll(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// generateAll generates the fees of every user for the month of now and, to catch up on a
+// run missed at the end of the month, for the month before.
+func (s *BankFeeService) generateAll(ctx context.Context, now time.Time) {
+	profiles, err := s.Profiles.GetProfile(ctx)
+	if err != nil {
+		log.Printf("bank fees: failed to list users: %v", err)
+		return
+	}
+
+	firstOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	dates := []time.Time{firstOfMonth.Add(-time.Nanosecond), now}
+
+	for _, profile := range profiles {
+		if profile.UserProviderID == "" {
+			continue
+		}
+
+		userCtx := context.WithValue(ctx, "UserID", profile.UserProviderID)
+		for _, date := range dates {
+			if _, err := s.GenerateMonthlyFees(userCtx, date); err != nil {
+				log.Printf("bank fees: failed to generate the fees of user %s for %s: %v", profile.UserProviderID, date.Format("2006-01"), err)
+			}
+		}
+	}
+}
+
+func (s *BankFeeService) publishMessage(ctx context.Context, routeKey string, body []byte, trace string) error {
+	return s.mq.PublisherWithRouteKey(mq_exchange, routeKey, body, trace)
+}
@@ -0,0 +1,102 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	entity_profile "github.com/Tomelin/dashfin-backend-app/internal/core/entity/profile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProfileService lists the profiles it was built with.
+type fakeProfileService struct {
+	profiles []entity_profile.Profile
+}
+
+func (p *fakeProfileService) CreateProfile(ctx context.Context, data *entity_profile.Profile) (*entity_profile.Profile, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (p *fakeProfileService) GetProfileByID(ctx context.Context, id *string) (*entity_profile.Profile, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (p *fakeProfileService) GetProfile(ctx context.Context) ([]entity_profile.Profile, error) {
+	return p.profiles, nil
+}
+
+func (p *fakeProfileService) GetByFilter(ctx context.Context, data map[string]interface{}) ([]entity_profile.Profile, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (p *fakeProfileService) UpdateProfile(ctx context.Context, data *entity_profile.Profile) (*entity_profile.Profile, error) {
+	return nil, errors.New("not implemented")
+}
+
+func newBankFeeTestService() (*BankFeeService, *fakeExpenseRepository, *fakeMessageQueue) {
+	expenses := newFakeExpenseRepository()
+	mq := &fakeMessageQueue{}
+	s := &BankFeeService{
+		Accounts: &fakeBankAccountRepository{accounts: map[string]entity_finance.BankAccountRequest{
+			"checking": {ID: "checking", BankAccount: entity_finance.BankAccount{MonthlyFee: 29.9, MonthlyFeeDay: 10}},
+			"savings":  {ID: "savings"},
+		}},
+		Expenses: expenses,
+		Profiles: &fakeProfileService{profiles: []entity_profile.Profile{{ID: "profile-1", UserProviderID: "user-1"}, {ID: "profile-2"}}},
+		mq:       mq,
+	}
+	return s, expenses, mq
+}
+
+func TestBankFeeService_GenerateMonthlyFees(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "UserID", "user-1")
+	s, expenses, mq := newBankFeeTestService()
+
+	generated, err := s.GenerateMonthlyFees(ctx, time.Date(2025, 3, 9, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Empty(t, generated, "the fee is not due before its day")
+
+	generated, err = s.GenerateMonthlyFees(ctx, time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, generated, 1)
+	fee := generated[0]
+	assert.Equal(t, "bankfee_checking_2025-03", fee.ID)
+	assert.Equal(t, entity_finance.BankFeeCategory, fee.Category)
+	assert.Equal(t, "checking", fee.BankPaidFrom)
+	assert.Equal(t, entity_finance.ExpenseStatusPaid, fee.Status)
+	assert.Equal(t, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), fee.PaymentDate)
+	assert.Equal(t, "user-1", fee.UserID)
+	assert.Equal(t, []string{mq_rk_expense_create}, mq.published)
+
+	// A cancelled fee stays cancelled when the month is generated again.
+	cancelled := expenses.records[fee.ID]
+	cancelled.Status = entity_finance.ExpenseStatusCancelled
+	expenses.records[fee.ID] = cancelled
+
+	generated, err = s.GenerateMonthlyFees(ctx, time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Empty(t, generated)
+	assert.Len(t, expenses.records, 1)
+	assert.Equal(t, entity_finance.ExpenseStatusCancelled, expenses.records[fee.ID].Status)
+	assert.Len(t, mq.published, 1)
+
+	// Deleting the fee would have it charged again, so it is refused.
+	expenseService := &ExpenseRecordService{Repo: expenses, mq: mq}
+	err = expenseService.DeleteExpenseRecord(ctx, fee.ID)
+	assert.ErrorContains(t, err, "validation failed: a bank fee cannot be deleted")
+	assert.Contains(t, expenses.records, fee.ID)
+}
+
+func TestBankFeeService_GenerateAll(t *testing.T) {
+	s, expenses, _ := newBankFeeTestService()
+
+	s.generateAll(context.Background(), time.Date(2025, 4, 2, 8, 0, 0, 0, time.UTC))
+
+	require.Len(t, expenses.records, 1, "the fee of April is due on the 10th; March is caught up")
+	assert.Contains(t, expenses.records, "bankfee_checking_2025-03")
+	assert.Equal(t, "user-1", expenses.records["bankfee_checking_2025-03"].UserID)
+}
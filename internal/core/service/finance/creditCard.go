@@ -9,16 +9,41 @@ import (
 )
 
 type CreditCardService struct {
-	Repo entity.CreditCardRepositoryInterface
+	Repo         entity.CreditCardRepositoryInterface
+	Expenses     entity.ExpenseRecordRepositoryInterface
+	Installments entity.InstallmentPurchaseRepositoryInterface
+	Invoices     entity.CreditCardInvoiceRepositoryInterface
+	Series       entity.RecurringSeriesRepositoryInterface
+	Tx           entity.TransactionRunner
 }
 
-func InitializeCreditCardService(repo entity.CreditCardRepositoryInterface) (entity.CreditCardServiceInterface, error) {
+func InitializeCreditCardService(repo entity.CreditCardRepositoryInterface, expenses entity.ExpenseRecordRepositoryInterface, installments entity.InstallmentPurchaseRepositoryInterface, invoices entity.CreditCardInvoiceRepositoryInterface, series entity.RecurringSeriesRepositoryInterface, tx entity.TransactionRunner) (entity.CreditCardServiceInterface, error) {
 	if repo == nil {
 		return nil, errors.New("repo is nil")
 	}
+	if expenses == nil {
+		return nil, errors.New("expense record repository is nil for CreditCardService")
+	}
+	if installments == nil {
+		return nil, errors.New("installment purchase repository is nil for CreditCardService")
+	}
+	if invoices == nil {
+		return nil, errors.New("credit card invoice repository is nil for CreditCardService")
+	}
+	if series == nil {
+		return nil, errors.New("recurring series repository is nil for CreditCardService")
+	}
+	if tx == nil {
+		return nil, errors.New("transaction runner is nil for CreditCardService")
+	}
 
 	return &CreditCardService{
-		Repo: repo,
+		Repo:         repo,
+		Expenses:     expenses,
+		Installments: installments,
+		Invoices:     invoices,
+		Series:       series,
+		Tx:           tx,
 	}, nil
 }
 
@@ -109,13 +134,164 @@ func (s *CreditCardService) UpdateCreditCard(ctx context.Context, data *entity.C
 		return nil, fmt.Errorf("invalid data: %w", err)
 	}
 
+	// The alert level is kept by the limit check, not by the client.
+	current, err := s.Repo.GetCreditCardByID(ctx, &data.ID)
+	if err != nil {
+		return nil, err
+	}
+	data.LimitAlertLevel = current.LimitAlertLevel
+
 	return s.Repo.UpdateCreditCard(ctx, data)
 }
 
+// DeleteCreditCard removes a card no record points at. A card in use has to be reassigned
+// or archived instead.
 func (s *CreditCardService) DeleteCreditCard(ctx context.Context, id *string) error {
 	if id == nil || *id == "" {
 		return errors.New("id is empty")
 	}
 
+	if _, err := s.GetCreditCardByID(ctx, id); err != nil {
+		return err
+	}
+
+	references, err := s.references(ctx, *id)
+	if err != nil {
+		return err
+	}
+
+	if err := references.InUseError(); err != nil {
+		return err
+	}
+
 	return s.Repo.DeleteCreditCard(ctx, id)
 }
+
+// ReassignCreditCard moves the purchases of the card to the target card, on the invoices of
+// the target, and deletes it. A card with paid invoices keeps its history and can only be
+// archived.
+func (s *CreditCardService) ReassignCreditCard(ctx context.Context, id, targetID *string) error {
+	if id == nil || *id == "" {
+		return errors.New("id is empty")
+	}
+	if targetID == nil || *targetID == "" {
+		return errors.New("validation failed: reassignTo is required")
+	}
+	if *id == *targetID {
+		return errors.New("validation failed: reassignTo must be another credit card")
+	}
+
+	if _, err := s.GetCreditCardByID(ctx, id); err != nil {
+		return err
+	}
+
+	target, err := s.Repo.GetCreditCardByID(ctx, targetID)
+	if err != nil {
+		return fmt.Errorf("validation failed: reassignTo: %w", err)
+	}
+	if target.Archived {
+		return errors.New("validation failed: reassignTo: credit card is archived")
+	}
+
+	invoices, err := s.Invoices.GetInvoicesByCard(ctx, *id)
+	if err != nil {
+		return err
+	}
+	if len(invoices) > 0 {
+		return errors.New("validation failed: the credit card has paid invoices; archive it instead")
+	}
+
+	references, err := s.references(ctx, *id)
+	if err != nil {
+		return err
+	}
+
+	return s.Tx.Batch(ctx, func(ctx context.Context) error {
+		for _, expense := range references.Expenses {
+			existing := expense
+			expense.CreditCardID = *targetID
+			if err := assignCardInvoice(ctx, s.Repo, &expense); err != nil {
+				return err
+			}
+			if err := checkInvoicesNotPaid(ctx, s.Invoices, &existing, &expense); err != nil {
+				return err
+			}
+			if _, err := s.Expenses.UpdateExpenseRecord(ctx, expense.ID, &expense); err != nil {
+				return err
+			}
+		}
+
+		for _, purchase := range references.InstallmentPurchases {
+			purchase.CreditCardID = *targetID
+			if _, err := s.Installments.UpdateInstallmentPurchase(ctx, &purchase); err != nil {
+				return err
+			}
+		}
+
+		for _, series := range references.Series {
+			series.Expense.CreditCardID = *targetID
+			if _, err := s.Series.UpdateRecurringSeries(ctx, &series); err != nil {
+				return err
+			}
+		}
+
+		return s.Repo.DeleteCreditCard(ctx, id)
+	})
+}
+
+// ArchiveCreditCard keeps the card in the history of its purchases and stops it from being
+// picked for new ones.
+func (s *CreditCardService) ArchiveCreditCard(ctx context.Context, id *string) (*entity.CreditCardRequest, error) {
+	card, err := s.GetCreditCardByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	card.Archived = true
+	return s.Repo.UpdateCreditCard(ctx, card)
+}
+
+// references gathers the records that point at the card.
+func (s *CreditCardService) references(ctx context.Context, id string) (*entity.CreditCardReferences, error) {
+	expenses, err := s.Expenses.GetExpenseRecordsByFilter(ctx, map[string]interface{}{"CreditCardID": id})
+	if err != nil {
+		return nil, err
+	}
+
+	references := &entity.CreditCardReferences{Expenses: expenses}
+
+	purchases, err := s.Installments.GetInstallmentPurchases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, purchase := range purchases {
+		if purchase.CreditCardID == id {
+			references.InstallmentPurchases = append(references.InstallmentPurchases, purchase)
+		}
+	}
+
+	series, err := s.Series.GetRecurringSeriesByKind(ctx, entity.RecurringSeriesExpense)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range series {
+		if item.Expense != nil && item.Expense.CreditCardID == id {
+			references.Series = append(references.Series, item)
+		}
+	}
+
+	return references, nil
+}
+
+// checkCreditCardNotArchived rejects an archived card picked for a new purchase.
+func checkCreditCardNotArchived(ctx context.Context, cards entity.CreditCardRepositoryInterface, id string) error {
+	card, err := cards.GetCreditCardByID(ctx, &id)
+	if err != nil {
+		return fmt.Errorf("validation failed: creditCardId: %w", err)
+	}
+
+	if card.Archived {
+		return errors.New("validation failed: creditCardId: credit card is archived")
+	}
+	return nil
+}
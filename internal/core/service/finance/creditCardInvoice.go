@@ -0,0 +1,278 @@
+package finance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"github.com/Tomelin/dashfin-backend-app/pkg/message_queue"
+)
+
+// CreditCardInvoiceService builds the invoices of a card from its purchases and pays them.
+type CreditCardInvoiceService struct {
+	Repo     entity_finance.CreditCardInvoiceRepositoryInterface
+	Cards    entity_finance.CreditCardRepositoryInterface
+	Expenses entity_finance.ExpenseRecordRepositoryInterface
+	Accounts entity_finance.BankAccountRepositoryInterface
+	Tx       entity_finance.TransactionRunner
+	mq       message_queue.MessageQueue
+}
+
+// InitializeCreditCardInvoiceService creates a new CreditCardInvoiceService and starts the
+// consumer that checks the limit alerts of the cards.
+func InitializeCreditCardInvoiceService(repo entity_finance.CreditCardInvoiceRepositoryInterface, cards entity_finance.CreditCardRepositoryInterface, expenses entity_finance.ExpenseRecordRepositoryInterface, accounts entity_finance.BankAccountRepositoryInterface, tx entity_finance.TransactionRunner, mq message_queue.MessageQueue) (entity_finance.CreditCardInvoiceServiceInterface, error) {
+	if repo == nil {
+		return nil, errors.New("repository is nil for CreditCardInvoiceService")
+	}
+	if cards == nil {
+		return nil, errors.New("credit card repository is nil for CreditCardInvoiceService")
+	}
+	if expenses == nil {
+		return nil, errors.New("expense record repository is nil for CreditCardInvoiceService")
+	}
+	if accounts == nil {
+		return nil, errors.New("bank account repository is nil for CreditCardInvoiceService")
+	}
+	if tx == nil {
+		return nil, errors.New("transaction runner is nil for CreditCardInvoiceService")
+	}
+	if mq == nil {
+		return nil, errors.New("message queue is nil for CreditCardInvoiceService")
+	}
+
+	svc := &CreditCardInvoiceService{
+		Repo:     repo,
+		Cards:    cards,
+		Expenses: expenses,
+		Accounts: accounts,
+		Tx:       tx,
+		mq:       mq,
+	}
+
+	go svc.consumeExpenseEvents(context.Background())
+
+	return svc, nil
+}
+
+// GetInvoices returns the invoices of the card, most recent first, optionally filtered by status.
+func (s *CreditCardInvoiceService) GetInvoices(ctx context.Context, creditCardID string, statuses ...entity_finance.CreditCardInvoiceStatus) ([]entity_finance.CreditCardInvoice, error) {
+	card, err := s.Cards.GetCreditCardByID(ctx, &creditCardID)
+	if err != nil {
+		return nil, err
+	}
+
+	invoices, err := s.buildInvoices(ctx, card)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]entity_finance.CreditCardInvoice, 0, len(invoices))
+	for _, invoice := range invoices {
+		if len(statuses) == 0 || containsInvoiceStatus(statuses, invoice.Status) {
+			result = append(result, *invoice)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Month > result[j].Month
+	})
+	return result, nil
+}
+
+// GetInvoice returns the invoice of the card for the month (YYYY-MM).
+func (s *CreditCardInvoiceService) GetInvoice(ctx context.Context, creditCardID, month string) (*entity_finance.CreditCardInvoice, error) {
+	if _, err := entity_finance.ParseInvoiceMonth(month); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	card, err := s.Cards.GetCreditCardByID(ctx, &creditCardID)
+	if err != nil {
+		return nil, err
+	}
+
+	invoices, err := s.buildInvoices(ctx, card)
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice, ok := invoices[entity_finance.CreditCardInvoiceID(card.ID, month)]; ok {
+		return invoice, nil
+	}
+	return s.newInvoice(ctx, card, month)
+}
+
+// PayInvoice pays the invoice from a bank account. The payment is written as a single expense
+// record leaving the account, so the purchases are not charged to the account one by one.
+func (s *CreditCardInvoiceService) PayInvoice(ctx context.Context, creditCardID, month string, payment *entity_finance.CreditCardInvoicePayment) (*entity_finance.CreditCardInvoice, error) {
+	if payment == nil {
+		return nil, errors.New("payment data is nil")
+	}
+
+	if err := payment.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	// The payment leaves a registered account, so an unknown one is rejected as well.
+	if _, err := s.Accounts.GetBankAccountByID(ctx, &payment.BankAccountID); err != nil {
+		return nil, fmt.Errorf("validation failed: bankAccountId: %w", err)
+	}
+	if err := checkBankAccountNotArchived(ctx, s.Accounts, "bankAccountId", payment.BankAccountID); err != nil {
+		return nil, err
+	}
+
+	// Run as a transaction so two concurrent payments cannot both find the invoice open.
+	var invoice *entity_finance.CreditCardInvoice
+	var result *entity_finance.ExpenseRecord
+	err := s.Tx.RunTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		invoice, err = s.GetInvoice(ctx, creditCardID, month)
+		if err != nil {
+			return err
+		}
+
+		if invoice.Status == entity_finance.CreditCardInvoicePaid {
+			return errors.New("invalid status transition: invoice is already paid")
+		}
+		if invoice.Total <= 0 {
+			return errors.New("validation failed: invoice has no purchases to pay")
+		}
+
+		card, err := s.Cards.GetCreditCardByID(ctx, &creditCardID)
+		if err != nil {
+			return err
+		}
+
+		userID, _ := ctx.Value("UserID").(string)
+		now := time.Now()
+		outflow := &entity_finance.ExpenseRecord{
+			ID:            "invoice_" + invoice.ID,
+			Category:      "credit_card",
+			Description:   fmt.Sprintf("Credit card %s invoice %s", card.LastFourDigits, invoice.Month),
+			DueDate:       invoice.DueDate,
+			Status:        entity_finance.ExpenseStatusPaid,
+			PaymentDate:   payment.PaymentDate,
+			Amount:        invoice.Total,
+			BankPaidFrom:  payment.BankAccountID,
+			PaidInvoiceID: invoice.ID,
+			UserID:        userID,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+
+		result, err = s.Expenses.UpdateExpenseRecord(ctx, outflow.ID, outflow)
+		if err != nil {
+			return err
+		}
+
+		invoice.UserID = userID
+		invoice.Status = entity_finance.CreditCardInvoicePaid
+		invoice.PaidAmount = invoice.Total
+		invoice.PaymentDate = payment.PaymentDate
+		invoice.BankAccountID = payment.BankAccountID
+		invoice.PaymentExpenseID = result.ID
+
+		invoice, err = s.Repo.SaveInvoice(ctx, invoice)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Published once the invoice is paid, so the limit check of the event releases it.
+	b, _ := json.Marshal(result)
+	s.publishMessage(ctx, mq_rk_expense_create, b, "")
+
+	return invoice, nil
+}
+
+// buildInvoices groups the purchases of the card by invoice and merges the stored payments.
+func (s *CreditCardInvoiceService) buildInvoices(ctx context.Context, card *entity_finance.CreditCardRequest) (map[string]*entity_finance.CreditCardInvoice, error) {
+	purchases, err := s.Expenses.GetExpenseRecordsByFilter(ctx, map[string]interface{}{"CreditCardID": card.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	invoices := make(map[string]*entity_finance.CreditCardInvoice)
+	for _, purchase := range purchases {
+		if purchase.StoredStatus() == entity_finance.ExpenseStatusCancelled || purchase.IsInvoicePayment() {
+			continue
+		}
+
+		month := card.InvoiceMonth(purchase.DueDate)
+		id := entity_finance.CreditCardInvoiceID(card.ID, month)
+		invoice, ok := invoices[id]
+		if !ok {
+			invoice, err = s.newInvoice(ctx, card, month)
+			if err != nil {
+				return nil, err
+			}
+			invoices[id] = invoice
+		}
+		invoice.Total = math.Round((invoice.Total+purchase.Amount)*100) / 100
+		invoice.ExpenseCount++
+	}
+
+	stored, err := s.Repo.GetInvoicesByCard(ctx, card.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, paid := range stored {
+		invoice, ok := invoices[paid.ID]
+		if !ok {
+			invoice, err = s.newInvoice(ctx, card, paid.Month)
+			if err != nil {
+				return nil, err
+			}
+			invoices[paid.ID] = invoice
+		}
+		invoice.Status = paid.Status
+		invoice.PaidAmount = paid.PaidAmount
+		invoice.PaymentDate = paid.PaymentDate
+		invoice.BankAccountID = paid.BankAccountID
+		invoice.PaymentExpenseID = paid.PaymentExpenseID
+	}
+
+	now := time.Now()
+	for _, invoice := range invoices {
+		invoice.Status = invoice.StatusAt(now)
+	}
+	return invoices, nil
+}
+
+// newInvoice returns the empty invoice of the card for the month.
+func (s *CreditCardInvoiceService) newInvoice(ctx context.Context, card *entity_finance.CreditCardRequest, month string) (*entity_finance.CreditCardInvoice, error) {
+	parsed, err := entity_finance.ParseInvoiceMonth(month)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	closing, due := card.InvoiceDates(parsed)
+	invoice := &entity_finance.CreditCardInvoice{
+		ID:           entity_finance.CreditCardInvoiceID(card.ID, month),
+		CreditCardID: card.ID,
+		Month:        month,
+		ClosingDate:  closing,
+		DueDate:      due,
+	}
+	invoice.UserID, _ = ctx.Value("UserID").(string)
+	invoice.Status = invoice.StatusAt(time.Now())
+	return invoice, nil
+}
+
+func containsInvoiceStatus(statuses []entity_finance.CreditCardInvoiceStatus, status entity_finance.CreditCardInvoiceStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *CreditCardInvoiceService) publishMessage(ctx context.Context, routeKey string, body []byte, trace string) error {
+	return s.mq.PublisherWithRouteKey(mq_exchange, routeKey, body, trace)
+}
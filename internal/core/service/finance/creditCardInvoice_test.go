@@ -0,0 +1,253 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCreditCardRepository keeps the cards it was built with in memory.
+type fakeCreditCardRepository struct {
+	cards map[string]entity_finance.CreditCardRequest
+}
+
+func (r *fakeCreditCardRepository) CreateCreditCard(ctx context.Context, data *entity_finance.CreditCard) (*entity_finance.CreditCardRequest, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *fakeCreditCardRepository) GetCreditCardByID(ctx context.Context, id *string) (*entity_finance.CreditCardRequest, error) {
+	card, ok := r.cards[*id]
+	if !ok {
+		return nil, errors.New("credit card not found")
+	}
+	return &card, nil
+}
+
+func (r *fakeCreditCardRepository) GetCreditCards(ctx context.Context) ([]entity_finance.CreditCardRequest, error) {
+	cards := make([]entity_finance.CreditCardRequest, 0, len(r.cards))
+	for _, card := range r.cards {
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
+
+func (r *fakeCreditCardRepository) GetByFilter(ctx context.Context, data map[string]interface{}) ([]entity_finance.CreditCardRequest, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *fakeCreditCardRepository) UpdateCreditCard(ctx context.Context, data *entity_finance.CreditCardRequest) (*entity_finance.CreditCardRequest, error) {
+	r.cards[data.ID] = *data
+	return data, nil
+}
+
+func (r *fakeCreditCardRepository) DeleteCreditCard(ctx context.Context, id *string) error {
+	delete(r.cards, *id)
+	return nil
+}
+
+// fakeCreditCardInvoiceRepository keeps invoices in memory.
+type fakeCreditCardInvoiceRepository struct {
+	invoices map[string]entity_finance.CreditCardInvoice
+}
+
+func (r *fakeCreditCardInvoiceRepository) GetInvoiceByID(ctx context.Context, id string) (*entity_finance.CreditCardInvoice, error) {
+	invoice, ok := r.invoices[id]
+	if !ok {
+		return nil, errors.New("credit card invoice not found")
+	}
+	return &invoice, nil
+}
+
+func (r *fakeCreditCardInvoiceRepository) GetInvoicesByCard(ctx context.Context, creditCardID string) ([]entity_finance.CreditCardInvoice, error) {
+	invoices := make([]entity_finance.CreditCardInvoice, 0)
+	for _, invoice := range r.invoices {
+		if invoice.CreditCardID == creditCardID {
+			invoices = append(invoices, invoice)
+		}
+	}
+	return invoices, nil
+}
+
+func (r *fakeCreditCardInvoiceRepository) SaveInvoice(ctx context.Context, data *entity_finance.CreditCardInvoice) (*entity_finance.CreditCardInvoice, error) {
+	r.invoices[data.ID] = *data
+	return data, nil
+}
+
+func TestCreditCardInvoiceService(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "UserID", "user-1")
+	cards := &fakeCreditCardRepository{cards: map[string]entity_finance.CreditCardRequest{
+		"card-1": {ID: "card-1", CreditCard: entity_finance.CreditCard{CardBrand: "visa", LastFourDigits: "1234", InvoiceDueDate: 10, InvoiceClosingDay: 3}},
+	}}
+	accounts := &fakeBankAccountRepository{accounts: map[string]entity_finance.BankAccountRequest{
+		"bank-1":  {ID: "bank-1"},
+		"archive": {ID: "archive", BankAccount: entity_finance.BankAccount{Archived: true}},
+	}}
+
+	newServices := func(t *testing.T) (*ExpenseRecordService, *CreditCardInvoiceService, *fakeExpenseRepository, *fakeMessageQueue) {
+		repo := newFakeExpenseRepository()
+		mq := &fakeMessageQueue{}
+		stored := &fakeCreditCardInvoiceRepository{invoices: make(map[string]entity_finance.CreditCardInvoice)}
+		expenses := &ExpenseRecordService{Repo: repo, Series: newFakeRecurringSeriesRepository(), Cards: cards, Invoices: stored, Accounts: &fakeBankAccountRepository{}, Tx: &fakeTransactionRunner{}, mq: mq}
+		invoices := &CreditCardInvoiceService{Repo: stored, Cards: cards, Expenses: repo, Accounts: accounts, Tx: &fakeTransactionRunner{}, mq: mq}
+
+		for _, purchase := range []struct {
+			day    int
+			amount float64
+		}{{1, 100}, {3, 50.5}, {4, 30}} {
+			_, err := expenses.CreateExpenseRecord(ctx, &entity_finance.ExpenseRecord{
+				Category:     "food",
+				DueDate:      time.Date(2025, 3, purchase.day, 0, 0, 0, 0, time.UTC),
+				Amount:       purchase.amount,
+				CreditCardID: "card-1",
+				UserID:       "user-1",
+			})
+			require.NoError(t, err)
+		}
+		mq.published = nil
+		return expenses, invoices, repo, mq
+	}
+
+	t.Run("purchases are grouped by invoice", func(t *testing.T) {
+		_, s, repo, _ := newServices(t)
+
+		for _, record := range repo.records {
+			assert.Equal(t, entity_finance.ExpenseStatusPaid, record.Status)
+			assert.Empty(t, record.BankPaidFrom)
+		}
+
+		invoices, err := s.GetInvoices(ctx, "card-1")
+		require.NoError(t, err)
+		require.Len(t, invoices, 2)
+
+		assert.Equal(t, "2025-04", invoices[0].Month)
+		assert.Equal(t, 30.0, invoices[0].Total)
+		assert.Equal(t, "2025-03", invoices[1].Month)
+		assert.Equal(t, 150.5, invoices[1].Total)
+		assert.Equal(t, 2, invoices[1].ExpenseCount)
+		assert.Equal(t, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), invoices[1].DueDate)
+		assert.Equal(t, entity_finance.CreditCardInvoiceClosed, invoices[1].Status)
+
+		paid, err := s.GetInvoices(ctx, "card-1", entity_finance.CreditCardInvoicePaid)
+		require.NoError(t, err)
+		assert.Empty(t, paid)
+	})
+
+	t.Run("paying writes a single outflow", func(t *testing.T) {
+		_, s, repo, mq := newServices(t)
+		payment := &entity_finance.CreditCardInvoicePayment{BankAccountID: "bank-1", PaymentDate: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)}
+
+		invoice, err := s.PayInvoice(ctx, "card-1", "2025-03", payment)
+		require.NoError(t, err)
+		assert.Equal(t, entity_finance.CreditCardInvoicePaid, invoice.Status)
+		assert.Equal(t, 150.5, invoice.PaidAmount)
+
+		outflow := repo.records[invoice.PaymentExpenseID]
+		assert.Equal(t, 150.5, outflow.Amount)
+		assert.Equal(t, "bank-1", outflow.BankPaidFrom)
+		assert.True(t, outflow.IsInvoicePayment())
+		assert.Len(t, mq.published, 1)
+		assert.Len(t, entity_finance.WithoutInvoicePayments(mapValues(repo.records)), 3)
+
+		invoices, err := s.GetInvoices(ctx, "card-1", entity_finance.CreditCardInvoicePaid)
+		require.NoError(t, err)
+		require.Len(t, invoices, 1)
+		assert.Equal(t, 150.5, invoices[0].Total)
+
+		_, err = s.PayInvoice(ctx, "card-1", "2025-03", payment)
+		assert.ErrorContains(t, err, "invalid status transition")
+	})
+
+	t.Run("a paid invoice takes no new purchases", func(t *testing.T) {
+		expenses, s, repo, _ := newServices(t)
+		_, err := s.PayInvoice(ctx, "card-1", "2025-03", &entity_finance.CreditCardInvoicePayment{BankAccountID: "bank-1", PaymentDate: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)})
+		require.NoError(t, err)
+
+		_, err = expenses.CreateExpenseRecord(ctx, &entity_finance.ExpenseRecord{
+			Category:     "food",
+			DueDate:      time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC),
+			Amount:       20,
+			CreditCardID: "card-1",
+			UserID:       "user-1",
+		})
+		assert.ErrorContains(t, err, "validation failed: the credit card invoice 2025-03 is already paid")
+
+		// The next invoice is still open.
+		_, err = expenses.CreateExpenseRecord(ctx, &entity_finance.ExpenseRecord{
+			Category:     "food",
+			DueDate:      time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC),
+			Amount:       20,
+			CreditCardID: "card-1",
+			UserID:       "user-1",
+		})
+		require.NoError(t, err)
+
+		invoices, err := s.GetInvoices(ctx, "card-1", entity_finance.CreditCardInvoicePaid)
+		require.NoError(t, err)
+		require.Len(t, invoices, 1)
+		assert.Equal(t, invoices[0].PaidAmount, invoices[0].Total)
+		assert.Len(t, entity_finance.WithoutInvoicePayments(mapValues(repo.records)), 4)
+	})
+
+	t.Run("purchases of a paid invoice keep their amount", func(t *testing.T) {
+		expenses, s, repo, _ := newServices(t)
+		_, err := s.PayInvoice(ctx, "card-1", "2025-03", &entity_finance.CreditCardInvoicePayment{BankAccountID: "bank-1", PaymentDate: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)})
+		require.NoError(t, err)
+
+		var purchase entity_finance.ExpenseRecord
+		for _, record := range repo.records {
+			if record.Amount == 100 {
+				purchase = record
+			}
+		}
+
+		purchase.Description = "groceries"
+		_, err = expenses.UpdateExpenseRecord(ctx, purchase.ID, &purchase)
+		require.NoError(t, err, "a change that keeps the total is allowed")
+
+		purchase.Amount = 120
+		_, err = expenses.UpdateExpenseRecord(ctx, purchase.ID, &purchase)
+		assert.ErrorContains(t, err, "validation failed: the credit card invoice 2025-03 is already paid")
+
+		moved := repo.records[purchase.ID]
+		moved.DueDate = time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC)
+		_, err = expenses.UpdateExpenseRecord(ctx, moved.ID, &moved)
+		assert.ErrorContains(t, err, "validation failed: the credit card invoice 2025-03 is already paid")
+		assert.Equal(t, 100.0, repo.records[purchase.ID].Amount)
+	})
+
+	t.Run("paying from an unknown or archived account", func(t *testing.T) {
+		_, s, repo, mq := newServices(t)
+		paymentDate := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+
+		_, err := s.PayInvoice(ctx, "card-1", "2025-03", &entity_finance.CreditCardInvoicePayment{BankAccountID: "missing", PaymentDate: paymentDate})
+		assert.ErrorContains(t, err, "validation failed: bankAccountId: bank account not found")
+
+		_, err = s.PayInvoice(ctx, "card-1", "2025-03", &entity_finance.CreditCardInvoicePayment{BankAccountID: "archive", PaymentDate: paymentDate})
+		assert.ErrorContains(t, err, "validation failed: bankAccountId: bank account is archived")
+
+		assert.Len(t, repo.records, 3)
+		assert.Empty(t, mq.published)
+	})
+
+	t.Run("card purchases are paid through the invoice", func(t *testing.T) {
+		expenses, _, repo, _ := newServices(t)
+
+		for id := range repo.records {
+			_, err := expenses.PayExpenseRecord(ctx, id, &entity_finance.ExpensePayment{BankPaidFrom: "bank-1", PaymentDate: time.Now()})
+			assert.ErrorContains(t, err, "paid through the invoice")
+		}
+	})
+}
+
+func mapValues(records map[string]entity_finance.ExpenseRecord) []entity_finance.ExpenseRecord {
+	values := make([]entity_finance.ExpenseRecord, 0, len(records))
+	for _, record := range records {
+		values = append(values, record)
+	}
+	return values
+}
@@ -0,0 +1,129 @@
+package finance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+)
+
+// GetCreditCardLimit returns how much of the limit of the card is committed and available.
+func (s *CreditCardInvoiceService) GetCreditCardLimit(ctx context.Context, creditCardID string) (*entity_finance.CreditCardLimit, error) {
+	card, err := s.Cards.GetCreditCardByID(ctx, &creditCardID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.calculateLimit(ctx, card)
+}
+
+// GetCreditCardLimits returns the limit of every card of the user.
+func (s *CreditCardInvoiceService) GetCreditCardLimits(ctx context.Context) ([]entity_finance.CreditCardLimit, error) {
+	cards, err := s.Cards.GetCreditCards(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := make([]entity_finance.CreditCardLimit, 0, len(cards))
+	for i := range cards {
+		limit, err := s.calculateLimit(ctx, &cards[i])
+		if err != nil {
+			return nil, err
+		}
+		limits = append(limits, *limit)
+	}
+	return limits, nil
+}
+
+func (s *CreditCardInvoiceService) calculateLimit(ctx context.Context, card *entity_finance.CreditCardRequest) (*entity_finance.CreditCardLimit, error) {
+	purchases, err := s.Expenses.GetExpenseRecordsByFilter(ctx, map[string]interface{}{"CreditCardID": card.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := s.Repo.GetInvoicesByCard(ctx, card.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	paidInvoices := make(map[string]bool)
+	for _, invoice := range stored {
+		if invoice.Status == entity_finance.CreditCardInvoicePaid {
+			paidInvoices[invoice.ID] = true
+		}
+	}
+
+	limit := entity_finance.CalculateCreditCardLimit(*card, purchases, paidInvoices, time.Now())
+	return &limit, nil
+}
+
+// checkLimitAlert publishes an alert when the utilization of the card reached a threshold
+// above the one last alerted, and stores the threshold reached as the new alert level.
+func (s *CreditCardInvoiceService) checkLimitAlert(ctx context.Context, creditCardID string) error {
+	card, err := s.Cards.GetCreditCardByID(ctx, &creditCardID)
+	if err != nil {
+		return err
+	}
+
+	limit, err := s.calculateLimit(ctx, card)
+	if err != nil {
+		return err
+	}
+
+	level := card.LimitAlertThreshold(limit.UtilizationPercent)
+	if level == card.LimitAlertLevel {
+		return nil
+	}
+
+	if level > card.LimitAlertLevel {
+		userID, _ := ctx.Value("UserID").(string)
+		b, _ := json.Marshal(entity_finance.CreditCardLimitAlert{
+			UserID:          userID,
+			Threshold:       level,
+			CreditCardLimit: *limit,
+			CreatedAt:       time.Now(),
+		})
+		if err := s.publishMessage(ctx, mq_rk_credit_card_limit, b, ""); err != nil {
+			return fmt.Errorf("failed to publish credit card limit alert: %w", err)
+		}
+	}
+
+	card.LimitAlertLevel = level
+	_, err = s.Cards.UpdateCreditCard(ctx, card)
+	return err
+}
+
+func (s *CreditCardInvoiceService) consumeExpenseEvents(ctx context.Context) {
+	if err := s.mq.Consumer(ctx, mq_exchange, mq_queue_credit_card, s.processExpenseEvent); err != nil {
+		log.Printf("credit card consumer stopped: %v", err)
+	}
+}
+
+// processExpenseEvent checks the limit alerts of the card charged by, or paid by, the
+// expense record of the event. Events of deleted cards are acknowledged.
+func (s *CreditCardInvoiceService) processExpenseEvent(body []byte, traceID string) error {
+	var record entity_finance.ExpenseRecord
+	if err := json.Unmarshal(body, &record); err != nil {
+		return fmt.Errorf("erro ao deserializar: %w", err)
+	}
+
+	creditCardID := record.CreditCardID
+	if record.IsInvoicePayment() {
+		creditCardID = entity_finance.CreditCardIDOfInvoice(record.PaidInvoiceID)
+	}
+	if creditCardID == "" {
+		return nil
+	}
+
+	ctx := context.WithValue(context.Background(), "UserID", record.UserID)
+
+	err := s.checkLimitAlert(ctx, creditCardID)
+	if err != nil && strings.Contains(err.Error(), "not found") {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,72 @@
+package finance
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreditCardInvoiceService_LimitAlerts(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "UserID", "user-1")
+	cards := &fakeCreditCardRepository{cards: map[string]entity_finance.CreditCardRequest{
+		"card-1": {ID: "card-1", CreditCard: entity_finance.CreditCard{CardBrand: "visa", LastFourDigits: "1234", InvoiceDueDate: 10, CreditLimit: 200, LimitAlertThresholds: []float64{50, 80}}},
+	}}
+	repo := newFakeExpenseRepository()
+	mq := &fakeMessageQueue{}
+	s := &CreditCardInvoiceService{Repo: &fakeCreditCardInvoiceRepository{invoices: make(map[string]entity_finance.CreditCardInvoice)}, Cards: cards, Expenses: repo, Tx: &fakeTransactionRunner{}, mq: mq}
+
+	purchase := func(id string, amount float64) []byte {
+		record := entity_finance.ExpenseRecord{ID: id, Category: "food", DueDate: time.Now(), Amount: amount, CreditCardID: "card-1", UserID: "user-1"}
+		repo.records[id] = record
+		b, _ := json.Marshal(record)
+		return b
+	}
+
+	require.NoError(t, s.processExpenseEvent(purchase("a", 120), ""))
+	assert.Equal(t, []string{mq_rk_credit_card_limit}, mq.published)
+	assert.Equal(t, 50.0, cards.cards["card-1"].LimitAlertLevel)
+
+	require.NoError(t, s.processExpenseEvent(purchase("b", 10), ""))
+	assert.Len(t, mq.published, 1)
+
+	require.NoError(t, s.processExpenseEvent(purchase("c", 40), ""))
+	assert.Len(t, mq.published, 2)
+	assert.Equal(t, 80.0, cards.cards["card-1"].LimitAlertLevel)
+
+	limit, err := s.GetCreditCardLimit(ctx, "card-1")
+	require.NoError(t, err)
+	assert.Equal(t, 170.0, limit.CommittedAmount)
+	assert.Equal(t, 30.0, limit.AvailableLimit)
+	assert.Equal(t, 85.0, limit.UtilizationPercent)
+
+	delete(repo.records, "c")
+	delete(repo.records, "b")
+	require.NoError(t, s.processExpenseEvent(purchase("a", 60), ""))
+	assert.Len(t, mq.published, 2)
+	assert.Equal(t, 0.0, cards.cards["card-1"].LimitAlertLevel)
+
+	require.NoError(t, s.processExpenseEvent(purchase("d", 50), ""))
+	assert.Len(t, mq.published, 3)
+
+	limits, err := s.GetCreditCardLimits(ctx)
+	require.NoError(t, err)
+	require.Len(t, limits, 1)
+	assert.Equal(t, 55.0, limits[0].UtilizationPercent)
+}
+
+func TestCreditCardInvoiceService_ProcessExpenseEventIgnoresOtherRecords(t *testing.T) {
+	mq := &fakeMessageQueue{}
+	s := &CreditCardInvoiceService{Repo: &fakeCreditCardInvoiceRepository{invoices: make(map[string]entity_finance.CreditCardInvoice)}, Cards: &fakeCreditCardRepository{cards: map[string]entity_finance.CreditCardRequest{}}, Expenses: newFakeExpenseRepository(), Tx: &fakeTransactionRunner{}, mq: mq}
+
+	b, _ := json.Marshal(entity_finance.ExpenseRecord{ID: "x", BankPaidFrom: "bank-1", UserID: "user-1"})
+	assert.NoError(t, s.processExpenseEvent(b, ""))
+
+	b, _ = json.Marshal(entity_finance.ExpenseRecord{ID: "y", CreditCardID: "deleted", UserID: "user-1"})
+	assert.NoError(t, s.processExpenseEvent(b, ""))
+	assert.Empty(t, mq.published)
+}
@@ -0,0 +1,74 @@
+package finance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreditCardService_DeleteCreditCard(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "UserID", "user-1")
+
+	newService := func() (*CreditCardService, *fakeCreditCardRepository, *fakeExpenseRepository, *fakeInstallmentPurchaseRepository, *fakeCreditCardInvoiceRepository) {
+		cards := &fakeCreditCardRepository{cards: map[string]entity_finance.CreditCardRequest{
+			"old": {ID: "old", CreditCard: entity_finance.CreditCard{InvoiceDueDate: 10, InvoiceClosingDay: 3}},
+			"new": {ID: "new", CreditCard: entity_finance.CreditCard{InvoiceDueDate: 20, InvoiceClosingDay: 13}},
+		}}
+		expenses := newFakeExpenseRepository()
+		expenses.records["market"] = entity_finance.ExpenseRecord{
+			ID: "market", Amount: 80, CreditCardID: "old", DueDate: time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC),
+			InvoiceID: entity_finance.CreditCardInvoiceID("old", "2025-04"),
+		}
+		installments := &fakeInstallmentPurchaseRepository{purchases: map[string]entity_finance.InstallmentPurchase{
+			"tv": {ID: "tv", CreditCardID: "old"},
+		}}
+		invoices := &fakeCreditCardInvoiceRepository{invoices: make(map[string]entity_finance.CreditCardInvoice)}
+
+		s := &CreditCardService{
+			Repo:         cards,
+			Expenses:     expenses,
+			Installments: installments,
+			Invoices:     invoices,
+			Series:       newFakeRecurringSeriesRepository(),
+			Tx:           &fakeTransactionRunner{},
+		}
+		return s, cards, expenses, installments, invoices
+	}
+
+	t.Run("blocked while purchases point at the card", func(t *testing.T) {
+		s, cards, _, _, _ := newService()
+
+		id := "old"
+		assert.EqualError(t, s.DeleteCreditCard(ctx, &id), "credit card is in use (expenses: 1, installment purchases: 1); reassign its records or archive it")
+		assert.Contains(t, cards.cards, "old")
+	})
+
+	t.Run("reassign moves the purchases to the invoices of the target", func(t *testing.T) {
+		s, cards, expenses, installments, _ := newService()
+
+		id, target := "old", "new"
+		require.NoError(t, s.ReassignCreditCard(ctx, &id, &target))
+
+		assert.NotContains(t, cards.cards, "old")
+		assert.Equal(t, "new", expenses.records["market"].CreditCardID)
+		assert.Equal(t, entity_finance.CreditCardInvoiceID("new", "2025-03"), expenses.records["market"].InvoiceID)
+		assert.Equal(t, "new", installments.purchases["tv"].CreditCardID)
+	})
+
+	t.Run("a card with paid invoices can only be archived", func(t *testing.T) {
+		s, cards, _, _, invoices := newService()
+		invoices.invoices["old_2025-02"] = entity_finance.CreditCardInvoice{ID: "old_2025-02", CreditCardID: "old", Status: entity_finance.CreditCardInvoicePaid}
+
+		id, target := "old", "new"
+		assert.ErrorContains(t, s.ReassignCreditCard(ctx, &id, &target), "archive it instead")
+
+		archived, err := s.ArchiveCreditCard(ctx, &id)
+		require.NoError(t, err)
+		assert.True(t, archived.Archived)
+		assert.True(t, cards.cards["old"].Archived)
+	})
+}
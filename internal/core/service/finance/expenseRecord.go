@@ -6,28 +6,57 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"strings"
 	"time"
 
 	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
-	"github.com/Tomelin/dashfin-backend-app/pkg/llm"
+	"github.com/Tomelin/dashfin-backend-app/internal/core/service/finance/nfce"
 	"github.com/Tomelin/dashfin-backend-app/pkg/message_queue"
 )
 
 // ExpenseRecordService provides business logic for expense records.
 type ExpenseRecordService struct {
-	Repo entity_finance.ExpenseRecordRepositoryInterface
-	mq   message_queue.MessageQueue
+	Repo       entity_finance.ExpenseRecordRepositoryInterface
+	Series     entity_finance.RecurringSeriesRepositoryInterface
+	Cards      entity_finance.CreditCardRepositoryInterface
+	Invoices   entity_finance.CreditCardInvoiceRepositoryInterface
+	Accounts   entity_finance.BankAccountRepositoryInterface
+	Tx         entity_finance.TransactionRunner
+	mq         message_queue.MessageQueue
+	nfceParser entity_finance.NFCeParser
 }
 
 // InitializeExpenseRecordService creates a new ExpenseRecordService.
-func InitializeExpenseRecordService(repo entity_finance.ExpenseRecordRepositoryInterface, mq message_queue.MessageQueue) (entity_finance.ExpenseRecordServiceInterface, error) {
+func InitializeExpenseRecordService(repo entity_finance.ExpenseRecordRepositoryInterface, series entity_finance.RecurringSeriesRepositoryInterface, cards entity_finance.CreditCardRepositoryInterface, invoices entity_finance.CreditCardInvoiceRepositoryInterface, accounts entity_finance.BankAccountRepositoryInterface, tx entity_finance.TransactionRunner, mq message_queue.MessageQueue) (entity_finance.ExpenseRecordServiceInterface, error) {
 	if repo == nil {
 		return nil, errors.New("repository is nil for ExpenseRecordService")
 	}
+	if series == nil {
+		return nil, errors.New("recurring series repository is nil for ExpenseRecordService")
+	}
+	if cards == nil {
+		return nil, errors.New("credit card repository is nil for ExpenseRecordService")
+	}
+	if invoices == nil {
+		return nil, errors.New("credit card invoice repository is nil for ExpenseRecordService")
+	}
+	if accounts == nil {
+		return nil, errors.New("bank account repository is nil for ExpenseRecordService")
+	}
+	if tx == nil {
+		return nil, errors.New("transaction runner is nil for ExpenseRecordService")
+	}
 	return &ExpenseRecordService{
-		Repo: repo,
-		mq:   mq,
+		Repo:       repo,
+		Series:     series,
+		Cards:      cards,
+		Invoices:   invoices,
+		Accounts:   accounts,
+		Tx:         tx,
+		mq:         mq,
+		nfceParser: nfce.InitializeNFCeParser(),
 	}, nil
 }
 
@@ -37,6 +66,8 @@ func (s *ExpenseRecordService) CreateExpenseRecord(ctx context.Context, data *en
 		return nil, errors.New("expense record data is nil")
 	}
 
+	data.NormalizeStatus()
+	data.SeriesID = "" // Set when the record is written as an occurrence of a series.
 	if err := data.Validate(); err != nil {
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
@@ -46,34 +77,27 @@ func (s *ExpenseRecordService) CreateExpenseRecord(ctx context.Context, data *en
 		return nil, errors.New("userID is required in expense record data")
 	}
 
+	if data.IsCardPurchase() {
+		if err := checkCreditCardNotArchived(ctx, s.Cards, data.CreditCardID); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkBankAccountNotArchived(ctx, s.Accounts, "bankPaidFrom", data.BankPaidFrom); err != nil {
+		return nil, err
+	}
+
 	// For new records, ensure CreatedAt and UpdatedAt are set.
 	// ID will be set by the repository.
 	data.CreatedAt = time.Now()
 	data.UpdatedAt = time.Now()
 
-	// If the expense is recurring and RecurrenceCount is greater than zero,
-	// we might need to handle the creation of future occurrences here or in the repository.
-	if data.IsRecurring && data.RecurrenceCount > 0 {
-		expensesCreated := make([]entity_finance.ExpenseRecord, 0)
-
-		snapDueDate := data.DueDate
-
-		for i := 0; i < data.RecurrenceCount; i++ {
-
-			data.RecurrenceNumber = i + 1
-			if i == 0 {
-				result, _ := s.Repo.CreateExpenseRecord(ctx, data)
-				expensesCreated = append(expensesCreated, *result)
-			} else {
-				data.DueDate = snapDueDate.AddDate(0, i, 0) // Add i months
-				result, _ := s.Repo.CreateExpenseRecord(ctx, data)
-				expensesCreated = append(expensesCreated, *result)
-			}
+	// A recurring expense is stored as a series; its occurrences are written on demand.
+	if rule := data.EffectiveRecurrence(); rule != nil {
+		return s.createExpenseSeries(ctx, data, *rule)
+	}
 
-			b, _ := json.Marshal(expensesCreated[i])
-			s.publishMessage(ctx, mq_rk_expense_create, b, "")
-		}
-		return &expensesCreated[0], nil // Return the first created expense record
+	if err := s.assignInvoice(ctx, nil, data); err != nil {
+		return nil, err
 	}
 
 	result, err := s.Repo.CreateExpenseRecord(ctx, data)
@@ -128,41 +152,49 @@ func (s *ExpenseRecordService) GetExpenseRecords(ctx context.Context) ([]entity_
 	return records, nil
 }
 
-func (s *ExpenseRecordService) GetExpenseRecordsByDate(ctx context.Context, filter *entity_finance.ExpenseRecordQueryByDate) ([]entity_finance.ExpenseRecord, error) {
+// GetExpenseRecordsPage retrieves one page of the expense records of the authenticated user,
+// optionally bounded by due date.
+func (s *ExpenseRecordService) GetExpenseRecordsPage(ctx context.Context, filter *entity_finance.ExpenseRecordQueryByDate, page *entity_finance.PageRequest) (*entity_finance.ExpenseRecordPage, error) {
+	if page == nil {
+		return nil, errors.New("page request is nil")
+	}
+
 	userIDFromCtx := ctx.Value("UserID")
 	if userIDFromCtx == nil || userIDFromCtx.(string) == "" {
 		return nil, errors.New("userID not found in context")
 	}
 
-	records, err := s.Repo.GetExpenseRecords(ctx)
-	if err != nil {
-		return nil, err
+	if filter != nil {
+		if _, err := entity_finance.ParseRecordDateRange(filter.StartDate, filter.EndDate); err != nil {
+			return nil, err
+		}
 	}
 
-	var filteredRecords []entity_finance.ExpenseRecord
-	startDate, _ := time.Parse("2006-01-02", filter.StartDate)
-	endDate, _ := time.Parse("2006-01-02", filter.EndDate)
+	return s.Repo.GetExpenseRecordsPage(ctx, filter, page)
+}
 
-	for _, record := range records {
+func (s *ExpenseRecordService) GetExpenseRecordsByDate(ctx context.Context, filter *entity_finance.ExpenseRecordQueryByDate) ([]entity_finance.ExpenseRecord, error) {
+	dateRange, err := entity_finance.ParseRecordDateRange(filter.StartDate, filter.EndDate)
+	if err != nil {
+		return nil, err
+	}
 
-		if record.DueDate.IsZero() {
-			return nil, errors.New("dueDate must be in ISO 8601 format (YYYY-MM-DD)")
-		}
-		if startDate != (time.Time{}) && record.DueDate.Before(startDate) {
-			continue
-		}
+	return s.GetExpenseRecordsByDateRange(ctx, dateRange)
+}
 
-		if endDate != (time.Time{}) && record.DueDate.After(endDate) {
-			continue
-		}
+// GetExpenseRecordsByDateRange retrieves the expense records of the authenticated user due
+// within the range. The range is queried on the database, not filtered in memory.
+func (s *ExpenseRecordService) GetExpenseRecordsByDateRange(ctx context.Context, filter *entity_finance.RecordDateRange) ([]entity_finance.ExpenseRecord, error) {
+	userIDFromCtx := ctx.Value("UserID")
+	if userIDFromCtx == nil || userIDFromCtx.(string) == "" {
+		return nil, errors.New("userID not found in context")
+	}
 
-		if record.DueDate.After(endDate) {
-			continue
-		}
-		filteredRecords = append(filteredRecords, record)
+	if err := filter.Validate(); err != nil {
+		return nil, err
 	}
 
-	return filteredRecords, nil
+	return s.Repo.GetExpenseRecordsByDateRange(ctx, filter)
 }
 
 // GetExpenseRecordsByFilter retrieves expense records based on a filter for the authenticated user.
@@ -175,13 +207,30 @@ func (s *ExpenseRecordService) GetExpenseRecordsByFilter(ctx context.Context, fi
 		return nil, errors.New("userID not found in context")
 	}
 
-	// Ensure the filter always includes the UserID from context to scope results.
-	filter["userId"] = userIDFromCtx.(string)
+	// The filter belongs to the caller; the status and user entries go into a copy.
+	query := make(map[string]interface{}, len(filter)+1)
+	for field, value := range filter {
+		query[field] = value
+	}
+
+	// The status is derived from DueDate, so it is applied after the query.
+	statuses, err := statusFilter(query)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
 
-	records, err := s.Repo.GetExpenseRecordsByFilter(ctx, filter)
+	var records []entity_finance.ExpenseRecord
+	if len(query) == 0 {
+		records, err = s.Repo.GetExpenseRecords(ctx)
+	} else {
+		// Ensure the filter always includes the UserID from context to scope results.
+		query["userId"] = userIDFromCtx.(string)
+		records, err = s.Repo.GetExpenseRecordsByFilter(ctx, query)
+	}
 	if err != nil {
 		return nil, err
 	}
+	records = entity_finance.FilterExpenseRecordsByStatus(records, time.Now(), statuses...)
 	// if len(records) == 0 { // Similar to GetExpenseRecords, decide if "not found" is an error or empty slice.
 	// 	return nil, errors.New("no expense records found matching the filter for the user")
 	// }
@@ -190,6 +239,24 @@ func (s *ExpenseRecordService) GetExpenseRecordsByFilter(ctx context.Context, fi
 
 // UpdateExpenseRecord handles updating an existing expense record.
 func (s *ExpenseRecordService) UpdateExpenseRecord(ctx context.Context, id string, data *entity_finance.ExpenseRecord) (*entity_finance.ExpenseRecord, error) {
+	existingRecord, err := s.prepareUpdate(ctx, id, data)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.Repo.UpdateExpenseRecord(ctx, id, data)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishAmountChange(ctx, existingRecord, result)
+
+	return result, nil
+}
+
+// prepareUpdate validates the update of the record and fills in the fields the payload cannot
+// change. It returns the stored record; nothing is written or published.
+func (s *ExpenseRecordService) prepareUpdate(ctx context.Context, id string, data *entity_finance.ExpenseRecord) (*entity_finance.ExpenseRecord, error) {
 	if id == "" {
 		return nil, errors.New("id is empty for update")
 	}
@@ -218,21 +285,121 @@ func (s *ExpenseRecordService) UpdateExpenseRecord(ctx context.Context, id strin
 		return nil, errors.New("expense record not found or access denied for update")
 	}
 
+	data.NormalizeStatus()
+	if err := existingRecord.StoredStatus().CanTransitionTo(data.Status); err != nil {
+		return nil, err
+	}
+
 	// Ensure critical fields like ID and UserID are not changed by the update payload directly,
 	// or are consistent.
 	data.ID = existingRecord.ID               // Preserve original ID
 	data.UserID = existingRecord.UserID       // Preserve original UserID
 	data.CreatedAt = existingRecord.CreatedAt // Preserve original CreatedAt
 	data.UpdatedAt = time.Now()               // Update timestamp
+	data.NfceAccessKey = existingRecord.NfceAccessKey
+	data.PaidInvoiceID = existingRecord.PaidInvoiceID
+	data.InstallmentPurchaseID = existingRecord.InstallmentPurchaseID
+	data.InstallmentNumber = existingRecord.InstallmentNumber
+	data.InstallmentCount = existingRecord.InstallmentCount
+	if existingRecord.SeriesID != "" {
+		// The position in the series is owned by the series, not by the payload.
+		data.SeriesID = existingRecord.SeriesID
+		data.IsRecurring = existingRecord.IsRecurring
+		data.RecurrenceNumber = existingRecord.RecurrenceNumber
+		data.RecurrenceCount = existingRecord.RecurrenceCount
+	}
+
+	if err := s.assignInvoice(ctx, existingRecord, data); err != nil {
+		return nil, err
+	}
+
+	return existingRecord, nil
+}
+
+// assignInvoice charges the record to its invoice. existing is the stored record on an
+// update, nil for a new one.
+func (s *ExpenseRecordService) assignInvoice(ctx context.Context, existing, record *entity_finance.ExpenseRecord) error {
+	if err := assignCardInvoice(ctx, s.Cards, record); err != nil {
+		return err
+	}
+	return checkInvoicesNotPaid(ctx, s.Invoices, existing, record)
+}
+
+// assignCardInvoice charges a card purchase to the invoice of its purchase date (DueDate). The
+// purchase is settled by the card, so it is paid and leaves the bank accounts to the invoice payment.
+func assignCardInvoice(ctx context.Context, cards entity_finance.CreditCardRepositoryInterface, record *entity_finance.ExpenseRecord) error {
+	if !record.IsCardPurchase() {
+		record.InvoiceID = ""
+		return nil
+	}
+
+	card, err := cards.GetCreditCardByID(ctx, &record.CreditCardID)
+	if err != nil {
+		return fmt.Errorf("validation failed: creditCardId: %w", err)
+	}
 
-	if data.Amount != existingRecord.Amount {
-		old, _ := json.Marshal(existingRecord)
-		new, _ := json.Marshal(data)
-		s.publishMessage(ctx, mq_rk_expense_delete, old, "")
-		s.publishMessage(ctx, mq_rk_expense_create, new, "")
+	record.InvoiceID = entity_finance.CreditCardInvoiceID(card.ID, card.InvoiceMonth(record.DueDate))
+	if record.StoredStatus() != entity_finance.ExpenseStatusCancelled {
+		record.Status = entity_finance.ExpenseStatusPaid
+		if record.PaymentDate.IsZero() {
+			record.PaymentDate = record.DueDate
+		}
 	}
+	return nil
+}
 
-	return s.Repo.UpdateExpenseRecord(ctx, id, data)
+// checkInvoicesNotPaid rejects a change to the purchases of a paid invoice: a purchase
+// charged to it, moved out of it or with a new amount. The payment covers the total the
+// invoice had when it was paid. existing is nil for a new purchase.
+func checkInvoicesNotPaid(ctx context.Context, invoices entity_finance.CreditCardInvoiceRepositoryInterface, existing, record *entity_finance.ExpenseRecord) error {
+	before, after := invoiceCharge(existing), invoiceCharge(record)
+	changed := make(map[string]bool)
+	for id := range before {
+		changed[id] = before[id] != after[id]
+	}
+	for id := range after {
+		changed[id] = before[id] != after[id]
+	}
+
+	for id, isChanged := range changed {
+		if !isChanged {
+			continue
+		}
+
+		invoice, err := invoices.GetInvoiceByID(ctx, id)
+		if err != nil {
+			// Only paid invoices are stored.
+			if strings.Contains(err.Error(), "not found") {
+				continue
+			}
+			return err
+		}
+		if invoice.Status == entity_finance.CreditCardInvoicePaid {
+			return fmt.Errorf("validation failed: the credit card invoice %s is already paid", invoice.Month)
+		}
+	}
+	return nil
+}
+
+// invoiceCharge returns the amount the record adds to its invoice.
+func invoiceCharge(record *entity_finance.ExpenseRecord) map[string]float64 {
+	if record == nil || record.InvoiceID == "" || record.StoredStatus() == entity_finance.ExpenseStatusCancelled || record.IsInvoicePayment() {
+		return map[string]float64{}
+	}
+	return map[string]float64{record.InvoiceID: record.Amount}
+}
+
+// publishAmountChange replaces the record in the consumers (a delete and a create event)
+// when its amount changed.
+func (s *ExpenseRecordService) publishAmountChange(ctx context.Context, existing, updated *entity_finance.ExpenseRecord) {
+	if updated.Amount == existing.Amount {
+		return
+	}
+
+	old, _ := json.Marshal(existing)
+	new, _ := json.Marshal(updated)
+	s.publishMessage(ctx, mq_rk_expense_delete, old, "")
+	s.publishMessage(ctx, mq_rk_expense_create, new, "")
 }
 
 // DeleteExpenseRecord handles deleting an expense record.
@@ -256,6 +423,10 @@ func (s *ExpenseRecordService) DeleteExpenseRecord(ctx context.Context, id strin
 		return errors.New("expense record not found or access denied for delete")
 	}
 
+	if recordToVerify.IsBankFee() {
+		return errors.New("validation failed: a bank fee cannot be deleted, cancel it instead")
+	}
+
 	err = s.Repo.DeleteExpenseRecord(ctx, id)
 	if err != nil {
 		return err
@@ -267,50 +438,310 @@ func (s *ExpenseRecordService) DeleteExpenseRecord(ctx context.Context, id strin
 	return err
 }
 
-func (s *ExpenseRecordService) CreateExpenseByNfceUrl(ctx context.Context, url *entity_finance.ExpenseByNfceUrl) (*entity_finance.ExpenseByNfceUrl, error) {
+// PayExpenseRecord marks the expense as paid, setting the payment date and the source
+// account in a single update.
+func (s *ExpenseRecordService) PayExpenseRecord(ctx context.Context, id string, payment *entity_finance.ExpensePayment) (*entity_finance.ExpenseRecord, error) {
+	if payment == nil {
+		return nil, errors.New("payment data is nil")
+	}
 
-	userIDFromCtx := ctx.Value("UserID")
-	if userIDFromCtx == nil || url.UserID != userIDFromCtx.(string) {
-		return nil, errors.New("user ID mismatch or not found in context for update")
+	if err := payment.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	record, err := s.GetExpenseRecordByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if record.IsCardPurchase() {
+		return nil, errors.New("validation failed: credit card purchases are paid through the invoice")
+	}
+
+	if err := record.StoredStatus().CanTransitionTo(entity_finance.ExpenseStatusPaid); err != nil {
+		return nil, err
+	}
+
+	if err := checkBankAccountNotArchived(ctx, s.Accounts, "bankPaidFrom", payment.BankPaidFrom); err != nil {
+		return nil, err
 	}
 
+	record.Status = entity_finance.ExpenseStatusPaid
+	record.PaymentDate = payment.PaymentDate
+	record.BankPaidFrom = payment.BankPaidFrom
+	record.CustomBankName = payment.CustomBankName
+
+	return s.changeStatus(ctx, record)
+}
+
+// CancelExpenseRecord marks the expense as cancelled, removing it from totals.
+func (s *ExpenseRecordService) CancelExpenseRecord(ctx context.Context, id string) (*entity_finance.ExpenseRecord, error) {
+	record, err := s.GetExpenseRecordByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := record.StoredStatus().CanTransitionTo(entity_finance.ExpenseStatusCancelled); err != nil {
+		return nil, err
+	}
+
+	record.Status = entity_finance.ExpenseStatusCancelled
+
+	return s.changeStatus(ctx, record)
+}
+
+// changeStatus stores the record with its new status and publishes the update.
+func (s *ExpenseRecordService) changeStatus(ctx context.Context, record *entity_finance.ExpenseRecord) (*entity_finance.ExpenseRecord, error) {
+	if err := record.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	record.UpdatedAt = time.Now()
+	result, err := s.Repo.UpdateExpenseRecord(ctx, record.ID, record)
+	if err != nil {
+		return nil, err
+	}
+
+	b, _ := json.Marshal(result)
+	s.publishMessage(ctx, mq_rk_expense_update, b, "")
+
+	return result, nil
+}
+
+// statusFilter removes the "status" entry from the filter and parses it. The value is either
+// a comma separated string or a list of strings.
+func statusFilter(filter map[string]interface{}) ([]entity_finance.ExpenseStatus, error) {
+	value, ok := filter["status"]
+	if !ok {
+		return nil, nil
+	}
+	delete(filter, "status")
+
+	switch v := value.(type) {
+	case string:
+		return entity_finance.ParseExpenseStatuses(v)
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			part, ok := item.(string)
+			if !ok {
+				return nil, errors.New("status must be a string or a list of strings")
+			}
+			parts = append(parts, part)
+		}
+		return entity_finance.ParseExpenseStatuses(strings.Join(parts, ","))
+	default:
+		return nil, errors.New("status must be a string or a list of strings")
+	}
+}
+
+// CreateExpenseByNfceUrl downloads a NFC-e and records its purchase as expense records,
+// either one per item or a single record with the receipt total.
+func (s *ExpenseRecordService) CreateExpenseByNfceUrl(ctx context.Context, url *entity_finance.ExpenseByNfceUrl) (*entity_finance.NFCeImportResult, error) {
 	if url == nil {
-		return nil, errors.New("expense record data is nil")
+		return nil, errors.New("nfce url data is nil")
+	}
+
+	userIDFromCtx := ctx.Value("UserID")
+	if userIDFromCtx == nil || url.UserID != userIDFromCtx.(string) {
+		return nil, errors.New("user ID mismatch or not found in context for nfce import")
 	}
 
 	if err := url.Validate(); err != nil {
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
 
-	body, err := s.getBody(ctx, url.NfceUrl)
+	accessKey, err := entity_finance.NFCeAccessKeyFromURL(url.NfceUrl)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	previous, err := s.previousNfceImport(ctx, url, accessKey)
 	if err != nil {
 		return nil, err
 	}
+	if previous != nil {
+		return previous, nil
+	}
 
-	llmQuery, err := llm.NewAgent()
+	body, err := s.getBody(ctx, url.NfceUrl)
 	if err != nil {
 		return nil, err
 	}
-	bResut, err := llmQuery.Run(ctx, string(body))
+
+	receipt, err := s.nfceParser.Parse(ctx, url.NfceUrl, body)
 	if err != nil {
 		return nil, err
 	}
 
-	var itens interface{}
-	err = json.Unmarshal(bResut, &itens)
+	return s.importNfce(ctx, url, accessKey, receipt)
+}
+
+// previousNfceImport rebuilds the result of an earlier import of the same receipt, or returns
+// nil when it was not imported yet.
+func (s *ExpenseRecordService) previousNfceImport(ctx context.Context, req *entity_finance.ExpenseByNfceUrl, accessKey *entity_finance.NFCeAccessKey) (*entity_finance.NFCeImportResult, error) {
+	records, err := s.Repo.GetExpenseRecordsByFilter(ctx, map[string]interface{}{"NfceAccessKey": accessKey.Key})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check earlier imports of the nfce: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	result := &entity_finance.NFCeImportResult{
+		ImportMode:   req.ImportMode,
+		AccessKey:    accessKey.Key,
+		Duplicate:    true,
+		SellerCNPJ:   accessKey.FormattedCNPJ(),
+		CreatedIDs:   make([]string, 0, len(records)),
+		SkippedItems: make([]entity_finance.NFCeSkippedItem, 0),
+	}
+	for _, record := range records {
+		result.CreatedIDs = append(result.CreatedIDs, record.ID)
+		result.TotalAmount += record.Amount
+	}
+	result.TotalAmount = math.Round(result.TotalAmount*100) / 100
+
+	return result, nil
+}
+
+// importNfce creates the expense records for the parsed NFC-e according to the import mode.
+func (s *ExpenseRecordService) importNfce(ctx context.Context, req *entity_finance.ExpenseByNfceUrl, accessKey *entity_finance.NFCeAccessKey, receipt *entity_finance.NFCe) (*entity_finance.NFCeImportResult, error) {
+	result := &entity_finance.NFCeImportResult{
+		ImportMode:   req.ImportMode,
+		AccessKey:    accessKey.Key,
+		SellerCNPJ:   receipt.IDSeller,
+		CreatedIDs:   make([]string, 0),
+		SkippedItems: make([]entity_finance.NFCeSkippedItem, 0),
+	}
+
+	if result.SellerCNPJ == "" {
+		result.SellerCNPJ = accessKey.FormattedCNPJ()
+	}
+
+	validItems := make([]entity_finance.NFCeItem, 0, len(receipt.Itens))
+	for _, item := range receipt.Itens {
+		switch {
+		case strings.TrimSpace(item.ItemDescription) == "":
+			result.SkippedItems = append(result.SkippedItems, entity_finance.NFCeSkippedItem{Item: item, Reason: "item description is empty"})
+		case item.ItemPrice <= 0:
+			result.SkippedItems = append(result.SkippedItems, entity_finance.NFCeSkippedItem{Item: item, Reason: "item price must be greater than 0"})
+		default:
+			validItems = append(validItems, item)
+		}
+	}
+
+	if len(validItems) == 0 {
+		return nil, errors.New("no valid items found in nfce")
+	}
+
+	purchaseDate := time.Now().UTC().Truncate(24 * time.Hour)
+	if !receipt.EmissionDate.IsZero() {
+		emission := receipt.EmissionDate
+		purchaseDate = time.Date(emission.Year(), emission.Month(), emission.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	records := make([]*entity_finance.ExpenseRecord, 0, len(validItems))
+	if req.ImportMode == entity_finance.NfceUrlTotal {
+		// The amount to pay already has the receipt discounts; the item sum is only used when
+		// the page has no total.
+		total := receipt.TotalAmount
+		if total <= 0 {
+			for _, item := range validItems {
+				total += item.ItemPrice
+			}
+		}
+		records = append(records, s.nfceExpense(req, purchaseDate, total, "NFC-e "+result.SellerCNPJ))
+	} else {
+		for _, item := range validItems {
+			records = append(records, s.nfceExpense(req, purchaseDate, item.ItemPrice, item.ItemDescription))
+		}
+	}
+
+	valid := make([]*entity_finance.ExpenseRecord, 0, len(records))
+	for i, record := range records {
+		record.NfceAccessKey = accessKey.Key
+		if err := record.Validate(); err != nil {
+			if req.ImportMode == entity_finance.NfceUrlItems {
+				result.SkippedItems = append(result.SkippedItems, entity_finance.NFCeSkippedItem{Item: validItems[i], Reason: err.Error()})
+				continue
+			}
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+		valid = append(valid, record)
+	}
+
+	// The records get the IDs of the receipt and the earlier import is read in the same
+	// transaction, so two imports of one receipt write it once; the loser gets the result of
+	// the winner.
+	for i, record := range valid {
+		record.ID = entity_finance.NfceExpenseID(accessKey.Key, i+1)
+	}
+
+	var created []entity_finance.ExpenseRecord
+	var previous *entity_finance.NFCeImportResult
+	err := s.Tx.RunTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		previous, err = s.previousNfceImport(ctx, req, accessKey)
+		if err != nil || previous != nil {
+			return err
+		}
+
+		created = make([]entity_finance.ExpenseRecord, 0, len(valid))
+		now := time.Now()
+		for _, record := range valid {
+			record.CreatedAt = now
+			stored, err := s.Repo.UpdateExpenseRecord(ctx, record.ID, record)
+			if err != nil {
+				return err
+			}
+			created = append(created, *stored)
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	if previous != nil {
+		return previous, nil
+	}
+
+	for _, record := range created {
+		result.CreatedIDs = append(result.CreatedIDs, record.ID)
+		result.TotalAmount += record.Amount
+
+		b, _ := json.Marshal(record)
+		s.publishMessage(ctx, mq_rk_expense_create, b, "")
+	}
+
+	result.TotalAmount = math.Round(result.TotalAmount*100) / 100
+	return result, nil
+}
 
-	return nil, nil
+// nfceExpense builds a paid expense record for a NFC-e purchase.
+func (s *ExpenseRecordService) nfceExpense(req *entity_finance.ExpenseByNfceUrl, purchaseDate time.Time, amount float64, description string) *entity_finance.ExpenseRecord {
+	category := strings.TrimSpace(req.Category)
+	if category == "" {
+		category = entity_finance.NfceDefaultCategory
+	}
+
+	description = strings.TrimSpace(description)
+	if runes := []rune(description); len(runes) > 200 {
+		description = string(runes[:200])
+	}
+
+	record := entity_finance.NewExpenseRecord(category, purchaseDate, math.Round(amount*100)/100, req.UserID)
+	record.Subcategory = req.Subcategory
+	record.Description = description
+	record.PaymentDate = purchaseDate
+	record.Status = entity_finance.ExpenseStatusPaid
+	return record
 }
 
 func (s *ExpenseRecordService) getBody(ctx context.Context, url string) ([]byte, error) {
 
-	// Suggested code may be subject to a license. Learn more: ~LicenseLog:3523473348.
-	// Suggested code may be subject to a license. Learn more: ~LicenseLog:2661324889.
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", url, nil)
+	client := &http.Client{Timeout: 30 * time.Second}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -322,6 +753,10 @@ func (s *ExpenseRecordService) getBody(ctx context.Context, url string) ([]byte,
 
 	defer res.Body.Close()
 
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return nil, fmt.Errorf("failed to fetch nfce page: status code %d", res.StatusCode)
+	}
+
 	body, err := io.ReadAll(res.Body)
 
 	if err != nil {
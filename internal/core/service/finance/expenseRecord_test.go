@@ -1,7 +1,18 @@
 package finance
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // Basic test structure. Full tests require mocking ExpenseRecordRepositoryInterface.
@@ -9,3 +20,464 @@ func TestExpenseRecordService_Placeholder(t *testing.T) {
 	// TODO: Add actual tests with mocks
 	t.Log("Placeholder test for ExpenseRecordService. Further tests require repository mocking.")
 }
+
+// fakeExpenseRepository keeps expense records in memory.
+type fakeExpenseRepository struct {
+	records map[string]entity_finance.ExpenseRecord
+	nextID  int
+}
+
+func newFakeExpenseRepository() *fakeExpenseRepository {
+	return &fakeExpenseRepository{records: make(map[string]entity_finance.ExpenseRecord)}
+}
+
+func (r *fakeExpenseRepository) CreateExpenseRecord(ctx context.Context, data *entity_finance.ExpenseRecord) (*entity_finance.ExpenseRecord, error) {
+	r.nextID++
+	record := *data
+	record.ID = fmt.Sprintf("expense-%d", r.nextID)
+	r.records[record.ID] = record
+	return &record, nil
+}
+
+func (r *fakeExpenseRepository) GetExpenseRecordByID(ctx context.Context, id string) (*entity_finance.ExpenseRecord, error) {
+	record, ok := r.records[id]
+	if !ok {
+		return nil, errors.New("expense record not found")
+	}
+	return &record, nil
+}
+
+func (r *fakeExpenseRepository) GetExpenseRecords(ctx context.Context) ([]entity_finance.ExpenseRecord, error) {
+	records := make([]entity_finance.ExpenseRecord, 0, len(r.records))
+	for _, record := range r.records {
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func (r *fakeExpenseRepository) GetExpenseRecordsByDateRange(ctx context.Context, filter *entity_finance.RecordDateRange) ([]entity_finance.ExpenseRecord, error) {
+	records := make([]entity_finance.ExpenseRecord, 0)
+	for _, record := range r.records {
+		if inRecordDateRange(filter, record.DueDate, record.BankPaidFrom, record.Category) {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+// GetExpenseRecordsPage orders by due date and ID like the database; the cursor is the ID of
+// the last record of the previous page.
+func (r *fakeExpenseRepository) GetExpenseRecordsPage(ctx context.Context, filter *entity_finance.ExpenseRecordQueryByDate, page *entity_finance.PageRequest) (*entity_finance.ExpenseRecordPage, error) {
+	records, _ := r.GetExpenseRecords(ctx)
+	sort.Slice(records, func(i, j int) bool {
+		if !records[i].DueDate.Equal(records[j].DueDate) {
+			return records[i].DueDate.Before(records[j].DueDate)
+		}
+		return records[i].ID < records[j].ID
+	})
+
+	result := &entity_finance.ExpenseRecordPage{Records: make([]entity_finance.ExpenseRecord, 0)}
+	started := page.Cursor == ""
+	for _, record := range records {
+		if !started {
+			started = record.ID == page.Cursor
+			continue
+		}
+		if filter != nil && filter.EndDate != "" && record.DueDate.Format("2006-01-02") > filter.EndDate {
+			continue
+		}
+		if len(result.Records) == page.Limit {
+			result.NextCursor = result.Records[len(result.Records)-1].ID
+			break
+		}
+		result.Records = append(result.Records, record)
+	}
+	return result, nil
+}
+
+func (r *fakeExpenseRepository) GetExpenseRecordsByFilter(ctx context.Context, filter map[string]interface{}) ([]entity_finance.ExpenseRecord, error) {
+	records := make([]entity_finance.ExpenseRecord, 0)
+	for _, record := range r.records {
+		if key, ok := filter["NfceAccessKey"]; ok && record.NfceAccessKey != key {
+			continue
+		}
+		if seriesID, ok := filter["SeriesID"]; ok && record.SeriesID != seriesID {
+			continue
+		}
+		if cardID, ok := filter["CreditCardID"]; ok && record.CreditCardID != cardID {
+			continue
+		}
+		if purchaseID, ok := filter["InstallmentPurchaseID"]; ok && record.InstallmentPurchaseID != purchaseID {
+			continue
+		}
+		if accountID, ok := filter["BankPaidFrom"]; ok && record.BankPaidFrom != accountID {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// UpdateExpenseRecord merges like the database does, creating the record when it does not exist.
+func (r *fakeExpenseRepository) UpdateExpenseRecord(ctx context.Context, id string, data *entity_finance.ExpenseRecord) (*entity_finance.ExpenseRecord, error) {
+	record := *data
+	record.ID = id
+	r.records[id] = record
+	return &record, nil
+}
+
+func (r *fakeExpenseRepository) DeleteExpenseRecord(ctx context.Context, id string) error {
+	delete(r.records, id)
+	return nil
+}
+
+// fakeMessageQueue records the routing keys of the published messages.
+type fakeMessageQueue struct {
+	published []string
+}
+
+func (m *fakeMessageQueue) Consumer(ctx context.Context, exchangeName, queueName string, handler func([]byte, string) error) error {
+	return nil
+}
+
+func (m *fakeMessageQueue) Publisher(exchange, queue string, msg []byte, traceID string) error {
+	m.published = append(m.published, queue)
+	return nil
+}
+
+func (m *fakeMessageQueue) PublisherWithRouteKey(exchange, routeKey string, msg []byte, traceID string) error {
+	m.published = append(m.published, routeKey)
+	return nil
+}
+
+func (m *fakeMessageQueue) Close() error { return nil }
+
+func (m *fakeMessageQueue) Setup() error { return nil }
+
+// fakeTransactionRunner runs the units of work directly on the fake repositories.
+type fakeTransactionRunner struct {
+	runs int
+}
+
+func (r *fakeTransactionRunner) RunTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	r.runs++
+	return fn(ctx)
+}
+
+func (r *fakeTransactionRunner) Batch(ctx context.Context, fn func(ctx context.Context) error) error {
+	r.runs++
+	return fn(ctx)
+}
+
+const testNfceKey = "43250400776574163454653020000395661694784220"
+
+func TestExpenseRecordService_ImportNfce(t *testing.T) {
+	accessKey, err := entity_finance.ParseNFCeAccessKey(testNfceKey)
+	require.NoError(t, err)
+
+	nfce := &entity_finance.NFCe{
+		IDSeller: "12.345.678/0001-90",
+		Itens: []entity_finance.NFCeItem{
+			{ItemDescription: "ARROZ 5KG", ItemPrice: 25.9},
+			{ItemDescription: "FEIJAO 1KG", ItemPrice: 8.49},
+			{ItemDescription: "DESCONTO", ItemPrice: 0},
+			{ItemDescription: "", ItemPrice: 3},
+		},
+	}
+
+	t.Run("item by item", func(t *testing.T) {
+		repo := newFakeExpenseRepository()
+		mq := &fakeMessageQueue{}
+		s := &ExpenseRecordService{Repo: repo, Tx: &fakeTransactionRunner{}, mq: mq}
+
+		req := &entity_finance.ExpenseByNfceUrl{UserID: "user-1", ImportMode: entity_finance.NfceUrlItems}
+		result, err := s.importNfce(context.Background(), req, accessKey, nfce)
+		require.NoError(t, err)
+
+		assert.Equal(t, "12.345.678/0001-90", result.SellerCNPJ)
+		assert.Len(t, result.CreatedIDs, 2)
+		assert.Len(t, result.SkippedItems, 2)
+		assert.Equal(t, 34.39, result.TotalAmount)
+		assert.Equal(t, []string{mq_rk_expense_create, mq_rk_expense_create}, mq.published)
+
+		created := repo.records[result.CreatedIDs[0]]
+		assert.Equal(t, entity_finance.NfceDefaultCategory, created.Category)
+		assert.Equal(t, "ARROZ 5KG", created.Description)
+		assert.Equal(t, "user-1", created.UserID)
+		assert.Equal(t, testNfceKey, created.NfceAccessKey)
+		assert.Equal(t, testNfceKey, result.AccessKey)
+		assert.False(t, created.PaymentDate.IsZero())
+	})
+
+	t.Run("total value", func(t *testing.T) {
+		repo := newFakeExpenseRepository()
+		s := &ExpenseRecordService{Repo: repo, Tx: &fakeTransactionRunner{}, mq: &fakeMessageQueue{}}
+
+		req := &entity_finance.ExpenseByNfceUrl{UserID: "user-1", ImportMode: entity_finance.NfceUrlTotal, Category: "market"}
+		result, err := s.importNfce(context.Background(), req, accessKey, nfce)
+		require.NoError(t, err)
+
+		require.Len(t, result.CreatedIDs, 1)
+		created := repo.records[result.CreatedIDs[0]]
+		assert.Equal(t, 34.39, created.Amount, "without a receipt total the items are summed")
+		assert.Equal(t, "market", created.Category)
+
+		repo = newFakeExpenseRepository()
+		s.Repo = repo
+		discounted := *nfce
+		discounted.TotalAmount = 31.5
+		result, err = s.importNfce(context.Background(), req, accessKey, &discounted)
+		require.NoError(t, err)
+		assert.Equal(t, 31.5, repo.records[result.CreatedIDs[0]].Amount, "the amount paid includes the receipt discounts")
+	})
+
+	t.Run("imported twice", func(t *testing.T) {
+		repo := newFakeExpenseRepository()
+		mq := &fakeMessageQueue{}
+		s := &ExpenseRecordService{Repo: repo, Tx: &fakeTransactionRunner{}, mq: mq}
+
+		req := &entity_finance.ExpenseByNfceUrl{UserID: "user-1", ImportMode: entity_finance.NfceUrlItems}
+		first, err := s.importNfce(context.Background(), req, accessKey, nfce)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"nfce_" + testNfceKey + "_1", "nfce_" + testNfceKey + "_2"}, first.CreatedIDs)
+
+		second, err := s.importNfce(context.Background(), req, accessKey, nfce)
+		require.NoError(t, err)
+		assert.True(t, second.Duplicate)
+		assert.ElementsMatch(t, first.CreatedIDs, second.CreatedIDs)
+		assert.Equal(t, first.TotalAmount, second.TotalAmount)
+		assert.Len(t, repo.records, 2)
+		assert.Len(t, mq.published, 2, "the duplicate publishes nothing")
+	})
+
+	t.Run("long description", func(t *testing.T) {
+		repo := newFakeExpenseRepository()
+		s := &ExpenseRecordService{Repo: repo, Tx: &fakeTransactionRunner{}, mq: &fakeMessageQueue{}}
+
+		long := &entity_finance.NFCe{Itens: []entity_finance.NFCeItem{{ItemDescription: strings.Repeat("PÃO FRANCÊS ", 20), ItemPrice: 12}}}
+		req := &entity_finance.ExpenseByNfceUrl{UserID: "user-1", ImportMode: entity_finance.NfceUrlItems}
+		result, err := s.importNfce(context.Background(), req, accessKey, long)
+		require.NoError(t, err)
+
+		require.Len(t, result.CreatedIDs, 1)
+		description := repo.records[result.CreatedIDs[0]].Description
+		assert.True(t, utf8.ValidString(description))
+		assert.Equal(t, 200, utf8.RuneCountInString(description))
+	})
+
+	t.Run("without valid items", func(t *testing.T) {
+		s := &ExpenseRecordService{Repo: newFakeExpenseRepository(), Tx: &fakeTransactionRunner{}, mq: &fakeMessageQueue{}}
+
+		req := &entity_finance.ExpenseByNfceUrl{UserID: "user-1", ImportMode: entity_finance.NfceUrlItems}
+		_, err := s.importNfce(context.Background(), req, accessKey, &entity_finance.NFCe{})
+		assert.Error(t, err)
+	})
+}
+
+func TestExpenseRecordService_CreateExpenseByNfceUrl_Duplicate(t *testing.T) {
+	repo := newFakeExpenseRepository()
+	s := &ExpenseRecordService{Repo: repo, Tx: &fakeTransactionRunner{}, mq: &fakeMessageQueue{}}
+
+	first, _ := repo.CreateExpenseRecord(context.Background(), &entity_finance.ExpenseRecord{Amount: 10, NfceAccessKey: testNfceKey, UserID: "user-1"})
+	second, _ := repo.CreateExpenseRecord(context.Background(), &entity_finance.ExpenseRecord{Amount: 5.5, NfceAccessKey: testNfceKey, UserID: "user-1"})
+
+	ctx := context.WithValue(context.Background(), "UserID", "user-1")
+	req := &entity_finance.ExpenseByNfceUrl{
+		NfceUrl:    "https://dfe-portal.svrs.rs.gov.br/Dfe/QrCodeNFce?p=" + testNfceKey + "|2|1|1|b5bd8ab6f361bea7d94707cdcacfd96b44b4d42b",
+		UserID:     "user-1",
+		ImportMode: entity_finance.NfceUrlItems,
+	}
+
+	result, err := s.CreateExpenseByNfceUrl(ctx, req)
+	require.NoError(t, err)
+
+	assert.True(t, result.Duplicate)
+	assert.ElementsMatch(t, []string{first.ID, second.ID}, result.CreatedIDs)
+	assert.Equal(t, 15.5, result.TotalAmount)
+	assert.Equal(t, "00.776.574/1634-54", result.SellerCNPJ)
+}
+
+func TestExpenseRecordService_CreateExpenseByNfceUrl_InvalidKey(t *testing.T) {
+	s := &ExpenseRecordService{Repo: newFakeExpenseRepository(), Tx: &fakeTransactionRunner{}, mq: &fakeMessageQueue{}}
+
+	ctx := context.WithValue(context.Background(), "UserID", "user-1")
+	req := &entity_finance.ExpenseByNfceUrl{
+		NfceUrl:    "https://dfe-portal.svrs.rs.gov.br/Dfe/QrCodeNFce?p=43250400776574163454653020000395661694784221|2|1|1|abc",
+		UserID:     "user-1",
+		ImportMode: entity_finance.NfceUrlItems,
+	}
+
+	_, err := s.CreateExpenseByNfceUrl(ctx, req)
+	assert.ErrorContains(t, err, "check digit")
+}
+
+func TestExpenseRecordService_PayExpenseRecord(t *testing.T) {
+	repo := newFakeExpenseRepository()
+	mq := &fakeMessageQueue{}
+	s := &ExpenseRecordService{Repo: repo, Accounts: &fakeBankAccountRepository{accounts: map[string]entity_finance.BankAccountRequest{
+		"account-1": {ID: "account-1"},
+		"closed":    {ID: "closed", BankAccount: entity_finance.BankAccount{Archived: true}},
+	}}, mq: mq}
+	ctx := context.WithValue(context.Background(), "UserID", "user-1")
+
+	created, _ := repo.CreateExpenseRecord(ctx, &entity_finance.ExpenseRecord{
+		Category: "housing",
+		DueDate:  time.Now().AddDate(0, 0, -3),
+		Amount:   120,
+		UserID:   "user-1",
+		Status:   entity_finance.ExpenseStatusPending,
+	})
+	assert.Equal(t, entity_finance.ExpenseStatusOverdue, created.EffectiveStatus(time.Now()))
+
+	paymentDate := time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)
+	_, err := s.PayExpenseRecord(ctx, created.ID, &entity_finance.ExpensePayment{PaymentDate: paymentDate, BankPaidFrom: "closed"})
+	assert.ErrorContains(t, err, "validation failed: bankPaidFrom: bank account is archived")
+
+	paid, err := s.PayExpenseRecord(ctx, created.ID, &entity_finance.ExpensePayment{PaymentDate: paymentDate, BankPaidFrom: "account-1"})
+	require.NoError(t, err)
+
+	stored := repo.records[created.ID]
+	assert.Equal(t, entity_finance.ExpenseStatusPaid, paid.Status)
+	assert.Equal(t, entity_finance.ExpenseStatusPaid, stored.Status)
+	assert.Equal(t, paymentDate, stored.PaymentDate)
+	assert.Equal(t, "account-1", stored.BankPaidFrom)
+	assert.Equal(t, []string{mq_rk_expense_update}, mq.published)
+
+	_, err = s.CancelExpenseRecord(ctx, created.ID)
+	assert.ErrorContains(t, err, "invalid status transition")
+
+	_, err = s.PayExpenseRecord(context.WithValue(context.Background(), "UserID", "user-2"), created.ID, &entity_finance.ExpensePayment{PaymentDate: paymentDate, BankPaidFrom: "account-1"})
+	assert.ErrorContains(t, err, "access denied")
+
+	_, err = s.PayExpenseRecord(ctx, created.ID, &entity_finance.ExpensePayment{PaymentDate: paymentDate})
+	assert.ErrorContains(t, err, "validation failed")
+}
+
+func TestExpenseRecordService_UpdateExpenseRecord_Status(t *testing.T) {
+	repo := newFakeExpenseRepository()
+	s := &ExpenseRecordService{Repo: repo, Tx: &fakeTransactionRunner{}, mq: &fakeMessageQueue{}}
+	ctx := context.WithValue(context.Background(), "UserID", "user-1")
+
+	created, _ := repo.CreateExpenseRecord(ctx, &entity_finance.ExpenseRecord{
+		Category: "housing",
+		DueDate:  time.Now(),
+		Amount:   120,
+		UserID:   "user-1",
+		Status:   entity_finance.ExpenseStatusCancelled,
+	})
+
+	update := *created
+	update.Status = ""
+	update.PaymentDate = time.Now()
+	_, err := s.UpdateExpenseRecord(ctx, created.ID, &update)
+	assert.ErrorContains(t, err, "invalid status transition")
+
+	update = *created
+	update.Status = entity_finance.ExpenseStatusPending
+	result, err := s.UpdateExpenseRecord(ctx, created.ID, &update)
+	require.NoError(t, err)
+	assert.Equal(t, entity_finance.ExpenseStatusPending, result.Status)
+}
+
+func TestExpenseRecordService_GetExpenseRecordsByFilter_Status(t *testing.T) {
+	repo := newFakeExpenseRepository()
+	s := &ExpenseRecordService{Repo: repo, Series: newFakeRecurringSeriesRepository(), Tx: &fakeTransactionRunner{}, mq: &fakeMessageQueue{}}
+	ctx := context.WithValue(context.Background(), "UserID", "user-1")
+
+	overdue, _ := repo.CreateExpenseRecord(ctx, &entity_finance.ExpenseRecord{Category: "housing", DueDate: time.Now().AddDate(0, 0, -10), Amount: 10, UserID: "user-1"})
+	repo.CreateExpenseRecord(ctx, &entity_finance.ExpenseRecord{Category: "housing", DueDate: time.Now().AddDate(0, 0, 10), Amount: 10, UserID: "user-1"})
+	repo.CreateExpenseRecord(ctx, &entity_finance.ExpenseRecord{Category: "housing", DueDate: time.Now().AddDate(0, 0, -10), Amount: 10, UserID: "user-1", Status: entity_finance.ExpenseStatusCancelled})
+
+	filter := map[string]interface{}{"status": "overdue"}
+	records, err := s.GetExpenseRecordsByFilter(ctx, filter)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, overdue.ID, records[0].ID)
+	assert.Equal(t, map[string]interface{}{"status": "overdue"}, filter, "the caller's filter is left as it was")
+
+	records, err = s.GetExpenseRecordsByFilter(ctx, map[string]interface{}{"status": []interface{}{"pending", "overdue"}})
+	require.NoError(t, err)
+	assert.Len(t, records, 2)
+
+	_, err = s.GetExpenseRecordsByFilter(ctx, map[string]interface{}{"status": "late"})
+	assert.ErrorContains(t, err, "validation failed")
+}
+
+func TestExpenseRecordService_GetExpenseRecordsPage(t *testing.T) {
+	repo := newFakeExpenseRepository()
+	series := newFakeRecurringSeriesRepository()
+	s := &ExpenseRecordService{Repo: repo, Series: series, Tx: &fakeTransactionRunner{}, mq: &fakeMessageQueue{}}
+	ctx := context.WithValue(context.Background(), "UserID", "user-1")
+
+	for day := 1; day <= 5; day++ {
+		repo.CreateExpenseRecord(ctx, &entity_finance.ExpenseRecord{Category: "housing", DueDate: time.Date(2025, 3, day, 0, 0, 0, 0, time.UTC), Amount: 10, UserID: "user-1"})
+	}
+
+	first, err := s.GetExpenseRecordsPage(ctx, nil, &entity_finance.PageRequest{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, first.Records, 2)
+	assert.Equal(t, 1, first.Records[0].DueDate.Day())
+	require.NotEmpty(t, first.NextCursor)
+
+	second, err := s.GetExpenseRecordsPage(ctx, nil, &entity_finance.PageRequest{Limit: 2, Cursor: first.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, second.Records, 2)
+	assert.Equal(t, 3, second.Records[0].DueDate.Day())
+
+	last, err := s.GetExpenseRecordsPage(ctx, nil, &entity_finance.PageRequest{Limit: 2, Cursor: second.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, last.Records, 1)
+	assert.Empty(t, last.NextCursor)
+
+	_, err = s.GetExpenseRecordsPage(context.Background(), nil, &entity_finance.PageRequest{Limit: 2})
+	assert.ErrorContains(t, err, "userID not found")
+
+	for _, filter := range []entity_finance.ExpenseRecordQueryByDate{{StartDate: "2024-13-01"}, {EndDate: "01/02/2024"}} {
+		_, err = s.GetExpenseRecordsPage(ctx, &filter, &entity_finance.PageRequest{Limit: 2})
+		assert.ErrorContains(t, err, "validation failed", "%+v", filter)
+	}
+}
+
+// inRecordDateRange matches a record like the conditionals built from the range do.
+func inRecordDateRange(filter *entity_finance.RecordDateRange, date time.Time, accountID, category string) bool {
+	day := date.Format("2006-01-02")
+	if !filter.StartDate.IsZero() && day < filter.StartDate.Format("2006-01-02") {
+		return false
+	}
+	if !filter.EndDate.IsZero() && day > filter.EndDate.Format("2006-01-02") {
+		return false
+	}
+	if filter.BankAccountID != "" && accountID != filter.BankAccountID {
+		return false
+	}
+	if len(filter.Categories) > 0 {
+		for _, c := range filter.Categories {
+			if c == category {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func TestExpenseRecordService_GetExpenseRecordsByDate(t *testing.T) {
+	repo := newFakeExpenseRepository()
+	s := &ExpenseRecordService{Repo: repo, Series: newFakeRecurringSeriesRepository(), Tx: &fakeTransactionRunner{}, mq: &fakeMessageQueue{}}
+	ctx := context.WithValue(context.Background(), "UserID", "user-1")
+
+	for _, day := range []int{1, 15, 31} {
+		repo.CreateExpenseRecord(ctx, &entity_finance.ExpenseRecord{Category: "housing", DueDate: time.Date(2025, 3, day, 0, 0, 0, 0, time.UTC), Amount: 10, UserID: "user-1"})
+	}
+	repo.CreateExpenseRecord(ctx, &entity_finance.ExpenseRecord{Category: "housing", DueDate: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), Amount: 10, UserID: "user-1"})
+
+	records, err := s.GetExpenseRecordsByDate(ctx, &entity_finance.ExpenseRecordQueryByDate{StartDate: "2025-03-01", EndDate: "2025-03-31"})
+	require.NoError(t, err)
+	assert.Len(t, records, 3)
+
+	_, err = s.GetExpenseRecordsByDate(ctx, &entity_finance.ExpenseRecordQueryByDate{StartDate: "2025-03-31", EndDate: "2025-03-01"})
+	assert.ErrorContains(t, err, "validation failed")
+
+	_, err = s.GetExpenseRecordsByDate(ctx, &entity_finance.ExpenseRecordQueryByDate{StartDate: "03/01/2025"})
+	assert.ErrorContains(t, err, "validation failed")
+}
@@ -16,21 +16,36 @@ import (
 
 // IncomeRecordService provides business logic for income records.
 type IncomeRecordService struct {
-	Repo entity_finance.IncomeRecordRepositoryInterface
-	mq   message_queue.MessageQueue
+	Repo     entity_finance.IncomeRecordRepositoryInterface
+	Series   entity_finance.RecurringSeriesRepositoryInterface
+	Accounts entity_finance.BankAccountRepositoryInterface
+	Tx       entity_finance.TransactionRunner
+	mq       message_queue.MessageQueue
 }
 
 // InitializeIncomeRecordService creates a new IncomeRecordService.
-func InitializeIncomeRecordService(repo entity_finance.IncomeRecordRepositoryInterface, mq message_queue.MessageQueue) (entity_finance.IncomeRecordServiceInterface, error) {
+func InitializeIncomeRecordService(repo entity_finance.IncomeRecordRepositoryInterface, series entity_finance.RecurringSeriesRepositoryInterface, accounts entity_finance.BankAccountRepositoryInterface, tx entity_finance.TransactionRunner, mq message_queue.MessageQueue) (entity_finance.IncomeRecordServiceInterface, error) {
 	if repo == nil {
 		return nil, errors.New("repository is nil for IncomeRecordService")
 	}
+	if series == nil {
+		return nil, errors.New("recurring series repository is nil for IncomeRecordService")
+	}
+	if accounts == nil {
+		return nil, errors.New("bank account repository is nil for IncomeRecordService")
+	}
+	if tx == nil {
+		return nil, errors.New("transaction runner is nil for IncomeRecordService")
+	}
 	if mq == nil {
 		return nil, errors.New("message queue is nil for IncomeRecordService")
 	}
 	return &IncomeRecordService{
-		Repo: repo,
-		mq:   mq,
+		Repo:     repo,
+		Series:   series,
+		Accounts: accounts,
+		Tx:       tx,
+		mq:       mq,
 	}, nil
 }
 
@@ -54,51 +69,22 @@ func (s *IncomeRecordService) CreateIncomeRecord(ctx context.Context, data *enti
 		return nil, errors.New("userID in context is empty")
 	}
 	data.UserID = userIDStr
+	data.SeriesID = "" // Set when the record is written as an occurrence of a series.
 
 	if err := data.Validate(); err != nil {
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
 
+	if err := checkBankAccountNotArchived(ctx, s.Accounts, "bankAccountId", data.BankAccountID); err != nil {
+		return nil, err
+	}
+
 	data.CreatedAt = time.Now()
 	data.UpdatedAt = time.Now()
 
-	// Handle recurring income
-	if data.IsRecurring && data.RecurrenceCount > 0 {
-		var firstCreatedRecord *entity_finance.IncomeRecord
-		var err error
-
-		for i := 0; i < data.RecurrenceCount; i++ {
-			currentRecord := *data // Create a copy for each recurrence
-			currentRecord.RecurrenceNumber = i + 1
-
-			if i > 0 { // For subsequent recurrences, adjust the date
-				currentRecord.ReceiptDate = data.ReceiptDate.AddDate(0, i, 0)
-				currentRecord.ID = "" // Ensure new ID is generated by repository
-				currentRecord.CreatedAt = time.Now()
-				currentRecord.UpdatedAt = time.Now()
-			}
-
-			// Validate each potentially modified record (especially date)
-			// Note: Validate() might be too broad if only date changes, but safe.
-			if errVal := currentRecord.Validate(); errVal != nil {
-				// Log this error, decide if we should stop or skip this one occurrence
-				// For now, let's stop and return error for simplicity
-				return nil, fmt.Errorf("validation failed for recurring instance %d: %w", i+1, errVal)
-			}
-
-			created, repoErr := s.Repo.CreateIncomeRecord(ctx, &currentRecord)
-			log.Printf("Created recurring income record instance %d: %+v", i+1, created)
-			if repoErr != nil {
-				// If one fails, should we rollback previous or just return error?
-				// For now, return error. Consider transactional behavior for production.
-				return nil, fmt.Errorf("failed to create recurring income record (instance %d): %w", i+1, repoErr)
-			}
-			if i == 0 {
-				firstCreatedRecord = created
-			}
-		}
-		s.publishMessage(ctx, mq_rk_income_create, firstCreatedRecord, "", entity_common.ActionCreate)
-		return firstCreatedRecord, err // Returns the first created record of the series
+	// A recurring income is stored as a series; its occurrences are written on demand.
+	if rule := data.EffectiveRecurrence(); rule != nil {
+		return s.createIncomeSeries(ctx, data, *rule)
 	}
 
 	// For non-recurring income
@@ -144,6 +130,55 @@ func (s *IncomeRecordService) GetIncomeRecordByID(ctx context.Context, id string
 
 // GetIncomeRecords retrieves income records based on filters for the authenticated user.
 func (s *IncomeRecordService) GetIncomeRecords(ctx context.Context, params *entity_finance.GetIncomeRecordsQueryParameters) ([]entity_finance.IncomeRecord, error) {
+	queryParams, err := s.incomeQueryParameters(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := s.Repo.GetIncomeRecords(ctx, queryParams)
+	if err != nil {
+		// Repository might return specific "not found" errors or empty slices.
+		// Service layer can decide if "no records found" is an error or just an empty result.
+		// Based on ExpenseRecordService, it seems we propagate the error.
+		return records, nil
+	}
+	// if len(records) == 0 { // This check might be redundant if repo returns specific "not found" error or handles empty slice
+	// 	return []entity_finance.IncomeRecord{}, nil // Or return error as per API contract
+	// }
+	return records, nil
+}
+
+// GetIncomeRecordsByDateRange retrieves the income records of the authenticated user received
+// within the range. The range is queried on the database, not filtered in memory.
+func (s *IncomeRecordService) GetIncomeRecordsByDateRange(ctx context.Context, filter *entity_finance.RecordDateRange) ([]entity_finance.IncomeRecord, error) {
+	userIDFromCtx, _ := ctx.Value("UserID").(string)
+	if userIDFromCtx == "" {
+		return nil, errors.New("userID not found in context for GetIncomeRecordsByDateRange")
+	}
+
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.GetIncomeRecordsByDateRange(ctx, filter)
+}
+
+// GetIncomeRecordsPage retrieves one page of the income records of the authenticated user.
+func (s *IncomeRecordService) GetIncomeRecordsPage(ctx context.Context, params *entity_finance.GetIncomeRecordsQueryParameters, page *entity_finance.PageRequest) (*entity_finance.IncomeRecordPage, error) {
+	if page == nil {
+		return nil, errors.New("page request is nil")
+	}
+
+	queryParams, err := s.incomeQueryParameters(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Repo.GetIncomeRecordsPage(ctx, queryParams, page)
+}
+
+// incomeQueryParameters validates the query of a listing, scoped to the user in context.
+func (s *IncomeRecordService) incomeQueryParameters(ctx context.Context, params *entity_finance.GetIncomeRecordsQueryParameters) (*entity_finance.GetIncomeRecordsQueryParameters, error) {
 	userIDFromCtx := ctx.Value("UserID")
 	if userIDFromCtx == nil {
 		return nil, errors.New("userID not found in context for GetIncomeRecords")
@@ -196,21 +231,29 @@ func (s *IncomeRecordService) GetIncomeRecords(ctx context.Context, params *enti
 		return nil, fmt.Errorf("invalid query parameters for GetIncomeRecords: %w", err)
 	}
 
-	records, err := s.Repo.GetIncomeRecords(ctx, queryParams)
-	if err != nil {
-		// Repository might return specific "not found" errors or empty slices.
-		// Service layer can decide if "no records found" is an error or just an empty result.
-		// Based on ExpenseRecordService, it seems we propagate the error.
-		return records, nil
-	}
-	// if len(records) == 0 { // This check might be redundant if repo returns specific "not found" error or handles empty slice
-	// 	return []entity_finance.IncomeRecord{}, nil // Or return error as per API contract
-	// }
-	return records, nil
+	return queryParams, nil
 }
 
 // UpdateIncomeRecord handles updating an existing income record.
 func (s *IncomeRecordService) UpdateIncomeRecord(ctx context.Context, id string, data *entity_finance.IncomeRecord) (*entity_finance.IncomeRecord, error) {
+	if _, err := s.prepareUpdate(ctx, id, data); err != nil {
+		return nil, err
+	}
+
+	result, err := s.Repo.UpdateIncomeRecord(ctx, id, data)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishMessage(ctx, mq_rk_income_delete, data, "", entity_common.ActionDelete)
+	s.publishMessage(ctx, mq_rk_income_create, result, "", entity_common.ActionCreate)
+
+	return result, err
+}
+
+// prepareUpdate validates the update of the record and fills in the fields the payload cannot
+// change. It returns the stored record; nothing is written or published.
+func (s *IncomeRecordService) prepareUpdate(ctx context.Context, id string, data *entity_finance.IncomeRecord) (*entity_finance.IncomeRecord, error) {
 	if strings.TrimSpace(id) == "" {
 		return nil, errors.New("id is empty for update")
 	}
@@ -254,16 +297,15 @@ func (s *IncomeRecordService) UpdateIncomeRecord(ctx context.Context, id string,
 	data.ID = existingRecord.ID // Ensure ID from path is used, not from payload if different
 	data.CreatedAt = existingRecord.CreatedAt
 	data.UpdatedAt = time.Now()
-
-	result, err := s.Repo.UpdateIncomeRecord(ctx, id, data)
-	if err != nil {
-		return nil, err
+	if existingRecord.SeriesID != "" {
+		// The position in the series is owned by the series, not by the payload.
+		data.SeriesID = existingRecord.SeriesID
+		data.IsRecurring = existingRecord.IsRecurring
+		data.RecurrenceNumber = existingRecord.RecurrenceNumber
+		data.RecurrenceCount = existingRecord.RecurrenceCount
 	}
 
-	s.publishMessage(ctx, mq_rk_income_delete, data, "", entity_common.ActionDelete)
-	s.publishMessage(ctx, mq_rk_income_create, result, "", entity_common.ActionCreate)
-
-	return result, err
+	return existingRecord, nil
 }
 
 // DeleteIncomeRecord handles deleting an income record.
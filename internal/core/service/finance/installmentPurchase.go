@@ -0,0 +1,175 @@
+package finance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"github.com/Tomelin/dashfin-backend-app/pkg/message_queue"
+)
+
+// InstallmentPurchaseService splits card purchases in installments, each one charged on its invoice.
+type InstallmentPurchaseService struct {
+	Repo     entity_finance.InstallmentPurchaseRepositoryInterface
+	Cards    entity_finance.CreditCardRepositoryInterface
+	Invoices entity_finance.CreditCardInvoiceRepositoryInterface
+	Expenses entity_finance.ExpenseRecordRepositoryInterface
+	Tx       entity_finance.TransactionRunner
+	mq       message_queue.MessageQueue
+}
+
+// InitializeInstallmentPurchaseService creates a new InstallmentPurchaseService.
+func InitializeInstallmentPurchaseService(repo entity_finance.InstallmentPurchaseRepositoryInterface, cards entity_finance.CreditCardRepositoryInterface, invoices entity_finance.CreditCardInvoiceRepositoryInterface, expenses entity_finance.ExpenseRecordRepositoryInterface, tx entity_finance.TransactionRunner, mq message_queue.MessageQueue) (entity_finance.InstallmentPurchaseServiceInterface, error) {
+	if repo == nil {
+		return nil, errors.New("repository is nil for InstallmentPurchaseService")
+	}
+	if cards == nil {
+		return nil, errors.New("credit card repository is nil for InstallmentPurchaseService")
+	}
+	if invoices == nil {
+		return nil, errors.New("credit card invoice repository is nil for InstallmentPurchaseService")
+	}
+	if expenses == nil {
+		return nil, errors.New("expense record repository is nil for InstallmentPurchaseService")
+	}
+	if tx == nil {
+		return nil, errors.New("transaction runner is nil for InstallmentPurchaseService")
+	}
+	return &InstallmentPurchaseService{
+		Repo:     repo,
+		Cards:    cards,
+		Invoices: invoices,
+		Expenses: expenses,
+		Tx:       tx,
+		mq:       mq,
+	}, nil
+}
+
+// CreateInstallmentPurchase stores the purchase and writes one charge per installment.
+func (s *InstallmentPurchaseService) CreateInstallmentPurchase(ctx context.Context, data *entity_finance.InstallmentPurchase) (*entity_finance.InstallmentPurchaseSummary, error) {
+	if data == nil {
+		return nil, errors.New("installment purchase data is nil")
+	}
+
+	if err := data.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if data.UserID == "" {
+		return nil, errors.New("userID is required in installment purchase data")
+	}
+
+	if err := checkCreditCardNotArchived(ctx, s.Cards, data.CreditCardID); err != nil {
+		return nil, err
+	}
+
+	data.CreatedAt = time.Now()
+	data.UpdatedAt = time.Now()
+
+	var purchase *entity_finance.InstallmentPurchase
+	var charges []entity_finance.ExpenseRecord
+	err := s.Tx.Batch(ctx, func(ctx context.Context) error {
+		var err error
+		purchase, err = s.Repo.CreateInstallmentPurchase(ctx, data)
+		if err != nil {
+			return err
+		}
+
+		charges = purchase.Charges()
+		for i := range charges {
+			if err := assignCardInvoice(ctx, s.Cards, &charges[i]); err != nil {
+				return err
+			}
+			if err := checkInvoicesNotPaid(ctx, s.Invoices, nil, &charges[i]); err != nil {
+				return err
+			}
+
+			if _, err := s.Expenses.UpdateExpenseRecord(ctx, charges[i].ID, &charges[i]); err != nil {
+				return fmt.Errorf("failed to write installment %s: %w", charges[i].InstallmentLabel(), err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, charge := range charges {
+		b, _ := json.Marshal(charge)
+		s.publishMessage(ctx, mq_rk_expense_create, b, "")
+	}
+
+	summary := entity_finance.SummarizeInstallmentPurchase(*purchase, time.Now())
+	return &summary, nil
+}
+
+// GetInstallmentPurchaseByID returns the purchase with its remaining commitment.
+func (s *InstallmentPurchaseService) GetInstallmentPurchaseByID(ctx context.Context, id string) (*entity_finance.InstallmentPurchaseSummary, error) {
+	if id == "" {
+		return nil, errors.New("id is required")
+	}
+
+	purchase, err := s.Repo.GetInstallmentPurchaseByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := entity_finance.SummarizeInstallmentPurchase(*purchase, time.Now())
+	return &summary, nil
+}
+
+// GetInstallmentPurchases returns the purchases of the user with their remaining commitment.
+func (s *InstallmentPurchaseService) GetInstallmentPurchases(ctx context.Context) ([]entity_finance.InstallmentPurchaseSummary, error) {
+	purchases, err := s.Repo.GetInstallmentPurchases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	summaries := make([]entity_finance.InstallmentPurchaseSummary, 0, len(purchases))
+	for _, purchase := range purchases {
+		summaries = append(summaries, entity_finance.SummarizeInstallmentPurchase(purchase, now))
+	}
+	return summaries, nil
+}
+
+// DeleteInstallmentPurchase removes the purchase and all its installment charges.
+func (s *InstallmentPurchaseService) DeleteInstallmentPurchase(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("id is required")
+	}
+
+	if _, err := s.Repo.GetInstallmentPurchaseByID(ctx, id); err != nil {
+		return err
+	}
+
+	charges, err := s.Expenses.GetExpenseRecordsByFilter(ctx, map[string]interface{}{"InstallmentPurchaseID": id})
+	if err != nil {
+		return err
+	}
+
+	err = s.Tx.Batch(ctx, func(ctx context.Context) error {
+		for _, charge := range charges {
+			if err := s.Expenses.DeleteExpenseRecord(ctx, charge.ID); err != nil {
+				return err
+			}
+		}
+		return s.Repo.DeleteInstallmentPurchase(ctx, id)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, charge := range charges {
+		b, _ := json.Marshal(charge)
+		s.publishMessage(ctx, mq_rk_expense_delete, b, "")
+	}
+	return nil
+}
+
+func (s *InstallmentPurchaseService) publishMessage(ctx context.Context, routeKey string, body []byte, trace string) error {
+	return s.mq.PublisherWithRouteKey(mq_exchange, routeKey, body, trace)
+}
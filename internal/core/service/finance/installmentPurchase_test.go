@@ -0,0 +1,98 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeInstallmentPurchaseRepository keeps installment purchases in memory.
+type fakeInstallmentPurchaseRepository struct {
+	purchases map[string]entity_finance.InstallmentPurchase
+}
+
+func (r *fakeInstallmentPurchaseRepository) CreateInstallmentPurchase(ctx context.Context, data *entity_finance.InstallmentPurchase) (*entity_finance.InstallmentPurchase, error) {
+	data.ID = "purchase-1"
+	r.purchases[data.ID] = *data
+	return data, nil
+}
+
+func (r *fakeInstallmentPurchaseRepository) GetInstallmentPurchaseByID(ctx context.Context, id string) (*entity_finance.InstallmentPurchase, error) {
+	purchase, ok := r.purchases[id]
+	if !ok {
+		return nil, errors.New("installment purchase not found")
+	}
+	return &purchase, nil
+}
+
+func (r *fakeInstallmentPurchaseRepository) GetInstallmentPurchases(ctx context.Context) ([]entity_finance.InstallmentPurchase, error) {
+	purchases := make([]entity_finance.InstallmentPurchase, 0, len(r.purchases))
+	for _, purchase := range r.purchases {
+		purchases = append(purchases, purchase)
+	}
+	return purchases, nil
+}
+
+func (r *fakeInstallmentPurchaseRepository) UpdateInstallmentPurchase(ctx context.Context, data *entity_finance.InstallmentPurchase) (*entity_finance.InstallmentPurchase, error) {
+	r.purchases[data.ID] = *data
+	return data, nil
+}
+
+func (r *fakeInstallmentPurchaseRepository) DeleteInstallmentPurchase(ctx context.Context, id string) error {
+	delete(r.purchases, id)
+	return nil
+}
+
+func TestInstallmentPurchaseService(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "UserID", "user-1")
+	cards := &fakeCreditCardRepository{cards: map[string]entity_finance.CreditCardRequest{
+		"card-1": {ID: "card-1", CreditCard: entity_finance.CreditCard{CardBrand: "visa", LastFourDigits: "1234", InvoiceDueDate: 10, InvoiceClosingDay: 3}},
+	}}
+	repo := &fakeInstallmentPurchaseRepository{purchases: make(map[string]entity_finance.InstallmentPurchase)}
+	expenses := newFakeExpenseRepository()
+	mq := &fakeMessageQueue{}
+	s := &InstallmentPurchaseService{Repo: repo, Cards: cards, Invoices: &fakeCreditCardInvoiceRepository{invoices: make(map[string]entity_finance.CreditCardInvoice)}, Expenses: expenses, Tx: &fakeTransactionRunner{}, mq: mq}
+
+	summary, err := s.CreateInstallmentPurchase(ctx, &entity_finance.InstallmentPurchase{
+		UserID:       "user-1",
+		CreditCardID: "card-1",
+		Category:     "electronics",
+		Description:  "TV",
+		PurchaseDate: time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC),
+		TotalAmount:  1000,
+		Installments: 10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, summary.InstallmentAmount)
+
+	require.Len(t, expenses.records, 10)
+	assert.Len(t, mq.published, 10)
+	third := expenses.records["purchase-1_3"]
+	assert.Equal(t, "installment 3/10", third.InstallmentLabel())
+	assert.Equal(t, 100.0, third.Amount)
+	// Bought after the closing day, the first installment goes to the April invoice.
+	assert.Equal(t, "card-1_2025-04", expenses.records["purchase-1_1"].InvoiceID)
+	assert.Equal(t, "card-1_2025-06", third.InvoiceID)
+	assert.Equal(t, entity_finance.ExpenseStatusPaid, third.Status)
+
+	_, err = s.CreateInstallmentPurchase(ctx, &entity_finance.InstallmentPurchase{
+		UserID:       "user-1",
+		CreditCardID: "card-2",
+		Category:     "electronics",
+		PurchaseDate: time.Now(),
+		TotalAmount:  1000,
+		Installments: 10,
+	})
+	assert.ErrorContains(t, err, "validation failed")
+
+	mq.published = nil
+	require.NoError(t, s.DeleteInstallmentPurchase(ctx, "purchase-1"))
+	assert.Empty(t, expenses.records)
+	assert.Empty(t, repo.purchases)
+	assert.Len(t, mq.published, 10)
+}
@@ -0,0 +1,192 @@
+package nfce
+
+import (
+	"bytes"
+	"errors"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// brazilLocation is the offset used by the SEFAZ portals when the emission date has no zone.
+var brazilLocation = time.FixedZone("BRT", -3*60*60)
+
+var (
+	cnpjPattern     = regexp.MustCompile(`\d{2}\.?\d{3}\.?\d{3}/?\d{4}-?\d{2}`)
+	emissionPattern = regexp.MustCompile(`Emiss[ãa]o:?\s*(\d{2}/\d{2}/\d{4})\s+(\d{2}:\d{2}:\d{2})`)
+	codePattern     = regexp.MustCompile(`C[óo]digo:\s*([0-9A-Za-z]+)`)
+)
+
+// parseHTML parses the page body into a node tree.
+func parseHTML(body []byte) (*html.Node, error) {
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// findAll returns every node below n that matches the predicate, in document order.
+func findAll(n *html.Node, match func(*html.Node) bool) []*html.Node {
+	var nodes []*html.Node
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		if match(node) {
+			nodes = append(nodes, node)
+		}
+		for c := node.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return nodes
+}
+
+// findFirst returns the first node below n that matches the predicate.
+func findFirst(n *html.Node, match func(*html.Node) bool) *html.Node {
+	if nodes := findAll(n, match); len(nodes) > 0 {
+		return nodes[0]
+	}
+	return nil
+}
+
+// byTag matches element nodes with the given tag name.
+func byTag(tag string) func(*html.Node) bool {
+	return func(n *html.Node) bool {
+		return n.Type == html.ElementNode && n.Data == tag
+	}
+}
+
+// byClass matches element nodes with the given tag name and CSS class.
+func byClass(tag, class string) func(*html.Node) bool {
+	return func(n *html.Node) bool {
+		return n.Type == html.ElementNode && n.Data == tag && hasClass(n, class)
+	}
+}
+
+// byID matches element nodes with the given id attribute.
+func byID(id string) func(*html.Node) bool {
+	return func(n *html.Node) bool {
+		return n.Type == html.ElementNode && attr(n, "id") == id
+	}
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func hasClass(n *html.Node, class string) bool {
+	for _, c := range strings.Fields(attr(n, "class")) {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// textContent returns the text below n with the whitespace collapsed.
+func textContent(n *html.Node) string {
+	if n == nil {
+		return ""
+	}
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		if node.Type == html.TextNode {
+			sb.WriteString(node.Data)
+			sb.WriteString(" ")
+		}
+		for c := node.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// valueAfter returns the text that follows the label, e.g. "Qtde.: 2" -> "2".
+func valueAfter(text, label string) string {
+	if i := strings.Index(text, label); i >= 0 {
+		text = text[i+len(label):]
+	}
+	return strings.TrimSpace(text)
+}
+
+// parseNumber converts both the Brazilian ("1.234,56") and the plain ("2.0000") notations.
+func parseNumber(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	value = strings.TrimPrefix(value, "R$")
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, errors.New("empty number")
+	}
+	if strings.Contains(value, ",") {
+		value = strings.ReplaceAll(value, ".", "")
+		value = strings.ReplaceAll(value, ",", ".")
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// findCNPJ returns the first CNPJ in the text formatted as 00.000.000/0000-00.
+func findCNPJ(text string) string {
+	match := cnpjPattern.FindString(text)
+	if match == "" {
+		return ""
+	}
+	digits := strings.NewReplacer(".", "", "/", "", "-", "").Replace(match)
+	return digits[0:2] + "." + digits[2:5] + "." + digits[5:8] + "/" + digits[8:12] + "-" + digits[12:14]
+}
+
+// findEmissionDate looks for the "Emissão: dd/mm/yyyy hh:mm:ss" label used by the portals.
+func findEmissionDate(text string) time.Time {
+	match := emissionPattern.FindStringSubmatch(text)
+	if len(match) != 3 {
+		return time.Time{}
+	}
+	emission, err := time.ParseInLocation("02/01/2006 15:04:05", match[1]+" "+match[2], brazilLocation)
+	if err != nil {
+		return time.Time{}
+	}
+	return emission
+}
+
+// findItemCode extracts the product code from "(Código: 7891234567890 )".
+func findItemCode(text string) string {
+	match := codePattern.FindStringSubmatch(text)
+	if len(match) != 2 {
+		return ""
+	}
+	return match[1]
+}
+
+// hostOf returns the lower case host of the consultation URL.
+func hostOf(pageURL string) string {
+	parsed, err := url.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+// matchesHost reports whether the URL host is one of the hosts or a subdomain of them.
+func matchesHost(pageURL string, hosts []string) bool {
+	host := hostOf(pageURL)
+	if host == "" {
+		return false
+	}
+	for _, h := range hosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
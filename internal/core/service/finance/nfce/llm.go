@@ -0,0 +1,55 @@
+package nfce
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"github.com/Tomelin/dashfin-backend-app/pkg/llm"
+)
+
+// llmParser sends the page to the Gemini agent. It accepts any page and is meant
+// to be used only when no deterministic parser recognizes the layout.
+type llmParser struct {
+	newAgent func() (llm.AgentInterface, error)
+}
+
+// NewLLMParser creates the parser backed by pkg/llm.
+func NewLLMParser() entity_finance.NFCeParser {
+	return &llmParser{newAgent: llm.NewAgent}
+}
+
+func (p *llmParser) CanParse(pageURL string, body []byte) bool {
+	return len(body) > 0
+}
+
+func (p *llmParser) Parse(ctx context.Context, pageURL string, body []byte) (*entity_finance.NFCe, error) {
+	agent, err := p.newAgent()
+	if err != nil {
+		return nil, err
+	}
+
+	bResult, err := agent.Run(ctx, string(body))
+	if err != nil {
+		return nil, err
+	}
+
+	// The agent returns the model answer as a JSON encoded string.
+	var answer string
+	if err := json.Unmarshal(bResult, &answer); err != nil {
+		answer = string(bResult)
+	}
+	answer = strings.TrimSpace(answer)
+	answer = strings.TrimPrefix(answer, "```json")
+	answer = strings.TrimPrefix(answer, "```")
+	answer = strings.TrimSuffix(answer, "```")
+
+	var nfce entity_finance.NFCe
+	if err := json.Unmarshal([]byte(answer), &nfce); err != nil {
+		return nil, fmt.Errorf("invalid nfce data returned by llm: %w", err)
+	}
+
+	return &nfce, nil
+}
@@ -0,0 +1,147 @@
+package nfce
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"golang.org/x/net/html"
+)
+
+var dateTimePattern = regexp.MustCompile(`\d{2}/\d{2}/\d{4} \d{2}:\d{2}:\d{2}`)
+
+// mgParser reads the SEFAZ MG portal, where the items are rows of the "myTable" table and
+// each cell carries its own label ("Qtde total de ítens:", "UN:", "Valor total R$:").
+type mgParser struct {
+	hosts []string
+}
+
+// NewMGParser parses the pages of SEFAZ MG.
+func NewMGParser() entity_finance.NFCeParser {
+	return &mgParser{hosts: []string{"fazenda.mg.gov.br"}}
+}
+
+func (p *mgParser) CanParse(pageURL string, body []byte) bool {
+	return matchesHost(pageURL, p.hosts) && bytes.Contains(body, []byte("myTable"))
+}
+
+func (p *mgParser) Parse(ctx context.Context, pageURL string, body []byte) (*entity_finance.NFCe, error) {
+	doc, err := parseHTML(body)
+	if err != nil {
+		return nil, err
+	}
+
+	table := findFirst(doc, byID("myTable"))
+	if table == nil {
+		return nil, errors.New("nfce items table not found")
+	}
+
+	nfce := &entity_finance.NFCe{
+		Itens: make([]entity_finance.NFCeItem, 0),
+	}
+
+	for _, row := range findAll(table, byTag("tr")) {
+		cells := findAll(row, byTag("td"))
+		if len(cells) < 4 {
+			continue
+		}
+
+		item, err := p.parseItem(cells)
+		if err != nil {
+			return nil, err
+		}
+		nfce.Itens = append(nfce.Itens, item)
+	}
+
+	if len(nfce.Itens) == 0 {
+		return nil, errors.New("no items found in nfce page")
+	}
+
+	if header := findFirst(doc, byTag("th")); header != nil {
+		nfce.SellerName = textContent(header)
+	}
+	for _, cell := range findAll(doc, byTag("td")) {
+		text := textContent(cell)
+		if strings.Contains(text, "CNPJ") {
+			nfce.IDSeller = findCNPJ(text)
+			break
+		}
+	}
+
+	nfce.EmissionDate = p.emissionDate(doc)
+
+	for _, item := range nfce.Itens {
+		nfce.TotalAmount += item.ItemPrice
+	}
+	nfce.TotalAmount = math.Round(nfce.TotalAmount*100) / 100
+
+	return nfce, nil
+}
+
+// parseItem reads a "myTable" row: description and code, quantity, unit and total.
+func (p *mgParser) parseItem(cells []*html.Node) (entity_finance.NFCeItem, error) {
+	description := textContent(findFirst(cells[0], byTag("h7")))
+	if description == "" {
+		description = strings.TrimSpace(strings.Split(textContent(cells[0]), "(")[0])
+	}
+
+	item := entity_finance.NFCeItem{
+		ItemDescription: description,
+		ItemCode:        findItemCode(textContent(cells[0])),
+		Unit:            valueAfter(textContent(cells[2]), "UN:"),
+	}
+
+	item.Quantity, _ = parseNumber(valueAfter(textContent(cells[1]), ":"))
+
+	price, err := parseNumber(valueAfter(textContent(cells[3]), "R$:"))
+	if err != nil {
+		return item, fmt.Errorf("invalid price for item %q: %w", description, err)
+	}
+	item.ItemPrice = price
+
+	if item.Quantity > 0 {
+		item.UnitPrice = math.Round(item.ItemPrice/item.Quantity*100) / 100
+	}
+
+	return item, nil
+}
+
+// emissionDate reads the "Data Emissão" column of the invoice information table.
+func (p *mgParser) emissionDate(doc *html.Node) time.Time {
+	for _, table := range findAll(doc, byTag("table")) {
+		headers := findAll(table, byTag("th"))
+		for i, header := range headers {
+			if !strings.Contains(textContent(header), "Emissão") {
+				continue
+			}
+			for _, row := range findAll(table, byTag("tr")) {
+				cells := findAll(row, byTag("td"))
+				if len(cells) > i {
+					if emission := parseDateTime(textContent(cells[i])); !emission.IsZero() {
+						return emission
+					}
+				}
+			}
+		}
+	}
+	return parseDateTime(textContent(doc))
+}
+
+// parseDateTime returns the first "dd/mm/yyyy hh:mm:ss" found in the text.
+func parseDateTime(text string) time.Time {
+	match := dateTimePattern.FindString(text)
+	if match == "" {
+		return time.Time{}
+	}
+	emission, err := time.ParseInLocation("02/01/2006 15:04:05", match, brazilLocation)
+	if err != nil {
+		return time.Time{}
+	}
+	return emission
+}
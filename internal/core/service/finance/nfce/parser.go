@@ -0,0 +1,69 @@
+package nfce
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+)
+
+// ErrUnsupportedPage is returned when no parser recognizes the NFC-e page.
+var ErrUnsupportedPage = errors.New("nfce page layout not supported")
+
+// Parser tries the deterministic parsers in order and only calls the fallback
+// when none of them recognizes or manages to parse the page.
+type Parser struct {
+	parsers  []entity_finance.NFCeParser
+	fallback entity_finance.NFCeParser
+}
+
+// NewParser creates a Parser. The fallback may be nil.
+func NewParser(fallback entity_finance.NFCeParser, parsers ...entity_finance.NFCeParser) entity_finance.NFCeParser {
+	return &Parser{
+		parsers:  parsers,
+		fallback: fallback,
+	}
+}
+
+// InitializeNFCeParser creates the parser for the SEFAZ portals we know (RS/SVRS, SP, MG, PR),
+// using the LLM only for unknown layouts.
+func InitializeNFCeParser() entity_finance.NFCeParser {
+	return NewParser(
+		NewLLMParser(),
+		NewSVRSParser(),
+		NewSPParser(),
+		NewMGParser(),
+		NewPRParser(),
+		NewPortalParser(),
+	)
+}
+
+func (p *Parser) CanParse(pageURL string, body []byte) bool {
+	for _, parser := range p.parsers {
+		if parser.CanParse(pageURL, body) {
+			return true
+		}
+	}
+	return p.fallback != nil && p.fallback.CanParse(pageURL, body)
+}
+
+func (p *Parser) Parse(ctx context.Context, pageURL string, body []byte) (*entity_finance.NFCe, error) {
+	for _, parser := range p.parsers {
+		if !parser.CanParse(pageURL, body) {
+			continue
+		}
+
+		nfce, err := parser.Parse(ctx, pageURL, body)
+		if err == nil {
+			return nfce, nil
+		}
+		log.Printf("nfce parser failed for %s: %v", hostOf(pageURL), err)
+	}
+
+	if p.fallback == nil || !p.fallback.CanParse(pageURL, body) {
+		return nil, ErrUnsupportedPage
+	}
+
+	return p.fallback.Parse(ctx, pageURL, body)
+}
@@ -0,0 +1,206 @@
+package nfce
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	svrsURL = "https://dfe-portal.svrs.rs.gov.br/Dfe/QrCodeNFce?p=43250400776574163454653020000395661694784220%7C2%7C1%7C1%7Cb5bd8ab6f361bea7d94707cdcacfd96b44b4d42b"
+	spURL   = "https://www.nfce.fazenda.sp.gov.br/NFCeConsultaPublica/Paginas/ConsultaQRCode.aspx?p=35250561585865000151650010000012341000012348%7C2%7C1%7C1%7Cabc"
+	prURL   = "http://www.fazenda.pr.gov.br/nfce/qrcode?p=41250376189406002277650010000001181000001187%7C2%7C1%7C1%7Cabc"
+	mgURL   = "https://portalsped.fazenda.mg.gov.br/portalnfce/sistema/qrcode.xhtml?p=31250404641376000105650050004829131004829139%7C2%7C1%7C1%7Cabc"
+)
+
+func loadFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	return body
+}
+
+// fakeParser records whether it was called and returns a fixed NFCe.
+type fakeParser struct {
+	called bool
+}
+
+func (f *fakeParser) CanParse(pageURL string, body []byte) bool { return true }
+
+func (f *fakeParser) Parse(ctx context.Context, pageURL string, body []byte) (*entity_finance.NFCe, error) {
+	f.called = true
+	return &entity_finance.NFCe{IDSeller: "fallback"}, nil
+}
+
+func TestPortalParser_SVRS(t *testing.T) {
+	body := loadFixture(t, "svrs.html")
+	parser := NewSVRSParser()
+
+	require.True(t, parser.CanParse(svrsURL, body))
+	assert.False(t, parser.CanParse(spURL, body))
+
+	nfce, err := parser.Parse(context.Background(), svrsURL, body)
+	require.NoError(t, err)
+
+	assert.Equal(t, "00.776.574/1634-54", nfce.IDSeller)
+	assert.Equal(t, "COMERCIAL ZAFFARI LTDA", nfce.SellerName)
+	assert.Equal(t, 55.46, nfce.TotalAmount)
+	assert.Equal(t, time.Date(2025, 4, 22, 10, 15, 43, 0, brazilLocation).Unix(), nfce.EmissionDate.Unix())
+
+	require.Len(t, nfce.Itens, 3)
+	assert.Equal(t, entity_finance.NFCeItem{
+		ItemDescription: "ARROZ TIO JOAO T1 5KG",
+		ItemPrice:       25.90,
+		ItemCode:        "7893500018469",
+		Quantity:        1,
+		Unit:            "UN",
+		UnitPrice:       25.90,
+	}, nfce.Itens[0])
+	assert.Equal(t, 1.235, nfce.Itens[1].Quantity)
+	assert.Equal(t, "KG", nfce.Itens[1].Unit)
+	assert.Equal(t, 8.62, nfce.Itens[1].ItemPrice)
+	assert.Equal(t, 6.0, nfce.Itens[2].Quantity)
+	assert.Equal(t, 3.49, nfce.Itens[2].UnitPrice)
+}
+
+func TestPortalParser_SP(t *testing.T) {
+	body := loadFixture(t, "sp.html")
+	parser := NewSPParser()
+
+	require.True(t, parser.CanParse(spURL, body))
+
+	nfce, err := parser.Parse(context.Background(), spURL, body)
+	require.NoError(t, err)
+
+	assert.Equal(t, "61.585.865/0001-51", nfce.IDSeller)
+	assert.Equal(t, "MERCADO EXEMPLO PAULISTA LTDA", nfce.SellerName)
+	assert.Equal(t, 45.37, nfce.TotalAmount, "the amount to pay already has the discount")
+	assert.Equal(t, time.Date(2025, 5, 5, 18, 2, 11, 0, brazilLocation).Unix(), nfce.EmissionDate.Unix())
+
+	require.Len(t, nfce.Itens, 3)
+	assert.Equal(t, entity_finance.NFCeItem{
+		ItemDescription: "CAFE TORRADO MOIDO 500G",
+		ItemPrice:       30.98,
+		ItemCode:        "7896005800010",
+		Quantity:        2,
+		Unit:            "UN",
+		UnitPrice:       15.49,
+	}, nfce.Itens[0])
+	assert.Equal(t, 0.845, nfce.Itens[1].Quantity)
+	assert.Equal(t, "KG", nfce.Itens[1].Unit)
+	assert.Equal(t, 7.60, nfce.Itens[1].ItemPrice)
+}
+
+func TestPortalParser_PR(t *testing.T) {
+	body := loadFixture(t, "pr.html")
+	parser := NewPRParser()
+
+	require.True(t, parser.CanParse(prURL, body))
+
+	nfce, err := parser.Parse(context.Background(), prURL, body)
+	require.NoError(t, err)
+
+	assert.Equal(t, "76.189.406/0022-77", nfce.IDSeller)
+	assert.Equal(t, 2039.90, nfce.TotalAmount)
+	require.Len(t, nfce.Itens, 1)
+	assert.Equal(t, "7896089011836", nfce.Itens[0].ItemCode)
+	assert.Equal(t, 1019.95, nfce.Itens[0].UnitPrice)
+	assert.Equal(t, 2.0, nfce.Itens[0].Quantity)
+	assert.Equal(t, time.Date(2025, 3, 1, 9, 0, 0, 0, brazilLocation).Unix(), nfce.EmissionDate.Unix())
+}
+
+func TestMGParser(t *testing.T) {
+	body := loadFixture(t, "mg.html")
+	parser := NewMGParser()
+
+	require.True(t, parser.CanParse(mgURL, body))
+	assert.False(t, NewPortalParser().CanParse(mgURL, body))
+
+	nfce, err := parser.Parse(context.Background(), mgURL, body)
+	require.NoError(t, err)
+
+	assert.Equal(t, "04.641.376/0001-05", nfce.IDSeller)
+	assert.Equal(t, "SUPERMERCADOS BH COMERCIO DE ALIMENTOS LTDA", nfce.SellerName)
+	assert.Equal(t, 17.14, nfce.TotalAmount)
+	assert.Equal(t, time.Date(2025, 4, 10, 14, 30, 12, 0, brazilLocation).Unix(), nfce.EmissionDate.Unix())
+
+	require.Len(t, nfce.Itens, 2)
+	assert.Equal(t, entity_finance.NFCeItem{
+		ItemDescription: "LEITE UHT ITAMBE INTEGRAL 1L",
+		ItemPrice:       9.98,
+		ItemCode:        "7896051111118",
+		Quantity:        2,
+		Unit:            "UN",
+		UnitPrice:       4.99,
+	}, nfce.Itens[0])
+	assert.Equal(t, 0.45, nfce.Itens[1].Quantity)
+	assert.Equal(t, "KG", nfce.Itens[1].Unit)
+}
+
+func TestParser_UsesFallbackOnlyForUnknownPages(t *testing.T) {
+	t.Run("known layout", func(t *testing.T) {
+		fallback := &fakeParser{}
+		parser := NewParser(fallback, NewSVRSParser(), NewMGParser())
+
+		nfce, err := parser.Parse(context.Background(), svrsURL, loadFixture(t, "svrs.html"))
+		require.NoError(t, err)
+		assert.False(t, fallback.called)
+		assert.Equal(t, "00.776.574/1634-54", nfce.IDSeller)
+	})
+
+	t.Run("unknown host with the shared layout", func(t *testing.T) {
+		fallback := &fakeParser{}
+		parser := NewParser(fallback, NewSVRSParser(), NewPortalParser())
+
+		nfce, err := parser.Parse(context.Background(), "https://nfce.sefaz.example.gov.br/qr?p=1", loadFixture(t, "sp.html"))
+		require.NoError(t, err)
+		assert.False(t, fallback.called)
+		assert.Equal(t, "61.585.865/0001-51", nfce.IDSeller)
+	})
+
+	t.Run("unknown layout", func(t *testing.T) {
+		fallback := &fakeParser{}
+		parser := NewParser(fallback, NewSVRSParser(), NewMGParser(), NewPortalParser())
+
+		nfce, err := parser.Parse(context.Background(), svrsURL, loadFixture(t, "unknown.html"))
+		require.NoError(t, err)
+		assert.True(t, fallback.called)
+		assert.Equal(t, "fallback", nfce.IDSeller)
+	})
+
+	t.Run("unknown layout without fallback", func(t *testing.T) {
+		parser := NewParser(nil, NewSVRSParser())
+
+		_, err := parser.Parse(context.Background(), svrsURL, loadFixture(t, "unknown.html"))
+		assert.ErrorIs(t, err, ErrUnsupportedPage)
+	})
+}
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected float64
+	}{
+		{"25,90", 25.90},
+		{"1.019,95", 1019.95},
+		{"R$ 9,98", 9.98},
+		{"2.0000", 2},
+		{"0.4500", 0.45},
+		{"6", 6},
+	}
+
+	for _, tt := range tests {
+		got, err := parseNumber(tt.value)
+		require.NoError(t, err, tt.value)
+		assert.Equal(t, tt.expected, got, tt.value)
+	}
+
+	_, err := parseNumber("")
+	assert.Error(t, err)
+}
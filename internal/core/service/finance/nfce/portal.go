@@ -0,0 +1,127 @@
+package nfce
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"golang.org/x/net/html"
+)
+
+// portalParser reads the "Consulta NFC-e" layout shared by SVRS and several state portals,
+// where every item is a row of the "tabResult" table.
+type portalParser struct {
+	hosts []string
+}
+
+// NewSVRSParser parses the pages of SEFAZ RS and of the states served by SVRS.
+func NewSVRSParser() entity_finance.NFCeParser {
+	return &portalParser{hosts: []string{"svrs.rs.gov.br", "sefaz.rs.gov.br"}}
+}
+
+// NewSPParser parses the pages of SEFAZ SP.
+func NewSPParser() entity_finance.NFCeParser {
+	return &portalParser{hosts: []string{"fazenda.sp.gov.br"}}
+}
+
+// NewPRParser parses the pages of SEFA PR.
+func NewPRParser() entity_finance.NFCeParser {
+	return &portalParser{hosts: []string{"fazenda.pr.gov.br"}}
+}
+
+// NewPortalParser parses any page that uses the shared portal layout, whatever the host.
+func NewPortalParser() entity_finance.NFCeParser {
+	return &portalParser{}
+}
+
+func (p *portalParser) CanParse(pageURL string, body []byte) bool {
+	if len(p.hosts) > 0 && !matchesHost(pageURL, p.hosts) {
+		return false
+	}
+	return bytes.Contains(body, []byte("tabResult"))
+}
+
+func (p *portalParser) Parse(ctx context.Context, pageURL string, body []byte) (*entity_finance.NFCe, error) {
+	doc, err := parseHTML(body)
+	if err != nil {
+		return nil, err
+	}
+
+	table := findFirst(doc, byID("tabResult"))
+	if table == nil {
+		return nil, errors.New("nfce items table not found")
+	}
+
+	nfce := &entity_finance.NFCe{
+		Itens: make([]entity_finance.NFCeItem, 0),
+	}
+
+	for _, row := range findAll(table, byTag("tr")) {
+		description := findFirst(row, byClass("span", "txtTit"))
+		if description == nil {
+			continue
+		}
+
+		item, err := p.parseItem(row, textContent(description))
+		if err != nil {
+			return nil, err
+		}
+		nfce.Itens = append(nfce.Itens, item)
+	}
+
+	if len(nfce.Itens) == 0 {
+		return nil, errors.New("no items found in nfce page")
+	}
+
+	nfce.SellerName = textContent(findFirst(doc, byClass("div", "txtTopo")))
+	for _, div := range findAll(doc, byClass("div", "text")) {
+		if cnpj := findCNPJ(textContent(div)); cnpj != "" {
+			nfce.IDSeller = cnpj
+			break
+		}
+	}
+
+	nfce.EmissionDate = findEmissionDate(textContent(findFirst(doc, byID("infos"))))
+	if nfce.EmissionDate.IsZero() {
+		nfce.EmissionDate = findEmissionDate(textContent(doc))
+	}
+
+	if total := findFirst(doc, byClass("span", "txtMax")); total != nil {
+		nfce.TotalAmount, _ = parseNumber(textContent(total))
+	}
+	if nfce.TotalAmount == 0 {
+		for _, item := range nfce.Itens {
+			nfce.TotalAmount += item.ItemPrice
+		}
+		nfce.TotalAmount = math.Round(nfce.TotalAmount*100) / 100
+	}
+
+	return nfce, nil
+}
+
+// parseItem reads the code, quantity, unit and prices of a "tabResult" row.
+func (p *portalParser) parseItem(row *html.Node, description string) (entity_finance.NFCeItem, error) {
+	item := entity_finance.NFCeItem{
+		ItemDescription: description,
+		ItemCode:        findItemCode(textContent(findFirst(row, byClass("span", "RCod")))),
+		Unit:            valueAfter(textContent(findFirst(row, byClass("span", "RUN"))), "UN:"),
+	}
+
+	if qty := findFirst(row, byClass("span", "Rqtd")); qty != nil {
+		item.Quantity, _ = parseNumber(valueAfter(textContent(qty), "Qtde.:"))
+	}
+	if unitPrice := findFirst(row, byClass("span", "RvlUnit")); unitPrice != nil {
+		item.UnitPrice, _ = parseNumber(valueAfter(textContent(unitPrice), "Vl. Unit.:"))
+	}
+
+	price, err := parseNumber(textContent(findFirst(row, byClass("span", "valor"))))
+	if err != nil {
+		return item, fmt.Errorf("invalid price for item %q: %w", description, err)
+	}
+	item.ItemPrice = price
+
+	return item, nil
+}
@@ -0,0 +1,151 @@
+package finance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"github.com/Tomelin/dashfin-backend-app/pkg/message_queue"
+)
+
+// NFCeImportService queues NFC-e imports and processes them from the message queue.
+type NFCeImportService struct {
+	Repo    entity_finance.NFCeImportJobRepositoryInterface
+	expense entity_finance.ExpenseRecordServiceInterface
+	mq      message_queue.MessageQueue
+}
+
+// InitializeNFCeImportService creates a new NFCeImportService and starts the import consumer.
+func InitializeNFCeImportService(repo entity_finance.NFCeImportJobRepositoryInterface, expense entity_finance.ExpenseRecordServiceInterface, mq message_queue.MessageQueue) (entity_finance.NFCeImportServiceInterface, error) {
+	if repo == nil {
+		return nil, errors.New("repository is nil for NFCeImportService")
+	}
+	if expense == nil {
+		return nil, errors.New("expense record service is nil for NFCeImportService")
+	}
+	if mq == nil {
+		return nil, errors.New("message queue is nil for NFCeImportService")
+	}
+
+	svc := &NFCeImportService{
+		Repo:    repo,
+		expense: expense,
+		mq:      mq,
+	}
+
+	go svc.consumeImports(context.Background())
+
+	return svc, nil
+}
+
+// EnqueueNFCeImport validates the request, stores a pending job and publishes it to the queue.
+func (s *NFCeImportService) EnqueueNFCeImport(ctx context.Context, data *entity_finance.ExpenseByNfceUrl) (*entity_finance.NFCeImportJob, error) {
+	if data == nil {
+		return nil, errors.New("nfce url data is nil")
+	}
+
+	userIDFromCtx := ctx.Value("UserID")
+	if userIDFromCtx == nil || data.UserID != userIDFromCtx.(string) {
+		return nil, errors.New("user ID mismatch or not found in context for nfce import")
+	}
+
+	if err := data.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	job, err := s.Repo.CreateNFCeImportJob(ctx, &entity_finance.NFCeImportJob{
+		UserID:    data.UserID,
+		Status:    entity_finance.NFCeImportPending,
+		Request:   *data,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	b, _ := json.Marshal(entity_finance.NFCeImportMessage{JobID: job.ID, UserID: job.UserID})
+	if err := s.mq.PublisherWithRouteKey(mq_exchange, mq_rk_nfce_import, b, job.ID); err != nil {
+		s.finishJob(ctx, job, nil, fmt.Errorf("failed to enqueue nfce import: %w", err))
+		return nil, fmt.Errorf("failed to enqueue nfce import: %w", err)
+	}
+
+	return job, nil
+}
+
+// GetNFCeImportJob returns the job if it belongs to the user in context.
+func (s *NFCeImportService) GetNFCeImportJob(ctx context.Context, id string) (*entity_finance.NFCeImportJob, error) {
+	if id == "" {
+		return nil, errors.New("id is empty")
+	}
+
+	userIDFromCtx := ctx.Value("UserID")
+	if userIDFromCtx == nil || userIDFromCtx.(string) == "" {
+		return nil, errors.New("userID not found in context")
+	}
+
+	job, err := s.Repo.GetNFCeImportJobByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.UserID != userIDFromCtx.(string) {
+		return nil, errors.New("nfce import job not found or access denied")
+	}
+
+	return job, nil
+}
+
+func (s *NFCeImportService) consumeImports(ctx context.Context) {
+	if err := s.mq.Consumer(ctx, mq_exchange, mq_queue_nfce_import, s.processImport); err != nil {
+		log.Printf("nfce import consumer stopped: %v", err)
+	}
+}
+
+// processImport runs a queued import. Import failures are recorded on the job and
+// acknowledged; only errors saving the job are returned so the message is retried.
+func (s *NFCeImportService) processImport(body []byte, traceID string) error {
+	var message entity_finance.NFCeImportMessage
+	if err := json.Unmarshal(body, &message); err != nil {
+		return fmt.Errorf("erro ao deserializar: %w", err)
+	}
+
+	ctx := context.WithValue(context.Background(), "UserID", message.UserID)
+
+	job, err := s.Repo.GetNFCeImportJobByID(ctx, message.JobID)
+	if err != nil {
+		return err
+	}
+
+	if job.IsFinished() {
+		return nil
+	}
+
+	job.Status = entity_finance.NFCeImportRunning
+	job.UpdatedAt = time.Now()
+	if _, err := s.Repo.UpdateNFCeImportJob(ctx, job); err != nil {
+		return err
+	}
+
+	result, importErr := s.expense.CreateExpenseByNfceUrl(ctx, &job.Request)
+	return s.finishJob(ctx, job, result, importErr)
+}
+
+// finishJob stores the final status of the job.
+func (s *NFCeImportService) finishJob(ctx context.Context, job *entity_finance.NFCeImportJob, result *entity_finance.NFCeImportResult, importErr error) error {
+	job.Status = entity_finance.NFCeImportSucceeded
+	job.Result = result
+	job.Error = ""
+	if importErr != nil {
+		job.Status = entity_finance.NFCeImportFailed
+		job.Error = importErr.Error()
+	}
+	job.UpdatedAt = time.Now()
+
+	_, err := s.Repo.UpdateNFCeImportJob(ctx, job)
+	return err
+}
@@ -0,0 +1,129 @@
+package finance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNFCeImportJobRepository keeps import jobs in memory.
+type fakeNFCeImportJobRepository struct {
+	jobs   map[string]entity_finance.NFCeImportJob
+	nextID int
+}
+
+func newFakeNFCeImportJobRepository() *fakeNFCeImportJobRepository {
+	return &fakeNFCeImportJobRepository{jobs: make(map[string]entity_finance.NFCeImportJob)}
+}
+
+func (r *fakeNFCeImportJobRepository) CreateNFCeImportJob(ctx context.Context, data *entity_finance.NFCeImportJob) (*entity_finance.NFCeImportJob, error) {
+	r.nextID++
+	job := *data
+	job.ID = fmt.Sprintf("job-%d", r.nextID)
+	r.jobs[job.ID] = job
+	return &job, nil
+}
+
+func (r *fakeNFCeImportJobRepository) GetNFCeImportJobByID(ctx context.Context, id string) (*entity_finance.NFCeImportJob, error) {
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, errors.New("nfce import job not found")
+	}
+	return &job, nil
+}
+
+func (r *fakeNFCeImportJobRepository) UpdateNFCeImportJob(ctx context.Context, data *entity_finance.NFCeImportJob) (*entity_finance.NFCeImportJob, error) {
+	job := *data
+	r.jobs[job.ID] = job
+	return &job, nil
+}
+
+func TestNFCeImportService_EnqueueNFCeImport(t *testing.T) {
+	repo := newFakeNFCeImportJobRepository()
+	mq := &fakeMessageQueue{}
+	s := &NFCeImportService{Repo: repo, expense: &ExpenseRecordService{Repo: newFakeExpenseRepository(), Tx: &fakeTransactionRunner{}, mq: mq}, mq: mq}
+
+	ctx := context.WithValue(context.Background(), "UserID", "user-1")
+	req := &entity_finance.ExpenseByNfceUrl{
+		NfceUrl:    "https://dfe-portal.svrs.rs.gov.br/Dfe/QrCodeNFce?p=" + testNfceKey + "|2|1|1|abc",
+		UserID:     "user-1",
+		ImportMode: entity_finance.NfceUrlItems,
+	}
+
+	job, err := s.EnqueueNFCeImport(ctx, req)
+	require.NoError(t, err)
+	assert.NotEmpty(t, job.ID)
+	assert.Equal(t, entity_finance.NFCeImportPending, job.Status)
+	assert.Equal(t, []string{mq_rk_nfce_import}, mq.published)
+
+	_, err = s.GetNFCeImportJob(context.WithValue(context.Background(), "UserID", "user-2"), job.ID)
+	assert.ErrorContains(t, err, "access denied")
+
+	t.Run("invalid request", func(t *testing.T) {
+		_, err := s.EnqueueNFCeImport(ctx, &entity_finance.ExpenseByNfceUrl{UserID: "user-1"})
+		assert.ErrorContains(t, err, "validation failed")
+	})
+}
+
+func TestNFCeImportService_ProcessImport(t *testing.T) {
+	newService := func() (*NFCeImportService, *fakeNFCeImportJobRepository, *fakeExpenseRepository) {
+		jobs := newFakeNFCeImportJobRepository()
+		expenses := newFakeExpenseRepository()
+		mq := &fakeMessageQueue{}
+		return &NFCeImportService{Repo: jobs, expense: &ExpenseRecordService{Repo: expenses, Tx: &fakeTransactionRunner{}, mq: mq}, mq: mq}, jobs, expenses
+	}
+
+	enqueue := func(t *testing.T, s *NFCeImportService, nfceURL string) []byte {
+		ctx := context.WithValue(context.Background(), "UserID", "user-1")
+		job, err := s.EnqueueNFCeImport(ctx, &entity_finance.ExpenseByNfceUrl{
+			NfceUrl:    nfceURL,
+			UserID:     "user-1",
+			ImportMode: entity_finance.NfceUrlItems,
+		})
+		require.NoError(t, err)
+		body, _ := json.Marshal(entity_finance.NFCeImportMessage{JobID: job.ID, UserID: job.UserID})
+		return body
+	}
+
+	t.Run("succeeded", func(t *testing.T) {
+		s, jobs, expenses := newService()
+		existing, _ := expenses.CreateExpenseRecord(context.Background(), &entity_finance.ExpenseRecord{Amount: 10, NfceAccessKey: testNfceKey, UserID: "user-1"})
+
+		body := enqueue(t, s, "https://dfe-portal.svrs.rs.gov.br/Dfe/QrCodeNFce?p="+testNfceKey+"|2|1|1|abc")
+		require.NoError(t, s.processImport(body, "trace"))
+
+		job := jobs.jobs["job-1"]
+		assert.Equal(t, entity_finance.NFCeImportSucceeded, job.Status)
+		require.NotNil(t, job.Result)
+		assert.Equal(t, []string{existing.ID}, job.Result.CreatedIDs)
+		assert.Empty(t, job.Error)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		s, jobs, _ := newService()
+
+		body := enqueue(t, s, "https://dfe-portal.svrs.rs.gov.br/Dfe/QrCodeNFce?p="+testNfceKey+"|2|1|1|abc")
+		job := jobs.jobs["job-1"]
+		job.Request.NfceUrl = "https://dfe-portal.svrs.rs.gov.br/Dfe/QrCodeNFce"
+		jobs.jobs["job-1"] = job
+
+		require.NoError(t, s.processImport(body, "trace"))
+
+		job = jobs.jobs["job-1"]
+		assert.Equal(t, entity_finance.NFCeImportFailed, job.Status)
+		assert.NotEmpty(t, job.Error)
+		assert.Nil(t, job.Result)
+	})
+
+	t.Run("unknown job", func(t *testing.T) {
+		s, _, _ := newService()
+		body, _ := json.Marshal(entity_finance.NFCeImportMessage{JobID: "missing", UserID: "user-1"})
+		assert.Error(t, s.processImport(body, "trace"))
+	})
+}
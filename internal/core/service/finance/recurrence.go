@@ -0,0 +1,518 @@
+package finance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	entity_common "github.com/Tomelin/dashfin-backend-app/internal/core/entity/common"
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+)
+
+// materializeUntil returns how far a series created now must be written: the recurrence
+// horizon, or the first occurrence when it falls after it.
+func materializeUntil(rule entity_finance.RecurrenceRule, start time.Time) (time.Time, error) {
+	first, ok := rule.First(start)
+	if !ok {
+		return time.Time{}, errors.New("validation failed: recurrence has no occurrences")
+	}
+
+	until := entity_finance.RecurrenceHorizon(time.Now())
+	if first.After(until) {
+		until = first
+	}
+	return until, nil
+}
+
+// createExpenseSeries stores the series of a recurring expense and writes its occurrences
+// up to the recurrence horizon. The first occurrence is returned.
+func (s *ExpenseRecordService) createExpenseSeries(ctx context.Context, data *entity_finance.ExpenseRecord, rule entity_finance.RecurrenceRule) (*entity_finance.ExpenseRecord, error) {
+	template := *data
+	template.ID = ""
+	template.Recurrence = nil
+
+	series := &entity_finance.RecurringSeries{
+		UserID:    data.UserID,
+		Kind:      entity_finance.RecurringSeriesExpense,
+		Rule:      rule,
+		StartDate: data.DueDate,
+		Expense:   &template,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := series.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	until, err := materializeUntil(rule, series.StartDate)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []entity_finance.ExpenseRecord
+	err = s.Tx.Batch(ctx, func(ctx context.Context) error {
+		created, err := s.Series.CreateRecurringSeries(ctx, series)
+		if err != nil {
+			return err
+		}
+
+		records, err = s.materializeExpenseSeries(ctx, created, until)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("validation failed: recurrence has no occurrences")
+	}
+
+	s.publishExpenseOccurrences(ctx, records)
+	return &records[0], nil
+}
+
+// materializeExpenses writes the pending occurrences of the user's expense series up to until.
+// A series that fails does not stop the others; the failures are returned together.
+func (s *ExpenseRecordService) materializeExpenses(ctx context.Context, until time.Time) error {
+	series, err := s.Series.GetRecurringSeriesByKind(ctx, entity_finance.RecurringSeriesExpense)
+	if err != nil {
+		return fmt.Errorf("failed to load expense series: %w", err)
+	}
+
+	var errs []error
+	for i := range series {
+		records, err := s.materializeExpenseSeries(ctx, &series[i], until)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to materialize expense series %s: %w", series[i].ID, err))
+			continue
+		}
+		s.publishExpenseOccurrences(ctx, records)
+	}
+	return errors.Join(errs...)
+}
+
+// materializeExpenseSeries writes the occurrences of the series up to until, together with
+// the series progress. Occurrences have deterministic IDs and are upserted, so running it
+// twice does not duplicate them. The caller publishes the records once they are committed.
+func (s *ExpenseRecordService) materializeExpenseSeries(ctx context.Context, series *entity_finance.RecurringSeries, until time.Time) ([]entity_finance.ExpenseRecord, error) {
+	occurrences := series.PendingOccurrences(until)
+	if len(occurrences) == 0 {
+		return []entity_finance.ExpenseRecord{}, nil
+	}
+
+	var records []entity_finance.ExpenseRecord
+	err := s.Tx.Batch(ctx, func(ctx context.Context) error {
+		records = make([]entity_finance.ExpenseRecord, 0, len(occurrences))
+		for _, occurrence := range occurrences {
+			record := series.ExpenseOccurrence(occurrence)
+			record.CreatedAt = time.Now()
+			if err := s.assignInvoice(ctx, nil, record); err != nil {
+				return err
+			}
+
+			// UpdateExpenseRecord merges into the document, creating it when it does not exist.
+			result, err := s.Repo.UpdateExpenseRecord(ctx, record.ID, record)
+			if err != nil {
+				return fmt.Errorf("failed to write occurrence %d: %w", occurrence.Number, err)
+			}
+			records = append(records, *result)
+		}
+
+		series.MaterializedCount = occurrences[len(occurrences)-1].Number
+		series.UpdatedAt = time.Now()
+		_, err := s.Series.UpdateRecurringSeries(ctx, series)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+func (s *ExpenseRecordService) publishExpenseOccurrences(ctx context.Context, records []entity_finance.ExpenseRecord) {
+	for i := range records {
+		b, _ := json.Marshal(records[i])
+		s.publishMessage(ctx, mq_rk_expense_create, b, "")
+	}
+}
+
+// createIncomeSeries stores the series of a recurring income and writes its occurrences
+// up to the recurrence horizon. The first occurrence is returned.
+func (s *IncomeRecordService) createIncomeSeries(ctx context.Context, data *entity_finance.IncomeRecord, rule entity_finance.RecurrenceRule) (*entity_finance.IncomeRecord, error) {
+	template := *data
+	template.ID = ""
+	template.Recurrence = nil
+
+	series := &entity_finance.RecurringSeries{
+		UserID:    data.UserID,
+		Kind:      entity_finance.RecurringSeriesIncome,
+		Rule:      rule,
+		StartDate: data.ReceiptDate,
+		Income:    &template,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := series.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	until, err := materializeUntil(rule, series.StartDate)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []entity_finance.IncomeRecord
+	err = s.Tx.Batch(ctx, func(ctx context.Context) error {
+		created, err := s.Series.CreateRecurringSeries(ctx, series)
+		if err != nil {
+			return err
+		}
+
+		records, err = s.materializeIncomeSeries(ctx, created, until)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("validation failed: recurrence has no occurrences")
+	}
+
+	s.publishIncomeOccurrences(ctx, records)
+	return &records[0], nil
+}
+
+// materializeIncomes writes the pending occurrences of the user's income series up to until.
+// A series that fails does not stop the others; the failures are returned together.
+func (s *IncomeRecordService) materializeIncomes(ctx context.Context, until time.Time) error {
+	series, err := s.Series.GetRecurringSeriesByKind(ctx, entity_finance.RecurringSeriesIncome)
+	if err != nil {
+		return fmt.Errorf("failed to load income series: %w", err)
+	}
+
+	var errs []error
+	for i := range series {
+		records, err := s.materializeIncomeSeries(ctx, &series[i], until)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to materialize income series %s: %w", series[i].ID, err))
+			continue
+		}
+		s.publishIncomeOccurrences(ctx, records)
+	}
+	return errors.Join(errs...)
+}
+
+// materializeIncomeSeries writes the occurrences of the series up to until, upserting them
+// by their deterministic IDs, together with the series progress.
+func (s *IncomeRecordService) materializeIncomeSeries(ctx context.Context, series *entity_finance.RecurringSeries, until time.Time) ([]entity_finance.IncomeRecord, error) {
+	occurrences := series.PendingOccurrences(until)
+	if len(occurrences) == 0 {
+		return []entity_finance.IncomeRecord{}, nil
+	}
+
+	var records []entity_finance.IncomeRecord
+	err := s.Tx.Batch(ctx, func(ctx context.Context) error {
+		records = make([]entity_finance.IncomeRecord, 0, len(occurrences))
+		for _, occurrence := range occurrences {
+			record := series.IncomeOccurrence(occurrence)
+			record.CreatedAt = time.Now()
+			record.UpdatedAt = time.Now()
+
+			// UpdateIncomeRecord merges into the document, creating it when it does not exist.
+			result, err := s.Repo.UpdateIncomeRecord(ctx, record.ID, record)
+			if err != nil {
+				return fmt.Errorf("failed to write occurrence %d: %w", occurrence.Number, err)
+			}
+			records = append(records, *result)
+		}
+
+		series.MaterializedCount = occurrences[len(occurrences)-1].Number
+		series.UpdatedAt = time.Now()
+		_, err := s.Series.UpdateRecurringSeries(ctx, series)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+func (s *IncomeRecordService) publishIncomeOccurrences(ctx context.Context, records []entity_finance.IncomeRecord) {
+	for i := range records {
+		s.publishMessage(ctx, mq_rk_income_create, &records[i], "", entity_common.ActionCreate)
+	}
+}
+
+// UpdateExpenseSeries updates the expense and, for the following and all scopes, the shared
+// fields of the other occurrences of its series. The series template is updated as well so
+// the occurrences not written yet get the new values.
+func (s *ExpenseRecordService) UpdateExpenseSeries(ctx context.Context, id string, data *entity_finance.ExpenseRecord, scope entity_finance.SeriesScope) (*entity_finance.ExpenseRecord, error) {
+	if scope == entity_finance.SeriesScopeSingle {
+		return s.UpdateExpenseRecord(ctx, id, data)
+	}
+
+	existing, series, err := s.expenseSeries(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every write is in the batch; the events are published once it is committed.
+	var result *entity_finance.ExpenseRecord
+	var before, after []entity_finance.ExpenseRecord
+	err = s.Tx.Batch(ctx, func(ctx context.Context) error {
+		stored, err := s.prepareUpdate(ctx, id, data)
+		if err != nil {
+			return err
+		}
+		result, err = s.Repo.UpdateExpenseRecord(ctx, id, data)
+		if err != nil {
+			return err
+		}
+
+		occurrences, err := s.Repo.GetExpenseRecordsByFilter(ctx, map[string]interface{}{"SeriesID": series.ID})
+		if err != nil {
+			return err
+		}
+
+		before, after = nil, nil
+		for _, occurrence := range occurrences {
+			if occurrence.ID == id || !scope.Includes(existing.RecurrenceNumber, occurrence.RecurrenceNumber) {
+				continue
+			}
+
+			updated := occurrence
+			updated.ApplySeriesChanges(result)
+			if _, err := s.Repo.UpdateExpenseRecord(ctx, updated.ID, &updated); err != nil {
+				return fmt.Errorf("failed to update occurrence %d: %w", occurrence.RecurrenceNumber, err)
+			}
+			before = append(before, occurrence)
+			after = append(after, updated)
+		}
+		before = append(before, *stored)
+		after = append(after, *result)
+
+		series.Expense.ApplySeriesChanges(result)
+		series.UpdatedAt = time.Now()
+		_, err = s.Series.UpdateRecurringSeries(ctx, series)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range after {
+		s.publishAmountChange(ctx, &before[i], &after[i])
+	}
+	return result, nil
+}
+
+// DeleteExpenseSeries deletes the expense and, for the following and all scopes, the other
+// occurrences of its series. The series is ended before the first deleted occurrence.
+func (s *ExpenseRecordService) DeleteExpenseSeries(ctx context.Context, id string, scope entity_finance.SeriesScope) error {
+	if scope == entity_finance.SeriesScopeSingle {
+		return s.DeleteExpenseRecord(ctx, id)
+	}
+
+	existing, series, err := s.expenseSeries(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	var deleted []entity_finance.ExpenseRecord
+	err = s.Tx.Batch(ctx, func(ctx context.Context) error {
+		// The series is ended in the same commit so a concurrent listing does not write the
+		// occurrences again.
+		var err error
+		if scope == entity_finance.SeriesScopeAll || existing.RecurrenceNumber <= 1 {
+			err = s.Series.DeleteRecurringSeries(ctx, series.ID)
+		} else {
+			series.EndBefore(existing.RecurrenceNumber)
+			series.UpdatedAt = time.Now()
+			_, err = s.Series.UpdateRecurringSeries(ctx, series)
+		}
+		if err != nil {
+			return err
+		}
+
+		occurrences, err := s.Repo.GetExpenseRecordsByFilter(ctx, map[string]interface{}{"SeriesID": series.ID})
+		if err != nil {
+			return err
+		}
+
+		deleted = nil
+		for _, occurrence := range occurrences {
+			if !scope.Includes(existing.RecurrenceNumber, occurrence.RecurrenceNumber) {
+				continue
+			}
+
+			if err := s.Repo.DeleteExpenseRecord(ctx, occurrence.ID); err != nil {
+				return fmt.Errorf("failed to delete occurrence %d: %w", occurrence.RecurrenceNumber, err)
+			}
+			deleted = append(deleted, occurrence)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, occurrence := range deleted {
+		b, _ := json.Marshal(occurrence)
+		s.publishMessage(ctx, mq_rk_expense_delete, b, "")
+	}
+	return nil
+}
+
+// expenseSeries returns the expense, checking it belongs to the user, and its series.
+func (s *ExpenseRecordService) expenseSeries(ctx context.Context, id string) (*entity_finance.ExpenseRecord, *entity_finance.RecurringSeries, error) {
+	existing, err := s.GetExpenseRecordByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if existing.SeriesID == "" {
+		return nil, nil, errors.New("validation failed: expense record is not part of a recurring series")
+	}
+
+	series, err := s.Series.GetRecurringSeriesByID(ctx, existing.SeriesID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return existing, series, nil
+}
+
+// UpdateIncomeSeries updates the income and, for the following and all scopes, the shared
+// fields of the other occurrences of its series and the series template.
+func (s *IncomeRecordService) UpdateIncomeSeries(ctx context.Context, id string, data *entity_finance.IncomeRecord, scope entity_finance.SeriesScope) (*entity_finance.IncomeRecord, error) {
+	if scope == entity_finance.SeriesScopeSingle {
+		return s.UpdateIncomeRecord(ctx, id, data)
+	}
+
+	existing, series, err := s.incomeSeries(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every write is in the batch; the events are published once it is committed.
+	var result *entity_finance.IncomeRecord
+	var before, after []entity_finance.IncomeRecord
+	err = s.Tx.Batch(ctx, func(ctx context.Context) error {
+		stored, err := s.prepareUpdate(ctx, id, data)
+		if err != nil {
+			return err
+		}
+		result, err = s.Repo.UpdateIncomeRecord(ctx, id, data)
+		if err != nil {
+			return err
+		}
+
+		occurrences, err := s.Repo.GetIncomeRecordsByFilter(ctx, map[string]interface{}{"SeriesID": series.ID})
+		if err != nil {
+			return err
+		}
+
+		before, after = nil, nil
+		for _, occurrence := range occurrences {
+			if occurrence.ID == id || !scope.Includes(existing.RecurrenceNumber, occurrence.RecurrenceNumber) {
+				continue
+			}
+
+			updated := occurrence
+			updated.ApplySeriesChanges(result)
+			if _, err := s.Repo.UpdateIncomeRecord(ctx, updated.ID, &updated); err != nil {
+				return fmt.Errorf("failed to update occurrence %d: %w", occurrence.RecurrenceNumber, err)
+			}
+			before = append(before, occurrence)
+			after = append(after, updated)
+		}
+		before = append(before, *stored)
+		after = append(after, *result)
+
+		series.Income.ApplySeriesChanges(result)
+		series.UpdatedAt = time.Now()
+		_, err = s.Series.UpdateRecurringSeries(ctx, series)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range after {
+		s.publishMessage(ctx, mq_rk_income_delete, &before[i], "", entity_common.ActionDelete)
+		s.publishMessage(ctx, mq_rk_income_create, &after[i], "", entity_common.ActionCreate)
+	}
+	return result, nil
+}
+
+// DeleteIncomeSeries deletes the income and, for the following and all scopes, the other
+// occurrences of its series. The series is ended before the first deleted occurrence.
+func (s *IncomeRecordService) DeleteIncomeSeries(ctx context.Context, id string, scope entity_finance.SeriesScope) error {
+	if scope == entity_finance.SeriesScopeSingle {
+		return s.DeleteIncomeRecord(ctx, id)
+	}
+
+	existing, series, err := s.incomeSeries(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	var deleted []entity_finance.IncomeRecord
+	err = s.Tx.Batch(ctx, func(ctx context.Context) error {
+		var err error
+		if scope == entity_finance.SeriesScopeAll || existing.RecurrenceNumber <= 1 {
+			err = s.Series.DeleteRecurringSeries(ctx, series.ID)
+		} else {
+			series.EndBefore(existing.RecurrenceNumber)
+			series.UpdatedAt = time.Now()
+			_, err = s.Series.UpdateRecurringSeries(ctx, series)
+		}
+		if err != nil {
+			return err
+		}
+
+		occurrences, err := s.Repo.GetIncomeRecordsByFilter(ctx, map[string]interface{}{"SeriesID": series.ID})
+		if err != nil {
+			return err
+		}
+
+		deleted = nil
+		for _, occurrence := range occurrences {
+			if !scope.Includes(existing.RecurrenceNumber, occurrence.RecurrenceNumber) {
+				continue
+			}
+
+			if err := s.Repo.DeleteIncomeRecord(ctx, occurrence.ID); err != nil {
+				return fmt.Errorf("failed to delete occurrence %d: %w", occurrence.RecurrenceNumber, err)
+			}
+			deleted = append(deleted, occurrence)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range deleted {
+		s.publishMessage(ctx, mq_rk_income_delete, &deleted[i], "", entity_common.ActionDelete)
+	}
+	return nil
+}
+
+// incomeSeries returns the income, checking it belongs to the user, and its series.
+func (s *IncomeRecordService) incomeSeries(ctx context.Context, id string) (*entity_finance.IncomeRecord, *entity_finance.RecurringSeries, error) {
+	existing, err := s.GetIncomeRecordByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if existing.SeriesID == "" {
+		return nil, nil, errors.New("validation failed: income record is not part of a recurring series")
+	}
+
+	series, err := s.Series.GetRecurringSeriesByID(ctx, existing.SeriesID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return existing, series, nil
+}
@@ -0,0 +1,297 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRecurringSeriesRepository keeps recurring series in memory.
+type fakeRecurringSeriesRepository struct {
+	series map[string]entity_finance.RecurringSeries
+	nextID int
+}
+
+func newFakeRecurringSeriesRepository() *fakeRecurringSeriesRepository {
+	return &fakeRecurringSeriesRepository{series: make(map[string]entity_finance.RecurringSeries)}
+}
+
+func (r *fakeRecurringSeriesRepository) CreateRecurringSeries(ctx context.Context, data *entity_finance.RecurringSeries) (*entity_finance.RecurringSeries, error) {
+	r.nextID++
+	series := *data
+	series.ID = fmt.Sprintf("series-%d", r.nextID)
+	r.series[series.ID] = series
+	return &series, nil
+}
+
+func (r *fakeRecurringSeriesRepository) GetRecurringSeriesByID(ctx context.Context, id string) (*entity_finance.RecurringSeries, error) {
+	series, ok := r.series[id]
+	if !ok {
+		return nil, errors.New("recurring series not found")
+	}
+	return &series, nil
+}
+
+func (r *fakeRecurringSeriesRepository) GetRecurringSeriesByKind(ctx context.Context, kind entity_finance.RecurringSeriesKind) ([]entity_finance.RecurringSeries, error) {
+	result := make([]entity_finance.RecurringSeries, 0)
+	for _, series := range r.series {
+		if series.Kind == kind {
+			result = append(result, series)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeRecurringSeriesRepository) UpdateRecurringSeries(ctx context.Context, data *entity_finance.RecurringSeries) (*entity_finance.RecurringSeries, error) {
+	r.series[data.ID] = *data
+	return data, nil
+}
+
+func (r *fakeRecurringSeriesRepository) DeleteRecurringSeries(ctx context.Context, id string) error {
+	delete(r.series, id)
+	return nil
+}
+
+func TestExpenseRecordService_CreateExpenseRecord_Recurring(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "UserID", "user-1")
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), 10, 0, 0, 0, 0, time.UTC)
+
+	t.Run("legacy recurrence count becomes a series", func(t *testing.T) {
+		repo := newFakeExpenseRepository()
+		series := newFakeRecurringSeriesRepository()
+		mq := &fakeMessageQueue{}
+		s := &ExpenseRecordService{Repo: repo, Series: series, Tx: &fakeTransactionRunner{}, mq: mq}
+
+		result, err := s.CreateExpenseRecord(ctx, &entity_finance.ExpenseRecord{
+			Category:        "housing",
+			DueDate:         start,
+			Amount:          1200,
+			UserID:          "user-1",
+			IsRecurring:     true,
+			RecurrenceCount: 24,
+		})
+		require.NoError(t, err)
+
+		require.Len(t, series.series, 1)
+		created := series.series["series-1"]
+		assert.Equal(t, entity_finance.RecurrenceMonthly, created.Rule.Frequency)
+		assert.Equal(t, 24, created.Rule.Count)
+
+		// Only this month and the next one are written, not the 24 copies.
+		assert.Equal(t, "series-1_1", result.ID)
+		assert.Equal(t, start, result.DueDate)
+		assert.Len(t, repo.records, 2)
+		assert.Equal(t, 2, created.MaterializedCount)
+		assert.Equal(t, start.AddDate(0, 1, 0), repo.records["series-1_2"].DueDate)
+		assert.Len(t, mq.published, 2)
+	})
+
+	t.Run("the job materializes the months ahead once", func(t *testing.T) {
+		repo := newFakeExpenseRepository()
+		series := newFakeRecurringSeriesRepository()
+		mq := &fakeMessageQueue{}
+		s := &ExpenseRecordService{Repo: repo, Series: series, Tx: &fakeTransactionRunner{}, mq: mq}
+		job := &RecurringSeriesService{Expenses: s, Incomes: &IncomeRecordService{Repo: &fakeIncomeRepository{}, Series: series, Tx: &fakeTransactionRunner{}, mq: mq}}
+
+		_, err := s.CreateExpenseRecord(ctx, &entity_finance.ExpenseRecord{
+			Category:   "housing",
+			DueDate:    start,
+			Amount:     1200,
+			UserID:     "user-1",
+			Recurrence: &entity_finance.RecurrenceRule{Frequency: entity_finance.RecurrenceMonthly},
+		})
+		require.NoError(t, err)
+		require.Len(t, repo.records, 2)
+
+		endDate := start.AddDate(0, 5, 0)
+		filter := &entity_finance.ExpenseRecordQueryByDate{StartDate: start.Format("2006-01-02"), EndDate: endDate.Format("2006-01-02")}
+
+		records, err := s.GetExpenseRecordsByDate(ctx, filter)
+		require.NoError(t, err)
+		assert.Len(t, records, 2, "listing only reads the stored records")
+
+		require.NoError(t, job.MaterializeSeries(ctx, endDate))
+		require.NoError(t, job.MaterializeSeries(ctx, endDate))
+
+		records, err = s.GetExpenseRecordsByDate(ctx, filter)
+		require.NoError(t, err)
+		assert.Len(t, records, 6)
+		assert.Len(t, repo.records, 6)
+		assert.Equal(t, 6, series.series["series-1"].MaterializedCount)
+		assert.Len(t, mq.published, 6)
+
+		assert.Error(t, job.MaterializeSeries(context.Background(), endDate), "the job runs for a user")
+	})
+
+	t.Run("the job returns the failures", func(t *testing.T) {
+		series := &failingSeriesRepository{newFakeRecurringSeriesRepository()}
+		series.series["series-1"] = entity_finance.RecurringSeries{
+			ID:        "series-1",
+			UserID:    "user-1",
+			Kind:      entity_finance.RecurringSeriesExpense,
+			Rule:      entity_finance.RecurrenceRule{Frequency: entity_finance.RecurrenceMonthly},
+			StartDate: start,
+			Expense:   &entity_finance.ExpenseRecord{Category: "housing", Amount: 1200, UserID: "user-1"},
+		}
+		mq := &fakeMessageQueue{}
+		job := &RecurringSeriesService{
+			Expenses: &ExpenseRecordService{Repo: newFakeExpenseRepository(), Series: series, Tx: &fakeTransactionRunner{}, mq: mq},
+			Incomes:  &IncomeRecordService{Repo: &fakeIncomeRepository{}, Series: series, Tx: &fakeTransactionRunner{}, mq: mq},
+		}
+
+		err := job.MaterializeSeries(ctx, start.AddDate(0, 3, 0))
+		assert.ErrorContains(t, err, "failed to materialize expense series series-1: series write failed")
+		assert.Empty(t, mq.published)
+	})
+
+	t.Run("later occurrences start pending", func(t *testing.T) {
+		repo := newFakeExpenseRepository()
+		s := &ExpenseRecordService{Repo: repo, Series: newFakeRecurringSeriesRepository(), Tx: &fakeTransactionRunner{}, mq: &fakeMessageQueue{}}
+
+		_, err := s.CreateExpenseRecord(ctx, &entity_finance.ExpenseRecord{
+			Category:    "housing",
+			DueDate:     start,
+			PaymentDate: start,
+			Amount:      1200,
+			UserID:      "user-1",
+			Recurrence:  &entity_finance.RecurrenceRule{Frequency: entity_finance.RecurrenceMonthly, Count: 2},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, entity_finance.ExpenseStatusPaid, repo.records["series-1_1"].Status)
+		assert.Equal(t, entity_finance.ExpenseStatusPending, repo.records["series-1_2"].Status)
+		assert.True(t, repo.records["series-1_2"].PaymentDate.IsZero())
+	})
+
+	t.Run("a failed write publishes nothing", func(t *testing.T) {
+		tx := &fakeTransactionRunner{}
+		mq := &fakeMessageQueue{}
+		s := &ExpenseRecordService{Repo: newFakeExpenseRepository(), Series: &failingSeriesRepository{newFakeRecurringSeriesRepository()}, Tx: tx, mq: mq}
+
+		result, err := s.CreateExpenseRecord(ctx, &entity_finance.ExpenseRecord{
+			Category:   "housing",
+			DueDate:    start,
+			Amount:     1200,
+			UserID:     "user-1",
+			Recurrence: &entity_finance.RecurrenceRule{Frequency: entity_finance.RecurrenceMonthly},
+		})
+		assert.EqualError(t, err, "series write failed")
+		assert.Nil(t, result)
+		assert.NotZero(t, tx.runs)
+		assert.Empty(t, mq.published)
+	})
+}
+
+// failingSeriesRepository fails to save the progress of a series, after its occurrences are written.
+type failingSeriesRepository struct {
+	*fakeRecurringSeriesRepository
+}
+
+func (r *failingSeriesRepository) UpdateRecurringSeries(ctx context.Context, data *entity_finance.RecurringSeries) (*entity_finance.RecurringSeries, error) {
+	return nil, errors.New("series write failed")
+}
+
+func TestExpenseRecordService_SeriesScope(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "UserID", "user-1")
+	start := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	newService := func(t *testing.T) (*ExpenseRecordService, *fakeExpenseRepository, *fakeRecurringSeriesRepository, *fakeMessageQueue) {
+		repo := newFakeExpenseRepository()
+		series := newFakeRecurringSeriesRepository()
+		mq := &fakeMessageQueue{}
+		s := &ExpenseRecordService{Repo: repo, Series: series, Tx: &fakeTransactionRunner{}, mq: mq}
+
+		_, err := s.CreateExpenseRecord(ctx, &entity_finance.ExpenseRecord{
+			Category:   "housing",
+			DueDate:    start,
+			Amount:     1200,
+			UserID:     "user-1",
+			Recurrence: &entity_finance.RecurrenceRule{Frequency: entity_finance.RecurrenceMonthly, Count: 4},
+		})
+		require.NoError(t, err)
+		require.Len(t, repo.records, 4)
+		mq.published = nil
+		return s, repo, series, mq
+	}
+
+	t.Run("update following", func(t *testing.T) {
+		s, repo, series, mq := newService(t)
+
+		update := repo.records["series-1_2"]
+		update.Amount = 1300
+		_, err := s.UpdateExpenseSeries(ctx, update.ID, &update, entity_finance.SeriesScopeFollowing)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1200.0, repo.records["series-1_1"].Amount)
+		for _, id := range []string{"series-1_2", "series-1_3", "series-1_4"} {
+			assert.Equal(t, 1300.0, repo.records[id].Amount, id)
+			assert.Equal(t, start.AddDate(0, repo.records[id].RecurrenceNumber-1, 0), repo.records[id].DueDate, id)
+			assert.Equal(t, "series-1", repo.records[id].SeriesID, id)
+		}
+		assert.Equal(t, 1300.0, series.series["series-1"].Expense.Amount)
+		// A delete and a create event for each of the three records.
+		assert.Len(t, mq.published, 6)
+	})
+
+	t.Run("update following publishes nothing when the batch fails", func(t *testing.T) {
+		s, repo, series, mq := newService(t)
+		s.Series = &failingSeriesRepository{series}
+
+		update := repo.records["series-1_2"]
+		update.Amount = 1300
+		_, err := s.UpdateExpenseSeries(ctx, update.ID, &update, entity_finance.SeriesScopeFollowing)
+		assert.ErrorContains(t, err, "series write failed")
+		assert.Empty(t, mq.published)
+	})
+
+	t.Run("update single", func(t *testing.T) {
+		s, repo, series, _ := newService(t)
+
+		update := repo.records["series-1_3"]
+		update.Amount = 1500
+		_, err := s.UpdateExpenseSeries(ctx, update.ID, &update, entity_finance.SeriesScopeSingle)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1500.0, repo.records["series-1_3"].Amount)
+		assert.Equal(t, 1200.0, repo.records["series-1_4"].Amount)
+		assert.Equal(t, 1200.0, series.series["series-1"].Expense.Amount)
+	})
+
+	t.Run("delete following ends the series", func(t *testing.T) {
+		s, repo, series, mq := newService(t)
+
+		require.NoError(t, s.DeleteExpenseSeries(ctx, "series-1_3", entity_finance.SeriesScopeFollowing))
+
+		assert.Len(t, repo.records, 2)
+		assert.Len(t, mq.published, 2)
+		assert.Equal(t, 2, series.series["series-1"].Rule.Count)
+
+		require.NoError(t, s.materializeExpenses(ctx, start.AddDate(1, 0, 0)))
+		assert.Len(t, repo.records, 2)
+	})
+
+	t.Run("delete all", func(t *testing.T) {
+		s, repo, series, mq := newService(t)
+
+		require.NoError(t, s.DeleteExpenseSeries(ctx, "series-1_2", entity_finance.SeriesScopeAll))
+
+		assert.Empty(t, repo.records)
+		assert.Empty(t, series.series)
+		assert.Len(t, mq.published, 4)
+	})
+
+	t.Run("record outside a series", func(t *testing.T) {
+		s, repo, _, _ := newService(t)
+		record, _ := repo.CreateExpenseRecord(ctx, &entity_finance.ExpenseRecord{Category: "housing", DueDate: start, Amount: 10, UserID: "user-1"})
+
+		err := s.DeleteExpenseSeries(ctx, record.ID, entity_finance.SeriesScopeAll)
+		assert.ErrorContains(t, err, "validation failed")
+	})
+}
@@ -0,0 +1,109 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	service_profile "github.com/Tomelin/dashfin-backend-app/internal/core/service/profile"
+	"github.com/Tomelin/dashfin-backend-app/pkg/message_queue"
+)
+
+// RecurringSeriesService writes the occurrences of the recurring expenses and incomes up to
+// the recurrence horizon, so the listings only read the records already stored.
+type RecurringSeriesService struct {
+	Expenses *ExpenseRecordService
+	Incomes  *IncomeRecordService
+	Profiles service_profile.ProfilePersonServiceInterface
+}
+
+// InitializeRecurringSeriesService creates a new RecurringSeriesService and starts writing the
+// occurrences of every user on recurrenceSchedule.
+func InitializeRecurringSeriesService(expenses entity_finance.ExpenseRecordRepositoryInterface, incomes entity_finance.IncomeRecordRepositoryInterface, series entity_finance.RecurringSeriesRepositoryInterface, cards entity_finance.CreditCardRepositoryInterface, invoices entity_finance.CreditCardInvoiceRepositoryInterface, tx entity_finance.TransactionRunner, profiles service_profile.ProfilePersonServiceInterface, mq message_queue.MessageQueue) (entity_finance.RecurringSeriesServiceInterface, error) {
+	if expenses == nil {
+		return nil, errors.New("expense record repository is nil for RecurringSeriesService")
+	}
+	if incomes == nil {
+		return nil, errors.New("income record repository is nil for RecurringSeriesService")
+	}
+	if series == nil {
+		return nil, errors.New("recurring series repository is nil for RecurringSeriesService")
+	}
+	if cards == nil {
+		return nil, errors.New("credit card repository is nil for RecurringSeriesService")
+	}
+	if invoices == nil {
+		return nil, errors.New("credit card invoice repository is nil for RecurringSeriesService")
+	}
+	if tx == nil {
+		return nil, errors.New("transaction runner is nil for RecurringSeriesService")
+	}
+	if profiles == nil {
+		return nil, errors.New("profile service is nil for RecurringSeriesService")
+	}
+	if mq == nil {
+		return nil, errors.New("message queue is nil for RecurringSeriesService")
+	}
+
+	svc := &RecurringSeriesService{
+		Expenses: &ExpenseRecordService{Repo: expenses, Series: series, Cards: cards, Invoices: invoices, Tx: tx, mq: mq},
+		Incomes:  &IncomeRecordService{Repo: incomes, Series: series, Tx: tx, mq: mq},
+		Profiles: profiles,
+	}
+
+	go svc.schedule(context.Background())
+
+	return svc, nil
+}
+
+// MaterializeSeries writes the pending occurrences of the expense and income series of the
+// user up to until. Every series is tried; the failures are returned together.
+func (s *RecurringSeriesService) MaterializeSeries(ctx context.Context, until time.Time) error {
+	userID, _ := ctx.Value("UserID").(string)
+	if userID == "" {
+		return errors.New("user id is empty")
+	}
+
+	return errors.Join(
+		s.Expenses.materializeExpenses(ctx, until),
+		s.Incomes.materializeIncomes(ctx, until),
+	)
+}
+
+func (s *RecurringSeriesService) schedule(ctx context.Context) {
+	ticker := time.NewTicker(recurrenceSchedule)
+	defer ticker.Stop()
+
+	for {
+		s.materializeAll(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// materializeAll writes the occurrences of every user up to the recurrence horizon of now.
+func (s *RecurringSeriesService) materializeAll(ctx context.Context, now time.Time) {
+	profiles, err := s.Profiles.GetProfile(ctx)
+	if err != nil {
+		log.Printf("recurring series: failed to list users: %v", err)
+		return
+	}
+
+	until := entity_finance.RecurrenceHorizon(now)
+	for _, profile := range profiles {
+		if profile.UserProviderID == "" {
+			continue
+		}
+
+		userCtx := context.WithValue(ctx, "UserID", profile.UserProviderID)
+		if err := s.MaterializeSeries(userCtx, until); err != nil {
+			log.Printf("recurring series: failed to write the occurrences of user %s: %v", profile.UserProviderID, err)
+		}
+	}
+}
@@ -16,6 +16,7 @@ type FinancialReportDataService struct {
 	// repo         entity.FinancialReportDataRepositoryInterface
 	income          entity.IncomeRecordServiceInterface
 	expense         entity.ExpenseRecordServiceInterface
+	balance         entity.BankAccountBalanceServiceInterface
 	cache           cache.CacheService
 	messageQueue    message_queue.MessageQueue
 	incomeRecords   []entity.IncomeRecord
@@ -27,6 +28,7 @@ func InitializeFinancialReportDataService(
 	// repo entity.FinancialReportDataRepositoryInterface,
 	income entity.IncomeRecordServiceInterface,
 	expense entity.ExpenseRecordServiceInterface,
+	balance entity.BankAccountBalanceServiceInterface,
 	cacheService cache.CacheService,
 	messageQueue message_queue.MessageQueue,
 ) (entity.FinancialReportDataServiceInterface, error) {
@@ -39,6 +41,10 @@ func InitializeFinancialReportDataService(
 		return nil, fmt.Errorf("expense cannot be nil")
 	}
 
+	if balance == nil {
+		return nil, fmt.Errorf("balance cannot be nil")
+	}
+
 	if cacheService == nil {
 		return nil, fmt.Errorf("cacheService cannot be nil")
 	}
@@ -50,6 +56,7 @@ func InitializeFinancialReportDataService(
 	report := FinancialReportDataService{
 		income:       income,
 		expense:      expense,
+		balance:      balance,
 		cache:        cacheService,
 		messageQueue: messageQueue,
 	}
@@ -78,6 +85,8 @@ func (s *FinancialReportDataService) GetFinancialReportData(ctx context.Context)
 	s.getMonthlyCashFlow(ctx)
 	s.getExpenseByCategory(ctx)
 	s.getExpenseByCategoryLast12Months(ctx)
+	s.financialReport.InstallmentCommitments = entity.InstallmentCommitments(s.expenseRecords, time.Now())
+	s.getBalanceByAccountType(ctx)
 
 	if s.financialReport != nil {
 		s.cache.Set(ctx, cacheKeyFinancialReport, *s.financialReport, serviceCacheTTL)
@@ -175,6 +184,7 @@ func (s *FinancialReportDataService) getExpenseRecords(ctx context.Context) erro
 	if err != nil {
 		return err
 	}
+	report = entity.WithoutInvoicePayments(entity.WithoutCancelledExpenses(report))
 
 	if len(report) > 0 {
 		cacheData, _ := json.Marshal(report)
@@ -266,6 +276,17 @@ func (s *FinancialReportDataService) getSummaryCards(ctx context.Context) error
 	return nil
 }
 
+// getBalanceByAccountType sets the current balances of the accounts summed per account type.
+func (s *FinancialReportDataService) getBalanceByAccountType(ctx context.Context) error {
+	balances, err := s.balance.GetBalances(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	s.financialReport.BalanceByAccountType = entity.GroupBalancesByAccountType(balances)
+	return nil
+}
+
 func (s *FinancialReportDataService) getMonthlyCashFlow(ctx context.Context) error {
 
 	_, err := utils.GetUserID(ctx)
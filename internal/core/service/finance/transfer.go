@@ -0,0 +1,123 @@
+package finance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"github.com/Tomelin/dashfin-backend-app/pkg/message_queue"
+)
+
+// TransferService records transfers between the bank accounts of the user.
+type TransferService struct {
+	Repo     entity_finance.TransferRepositoryInterface
+	Accounts entity_finance.BankAccountRepositoryInterface
+	mq       message_queue.MessageQueue
+}
+
+// InitializeTransferService creates a new TransferService.
+func InitializeTransferService(repo entity_finance.TransferRepositoryInterface, accounts entity_finance.BankAccountRepositoryInterface, mq message_queue.MessageQueue) (entity_finance.TransferServiceInterface, error) {
+	if repo == nil {
+		return nil, errors.New("repository is nil for TransferService")
+	}
+	if accounts == nil {
+		return nil, errors.New("bank account repository is nil for TransferService")
+	}
+	return &TransferService{
+		Repo:     repo,
+		Accounts: accounts,
+		mq:       mq,
+	}, nil
+}
+
+// CreateTransfer stores the transfer once both accounts are found.
+func (s *TransferService) CreateTransfer(ctx context.Context, data *entity_finance.Transfer) (*entity_finance.Transfer, error) {
+	if data == nil {
+		return nil, errors.New("transfer data is nil")
+	}
+
+	if err := data.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if data.UserID == "" {
+		return nil, errors.New("userID is required in transfer data")
+	}
+
+	if _, err := s.Accounts.GetBankAccountByID(ctx, &data.FromBankAccountID); err != nil {
+		return nil, fmt.Errorf("validation failed: fromBankAccountId: %w", err)
+	}
+	if _, err := s.Accounts.GetBankAccountByID(ctx, &data.ToBankAccountID); err != nil {
+		return nil, fmt.Errorf("validation failed: toBankAccountId: %w", err)
+	}
+	if err := checkBankAccountNotArchived(ctx, s.Accounts, "fromBankAccountId", data.FromBankAccountID); err != nil {
+		return nil, err
+	}
+	if err := checkBankAccountNotArchived(ctx, s.Accounts, "toBankAccountId", data.ToBankAccountID); err != nil {
+		return nil, err
+	}
+
+	data.CreatedAt = time.Now()
+	data.UpdatedAt = time.Now()
+
+	result, err := s.Repo.CreateTransfer(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	b, _ := json.Marshal(result)
+	s.publishMessage(ctx, mq_rk_transfer_create, b, "")
+
+	return result, nil
+}
+
+// GetTransferByID returns a transfer of the user.
+func (s *TransferService) GetTransferByID(ctx context.Context, id string) (*entity_finance.Transfer, error) {
+	if id == "" {
+		return nil, errors.New("id is required")
+	}
+
+	return s.Repo.GetTransferByID(ctx, id)
+}
+
+// GetTransfers returns the transfers of the user, the most recent first.
+func (s *TransferService) GetTransfers(ctx context.Context) ([]entity_finance.Transfer, error) {
+	transfers, err := s.Repo.GetTransfers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(transfers, func(i, j int) bool {
+		return transfers[i].TransferDate.After(transfers[j].TransferDate)
+	})
+	return transfers, nil
+}
+
+// DeleteTransfer removes a transfer, which gives the amount back to the source account.
+func (s *TransferService) DeleteTransfer(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("id is required")
+	}
+
+	transfer, err := s.Repo.GetTransferByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.Repo.DeleteTransfer(ctx, id); err != nil {
+		return err
+	}
+
+	b, _ := json.Marshal(transfer)
+	s.publishMessage(ctx, mq_rk_transfer_delete, b, "")
+
+	return nil
+}
+
+func (s *TransferService) publishMessage(ctx context.Context, routeKey string, body []byte, trace string) error {
+	return s.mq.PublisherWithRouteKey(mq_exchange, routeKey, body, trace)
+}
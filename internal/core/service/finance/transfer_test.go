@@ -0,0 +1,119 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBankAccountRepository finds the accounts it was built with.
+type fakeBankAccountRepository struct {
+	accounts map[string]entity_finance.BankAccountRequest
+}
+
+func (r *fakeBankAccountRepository) CreateBankAccount(ctx context.Context, data *entity_finance.BankAccount) (*entity_finance.BankAccountRequest, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *fakeBankAccountRepository) GetBankAccountByID(ctx context.Context, id *string) (*entity_finance.BankAccountRequest, error) {
+	account, ok := r.accounts[*id]
+	if !ok {
+		return nil, errors.New("bank account not found")
+	}
+	return &account, nil
+}
+
+func (r *fakeBankAccountRepository) GetBankAccounts(ctx context.Context) ([]entity_finance.BankAccountRequest, error) {
+	accounts := make([]entity_finance.BankAccountRequest, 0, len(r.accounts))
+	for _, account := range r.accounts {
+		accounts = append(accounts, account)
+	}
+	return accounts, nil
+}
+
+func (r *fakeBankAccountRepository) GetByFilter(ctx context.Context, data map[string]interface{}) ([]entity_finance.BankAccountRequest, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *fakeBankAccountRepository) UpdateBankAccount(ctx context.Context, data *entity_finance.BankAccountRequest) (*entity_finance.BankAccountRequest, error) {
+	r.accounts[data.ID] = *data
+	return data, nil
+}
+
+func (r *fakeBankAccountRepository) DeleteBankAccount(ctx context.Context, id *string) error {
+	delete(r.accounts, *id)
+	return nil
+}
+
+// fakeTransferRepository keeps transfers in memory.
+type fakeTransferRepository struct {
+	transfers map[string]entity_finance.Transfer
+}
+
+func (r *fakeTransferRepository) CreateTransfer(ctx context.Context, data *entity_finance.Transfer) (*entity_finance.Transfer, error) {
+	data.ID = "transfer-1"
+	r.transfers[data.ID] = *data
+	return data, nil
+}
+
+func (r *fakeTransferRepository) GetTransferByID(ctx context.Context, id string) (*entity_finance.Transfer, error) {
+	transfer, ok := r.transfers[id]
+	if !ok {
+		return nil, errors.New("transfer not found")
+	}
+	return &transfer, nil
+}
+
+func (r *fakeTransferRepository) GetTransfers(ctx context.Context) ([]entity_finance.Transfer, error) {
+	transfers := make([]entity_finance.Transfer, 0, len(r.transfers))
+	for _, transfer := range r.transfers {
+		transfers = append(transfers, transfer)
+	}
+	return transfers, nil
+}
+
+func (r *fakeTransferRepository) UpdateTransfer(ctx context.Context, data *entity_finance.Transfer) (*entity_finance.Transfer, error) {
+	r.transfers[data.ID] = *data
+	return data, nil
+}
+
+func (r *fakeTransferRepository) DeleteTransfer(ctx context.Context, id string) error {
+	delete(r.transfers, id)
+	return nil
+}
+
+func TestTransferService(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "UserID", "user-1")
+	repo := &fakeTransferRepository{transfers: make(map[string]entity_finance.Transfer)}
+	mq := &fakeMessageQueue{}
+	s := &TransferService{
+		Repo: repo,
+		Accounts: &fakeBankAccountRepository{accounts: map[string]entity_finance.BankAccountRequest{
+			"checking": {ID: "checking"},
+			"savings":  {ID: "savings"},
+			"closed":   {ID: "closed", BankAccount: entity_finance.BankAccount{Archived: true}},
+		}},
+		mq: mq,
+	}
+
+	_, err := s.CreateTransfer(ctx, &entity_finance.Transfer{UserID: "user-1", FromBankAccountID: "checking", ToBankAccountID: "closed", Amount: 100, TransferDate: time.Now()})
+	assert.ErrorContains(t, err, "validation failed: toBankAccountId: bank account is archived")
+
+	_, err = s.CreateTransfer(ctx, &entity_finance.Transfer{UserID: "user-1", FromBankAccountID: "checking", ToBankAccountID: "missing", Amount: 100, TransferDate: time.Now()})
+	assert.ErrorContains(t, err, "validation failed: toBankAccountId")
+
+	transfer, err := s.CreateTransfer(ctx, &entity_finance.Transfer{UserID: "user-1", FromBankAccountID: "checking", ToBankAccountID: "savings", Amount: 100, TransferDate: time.Now()})
+	require.NoError(t, err)
+	assert.Equal(t, []string{mq_rk_transfer_create}, mq.published)
+
+	require.NoError(t, s.DeleteTransfer(ctx, transfer.ID))
+	assert.Equal(t, []string{mq_rk_transfer_create, mq_rk_transfer_delete}, mq.published)
+	assert.Empty(t, repo.transfers)
+
+	assert.ErrorContains(t, s.DeleteTransfer(ctx, transfer.ID), "not found")
+}
@@ -16,6 +16,14 @@ const (
 	mq_queue_bank_account  = "bank_account"
 	mq_queue_credit_card   = "credit_card"
 	mq_queue_spending_plan = "spending_plan"
+	mq_queue_nfce_import   = "nfce_import"
+	mq_rk_nfce_import      = "expense.nfce.import"
+
+	// mq_rk_credit_card_limit is published when a card crosses a limit alert threshold.
+	mq_rk_credit_card_limit = "credit_card.limit.threshold"
+
+	mq_rk_transfer_create = "transfer.record.create"
+	mq_rk_transfer_delete = "transfer.record.delete"
 )
 
 // Cache attributes
@@ -33,6 +41,12 @@ const (
 	cacheKeyFinancialReport				 = "financial_report"
 )
 
+// bankFeeSchedule is how often the monthly fees of the bank accounts are generated.
+const bankFeeSchedule = 6 * time.Hour
+
+// recurrenceSchedule is how often the occurrences of the recurring series are written.
+const recurrenceSchedule = 6 * time.Hour
+
 // Date and time
 const (
 	dateLayout = "2006-01-02"
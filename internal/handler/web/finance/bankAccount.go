@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
 	web "github.com/Tomelin/dashfin-backend-app/internal/handler/web"
@@ -19,6 +20,7 @@ type BankAccountHandlerInterface interface {
 	GetBankAccounts(c *gin.Context)
 	UpdateBankAccount(c *gin.Context)
 	DeleteBankAccount(c *gin.Context)
+	ArchiveBankAccount(c *gin.Context)
 }
 
 type BankAccountHandler struct {
@@ -52,6 +54,7 @@ func (h *BankAccountHandler) setupRoutes(routerGroup *gin.RouterGroup, middlewar
 	routerGroup.GET("/finance/bank-accounts", append(middlewareList, h.GetBankAccounts)...)
 	routerGroup.PUT("/finance/bank-accounts/:id", append(middlewareList, h.UpdateBankAccount)...)
 	routerGroup.DELETE("/finance/bank-accounts/:id", append(middlewareList, h.DeleteBankAccount)...)
+	routerGroup.POST("/finance/bank-accounts/:id/archive", append(middlewareList, h.ArchiveBankAccount)...)
 }
 
 func (h *BankAccountHandler) CreateBankAccount(c *gin.Context) {
@@ -267,12 +270,71 @@ func (h *BankAccountHandler) DeleteBankAccount(c *gin.Context) {
 	ctx := context.WithValue(c.Request.Context(), "Authorization", token)
 	ctx = context.WithValue(ctx, "UserID", userId)
 
-	err = h.service.DeleteBankAccount(ctx, &id)
+	// With reassignTo the records of the account are moved to that account before it is deleted.
+	if reassignTo := c.Query("reassignTo"); reassignTo != "" {
+		err = h.service.ReassignBankAccount(ctx, &id, &reassignTo)
+	} else {
+		err = h.service.DeleteBankAccount(ctx, &id)
+	}
 	if err != nil {
 
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(deleteErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusNoContent, nil)
 }
+
+func (h *BankAccountHandler) ArchiveBankAccount(c *gin.Context) {
+	userId, token, err := web.GetRequiredHeaders(h.authClient, c.Request)
+	if err != nil {
+
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id parameter is required"})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), "Authorization", token)
+	ctx = context.WithValue(ctx, "UserID", userId)
+
+	result, err := h.service.ArchiveBankAccount(ctx, &id)
+	if err != nil {
+
+		c.JSON(deleteErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	b, err := json.Marshal(result)
+	if err != nil {
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	encryptedResult, err := h.encryptData.EncryptPayload(b)
+	if err != nil {
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payload": encryptedResult})
+}
+
+// deleteErrorStatus maps the errors of deleting, reassigning or archiving an account or a
+// card: one still in use is a conflict.
+func deleteErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "in use"):
+		return http.StatusConflict
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	default:
+		return http.StatusBadRequest
+	}
+}
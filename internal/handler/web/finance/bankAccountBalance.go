@@ -0,0 +1,250 @@
+package web_finance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	web "github.com/Tomelin/dashfin-backend-app/internal/handler/web"
+	"github.com/Tomelin/dashfin-backend-app/pkg/authenticatior"
+	cryptdata "github.com/Tomelin/dashfin-backend-app/pkg/cryptData"
+	"github.com/gin-gonic/gin"
+)
+
+type BankAccountBalanceHandlerInterface interface {
+	GetBalances(c *gin.Context)
+	CreateCheckpoint(c *gin.Context)
+	GetCheckpoints(c *gin.Context)
+	Reconcile(c *gin.Context)
+	PostAdjustment(c *gin.Context)
+}
+
+type BankAccountBalanceHandler struct {
+	service     entity_finance.BankAccountBalanceServiceInterface
+	router      *gin.RouterGroup
+	encryptData cryptdata.CryptDataInterface
+	authClient  authenticatior.Authenticator
+}
+
+// balanceCheckpointDTO is the payload of the create checkpoint operation.
+type balanceCheckpointDTO struct {
+	Date             string  `json:"date"` // YYYY-MM-DD
+	StatementBalance float64 `json:"statementBalance"`
+}
+
+func InitializeBankAccountBalanceHandler(svc entity_finance.BankAccountBalanceServiceInterface, encryptData cryptdata.CryptDataInterface, authClient authenticatior.Authenticator, routerGroup *gin.RouterGroup, middleware ...func(c *gin.Context)) BankAccountBalanceHandlerInterface {
+	handler := &BankAccountBalanceHandler{
+		service:     svc,
+		router:      routerGroup,
+		encryptData: encryptData,
+		authClient:  authClient,
+	}
+
+	handler.setupRoutes(middleware...)
+
+	return handler
+}
+
+func (h *BankAccountBalanceHandler) setupRoutes(middleware ...func(c *gin.Context)) {
+	middlewareList := make([]gin.HandlerFunc, len(middleware))
+	for i, mw := range middleware {
+		middlewareList[i] = mw
+	}
+
+	h.router.GET("/finance/bank-accounts/balances", append(middlewareList, h.GetBalances)...)
+
+	accountGroup := h.router.Group("/finance/bank-accounts/:id")
+	accountGroup.Use(middlewareList...)
+
+	accountGroup.POST("/checkpoints", append(middlewareList, h.CreateCheckpoint)...)
+	accountGroup.GET("/checkpoints", append(middlewareList, h.GetCheckpoints)...)
+	accountGroup.GET("/reconciliation", append(middlewareList, h.Reconcile)...)
+	accountGroup.POST("/reconciliation/adjustment", append(middlewareList, h.PostAdjustment)...)
+}
+
+// GetBalances returns the computed balance of every account; ?date=YYYY-MM-DD defaults to today.
+func (h *BankAccountBalanceHandler) GetBalances(c *gin.Context) {
+	userId, token, err := web.GetRequiredHeaders(h.authClient, c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	date, err := parseBalanceDate(c.Query("date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), "Authorization", token)
+	ctx = context.WithValue(ctx, "UserID", userId)
+
+	results, err := h.service.GetBalances(ctx, date)
+	h.respond(c, http.StatusOK, results, err)
+}
+
+func (h *BankAccountBalanceHandler) CreateCheckpoint(c *gin.Context) {
+	userId, token, err := web.GetRequiredHeaders(h.authClient, c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id parameter is required"})
+		return
+	}
+
+	var payload cryptdata.CryptData
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := h.encryptData.PayloadData(payload.Payload)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var checkpointData balanceCheckpointDTO
+	if err := json.Unmarshal(data, &checkpointData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", checkpointData.Date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date must be in YYYY-MM-DD format"})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), "Authorization", token)
+	ctx = context.WithValue(ctx, "UserID", userId)
+
+	result, err := h.service.CreateCheckpoint(ctx, &entity_finance.BalanceCheckpoint{
+		BankAccountID:    id,
+		Date:             date,
+		StatementBalance: checkpointData.StatementBalance,
+	})
+	h.respond(c, http.StatusCreated, result, err)
+}
+
+func (h *BankAccountBalanceHandler) GetCheckpoints(c *gin.Context) {
+	userId, token, err := web.GetRequiredHeaders(h.authClient, c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id parameter is required"})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), "Authorization", token)
+	ctx = context.WithValue(ctx, "UserID", userId)
+
+	results, err := h.service.GetCheckpoints(ctx, id)
+	h.respond(c, http.StatusOK, results, err)
+}
+
+// Reconcile compares the computed balance with the checkpoint of ?date=YYYY-MM-DD.
+func (h *BankAccountBalanceHandler) Reconcile(c *gin.Context) {
+	userId, token, err := web.GetRequiredHeaders(h.authClient, c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id parameter is required"})
+		return
+	}
+
+	date, err := parseBalanceDate(c.Query("date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), "Authorization", token)
+	ctx = context.WithValue(ctx, "UserID", userId)
+
+	result, err := h.service.Reconcile(ctx, id, date)
+	h.respond(c, http.StatusOK, result, err)
+}
+
+// PostAdjustment posts the difference with the checkpoint of ?date=YYYY-MM-DD as an adjustment.
+func (h *BankAccountBalanceHandler) PostAdjustment(c *gin.Context) {
+	userId, token, err := web.GetRequiredHeaders(h.authClient, c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id parameter is required"})
+		return
+	}
+
+	date, err := parseBalanceDate(c.Query("date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), "Authorization", token)
+	ctx = context.WithValue(ctx, "UserID", userId)
+
+	result, err := h.service.PostAdjustment(ctx, id, date)
+	h.respond(c, http.StatusCreated, result, err)
+}
+
+func parseBalanceDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Now().UTC().Truncate(24 * time.Hour), nil
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, errors.New("date must be in YYYY-MM-DD format")
+	}
+	return date, nil
+}
+
+func (h *BankAccountBalanceHandler) respond(c *gin.Context, status int, result interface{}, err error) {
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "validation failed"):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	b, err := json.Marshal(result)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	encryptedResult, err := h.encryptData.EncryptPayload(b)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(status, gin.H{"payload": encryptedResult})
+}
@@ -19,6 +19,7 @@ type CreditCardHandlerInterface interface {
 	GetCreditCards(c *gin.Context)
 	UpdateCreditCard(c *gin.Context)
 	DeleteCreditCard(c *gin.Context)
+	ArchiveCreditCard(c *gin.Context)
 }
 
 type CreditCardHandler struct {
@@ -55,6 +56,7 @@ func (h *CreditCardHandler) setupRoutes(middleware ...func(c *gin.Context)) {
 	credCardGroup.GET("", append(middlewareList, h.GetCreditCards)...)
 	credCardGroup.PUT("/:id", append(middlewareList, h.UpdateCreditCard)...)
 	credCardGroup.DELETE("/:id", append(middlewareList, h.DeleteCreditCard)...)
+	credCardGroup.POST("/:id/archive", append(middlewareList, h.ArchiveCreditCard)...)
 }
 
 func (h *CreditCardHandler) CreateCreditCard(c *gin.Context) {
@@ -272,12 +274,58 @@ func (h *CreditCardHandler) DeleteCreditCard(c *gin.Context) {
 	ctx := context.WithValue(c.Request.Context(), "Authorization", token)
 	ctx = context.WithValue(ctx, "UserID", userId)
 
-	err = h.service.DeleteCreditCard(ctx, &id)
+	// With reassignTo the purchases of the card are moved to that card before it is deleted.
+	if reassignTo := c.Query("reassignTo"); reassignTo != "" {
+		err = h.service.ReassignCreditCard(ctx, &id, &reassignTo)
+	} else {
+		err = h.service.DeleteCreditCard(ctx, &id)
+	}
 	if err != nil {
 
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(deleteErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusNoContent, nil)
 }
+
+func (h *CreditCardHandler) ArchiveCreditCard(c *gin.Context) {
+	userId, token, err := web.GetRequiredHeaders(h.authClient, c.Request)
+	if err != nil {
+
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id parameter is required"})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), "Authorization", token)
+	ctx = context.WithValue(ctx, "UserID", userId)
+
+	result, err := h.service.ArchiveCreditCard(ctx, &id)
+	if err != nil {
+
+		c.JSON(deleteErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	b, err := json.Marshal(result)
+	if err != nil {
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	encryptedResult, err := h.encryptData.EncryptPayload(b)
+	if err != nil {
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payload": encryptedResult})
+}
@@ -0,0 +1,224 @@
+package web_finance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	web "github.com/Tomelin/dashfin-backend-app/internal/handler/web"
+	"github.com/Tomelin/dashfin-backend-app/pkg/authenticatior"
+	cryptdata "github.com/Tomelin/dashfin-backend-app/pkg/cryptData"
+	"github.com/gin-gonic/gin"
+)
+
+type CreditCardInvoiceHandlerInterface interface {
+	GetInvoices(c *gin.Context)
+	GetInvoice(c *gin.Context)
+	PayInvoice(c *gin.Context)
+	GetCreditCardLimit(c *gin.Context)
+}
+
+type CreditCardInvoiceHandler struct {
+	service     entity_finance.CreditCardInvoiceServiceInterface
+	router      *gin.RouterGroup
+	encryptData cryptdata.CryptDataInterface
+	authClient  authenticatior.Authenticator
+}
+
+// creditCardInvoicePaymentDTO is the payload of the pay operation; paymentDate defaults to today.
+type creditCardInvoicePaymentDTO struct {
+	BankAccountID string `json:"bankAccountId"`
+	PaymentDate   string `json:"paymentDate,omitempty"`
+}
+
+func (p *creditCardInvoicePaymentDTO) toEntity() (*entity_finance.CreditCardInvoicePayment, error) {
+	payment := &entity_finance.CreditCardInvoicePayment{
+		BankAccountID: p.BankAccountID,
+		PaymentDate:   time.Now().UTC().Truncate(24 * time.Hour),
+	}
+
+	if p.PaymentDate != "" {
+		paymentDate, err := time.Parse("2006-01-02", p.PaymentDate)
+		if err != nil {
+			return nil, errors.New("paymentDate must be in YYYY-MM-DD format")
+		}
+		payment.PaymentDate = paymentDate
+	}
+
+	return payment, payment.Validate()
+}
+
+func InitializeCreditCardInvoiceHandler(svc entity_finance.CreditCardInvoiceServiceInterface, encryptData cryptdata.CryptDataInterface, authClient authenticatior.Authenticator, routerGroup *gin.RouterGroup, middleware ...func(c *gin.Context)) CreditCardInvoiceHandlerInterface {
+	handler := &CreditCardInvoiceHandler{
+		service:     svc,
+		router:      routerGroup,
+		encryptData: encryptData,
+		authClient:  authClient,
+	}
+
+	handler.setupRoutes(middleware...)
+
+	return handler
+}
+
+func (h *CreditCardInvoiceHandler) setupRoutes(middleware ...func(c *gin.Context)) {
+	middlewareList := make([]gin.HandlerFunc, len(middleware))
+	for i, mw := range middleware {
+		middlewareList[i] = mw
+	}
+
+	invoiceGroup := h.router.Group("/finance/cards/:id/invoices")
+	invoiceGroup.Use(middlewareList...)
+
+	invoiceGroup.GET("", append(middlewareList, h.GetInvoices)...)
+	invoiceGroup.GET("/:month", append(middlewareList, h.GetInvoice)...)
+	invoiceGroup.POST("/:month/pay", append(middlewareList, h.PayInvoice)...)
+
+	h.router.GET("/finance/cards/:id/limit", append(middlewareList, h.GetCreditCardLimit)...)
+}
+
+// GetInvoices lists the invoices of the card; ?status=open,closed filters them by status.
+func (h *CreditCardInvoiceHandler) GetInvoices(c *gin.Context) {
+	userId, token, err := web.GetRequiredHeaders(h.authClient, c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id parameter is required"})
+		return
+	}
+
+	statuses, err := entity_finance.ParseCreditCardInvoiceStatuses(c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), "Authorization", token)
+	ctx = context.WithValue(ctx, "UserID", userId)
+
+	results, err := h.service.GetInvoices(ctx, id, statuses...)
+	h.respond(c, results, err)
+}
+
+func (h *CreditCardInvoiceHandler) GetInvoice(c *gin.Context) {
+	userId, token, err := web.GetRequiredHeaders(h.authClient, c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id parameter is required"})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), "Authorization", token)
+	ctx = context.WithValue(ctx, "UserID", userId)
+
+	result, err := h.service.GetInvoice(ctx, id, c.Param("month"))
+	h.respond(c, result, err)
+}
+
+func (h *CreditCardInvoiceHandler) PayInvoice(c *gin.Context) {
+	userId, token, err := web.GetRequiredHeaders(h.authClient, c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id parameter is required"})
+		return
+	}
+
+	var payload cryptdata.CryptData
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := h.encryptData.PayloadData(payload.Payload)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var paymentDTO creditCardInvoicePaymentDTO
+	if err := json.Unmarshal(data, &paymentDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	payment, err := paymentDTO.toEntity()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), "Authorization", token)
+	ctx = context.WithValue(ctx, "UserID", userId)
+
+	result, err := h.service.PayInvoice(ctx, id, c.Param("month"), payment)
+	h.respond(c, result, err)
+}
+
+// GetCreditCardLimit returns the committed and available limit of the card.
+func (h *CreditCardInvoiceHandler) GetCreditCardLimit(c *gin.Context) {
+	userId, token, err := web.GetRequiredHeaders(h.authClient, c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id parameter is required"})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), "Authorization", token)
+	ctx = context.WithValue(ctx, "UserID", userId)
+
+	result, err := h.service.GetCreditCardLimit(ctx, id)
+	h.respond(c, result, err)
+}
+
+func (h *CreditCardInvoiceHandler) respond(c *gin.Context, result interface{}, err error) {
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case strings.Contains(err.Error(), "invalid status transition"):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case strings.Contains(err.Error(), "validation failed"):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	b, err := json.Marshal(result)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	encryptedResult, err := h.encryptData.EncryptPayload(b)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payload": encryptedResult})
+}
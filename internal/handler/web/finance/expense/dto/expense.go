@@ -21,11 +21,26 @@ type ExpenseRecordDTO struct {
 	CustomBankName   string    `json:"customBankName,omitempty"`
 	Description      string    `json:"description,omitempty"`
 	PaymentDate      string    `json:"paymentDate,omitempty"`
+	Status           string    `json:"status,omitempty"`
 	RecurrenceCount  int       `json:"recurrenceCount,omitempty"`
 	RecurrenceNumber int       `json:"recurrenceNumber,omitempty"`
 	Subcategory      string    `json:"subcategory,omitempty"`
+	NfceAccessKey    string    `json:"nfceAccessKey,omitempty"`
 	CreatedAt        time.Time `json:"createdAt,omitempty"`
 	UpdatedAt        time.Time `json:"updatedAt,omitempty"`
+
+	// Recurrence (or its RRULE notation, e.g. "FREQ=MONTHLY;BYMONTHDAY=-1") replaces recurrenceCount.
+	Recurrence *entity_finance.RecurrenceRule `json:"recurrence,omitempty"`
+	RRule      string                         `json:"rrule,omitempty"`
+	SeriesID   string                         `json:"seriesId,omitempty"`
+
+	// CreditCardID charges the expense to a card; InvoiceID is the invoice it was assigned to.
+	CreditCardID string `json:"creditCardId,omitempty"`
+	InvoiceID    string `json:"invoiceId,omitempty"`
+
+	// Set on the charges of an installment purchase, e.g. "installment 3/10".
+	InstallmentPurchaseID string `json:"installmentPurchaseId,omitempty"`
+	InstallmentLabel      string `json:"installmentLabel,omitempty"`
 }
 
 func (er *ExpenseRecordDTO) Validate() error {
@@ -75,7 +90,7 @@ func (er *ExpenseRecordDTO) Validate() error {
 			return errors.New("userID is required")
 		}
 
-		if er.IsRecurring && er.RecurrenceCount <= 0 {
+		if er.IsRecurring && er.RecurrenceCount <= 0 && er.Recurrence == nil && er.RRule == "" && er.SeriesID == "" {
 			return errors.New("recurrenceCount must be greater than 0 when isRecurring is true")
 		}
 	}
@@ -102,12 +117,18 @@ func (er *ExpenseRecordDTO) ToEntity() (*entity_finance.ExpenseRecord, error) {
 		}
 	}
 
+	recurrence, err := er.recurrence()
+	if err != nil {
+		return nil, err
+	}
+
 	expense := &entity_finance.ExpenseRecord{
 		ID:               er.ID,
 		Category:         er.Category,
 		Subcategory:      er.Subcategory,
 		DueDate:          dueDate,
 		PaymentDate:      paymentDate,
+		Status:           entity_finance.ExpenseStatus(er.Status),
 		Amount:           er.Amount,
 		BankPaidFrom:     er.BankPaidFrom,
 		CustomBankName:   er.CustomBankName,
@@ -115,6 +136,8 @@ func (er *ExpenseRecordDTO) ToEntity() (*entity_finance.ExpenseRecord, error) {
 		IsRecurring:      er.IsRecurring,
 		RecurrenceNumber: er.RecurrenceNumber,
 		RecurrenceCount:  er.RecurrenceCount,
+		Recurrence:       recurrence,
+		CreditCardID:     er.CreditCardID,
 		CreatedAt:        er.CreatedAt,
 		UpdatedAt:        er.UpdatedAt,
 		UserID:           er.UserID,
@@ -138,6 +161,7 @@ func (er *ExpenseRecordDTO) FromEntity(expense *entity_finance.ExpenseRecord) {
 	} else {
 		er.PaymentDate = ""
 	}
+	er.Status = string(expense.EffectiveStatus(time.Now()))
 	er.Amount = expense.Amount
 	er.BankPaidFrom = expense.BankPaidFrom
 	er.CustomBankName = expense.CustomBankName
@@ -145,8 +169,59 @@ func (er *ExpenseRecordDTO) FromEntity(expense *entity_finance.ExpenseRecord) {
 	er.IsRecurring = expense.IsRecurring
 	er.RecurrenceNumber = expense.RecurrenceNumber
 	er.RecurrenceCount = expense.RecurrenceCount
+	er.SeriesID = expense.SeriesID
+	er.CreditCardID = expense.CreditCardID
+	er.InvoiceID = expense.InvoiceID
+	er.InstallmentPurchaseID = expense.InstallmentPurchaseID
+	er.InstallmentLabel = expense.InstallmentLabel()
+	er.NfceAccessKey = expense.NfceAccessKey
 	er.CreatedAt = expense.CreatedAt
 	er.UpdatedAt = expense.UpdatedAt
 	er.UserID = expense.UserID
 
 }
+
+// recurrence returns the requested rule, parsing the RRULE notation when it is used.
+func (er *ExpenseRecordDTO) recurrence() (*entity_finance.RecurrenceRule, error) {
+	if er.RRule != "" {
+		return entity_finance.ParseRecurrenceRule(er.RRule)
+	}
+	return er.Recurrence, nil
+}
+
+// ExpensePaymentDTO is the payload of the pay operation.
+type ExpensePaymentDTO struct {
+	PaymentDate    string `json:"paymentDate"`
+	BankPaidFrom   string `json:"bankPaidFrom"`
+	CustomBankName string `json:"customBankName,omitempty"`
+}
+
+// ToEntity converts the payload, defaulting the payment date to today.
+func (p *ExpensePaymentDTO) ToEntity() (*entity_finance.ExpensePayment, error) {
+	payment := &entity_finance.ExpensePayment{
+		BankPaidFrom:   p.BankPaidFrom,
+		CustomBankName: p.CustomBankName,
+	}
+
+	if p.PaymentDate == "" {
+		payment.PaymentDate = time.Now().UTC().Truncate(24 * time.Hour)
+	} else {
+		paymentDate, err := time.Parse("2006-01-02", p.PaymentDate)
+		if err != nil {
+			return nil, errors.New("paymentDate must be in YYYY-MM-DD format")
+		}
+		payment.PaymentDate = paymentDate
+	}
+
+	if err := payment.Validate(); err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// ExpenseRecordPageDTO is the response of a paginated listing. NextCursor is empty on the last page.
+type ExpenseRecordPageDTO struct {
+	Records    []ExpenseRecordDTO `json:"records"`
+	NextCursor string             `json:"nextCursor"`
+}
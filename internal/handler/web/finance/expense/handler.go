@@ -6,6 +6,7 @@ import (
 	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
 	web "github.com/Tomelin/dashfin-backend-app/internal/handler/web"
@@ -23,8 +24,8 @@ type ExpenseRecordHandlerInterface interface {
 	GetExpenseRecordsByFilter(c *gin.Context) // Added for filtering
 	UpdateExpenseRecord(c *gin.Context)
 	DeleteExpenseRecord(c *gin.Context)
-	CreateExpenseByNfceUrl(c *gin.Context)
-	ProcessExpenseByNfceUrl(c *gin.Context)
+	PayExpenseRecord(c *gin.Context)
+	CancelExpenseRecord(c *gin.Context)
 }
 
 // ExpenseRecordHandler handles HTTP requests for ExpenseRecords.
@@ -67,79 +68,8 @@ func (h *ExpenseRecordHandler) setupRoutes(routerGroup *gin.RouterGroup, middlew
 	financeRoutes.POST("/filter", h.GetExpenseRecordsByFilter) // Route for filtered GET
 	financeRoutes.PUT("/:id", h.UpdateExpenseRecord)
 	financeRoutes.DELETE("/:id", h.DeleteExpenseRecord)
-	financeRoutes.POST("/process-nfce-url", h.CreateExpenseByNfceUrl)
-	financeRoutes.GET("/process", h.ProcessExpenseByNfceUrl)
-}
-
-func (h *ExpenseRecordHandler) ProcessExpenseByNfceUrl(c *gin.Context) {
-	ctx := context.WithValue(c.Request.Context(), "Authorization", "token")
-	ctx = context.WithValue(ctx, "UserID", "userID")
-
-	expenseNfceUrl := entity_finance.ExpenseByNfceUrl{
-		NfceUrl:    "https://dfe-portal.svrs.rs.gov.br/Dfe/QrCodeNFce?p=43250400776574163454653020000395661694784220%7C2%7C1%7C1%7Cb5bd8ab6f361bea7d94707cdcacfd96b44b4d42b",
-		UserID:     "userID",
-		ImportMode: entity_finance.NfceUrlItems,
-	}
-
-	_, err := h.service.CreateExpenseByNfceUrl(ctx, &expenseNfceUrl)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create expense record: " + err.Error()})
-		return
-	}
-
-	c.JSON(http.StatusCreated, gin.H{"payload": "ok"})
-}
-
-func (h *ExpenseRecordHandler) CreateExpenseByNfceUrl(c *gin.Context) {
-	userID, token, err := web.GetRequiredHeaders(h.authClient, c.Request)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	var payload cryptdata.CryptData
-	if err := c.ShouldBindJSON(&payload); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload: " + err.Error()})
-		return
-	}
-
-	decryptedData, err := h.encryptData.PayloadData(payload.Payload)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Error processing request data: " + err.Error()})
-		return
-	}
-
-	var expenseNfceUrl entity_finance.ExpenseByNfceUrl
-	if err := json.Unmarshal(decryptedData, &expenseNfceUrl); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data format: " + err.Error()})
-		return
-	}
-
-	expenseNfceUrl.UserID = userID
-
-	ctx := context.WithValue(c.Request.Context(), "Authorization", token)
-	ctx = context.WithValue(ctx, "UserID", userID)
-
-	result, err := h.service.CreateExpenseByNfceUrl(ctx, &expenseNfceUrl)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create expense record: " + err.Error()})
-		return
-	}
-
-	responseBytes, err := json.Marshal(result)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error preparing response: " + err.Error()})
-		return
-	}
-
-	encryptedResult, err := h.encryptData.EncryptPayload(responseBytes)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error securing response: " + err.Error()})
-		return
-	}
-
-	c.JSON(http.StatusCreated, gin.H{"payload": encryptedResult})
-
+	financeRoutes.POST("/:id/pay", h.PayExpenseRecord)
+	financeRoutes.POST("/:id/cancel", h.CancelExpenseRecord)
 }
 
 // CreateExpenseRecord handles the creation of a new expense record.
@@ -229,8 +159,8 @@ func (h *ExpenseRecordHandler) GetExpenseRecordByID(c *gin.Context) {
 	if err != nil {
 		// Distinguish between "not found" and other errors if possible
 		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "access denied") {
-			// c.JSON(http.StatusNoContent, gin.H{"payload": err.Error()})
-			// c.JSON(http.StatusNoContent, gin.H{"error": err.Error()})
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve expense record: " + err.Error()})
 			return
@@ -270,6 +200,22 @@ func (h *ExpenseRecordHandler) GetExpenseRecords(c *gin.Context) {
 	startDate := c.Query("startDate")
 	endDate := c.Query("endDate")
 
+	statuses, err := entity_finance.ParseExpenseStatuses(c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := entity_finance.ParsePageRequest(c.Query("limit"), c.Query("cursor"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if page != nil {
+		h.getExpenseRecordsPage(c, ctx, &entity_finance.ExpenseRecordQueryByDate{StartDate: startDate, EndDate: endDate}, page, statuses)
+		return
+	}
+
 	// define variables for filtering
 	results := make([]entity_finance.ExpenseRecord, 0)
 
@@ -282,7 +228,11 @@ func (h *ExpenseRecordHandler) GetExpenseRecords(c *gin.Context) {
 			filter.EndDate = endDate
 		}
 		// Call the service method that handles filtering
-		results, _ = h.service.GetExpenseRecordsByDate(ctx, &filter)
+		results, err = h.service.GetExpenseRecordsByDate(ctx, &filter)
+		if err != nil && strings.Contains(err.Error(), "validation failed") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 
 		if results == nil {
 			results = []entity_finance.ExpenseRecord{}
@@ -293,6 +243,8 @@ func (h *ExpenseRecordHandler) GetExpenseRecords(c *gin.Context) {
 		results, _ = h.service.GetExpenseRecords(ctx)
 	}
 
+	results = entity_finance.FilterExpenseRecordsByStatus(results, time.Now(), statuses...)
+
 	expenseResponse := make([]dto.ExpenseRecordDTO, 0, len(results))
 	for _, record := range results {
 		expenseDTO := dto.ExpenseRecordDTO{}
@@ -315,6 +267,46 @@ func (h *ExpenseRecordHandler) GetExpenseRecords(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"payload": encryptedResult})
 }
 
+// getExpenseRecordsPage responds with one page of the listing. The status filter applies to
+// the records of the page, so a page may hold fewer records than the limit.
+func (h *ExpenseRecordHandler) getExpenseRecordsPage(c *gin.Context, ctx context.Context, filter *entity_finance.ExpenseRecordQueryByDate, page *entity_finance.PageRequest, statuses []entity_finance.ExpenseStatus) {
+	result, err := h.service.GetExpenseRecordsPage(ctx, filter, page)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid cursor") || strings.Contains(err.Error(), "validation failed") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve expense records: " + err.Error()})
+		return
+	}
+
+	records := entity_finance.FilterExpenseRecordsByStatus(result.Records, time.Now(), statuses...)
+
+	expenseResponse := dto.ExpenseRecordPageDTO{
+		Records:    make([]dto.ExpenseRecordDTO, 0, len(records)),
+		NextCursor: result.NextCursor,
+	}
+	for _, record := range records {
+		expenseDTO := dto.ExpenseRecordDTO{}
+		expenseDTO.FromEntity(&record)
+		expenseResponse.Records = append(expenseResponse.Records, expenseDTO)
+	}
+
+	responseBytes, err := json.Marshal(expenseResponse)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error preparing response: " + err.Error()})
+		return
+	}
+
+	encryptedResult, err := h.encryptData.EncryptPayload(responseBytes)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error securing response: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payload": encryptedResult})
+}
+
 // GetExpenseRecordsByFilter handles fetching expense records based on a filtexpenseRecord.
 func (h *ExpenseRecordHandler) GetExpenseRecordsByFilter(c *gin.Context) {
 	userID, token, err := web.GetRequiredHeaders(h.authClient, c.Request)
@@ -346,6 +338,10 @@ func (h *ExpenseRecordHandler) GetExpenseRecordsByFilter(c *gin.Context) {
 
 	results, err := h.service.GetExpenseRecordsByFilter(ctx, filter)
 	if err != nil {
+		if strings.Contains(err.Error(), "validation failed") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		if strings.Contains(err.Error(), "not found") {
 			c.JSON(http.StatusOK, gin.H{"payload": "[]"}) // Return empty JSON array
 			return
@@ -394,6 +390,13 @@ func (h *ExpenseRecordHandler) UpdateExpenseRecord(c *gin.Context) {
 		return
 	}
 
+	// scope applies the update to the following or all occurrences of a recurring series.
+	scope, err := entity_finance.ParseSeriesScope(c.Query("scope"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	var payload cryptdata.CryptData
 	if err := c.ShouldBindJSON(&payload); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload for update: " + err.Error()})
@@ -426,8 +429,16 @@ func (h *ExpenseRecordHandler) UpdateExpenseRecord(c *gin.Context) {
 
 	// The service's UpdateExpenseRecord should handle verifying ownership and setting UpdatedAt.
 	// Pass the ID from the path and the unmarshalled data.
-	result, err := h.service.UpdateExpenseRecord(ctx, id, expenseRecord)
+	result, err := h.service.UpdateExpenseSeries(ctx, id, expenseRecord, scope)
 	if err != nil {
+		if strings.Contains(err.Error(), "validation failed") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "invalid status transition") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "access denied") {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
@@ -469,11 +480,21 @@ func (h *ExpenseRecordHandler) DeleteExpenseRecord(c *gin.Context) {
 		return
 	}
 
+	scope, err := entity_finance.ParseSeriesScope(c.Query("scope"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	ctx := context.WithValue(c.Request.Context(), "Authorization", token)
 	ctx = context.WithValue(ctx, "UserID", userID)
 
-	err = h.service.DeleteExpenseRecord(ctx, id)
+	err = h.service.DeleteExpenseSeries(ctx, id, scope)
 	if err != nil {
+		if strings.Contains(err.Error(), "validation failed") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "access denied") {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
@@ -485,3 +506,103 @@ func (h *ExpenseRecordHandler) DeleteExpenseRecord(c *gin.Context) {
 
 	c.JSON(http.StatusNoContent, nil)
 }
+
+// PayExpenseRecord handles marking an expense record as paid.
+func (h *ExpenseRecordHandler) PayExpenseRecord(c *gin.Context) {
+	userID, token, err := web.GetRequiredHeaders(h.authClient, c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID parameter is required for payment"})
+		return
+	}
+
+	var payload cryptdata.CryptData
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload for payment: " + err.Error()})
+		return
+	}
+
+	decryptedData, err := h.encryptData.PayloadData(payload.Payload)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error processing request data for payment: " + err.Error()})
+		return
+	}
+
+	var paymentDTO dto.ExpensePaymentDTO
+	if err := json.Unmarshal(decryptedData, &paymentDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data format for payment: " + err.Error()})
+		return
+	}
+
+	payment, err := paymentDTO.ToEntity()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payment data: " + err.Error()})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), "Authorization", token)
+	ctx = context.WithValue(ctx, "UserID", userID)
+
+	result, err := h.service.PayExpenseRecord(ctx, id, payment)
+	h.respondStatusChange(c, result, err)
+}
+
+// CancelExpenseRecord handles marking an expense record as cancelled.
+func (h *ExpenseRecordHandler) CancelExpenseRecord(c *gin.Context) {
+	userID, token, err := web.GetRequiredHeaders(h.authClient, c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID parameter is required for cancel"})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), "Authorization", token)
+	ctx = context.WithValue(ctx, "UserID", userID)
+
+	result, err := h.service.CancelExpenseRecord(ctx, id)
+	h.respondStatusChange(c, result, err)
+}
+
+// respondStatusChange writes the response of the pay and cancel operations.
+func (h *ExpenseRecordHandler) respondStatusChange(c *gin.Context, result *entity_finance.ExpenseRecord, err error) {
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "access denied"):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case strings.Contains(err.Error(), "invalid status transition"):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case strings.Contains(err.Error(), "validation failed"):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update expense record status: " + err.Error()})
+		}
+		return
+	}
+
+	expenseResponse := dto.ExpenseRecordDTO{}
+	expenseResponse.FromEntity(result)
+
+	responseBytes, err := json.Marshal(expenseResponse)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error preparing response: " + err.Error()})
+		return
+	}
+
+	encryptedResult, err := h.encryptData.EncryptPayload(responseBytes)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error securing response: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payload": encryptedResult})
+}
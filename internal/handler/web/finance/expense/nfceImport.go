@@ -0,0 +1,146 @@
+package web_finance_expense
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	web "github.com/Tomelin/dashfin-backend-app/internal/handler/web"
+	"github.com/Tomelin/dashfin-backend-app/pkg/authenticatior"
+	cryptdata "github.com/Tomelin/dashfin-backend-app/pkg/cryptData"
+	"github.com/gin-gonic/gin"
+)
+
+// NFCeImportHandlerInterface defines the HTTP handler operations for NFC-e imports.
+type NFCeImportHandlerInterface interface {
+	EnqueueNFCeImport(c *gin.Context)
+	GetNFCeImportJob(c *gin.Context)
+}
+
+// NFCeImportHandler handles HTTP requests for NFC-e imports.
+type NFCeImportHandler struct {
+	service     entity_finance.NFCeImportServiceInterface
+	encryptData cryptdata.CryptDataInterface
+	authClient  authenticatior.Authenticator
+}
+
+// InitializeNFCeImportHandler creates a new NFCeImportHandler and sets up routes.
+func InitializeNFCeImportHandler(
+	svc entity_finance.NFCeImportServiceInterface,
+	encryptData cryptdata.CryptDataInterface,
+	authClient authenticatior.Authenticator,
+	routerGroup *gin.RouterGroup,
+	middleware ...gin.HandlerFunc,
+) NFCeImportHandlerInterface {
+	handler := &NFCeImportHandler{
+		service:     svc,
+		encryptData: encryptData,
+		authClient:  authClient,
+	}
+
+	handler.setupRoutes(routerGroup, middleware...)
+	return handler
+}
+
+func (h *NFCeImportHandler) setupRoutes(routerGroup *gin.RouterGroup, middleware ...gin.HandlerFunc) {
+
+	financeRoutes := routerGroup.Group("/finance/expenses")
+	for _, mw := range middleware {
+		financeRoutes.Use(mw)
+	}
+
+	financeRoutes.POST("/process-nfce-url", h.EnqueueNFCeImport)
+	financeRoutes.GET("/imports/:jobId", h.GetNFCeImportJob)
+}
+
+// EnqueueNFCeImport handles queueing the import of a NFC-e and returns the job right away.
+func (h *NFCeImportHandler) EnqueueNFCeImport(c *gin.Context) {
+	userID, token, err := web.GetRequiredHeaders(h.authClient, c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var payload cryptdata.CryptData
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload: " + err.Error()})
+		return
+	}
+
+	decryptedData, err := h.encryptData.PayloadData(payload.Payload)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error processing request data: " + err.Error()})
+		return
+	}
+
+	var expenseNfceUrl entity_finance.ExpenseByNfceUrl
+	if err := json.Unmarshal(decryptedData, &expenseNfceUrl); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data format: " + err.Error()})
+		return
+	}
+
+	expenseNfceUrl.UserID = userID
+
+	ctx := context.WithValue(c.Request.Context(), "Authorization", token)
+	ctx = context.WithValue(ctx, "UserID", userID)
+
+	result, err := h.service.EnqueueNFCeImport(ctx, &expenseNfceUrl)
+	if err != nil {
+		if strings.Contains(err.Error(), "validation failed") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import nfce: " + err.Error()})
+		return
+	}
+
+	h.respond(c, http.StatusAccepted, result)
+}
+
+// GetNFCeImportJob handles fetching the status of a NFC-e import.
+func (h *NFCeImportHandler) GetNFCeImportJob(c *gin.Context) {
+	userID, token, err := web.GetRequiredHeaders(h.authClient, c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	jobID := c.Param("jobId")
+	if jobID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "jobId parameter is required"})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), "Authorization", token)
+	ctx = context.WithValue(ctx, "UserID", userID)
+
+	result, err := h.service.GetNFCeImportJob(ctx, jobID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "access denied") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve nfce import: " + err.Error()})
+		return
+	}
+
+	h.respond(c, http.StatusOK, result)
+}
+
+func (h *NFCeImportHandler) respond(c *gin.Context, status int, result interface{}) {
+	responseBytes, err := json.Marshal(result)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error preparing response: " + err.Error()})
+		return
+	}
+
+	encryptedResult, err := h.encryptData.EncryptPayload(responseBytes)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error securing response: " + err.Error()})
+		return
+	}
+
+	c.JSON(status, gin.H{"payload": encryptedResult})
+}
@@ -22,6 +22,11 @@ type IncomeRecordDTO struct {
 	RecurrenceNumber int     `json:"recurrenceNumber,omitempty"` // Pointer to allow null
 	Observations     string  `json:"observations,omitempty"`
 	UserID           string  `json:"userId,omitempty"` // To associate with a user
+
+	// Recurrence (or its RRULE notation, e.g. "FREQ=WEEKLY;INTERVAL=2") replaces recurrenceCount.
+	Recurrence *entity_finance.RecurrenceRule `json:"recurrence,omitempty"`
+	RRule      string                         `json:"rrule,omitempty"`
+	SeriesID   string                         `json:"seriesId,omitempty"`
 }
 
 // Validate checks the IncomeRecord fields for correctness.
@@ -56,7 +61,7 @@ func (ir *IncomeRecordDTO) Validate() error {
 	}
 	ir.ReceiptDate = parsedReceiptDate.Format("2006-01-02") // Ensure consistent format
 
-	if ir.IsRecurring {
+	if ir.IsRecurring && ir.Recurrence == nil && ir.RRule == "" && ir.SeriesID == "" {
 		if ir.RecurrenceCount < 1 {
 			return errors.New("recurrenceCount must be at least 1 if isRecurring is true")
 		}
@@ -87,6 +92,14 @@ func (ir *IncomeRecordDTO) ToEntity() (*entity_finance.IncomeRecord, error) {
 		return nil, err
 	}
 
+	recurrence := ir.Recurrence
+	if ir.RRule != "" {
+		recurrence, err = entity_finance.ParseRecurrenceRule(ir.RRule)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	income := &entity_finance.IncomeRecord{
 		ID:               ir.ID,
 		Category:         ir.Category,
@@ -97,6 +110,8 @@ func (ir *IncomeRecordDTO) ToEntity() (*entity_finance.IncomeRecord, error) {
 		IsRecurring:      ir.IsRecurring,
 		RecurrenceCount:  ir.RecurrenceCount,
 		RecurrenceNumber: ir.RecurrenceNumber,
+		Recurrence:       recurrence,
+		SeriesID:         ir.SeriesID,
 		Observations:     ir.Observations,
 		UserID:           ir.UserID,
 	}
@@ -119,6 +134,13 @@ func (ir *IncomeRecordDTO) FromEntity(income *entity_finance.IncomeRecord) {
 	ir.IsRecurring = income.IsRecurring
 	ir.RecurrenceCount = income.RecurrenceCount
 	ir.RecurrenceNumber = income.RecurrenceNumber
+	ir.SeriesID = income.SeriesID
 	ir.Observations = income.Observations
 	ir.UserID = income.UserID
 }
+
+// IncomeRecordPageDTO is the response of a paginated listing. NextCursor is empty on the last page.
+type IncomeRecordPageDTO struct {
+	Records    []IncomeRecordDTO `json:"records"`
+	NextCursor string            `json:"nextCursor"`
+}
@@ -197,6 +197,16 @@ func (h *IncomeRecordHandler) GetIncomeRecords(c *gin.Context) {
 		SortDirection: &sortDirection,
 	}
 
+	page, err := entity_finance.ParsePageRequest(c.Query("limit"), c.Query("cursor"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if page != nil {
+		h.getIncomeRecordsPage(c, ctx, &record, page)
+		return
+	}
+
 	results, err := h.service.GetIncomeRecords(ctx, &record)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "access denied") {
@@ -230,6 +240,43 @@ func (h *IncomeRecordHandler) GetIncomeRecords(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"payload": encryptedResult})
 }
 
+// getIncomeRecordsPage responds with one page of the listing.
+func (h *IncomeRecordHandler) getIncomeRecordsPage(c *gin.Context, ctx context.Context, params *entity_finance.GetIncomeRecordsQueryParameters, page *entity_finance.PageRequest) {
+	result, err := h.service.GetIncomeRecordsPage(ctx, params, page)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve income records: " + err.Error()})
+		}
+		return
+	}
+
+	incomeResponse := dto.IncomeRecordPageDTO{
+		Records:    make([]dto.IncomeRecordDTO, 0, len(result.Records)),
+		NextCursor: result.NextCursor,
+	}
+	for _, income := range result.Records {
+		var dtoIncome dto.IncomeRecordDTO
+		dtoIncome.FromEntity(&income)
+		incomeResponse.Records = append(incomeResponse.Records, dtoIncome)
+	}
+
+	responseBytes, err := json.Marshal(incomeResponse)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error preparing response: " + err.Error()})
+		return
+	}
+
+	encryptedResult, err := h.encryptData.EncryptPayload(responseBytes)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error securing response: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payload": encryptedResult})
+}
+
 // UpdateIncomeRecord handles updating an existing income record.
 func (h *IncomeRecordHandler) UpdateIncomeRecord(c *gin.Context) {
 	userID, token, err := web.GetRequiredHeaders(h.authClient, c.Request)
@@ -244,6 +291,13 @@ func (h *IncomeRecordHandler) UpdateIncomeRecord(c *gin.Context) {
 		return
 	}
 
+	// scope applies the update to the following or all occurrences of a recurring series.
+	scope, err := entity_finance.ParseSeriesScope(c.Query("scope"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	var payload cryptdata.CryptData
 	if err := c.ShouldBindJSON(&payload); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload for update: " + err.Error()})
@@ -275,7 +329,7 @@ func (h *IncomeRecordHandler) UpdateIncomeRecord(c *gin.Context) {
 
 	income.UserID = id // Ensure the ID is set for the update operation
 
-	result, err := h.service.UpdateIncomeRecord(ctx, id, income)
+	result, err := h.service.UpdateIncomeSeries(ctx, id, income, scope)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "access denied") {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
@@ -316,13 +370,21 @@ func (h *IncomeRecordHandler) DeleteIncomeRecord(c *gin.Context) {
 		return
 	}
 
+	scope, err := entity_finance.ParseSeriesScope(c.Query("scope"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	ctx := context.WithValue(c.Request.Context(), "Authorization", token)
 	ctx = context.WithValue(ctx, "UserID", userID)
 
-	err = h.service.DeleteIncomeRecord(ctx, id)
+	err = h.service.DeleteIncomeSeries(ctx, id, scope)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "access denied") {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else if strings.Contains(err.Error(), "validation failed") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to delete income record: " + err.Error()})
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete income record: " + err.Error()})
 		}
@@ -0,0 +1,213 @@
+package web_finance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	web "github.com/Tomelin/dashfin-backend-app/internal/handler/web"
+	"github.com/Tomelin/dashfin-backend-app/pkg/authenticatior"
+	cryptdata "github.com/Tomelin/dashfin-backend-app/pkg/cryptData"
+	"github.com/gin-gonic/gin"
+)
+
+type InstallmentPurchaseHandlerInterface interface {
+	CreateInstallmentPurchase(c *gin.Context)
+	GetInstallmentPurchase(c *gin.Context)
+	GetInstallmentPurchases(c *gin.Context)
+	DeleteInstallmentPurchase(c *gin.Context)
+}
+
+type InstallmentPurchaseHandler struct {
+	service     entity_finance.InstallmentPurchaseServiceInterface
+	router      *gin.RouterGroup
+	encryptData cryptdata.CryptDataInterface
+	authClient  authenticatior.Authenticator
+}
+
+// installmentPurchaseDTO is the payload of the create operation.
+type installmentPurchaseDTO struct {
+	CreditCardID string  `json:"creditCardId"`
+	Category     string  `json:"category"`
+	Subcategory  string  `json:"subcategory,omitempty"`
+	Description  string  `json:"description,omitempty"`
+	PurchaseDate string  `json:"purchaseDate"` // YYYY-MM-DD
+	TotalAmount  float64 `json:"totalAmount"`
+	Installments int     `json:"installments"`
+	InterestRate float64 `json:"interestRate,omitempty"`
+}
+
+func (p *installmentPurchaseDTO) toEntity(userID string) (*entity_finance.InstallmentPurchase, error) {
+	purchaseDate, err := time.Parse("2006-01-02", p.PurchaseDate)
+	if err != nil {
+		return nil, errors.New("purchaseDate must be in YYYY-MM-DD format")
+	}
+
+	return &entity_finance.InstallmentPurchase{
+		UserID:       userID,
+		CreditCardID: p.CreditCardID,
+		Category:     p.Category,
+		Subcategory:  p.Subcategory,
+		Description:  p.Description,
+		PurchaseDate: purchaseDate,
+		TotalAmount:  p.TotalAmount,
+		Installments: p.Installments,
+		InterestRate: p.InterestRate,
+	}, nil
+}
+
+func InitializeInstallmentPurchaseHandler(svc entity_finance.InstallmentPurchaseServiceInterface, encryptData cryptdata.CryptDataInterface, authClient authenticatior.Authenticator, routerGroup *gin.RouterGroup, middleware ...func(c *gin.Context)) InstallmentPurchaseHandlerInterface {
+	handler := &InstallmentPurchaseHandler{
+		service:     svc,
+		router:      routerGroup,
+		encryptData: encryptData,
+		authClient:  authClient,
+	}
+
+	handler.setupRoutes(middleware...)
+
+	return handler
+}
+
+func (h *InstallmentPurchaseHandler) setupRoutes(middleware ...func(c *gin.Context)) {
+	middlewareList := make([]gin.HandlerFunc, len(middleware))
+	for i, mw := range middleware {
+		middlewareList[i] = mw
+	}
+
+	installmentGroup := h.router.Group("/finance/installments")
+	installmentGroup.Use(middlewareList...)
+
+	installmentGroup.POST("", append(middlewareList, h.CreateInstallmentPurchase)...)
+	installmentGroup.GET("", append(middlewareList, h.GetInstallmentPurchases)...)
+	installmentGroup.GET("/:id", append(middlewareList, h.GetInstallmentPurchase)...)
+	installmentGroup.DELETE("/:id", append(middlewareList, h.DeleteInstallmentPurchase)...)
+}
+
+func (h *InstallmentPurchaseHandler) CreateInstallmentPurchase(c *gin.Context) {
+	userId, token, err := web.GetRequiredHeaders(h.authClient, c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var payload cryptdata.CryptData
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := h.encryptData.PayloadData(payload.Payload)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var purchaseDTO installmentPurchaseDTO
+	if err := json.Unmarshal(data, &purchaseDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	purchase, err := purchaseDTO.toEntity(userId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), "Authorization", token)
+	ctx = context.WithValue(ctx, "UserID", userId)
+
+	result, err := h.service.CreateInstallmentPurchase(ctx, purchase)
+	h.respond(c, http.StatusCreated, result, err)
+}
+
+func (h *InstallmentPurchaseHandler) GetInstallmentPurchase(c *gin.Context) {
+	userId, token, err := web.GetRequiredHeaders(h.authClient, c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id parameter is required"})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), "Authorization", token)
+	ctx = context.WithValue(ctx, "UserID", userId)
+
+	result, err := h.service.GetInstallmentPurchaseByID(ctx, id)
+	h.respond(c, http.StatusOK, result, err)
+}
+
+func (h *InstallmentPurchaseHandler) GetInstallmentPurchases(c *gin.Context) {
+	userId, token, err := web.GetRequiredHeaders(h.authClient, c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), "Authorization", token)
+	ctx = context.WithValue(ctx, "UserID", userId)
+
+	results, err := h.service.GetInstallmentPurchases(ctx)
+	h.respond(c, http.StatusOK, results, err)
+}
+
+func (h *InstallmentPurchaseHandler) DeleteInstallmentPurchase(c *gin.Context) {
+	userId, token, err := web.GetRequiredHeaders(h.authClient, c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id parameter is required"})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), "Authorization", token)
+	ctx = context.WithValue(ctx, "UserID", userId)
+
+	if err := h.service.DeleteInstallmentPurchase(ctx, id); err != nil {
+		h.respond(c, http.StatusNoContent, nil, err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *InstallmentPurchaseHandler) respond(c *gin.Context, status int, result interface{}, err error) {
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "validation failed"):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	b, err := json.Marshal(result)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	encryptedResult, err := h.encryptData.EncryptPayload(b)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(status, gin.H{"payload": encryptedResult})
+}
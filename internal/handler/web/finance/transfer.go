@@ -0,0 +1,207 @@
+package web_finance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	entity_finance "github.com/Tomelin/dashfin-backend-app/internal/core/entity/finance"
+	web "github.com/Tomelin/dashfin-backend-app/internal/handler/web"
+	"github.com/Tomelin/dashfin-backend-app/pkg/authenticatior"
+	cryptdata "github.com/Tomelin/dashfin-backend-app/pkg/cryptData"
+	"github.com/gin-gonic/gin"
+)
+
+type TransferHandlerInterface interface {
+	CreateTransfer(c *gin.Context)
+	GetTransfer(c *gin.Context)
+	GetTransfers(c *gin.Context)
+	DeleteTransfer(c *gin.Context)
+}
+
+type TransferHandler struct {
+	service     entity_finance.TransferServiceInterface
+	router      *gin.RouterGroup
+	encryptData cryptdata.CryptDataInterface
+	authClient  authenticatior.Authenticator
+}
+
+// transferDTO is the payload of the create operation.
+type transferDTO struct {
+	FromBankAccountID string  `json:"fromBankAccountId"`
+	ToBankAccountID   string  `json:"toBankAccountId"`
+	Amount            float64 `json:"amount"`
+	TransferDate      string  `json:"transferDate"` // YYYY-MM-DD
+	Description       string  `json:"description,omitempty"`
+}
+
+func (t *transferDTO) toEntity(userID string) (*entity_finance.Transfer, error) {
+	transferDate, err := time.Parse("2006-01-02", t.TransferDate)
+	if err != nil {
+		return nil, errors.New("transferDate must be in YYYY-MM-DD format")
+	}
+
+	return &entity_finance.Transfer{
+		UserID:            userID,
+		FromBankAccountID: t.FromBankAccountID,
+		ToBankAccountID:   t.ToBankAccountID,
+		Amount:            t.Amount,
+		TransferDate:      transferDate,
+		Description:       t.Description,
+	}, nil
+}
+
+func InitializeTransferHandler(svc entity_finance.TransferServiceInterface, encryptData cryptdata.CryptDataInterface, authClient authenticatior.Authenticator, routerGroup *gin.RouterGroup, middleware ...func(c *gin.Context)) TransferHandlerInterface {
+	handler := &TransferHandler{
+		service:     svc,
+		router:      routerGroup,
+		encryptData: encryptData,
+		authClient:  authClient,
+	}
+
+	handler.setupRoutes(middleware...)
+
+	return handler
+}
+
+func (h *TransferHandler) setupRoutes(middleware ...func(c *gin.Context)) {
+	middlewareList := make([]gin.HandlerFunc, len(middleware))
+	for i, mw := range middleware {
+		middlewareList[i] = mw
+	}
+
+	transferGroup := h.router.Group("/finance/transfers")
+	transferGroup.Use(middlewareList...)
+
+	transferGroup.POST("", append(middlewareList, h.CreateTransfer)...)
+	transferGroup.GET("", append(middlewareList, h.GetTransfers)...)
+	transferGroup.GET("/:id", append(middlewareList, h.GetTransfer)...)
+	transferGroup.DELETE("/:id", append(middlewareList, h.DeleteTransfer)...)
+}
+
+func (h *TransferHandler) CreateTransfer(c *gin.Context) {
+	userId, token, err := web.GetRequiredHeaders(h.authClient, c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var payload cryptdata.CryptData
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := h.encryptData.PayloadData(payload.Payload)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var transferData transferDTO
+	if err := json.Unmarshal(data, &transferData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	transfer, err := transferData.toEntity(userId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), "Authorization", token)
+	ctx = context.WithValue(ctx, "UserID", userId)
+
+	result, err := h.service.CreateTransfer(ctx, transfer)
+	h.respond(c, http.StatusCreated, result, err)
+}
+
+func (h *TransferHandler) GetTransfer(c *gin.Context) {
+	userId, token, err := web.GetRequiredHeaders(h.authClient, c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id parameter is required"})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), "Authorization", token)
+	ctx = context.WithValue(ctx, "UserID", userId)
+
+	result, err := h.service.GetTransferByID(ctx, id)
+	h.respond(c, http.StatusOK, result, err)
+}
+
+func (h *TransferHandler) GetTransfers(c *gin.Context) {
+	userId, token, err := web.GetRequiredHeaders(h.authClient, c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), "Authorization", token)
+	ctx = context.WithValue(ctx, "UserID", userId)
+
+	results, err := h.service.GetTransfers(ctx)
+	h.respond(c, http.StatusOK, results, err)
+}
+
+func (h *TransferHandler) DeleteTransfer(c *gin.Context) {
+	userId, token, err := web.GetRequiredHeaders(h.authClient, c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id parameter is required"})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), "Authorization", token)
+	ctx = context.WithValue(ctx, "UserID", userId)
+
+	if err := h.service.DeleteTransfer(ctx, id); err != nil {
+		h.respond(c, http.StatusNoContent, nil, err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *TransferHandler) respond(c *gin.Context, status int, result interface{}, err error) {
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "validation failed"):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	b, err := json.Marshal(result)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	encryptedResult, err := h.encryptData.EncryptPayload(b)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(status, gin.H{"payload": encryptedResult})
+}
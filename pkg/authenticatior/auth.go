@@ -50,6 +50,53 @@ type FirebaseConfig struct {
 	AppID             string      `json:"appId" yaml:"appId"`
 }
 
+const (
+	// DriverFirebase verifies Firebase ID tokens. It is the default.
+	DriverFirebase = "firebase"
+	// DriverLocal accepts a shared token, for local runs and end-to-end tests without Firebase.
+	DriverLocal = "local"
+)
+
+// AuthConfig selects the Authenticator the handlers use.
+type AuthConfig struct {
+	// Driver is DriverFirebase, the default, or DriverLocal.
+	Driver string `json:"driver" yaml:"driver"`
+	// Local is the configuration of DriverLocal.
+	Local LocalConfig `json:"local" yaml:"local"`
+}
+
+// Validate defaults the driver and checks the fields it needs.
+func (c *AuthConfig) Validate() error {
+	if c.Driver == "" {
+		c.Driver = DriverFirebase
+	}
+
+	switch c.Driver {
+	case DriverFirebase:
+	case DriverLocal:
+		if c.Local.Token == "" {
+			return errors.New("local.token is required for the local authenticator")
+		}
+	default:
+		return fmt.Errorf("unknown auth driver %q", c.Driver)
+	}
+	return nil
+}
+
+// Initialize creates the Authenticator selected by the config. The Firebase config is only
+// used by the Firebase driver.
+func Initialize(ctx context.Context, cfg AuthConfig, firebase *FirebaseConfig) (Authenticator, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	if cfg.Driver == DriverLocal {
+		log.Println("Using the local authenticator; every request with the configured token is trusted.")
+		return NewLocalAuthenticator(cfg.Local)
+	}
+	return InitializeAuth(ctx, firebase)
+}
+
 // InitializeAuth initializes the Firebase application and returns an Authenticator.
 func InitializeAuth(ctx context.Context, config *FirebaseConfig) (Authenticator, error) {
 	if config == nil {
@@ -0,0 +1,65 @@
+package authenticatior
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+)
+
+// ErrInvalidToken is returned by the local authenticator for a token other than the configured one.
+var ErrInvalidToken = errors.New("invalid token")
+
+// LocalConfig holds the configuration of the local authenticator.
+type LocalConfig struct {
+	// Token is the bearer token every request must send.
+	Token string `json:"token" yaml:"token"`
+}
+
+// localAuthenticator implements the Authenticator interface without an identity provider.
+// The token only proves the caller knows the shared secret, so the user is the one the
+// request asks for.
+type localAuthenticator struct {
+	token string
+}
+
+// NewLocalAuthenticator returns an Authenticator that accepts the configured token for any user.
+func NewLocalAuthenticator(config LocalConfig) (Authenticator, error) {
+	if config.Token == "" {
+		return nil, ErrEmptyToken
+	}
+	return &localAuthenticator{token: config.Token}, nil
+}
+
+// ValidateToken returns the claims of userID when authToken is the configured token.
+func (la *localAuthenticator) ValidateToken(ctx context.Context, userID string, authToken string) (map[string]interface{}, error) {
+	if userID == "" {
+		return nil, ErrEmptyUserID
+	}
+	if _, err := la.IsValid(ctx, authToken); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"user_id": userID,
+		"sub":     userID,
+	}, nil
+}
+
+// IsExpired reports false, local tokens do not expire.
+func (la *localAuthenticator) IsExpired(ctx context.Context, authToken string) (bool, error) {
+	if authToken == "" {
+		return true, ErrEmptyToken
+	}
+	return false, nil
+}
+
+// IsValid checks authToken against the configured token.
+func (la *localAuthenticator) IsValid(ctx context.Context, authToken string) (bool, error) {
+	if authToken == "" {
+		return false, ErrEmptyToken
+	}
+	if subtle.ConstantTimeCompare([]byte(authToken), []byte(la.token)) != 1 {
+		return false, ErrInvalidToken
+	}
+	return true, nil
+}
@@ -0,0 +1,39 @@
+package authenticatior
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalAuthenticator(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := NewLocalAuthenticator(LocalConfig{})
+	assert.ErrorIs(t, err, ErrEmptyToken)
+
+	auth, err := Initialize(ctx, AuthConfig{Driver: DriverLocal, Local: LocalConfig{Token: "dev-token"}}, nil)
+	require.NoError(t, err)
+
+	claims, err := auth.ValidateToken(ctx, "user-1", "dev-token")
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims["user_id"])
+
+	_, err = auth.ValidateToken(ctx, "user-1", "other")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = auth.ValidateToken(ctx, "", "dev-token")
+	assert.ErrorIs(t, err, ErrEmptyUserID)
+
+	valid, err := auth.IsValid(ctx, "dev-token")
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	expired, err := auth.IsExpired(ctx, "dev-token")
+	assert.NoError(t, err)
+	assert.False(t, expired)
+
+	_, err = Initialize(ctx, AuthConfig{Driver: "oauth"}, nil)
+	assert.EqualError(t, err, `unknown auth driver "oauth"`)
+}
@@ -3,6 +3,8 @@ package cache
 import (
 	"context"
 	"errors" // Added import for errors.New
+	"fmt"
+	"log"
 	"time"
 )
 
@@ -14,7 +16,16 @@ type CacheService interface {
 	Ping(ctx context.Context) error
 }
 
+const (
+	// DriverRedis keeps the cache in Redis. It is the default.
+	DriverRedis = "redis"
+	// DriverMemory keeps the cache in the process, for tests and local runs without Redis.
+	DriverMemory = "memory"
+)
+
 type CacheConfig struct {
+	// Driver is DriverRedis, the default, or DriverMemory.
+	Driver   string      `mapstructure:"driver" json:"driver"`
 	Address  string      `mapstructure:"address" json:"address"`
 	Password string      `mapstructure:"password" json:"password"`
 	DB       int         `mapstructure:"db" json:"db"`
@@ -22,5 +33,36 @@ type CacheConfig struct {
 	Username string      `mapstructure:"username" json:"username"`
 }
 
+// Validate defaults the driver and checks the fields it needs.
+func (c *CacheConfig) Validate() error {
+	if c.Driver == "" {
+		c.Driver = DriverRedis
+	}
+
+	switch c.Driver {
+	case DriverRedis:
+		if c.Address == "" {
+			return errors.New("address is required for the redis cache")
+		}
+	case DriverMemory:
+	default:
+		return fmt.Errorf("unknown cache driver %q", c.Driver)
+	}
+	return nil
+}
+
+// InitializeCache creates the cache selected by the config.
+func InitializeCache(cfg CacheConfig) (CacheService, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	if cfg.Driver == DriverMemory {
+		log.Println("Using the in-memory cache")
+		return NewMemoryCacheService(), nil
+	}
+	return NewRedisCacheService(cfg)
+}
+
 // ErrNotFound is returned when an item is not found in the cache.
 var ErrNotFound = errors.New("cache: item not found")
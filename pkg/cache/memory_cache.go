@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"context"
+	"encoding"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type memoryItem struct {
+	value   string
+	expires time.Time
+}
+
+type memoryCacheService struct {
+	mu    sync.Mutex
+	items map[string]memoryItem
+}
+
+// NewMemoryCacheService creates a CacheService that keeps the items in the process.
+// Values are stored as strings, the same way Redis stores them.
+func NewMemoryCacheService() CacheService {
+	return &memoryCacheService{items: make(map[string]memoryItem)}
+}
+
+func (m *memoryCacheService) Get(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item, ok := m.items[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	if !item.expires.IsZero() && time.Now().After(item.expires) {
+		delete(m.items, key)
+		return "", ErrNotFound
+	}
+	return item.value, nil
+}
+
+func (m *memoryCacheService) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	s, err := memoryValue(value)
+	if err != nil {
+		return err
+	}
+
+	item := memoryItem{value: s}
+	if ttl > 0 {
+		item.expires = time.Now().Add(ttl)
+	}
+
+	m.mu.Lock()
+	m.items[key] = item
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *memoryCacheService) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	delete(m.items, key)
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *memoryCacheService) Ping(ctx context.Context) error {
+	return nil
+}
+
+// memoryValue converts the value like the Redis client does, so a value the Redis cache
+// rejects is rejected here too.
+func memoryValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 64), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		if v {
+			return "1", nil
+		}
+		return "0", nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case encoding.BinaryMarshaler:
+		b, err := v.MarshalBinary()
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	default:
+		return "", fmt.Errorf("cache: can't marshal %T (implement encoding.BinaryMarshaler)", v)
+	}
+}
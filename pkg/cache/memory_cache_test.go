@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCacheService(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCacheService()
+
+	_, err := c.Get(ctx, "plan")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, c.Set(ctx, "plan", `{"amount":10}`, time.Minute))
+	require.NoError(t, c.Set(ctx, "count", 3, 0))
+	require.NoError(t, c.Set(ctx, "raw", []byte("bytes"), 0))
+
+	for key, want := range map[string]string{"plan": `{"amount":10}`, "count": "3", "raw": "bytes"} {
+		got, err := c.Get(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	require.NoError(t, c.Set(ctx, "expired", "value", time.Nanosecond))
+	time.Sleep(time.Millisecond)
+	_, err = c.Get(ctx, "expired")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, c.Delete(ctx, "plan"))
+	_, err = c.Get(ctx, "plan")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.EqualError(t, c.Set(ctx, "struct", struct{ A int }{1}, 0), "cache: can't marshal struct { A int } (implement encoding.BinaryMarshaler)")
+	assert.NoError(t, c.Ping(ctx))
+}
@@ -0,0 +1,24 @@
+package database
+
+import (
+	"fmt"
+	"log"
+)
+
+// InitializeDatabase creates the backend selected by the config. The Firebase config is only
+// used by the Firestore driver.
+func InitializeDatabase(cfg DatabaseConfig, firebase FirebaseConfig) (FirebaseDBInterface, error) {
+	switch cfg.Driver {
+	case "", DriverFirestore:
+		return InitializeFirebaseDB(firebase)
+	case DriverMemory:
+		log.Println("Using the in-memory database; documents are lost when the server stops.")
+		return InitializeMemoryDB(), nil
+	case DriverMongo:
+		return InitializeMongoDocumentDB(cfg.Mongo)
+	case DriverSQLite:
+		return InitializeSQLiteDB(cfg.SQLite)
+	default:
+		return nil, fmt.Errorf("unknown database driver %q", cfg.Driver)
+	}
+}
@@ -14,13 +14,12 @@ import (
 )
 
 type FirebaseDBInterface interface {
-	Get(ctx context.Context, collection string) ([]byte, error)
 	Create(ctx context.Context, data interface{}, collection string) ([]byte, error)
 	Update(ctx context.Context, id string, data interface{}, collection string) error
 	Delete(ctx context.Context, id, collection string) error
-	GetByQuery(ctx context.Context, collection string) firestore.Query
-	GetByConditional(ctx context.Context, conditional []Conditional, collection string) ([]byte, error)
-	GetByFilter(ctx context.Context, filters map[string]interface{}, collection string) ([]byte, error)
+	Find(ctx context.Context, query *Query) (*QueryResult, error)
+	RunTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+	Batch(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 type Filter string
@@ -33,12 +32,51 @@ const (
 	FilterArrayContains Filter = "array-contains"
 )
 
+const (
+	FilterGreaterOrEqual Filter = ">="
+	FilterLessOrEqual    Filter = "<="
+	// FilterIn matches any of the values of a slice, up to maxFilterInValues of them.
+	FilterIn Filter = "in"
+	// FilterArrayContainsAny matches arrays holding any of the values of a slice.
+	FilterArrayContainsAny Filter = "array-contains-any"
+)
+
+// maxFilterInValues is the most values Firestore accepts in an "in" or "array-contains-any" filter.
+const maxFilterInValues = 30
+
 type Conditional struct {
 	Field  string
 	Value  interface{}
 	Filter Filter
 }
 
+func (c Conditional) validate() error {
+	if c.Field == "" {
+		return fmt.Errorf("field in conditional cannot be empty")
+	}
+	if c.Value == nil {
+		return fmt.Errorf("value in conditional cannot be nil")
+	}
+	if c.Filter == "" {
+		return fmt.Errorf("filter in conditional cannot be empty")
+	}
+	switch c.Filter {
+	case FilterEquals, FilterNotEquals, FilterGreaterThan, FilterLessThan,
+		FilterGreaterOrEqual, FilterLessOrEqual, FilterArrayContains:
+	case FilterIn, FilterArrayContainsAny:
+		n, ok := isList(c.Value)
+		if !ok {
+			return fmt.Errorf("value of the %q filter on %s must be a list", c.Filter, c.Field)
+		}
+		if n == 0 || n > maxFilterInValues {
+			return fmt.Errorf("value of the %q filter on %s must have between 1 and %d items", c.Filter, c.Field, maxFilterInValues)
+		}
+	default:
+		return fmt.Errorf("invalid filter type: %s", c.Filter)
+	}
+	return nil
+}
+
 // FirebaseDB implements the DatabaseService interface for Firebase Firestore.
 type FirebaseDB struct {
 	client *firestore.Client
@@ -98,44 +136,6 @@ func (db *FirebaseDB) connect(cfg FirebaseConfig) error {
 	return nil
 }
 
-// Get retrieves a single document by its ID from a default collection.
-// Note: Firestore is schemaless, but often a default collection is used.
-// This implementation assumes a collection name might be needed or configured elsewhere.
-// For this example, let's assume Get needs a collection name.
-// This highlights a potential mismatch with the generic interface if not handled carefully.
-// We might need to adjust the interface or how collection names are passed.
-// For now, this is a placeholder.
-func (db *FirebaseDB) Get(ctx context.Context, collection string) ([]byte, error) {
-	// Placeholder: A real implementation would specify the collection.
-	if db.client == nil {
-		return nil, errors.New("firestore client not initialized. Call Connect first")
-	}
-
-	if err := db.validateWithoutData(ctx, collection); err != nil {
-		log.Println(" erro na validação", err)
-		return nil, err
-	}
-
-	doc, err := db.client.Collection(collection).Documents(ctx).GetAll()
-	if err != nil {
-		return nil, err
-	}
-
-	var results []interface{}
-	for _, d := range doc {
-		data := d.Data()
-		data["id"] = d.Ref.ID
-		results = append(results, data)
-	}
-
-	b, err := json.Marshal(results)
-	if err != nil {
-		return nil, err
-	}
-
-	return b, nil
-}
-
 // Create adds a new document to a default collection.
 // Placeholder: Collection name needed.
 func (db *FirebaseDB) Create(ctx context.Context, data interface{}, collection string) ([]byte, error) {
@@ -152,8 +152,15 @@ func (db *FirebaseDB) Create(ctx context.Context, data interface{}, collection s
 		}
 	}
 
-	colRef := db.client.Collection(collection)
-	docRef, _, err := colRef.Add(ctx, data)
+	docRef := db.client.Collection(collection).NewDoc()
+	var err error
+	if work := unitOfWorkFrom(ctx); work != nil {
+		err = work.write(func(tx *firestore.Transaction) error {
+			return tx.Create(docRef, data)
+		})
+	} else {
+		_, err = docRef.Create(ctx, data)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -194,7 +201,14 @@ func (db *FirebaseDB) Update(ctx context.Context, id string, data interface{}, c
 		return err
 	}
 
-	_, err := db.client.Collection(collection).Doc(id).Set(ctx, data, firestore.MergeAll)
+	docRef := db.client.Collection(collection).Doc(id)
+	if work := unitOfWorkFrom(ctx); work != nil {
+		return work.write(func(tx *firestore.Transaction) error {
+			return tx.Set(docRef, data, firestore.MergeAll)
+		})
+	}
+
+	_, err := docRef.Set(ctx, data, firestore.MergeAll)
 	if err != nil {
 		return err
 	}
@@ -216,91 +230,76 @@ func (db *FirebaseDB) Delete(ctx context.Context, id, collection string) error {
 		return err
 	}
 
-	_, err := db.client.Collection(collection).Doc(id).Delete(ctx)
-
-	return err
-}
-
-func (db *FirebaseDB) GetByQuery(ctx context.Context, collection string) firestore.Query {
-
-	query := db.client.Collection(collection).Query
+	docRef := db.client.Collection(collection).Doc(id)
+	if work := unitOfWorkFrom(ctx); work != nil {
+		return work.write(func(tx *firestore.Transaction) error {
+			return tx.Delete(docRef)
+		})
+	}
 
-	return query
+	_, err := docRef.Delete(ctx)
 
+	return err
 }
 
-// GetByConditional retrieves multiple documents based on a set of conditionals from a default collection.
-// Placeholder: Collection name needed.
-// This function allows for more complex queries using conditionals.
-func (db *FirebaseDB) GetByConditional(ctx context.Context, conditional []Conditional, collection string) ([]byte, error) {
-
-	if err := db.validateWithData(ctx, conditional, collection); err != nil {
+// Find runs the query on its collection. Documents are ordered by the OrderBy fields and then
+// by their ID; with a limit, one document more is read to tell whether there is a next page.
+// Inside RunTransaction the documents are read through the transaction.
+func (db *FirebaseDB) Find(ctx context.Context, query *Query) (*QueryResult, error) {
+	if query == nil {
+		return nil, errors.New("query is nil")
+	}
+	if err := db.validateWithoutData(ctx, query.collection); err != nil {
 		return nil, err
 	}
-
-	query := db.client.Collection(collection).Query
-	for _, cond := range conditional {
-		if cond.Field == "" {
-			return nil, fmt.Errorf("field in conditional cannot be empty")
-		}
-		if cond.Value == nil {
-			return nil, fmt.Errorf("value in conditional cannot be nil")
-		}
-		if cond.Filter == "" {
-			return nil, fmt.Errorf("filter in conditional cannot be empty")
-		}
-		if cond.Filter != FilterEquals && cond.Filter != FilterNotEquals &&
-			cond.Filter != FilterGreaterThan && cond.Filter != FilterLessThan &&
-			cond.Filter != FilterArrayContains {
-			return nil, fmt.Errorf("invalid filter type: %s", cond.Filter)
-		}
-
-		query = query.Where(cond.Field, string(cond.Filter), cond.Value)
+	if err := query.Validate(); err != nil {
+		return nil, err
 	}
 
-	iter := query.Documents(ctx)
+	q := db.client.Collection(query.collection).Query
+	if query.id != "" {
+		q = q.Where(firestore.DocumentID, "==", db.client.Collection(query.collection).Doc(query.id))
+	}
+	for _, cond := range query.conditionals {
+		q = q.Where(cond.Field, string(cond.Filter), cond.Value)
+	}
 
-	var results []interface{}
-	defer iter.Stop()
-	for {
-		doc, err := iter.Next()
+	paged := query.limit > 0 || query.cursor != ""
+	for _, order := range query.orders {
+		q = q.OrderBy(order.Field, firestoreDirection(order.Direction))
+	}
+	if paged {
+		q = q.OrderBy(firestore.DocumentID, firestoreDirection(query.direction()))
+	}
 
-		if err == iterator.Done {
-			break
-		}
+	if fields := query.selectedFields(); fields != nil {
+		q = q.Select(fields...)
+	}
 
+	if query.cursor != "" {
+		values, id, err := decodeCursor(query.cursor, len(query.orders))
 		if err != nil {
 			return nil, err
 		}
-
-		data := doc.Data()
-		data["id"] = doc.Ref.ID // Importante para updates
-		results = append(results, data)
+		q = q.StartAfter(append(values, id)...)
 	}
-
-	b, err := json.Marshal(results)
-	if err != nil {
-		return nil, err
+	if query.offset > 0 {
+		q = q.Offset(query.offset)
 	}
-	return b, nil
-}
-
-// GetByFilter retrieves multiple documents based on a set of filters from a default collection.
-// Placeholder: Collection name needed.
-func (db *FirebaseDB) GetByFilter(ctx context.Context, filters map[string]interface{}, collection string) ([]byte, error) {
-
-	if err := db.validateWithData(ctx, filters, collection); err != nil {
-		return nil, err
+	if query.limit > 0 {
+		q = q.Limit(query.limit + 1)
 	}
 
-	query := db.client.Collection(collection).Query
-	for key, value := range filters {
-		query = query.Where(key, "==", value)
+	var iter *firestore.DocumentIterator
+	if work := unitOfWorkFrom(ctx); work != nil && work.tx != nil {
+		iter = work.tx.Documents(q)
+	} else {
+		iter = q.Documents(ctx)
 	}
-
-	iter := query.Documents(ctx)
-	var results []interface{}
 	defer iter.Stop()
+
+	result := &QueryResult{}
+	var last *firestore.DocumentSnapshot
 	for {
 		doc, err := iter.Next()
 		if err == iterator.Done {
@@ -310,16 +309,32 @@ func (db *FirebaseDB) GetByFilter(ctx context.Context, filters map[string]interf
 			return nil, err
 		}
 
+		if query.limit > 0 && len(result.Documents) == query.limit {
+			values := make([]interface{}, 0, len(query.orders))
+			for _, order := range query.orders {
+				values = append(values, last.Data()[order.Field])
+			}
+			result.NextCursor, err = encodeCursor(values, last.Ref.ID)
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+
 		data := doc.Data()
 		data["id"] = doc.Ref.ID // Importante para updates
-		results = append(results, data)
+		result.Documents = append(result.Documents, data)
+		last = doc
 	}
 
-	b, err := json.Marshal(results)
-	if err != nil {
-		return nil, err
+	return result, nil
+}
+
+func firestoreDirection(direction Direction) firestore.Direction {
+	if direction == Descending {
+		return firestore.Desc
 	}
-	return b, nil
+	return firestore.Asc
 }
 
 // Close terminates the Firebase connection.
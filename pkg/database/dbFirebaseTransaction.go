@@ -0,0 +1,93 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+)
+
+// maxUnitOfWorkWrites is the most writes Firestore commits at once.
+const maxUnitOfWorkWrites = 500
+
+type unitOfWorkKey struct{}
+
+// unitOfWork is the transaction or the batch a context runs in. A transaction applies the
+// writes to tx as they are made; a batch keeps them until fn returns.
+type unitOfWork struct {
+	tx     *firestore.Transaction
+	writes []func(tx *firestore.Transaction) error
+}
+
+func unitOfWorkFrom(ctx context.Context) *unitOfWork {
+	work, _ := ctx.Value(unitOfWorkKey{}).(*unitOfWork)
+	return work
+}
+
+func (w *unitOfWork) write(op func(tx *firestore.Transaction) error) error {
+	if w.tx != nil {
+		return op(w.tx)
+	}
+	if len(w.writes) == maxUnitOfWorkWrites {
+		return fmt.Errorf("batch exceeds the limit of %d writes", maxUnitOfWorkWrites)
+	}
+	w.writes = append(w.writes, op)
+	return nil
+}
+
+// RunTransaction runs fn in a Firestore transaction. Find, Create, Update and Delete called
+// with the context given to fn take part in it: reads see a consistent snapshot and writes
+// are committed together when fn returns nil. Firestore requires every read to come before
+// the first write, and runs fn again when the documents read change before the commit, so
+// fn must not have side effects other than its writes. A transaction started inside
+// another transaction or batch joins it.
+func (db *FirebaseDB) RunTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if fn == nil {
+		return errors.New("transaction function is nil")
+	}
+	if unitOfWorkFrom(ctx) != nil {
+		return fn(ctx)
+	}
+	if db.client == nil {
+		return errors.New("firestore client not initialized. Call Connect first")
+	}
+
+	return db.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		return fn(context.WithValue(ctx, unitOfWorkKey{}, &unitOfWork{tx: tx}))
+	})
+}
+
+// Batch runs fn once and commits the Create, Update and Delete calls made with its context
+// together when it returns nil; none of them is written when it fails. Unlike RunTransaction,
+// reads are not isolated and may come after writes, but they do not see the pending writes.
+// A batch started inside another transaction or batch joins it.
+func (db *FirebaseDB) Batch(ctx context.Context, fn func(ctx context.Context) error) error {
+	if fn == nil {
+		return errors.New("batch function is nil")
+	}
+	if unitOfWorkFrom(ctx) != nil {
+		return fn(ctx)
+	}
+	if db.client == nil {
+		return errors.New("firestore client not initialized. Call Connect first")
+	}
+
+	work := &unitOfWork{}
+	if err := fn(context.WithValue(ctx, unitOfWorkKey{}, work)); err != nil {
+		return err
+	}
+	if len(work.writes) == 0 {
+		return nil
+	}
+
+	// Committed as a transaction without reads: unlike WriteBatch, it is retried on contention.
+	return db.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		for _, write := range work.writes {
+			if err := write(tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,40 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"cloud.google.com/go/firestore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitOfWork_Write(t *testing.T) {
+	work := &unitOfWork{}
+	for i := 0; i < maxUnitOfWorkWrites; i++ {
+		require.NoError(t, work.write(func(tx *firestore.Transaction) error { return nil }))
+	}
+
+	assert.Len(t, work.writes, maxUnitOfWorkWrites)
+	assert.EqualError(t, work.write(func(tx *firestore.Transaction) error { return nil }), "batch exceeds the limit of 500 writes")
+}
+
+func TestFirebaseDB_NestedUnitOfWork(t *testing.T) {
+	db := &FirebaseDB{}
+	outer := &unitOfWork{}
+	ctx := context.WithValue(context.Background(), unitOfWorkKey{}, outer)
+
+	// A nested batch or transaction joins the outer one instead of committing on its own.
+	for _, run := range []func(context.Context, func(context.Context) error) error{db.Batch, db.RunTransaction} {
+		var joined *unitOfWork
+		err := run(ctx, func(ctx context.Context) error {
+			joined = unitOfWorkFrom(ctx)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Same(t, outer, joined)
+	}
+
+	assert.EqualError(t, db.Batch(context.Background(), func(ctx context.Context) error { return nil }), "firestore client not initialized. Call Connect first")
+	assert.EqualError(t, db.RunTransaction(context.Background(), nil), "transaction function is nil")
+}
@@ -0,0 +1,35 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConditional_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cond    Conditional
+		wantErr string
+	}{
+		{name: "greater or equal", cond: Conditional{Field: "DueDate", Value: "2025-03-01", Filter: FilterGreaterOrEqual}},
+		{name: "less or equal", cond: Conditional{Field: "DueDate", Value: "2025-03-31", Filter: FilterLessOrEqual}},
+		{name: "in", cond: Conditional{Field: "Category", Value: []string{"housing", "food"}, Filter: FilterIn}},
+		{name: "in without a list", cond: Conditional{Field: "Category", Value: "housing", Filter: FilterIn}, wantErr: "must be a list"},
+		{name: "in with an empty list", cond: Conditional{Field: "Category", Value: []string{}, Filter: FilterIn}, wantErr: "between 1 and 30"},
+		{name: "in with too many values", cond: Conditional{Field: "Category", Value: make([]string, 31), Filter: FilterIn}, wantErr: "between 1 and 30"},
+		{name: "unknown filter", cond: Conditional{Field: "Category", Value: "housing", Filter: "like"}, wantErr: "invalid filter type"},
+		{name: "empty field", cond: Conditional{Value: "housing", Filter: FilterEquals}, wantErr: "field in conditional cannot be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cond.validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,791 @@
+package database
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+// MemoryDB implements FirebaseDBInterface in memory with the document semantics of Firestore:
+// collections addressed by path (data/{userID}/...), generated document IDs, merged updates,
+// server timestamps, and the filters, orders and cursors of Query. It is meant for tests and
+// local runs; the data is lost when the process stops.
+type MemoryDB struct {
+	mu          sync.Mutex
+	collections map[string]map[string]map[string]interface{}
+}
+
+// InitializeMemoryDB creates an empty MemoryDB.
+func InitializeMemoryDB() *MemoryDB {
+	return &MemoryDB{collections: make(map[string]map[string]map[string]interface{})}
+}
+
+type memoryWorkKey struct{}
+
+// memoryWork is the transaction or the batch a context runs in on a MemoryDB. The writes are
+// applied together when it commits. A transaction holds the lock of the database until then,
+// so its reads see no concurrent write.
+type memoryWork struct {
+	db     *MemoryDB
+	tx     bool
+	writes []memoryWrite
+}
+
+// memoryWrite is a pending write of a document; data is nil for a delete.
+type memoryWrite struct {
+	collection string
+	id         string
+	data       map[string]interface{}
+	merge      bool
+}
+
+func (db *MemoryDB) workFrom(ctx context.Context) *memoryWork {
+	work, _ := ctx.Value(memoryWorkKey{}).(*memoryWork)
+	if work == nil || work.db != db {
+		return nil
+	}
+	return work
+}
+
+// Create stores the data under a generated ID and returns it with the "id" added.
+func (db *MemoryDB) Create(ctx context.Context, data interface{}, collection string) ([]byte, error) {
+	if data == nil {
+		return nil, errors.New("data is nil")
+	}
+	if err := validateDocumentRequest(ctx, collection); err != nil {
+		return nil, err
+	}
+
+	// Same timestamps as FirebaseDB.Create.
+	mapData, isMap := data.(map[string]interface{})
+	if isMap {
+		if _, exists := mapData["createdAt"]; !exists {
+			mapData["updatedAt"] = firestore.ServerTimestamp
+		}
+	}
+
+	doc, err := toDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := newDocumentID()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.write(ctx, memoryWrite{collection: collection, id: id, data: doc}); err != nil {
+		return nil, err
+	}
+
+	if isMap {
+		mapData["id"] = id
+		data = mapData
+	}
+	return json.Marshal(data)
+}
+
+// Update merges the data into the document, creating it when it does not exist.
+func (db *MemoryDB) Update(ctx context.Context, id string, data interface{}, collection string) error {
+	if id == "" {
+		return fmt.Errorf("id is empty")
+	}
+	if data == nil {
+		return errors.New("data is nil")
+	}
+	if err := validateDocumentRequest(ctx, collection); err != nil {
+		return err
+	}
+
+	if mapData, ok := data.(map[string]interface{}); ok {
+		if _, exists := mapData["updatedAt"]; !exists {
+			mapData["updatedAt"] = firestore.ServerTimestamp
+		}
+	}
+
+	doc, err := toDocument(data)
+	if err != nil {
+		return err
+	}
+
+	return db.write(ctx, memoryWrite{collection: collection, id: id, data: doc, merge: true})
+}
+
+// Delete removes the document; deleting a missing document is not an error.
+func (db *MemoryDB) Delete(ctx context.Context, id, collection string) error {
+	if id == "" {
+		return fmt.Errorf("id is empty")
+	}
+	if err := validateDocumentRequest(ctx, collection); err != nil {
+		return err
+	}
+
+	return db.write(ctx, memoryWrite{collection: collection, id: id})
+}
+
+// Find runs the query like FirebaseDB.Find. Documents missing an OrderBy field are left out,
+// as Firestore does.
+func (db *MemoryDB) Find(ctx context.Context, query *Query) (*QueryResult, error) {
+	if query == nil {
+		return nil, errors.New("query is nil")
+	}
+	if err := validateDocumentRequest(ctx, query.collection); err != nil {
+		return nil, err
+	}
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	if work := db.workFrom(ctx); work != nil && work.tx {
+		if len(work.writes) > 0 {
+			return nil, errors.New("read after write in transaction")
+		}
+		return db.find(query)
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.find(query)
+}
+
+// RunTransaction runs fn with the database locked and applies its writes when it returns nil.
+// As on Firestore, reads must come before writes. Calls made inside fn with another context
+// than the one it receives block until the transaction ends.
+func (db *MemoryDB) RunTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if fn == nil {
+		return errors.New("transaction function is nil")
+	}
+	if db.workFrom(ctx) != nil {
+		return fn(ctx)
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	work := &memoryWork{db: db, tx: true}
+	if err := fn(context.WithValue(ctx, memoryWorkKey{}, work)); err != nil {
+		return err
+	}
+	db.apply(work.writes)
+	return nil
+}
+
+// Batch runs fn and applies its writes together when it returns nil. Reads inside fn do not
+// see the pending writes.
+func (db *MemoryDB) Batch(ctx context.Context, fn func(ctx context.Context) error) error {
+	if fn == nil {
+		return errors.New("batch function is nil")
+	}
+	if db.workFrom(ctx) != nil {
+		return fn(ctx)
+	}
+
+	work := &memoryWork{db: db}
+	if err := fn(context.WithValue(ctx, memoryWorkKey{}, work)); err != nil {
+		return err
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.apply(work.writes)
+	return nil
+}
+
+// Close drops every document.
+func (db *MemoryDB) Close() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.collections = make(map[string]map[string]map[string]interface{})
+	return nil
+}
+
+func (db *MemoryDB) write(ctx context.Context, w memoryWrite) error {
+	if work := db.workFrom(ctx); work != nil {
+		if len(work.writes) == maxUnitOfWorkWrites {
+			return fmt.Errorf("batch exceeds the limit of %d writes", maxUnitOfWorkWrites)
+		}
+		work.writes = append(work.writes, w)
+		return nil
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.apply([]memoryWrite{w})
+	return nil
+}
+
+// apply commits the writes; the lock must be held. Server timestamps get the commit time.
+func (db *MemoryDB) apply(writes []memoryWrite) {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	for _, w := range writes {
+		docs := db.collections[w.collection]
+		if w.data == nil {
+			delete(docs, w.id)
+			continue
+		}
+		if docs == nil {
+			docs = make(map[string]map[string]interface{})
+			db.collections[w.collection] = docs
+		}
+
+		data := copyValue(w.data).(map[string]interface{})
+		if existing, ok := docs[w.id]; ok && w.merge {
+			data = mergeDocument(copyValue(existing).(map[string]interface{}), data)
+		}
+		docs[w.id] = resolveSentinels(data, now)
+	}
+}
+
+// storedDocument is a document of a collection and its ID.
+type storedDocument struct {
+	id   string
+	data map[string]interface{}
+}
+
+// find reads the documents of the query; the lock must be held.
+func (db *MemoryDB) find(query *Query) (*QueryResult, error) {
+	docs := make([]storedDocument, 0, len(db.collections[query.collection]))
+	for id, data := range db.collections[query.collection] {
+		if query.id != "" && id != query.id {
+			continue
+		}
+		docs = append(docs, storedDocument{id: id, data: data})
+	}
+	return queryDocuments(query, docs)
+}
+
+// queryDocuments runs the query on the documents of its collection with the semantics of
+// Firestore: filters, then orders, cursor, offset, limit and projection.
+func queryDocuments(query *Query, all []storedDocument) (*QueryResult, error) {
+	conditionals := make([]Conditional, 0, len(query.conditionals))
+	for _, cond := range query.conditionals {
+		value, err := normalizeValue(cond.Value)
+		if err != nil {
+			return nil, fmt.Errorf("value of the filter on %s: %w", cond.Field, err)
+		}
+		conditionals = append(conditionals, Conditional{Field: cond.Field, Value: value, Filter: cond.Filter})
+	}
+
+	var docs []storedDocument
+	for _, doc := range all {
+		if matchesDocument(doc.data, conditionals) && hasFields(doc.data, query.orders) {
+			docs = append(docs, doc)
+		}
+	}
+
+	sort.Slice(docs, func(i, j int) bool {
+		return compareKeys(query, orderValues(docs[i].data, query.orders), docs[i].id, orderValues(docs[j].data, query.orders), docs[j].id) < 0
+	})
+
+	if query.cursor != "" {
+		values, id, err := decodeCursor(query.cursor, len(query.orders))
+		if err != nil {
+			return nil, err
+		}
+		for i := range values {
+			if values[i], err = normalizeValue(values[i]); err != nil {
+				return nil, fmt.Errorf("invalid cursor: %w", err)
+			}
+		}
+
+		start := sort.Search(len(docs), func(i int) bool {
+			return compareKeys(query, orderValues(docs[i].data, query.orders), docs[i].id, values, id) > 0
+		})
+		docs = docs[start:]
+	}
+
+	if query.offset > 0 {
+		if query.offset >= len(docs) {
+			docs = nil
+		} else {
+			docs = docs[query.offset:]
+		}
+	}
+
+	result := &QueryResult{}
+	if query.limit > 0 && len(docs) > query.limit {
+		docs = docs[:query.limit]
+		last := docs[len(docs)-1]
+		cursor, err := encodeCursor(orderValues(last.data, query.orders), last.id)
+		if err != nil {
+			return nil, err
+		}
+		result.NextCursor = cursor
+	}
+
+	fields := query.selectedFields()
+	for _, doc := range docs {
+		data := copyValue(doc.data).(map[string]interface{})
+		if fields != nil {
+			data = projectDocument(data, fields)
+		}
+		data["id"] = doc.id
+		result.Documents = append(result.Documents, data)
+	}
+	return result, nil
+}
+
+// validateDocumentRequest checks the collection path and the authorization of a request.
+func validateDocumentRequest(ctx context.Context, collection string) error {
+	if collection == "" {
+		return errors.New("collection is empty")
+	}
+
+	// A collection path has an odd number of segments: collection, document, collection...
+	segments := strings.Split(collection, "/")
+	if len(segments)%2 == 0 {
+		return fmt.Errorf("invalid collection path %q", collection)
+	}
+	for _, segment := range segments {
+		if segment == "" {
+			return fmt.Errorf("invalid collection path %q", collection)
+		}
+	}
+
+	if ctx.Value("Authorization") == "" {
+		return errors.New("authorization token is nil")
+	}
+	return nil
+}
+
+// splitCollectionPath returns the name of a collection path and the path of its parent
+// document: data/user-1/expenses is the "expenses" collection of "data/user-1".
+func splitCollectionPath(collection string) (string, string) {
+	i := strings.LastIndex(collection, "/")
+	if i < 0 {
+		return collection, ""
+	}
+	return collection[i+1:], collection[:i]
+}
+
+const documentIDAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+// newDocumentID returns a random ID shaped like the ones Firestore generates.
+func newDocumentID() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	for i := range b {
+		b[i] = documentIDAlphabet[int(b[i])%len(documentIDAlphabet)]
+	}
+	return string(b), nil
+}
+
+// toDocument converts the data to the document stored, as Firestore would encode it.
+func toDocument(data interface{}) (map[string]interface{}, error) {
+	value, err := normalizeValue(data)
+	if err != nil {
+		return nil, err
+	}
+	doc, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("data must be a map or a struct, got %T", data)
+	}
+	return doc, nil
+}
+
+// normalizeValue converts a value to the types Firestore returns: int64, float64, bool,
+// string, []byte, time.Time, []interface{} and map[string]interface{}. Structs are encoded
+// through their JSON form. The server timestamp and delete sentinels are kept.
+func normalizeValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case time.Time:
+		return v.UTC().Truncate(time.Microsecond), nil
+	case []byte:
+		return append([]byte(nil), v...), nil
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n, nil
+		}
+		return v.Float64()
+	}
+	if value == firestore.ServerTimestamp || value == firestore.Delete {
+		return value, nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		return normalizeValue(rv.Elem().Interface())
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil, nil
+		}
+		list := make([]interface{}, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			item, err := normalizeValue(rv.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+		}
+		return list, nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unsupported map key type %s", rv.Type().Key())
+		}
+		if rv.IsNil() {
+			return nil, nil
+		}
+		m := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			item, err := normalizeValue(iter.Value().Interface())
+			if err != nil {
+				return nil, err
+			}
+			m[iter.Key().String()] = item
+		}
+		return m, nil
+	case reflect.Struct:
+		b, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal(b, &m); err != nil {
+			return nil, err
+		}
+		return normalizeValue(m)
+	}
+	return nil, fmt.Errorf("unsupported type %T", value)
+}
+
+// copyValue deep copies a normalized value.
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[key] = copyValue(item)
+		}
+		return m
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = copyValue(item)
+		}
+		return list
+	case []byte:
+		return append([]byte(nil), v...)
+	}
+	return value
+}
+
+// resolveSentinels replaces the server timestamps with now and drops the fields to delete.
+func resolveSentinels(doc map[string]interface{}, now time.Time) map[string]interface{} {
+	for key, value := range doc {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			doc[key] = resolveSentinels(v, now)
+		default:
+			if value == firestore.ServerTimestamp {
+				doc[key] = now
+			} else if value == firestore.Delete {
+				delete(doc, key)
+			}
+		}
+	}
+	return doc
+}
+
+// mergeDocument merges the fields of data into doc, nested maps included, as
+// firestore.MergeAll does.
+func mergeDocument(doc, data map[string]interface{}) map[string]interface{} {
+	for key, value := range data {
+		nested, isMap := value.(map[string]interface{})
+		current, wasMap := doc[key].(map[string]interface{})
+		if isMap && wasMap {
+			doc[key] = mergeDocument(current, nested)
+			continue
+		}
+		doc[key] = value
+	}
+	return doc
+}
+
+// fieldValue reads a field of the document; a dotted path reads nested maps.
+func fieldValue(data map[string]interface{}, path string) (interface{}, bool) {
+	var value interface{} = data
+	for _, segment := range strings.Split(path, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = m[segment]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+func hasFields(data map[string]interface{}, orders []Order) bool {
+	for _, order := range orders {
+		if _, ok := fieldValue(data, order.Field); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func orderValues(data map[string]interface{}, orders []Order) []interface{} {
+	values := make([]interface{}, 0, len(orders))
+	for _, order := range orders {
+		value, _ := fieldValue(data, order.Field)
+		values = append(values, value)
+	}
+	return values
+}
+
+// compareKeys compares two documents by the values of the OrderBy fields and then by ID, in
+// the order the query returns them.
+func compareKeys(query *Query, a []interface{}, aID string, b []interface{}, bID string) int {
+	for i, order := range query.orders {
+		c := compareValues(a[i], b[i])
+		if order.Direction == Descending {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+
+	c := strings.Compare(aID, bID)
+	if query.direction() == Descending {
+		c = -c
+	}
+	return c
+}
+
+func projectDocument(data map[string]interface{}, fields []string) map[string]interface{} {
+	projected := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		value, ok := fieldValue(data, field)
+		if !ok {
+			continue
+		}
+
+		// Nested paths keep their parent maps.
+		segments := strings.Split(field, ".")
+		target := projected
+		for _, segment := range segments[:len(segments)-1] {
+			next, ok := target[segment].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				target[segment] = next
+			}
+			target = next
+		}
+		target[segments[len(segments)-1]] = value
+	}
+	return projected
+}
+
+func matchesDocument(data map[string]interface{}, conditionals []Conditional) bool {
+	for _, cond := range conditionals {
+		value, ok := fieldValue(data, cond.Field)
+		if !ok || !matchesConditional(value, cond) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesConditional applies a filter the way Firestore does: range filters only match values
+// of the same type, and "!=" does not match null.
+func matchesConditional(value interface{}, cond Conditional) bool {
+	switch cond.Filter {
+	case FilterEquals:
+		return equalValues(value, cond.Value)
+	case FilterNotEquals:
+		return value != nil && !equalValues(value, cond.Value)
+	case FilterGreaterThan, FilterGreaterOrEqual, FilterLessThan, FilterLessOrEqual:
+		if typeRank(value) != typeRank(cond.Value) {
+			return false
+		}
+		c := compareValues(value, cond.Value)
+		switch cond.Filter {
+		case FilterGreaterThan:
+			return c > 0
+		case FilterGreaterOrEqual:
+			return c >= 0
+		case FilterLessThan:
+			return c < 0
+		default:
+			return c <= 0
+		}
+	case FilterArrayContains:
+		return containsValue(value, cond.Value)
+	case FilterIn:
+		list, _ := cond.Value.([]interface{})
+		for _, item := range list {
+			if equalValues(value, item) {
+				return true
+			}
+		}
+	case FilterArrayContainsAny:
+		list, _ := cond.Value.([]interface{})
+		for _, item := range list {
+			if containsValue(value, item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsValue(list, value interface{}) bool {
+	items, ok := list.([]interface{})
+	if !ok {
+		return false
+	}
+	for _, item := range items {
+		if equalValues(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func equalValues(a, b interface{}) bool {
+	return typeRank(a) == typeRank(b) && compareValues(a, b) == 0
+}
+
+// typeRank is the position of the type of a value in the Firestore ordering of mixed types.
+func typeRank(value interface{}) int {
+	switch value.(type) {
+	case nil:
+		return 0
+	case bool:
+		return 1
+	case int64, float64:
+		return 2
+	case time.Time:
+		return 3
+	case string:
+		return 4
+	case []byte:
+		return 5
+	case []interface{}:
+		return 6
+	case map[string]interface{}:
+		return 7
+	}
+	return 8
+}
+
+// compareValues orders two normalized values as Firestore does.
+func compareValues(a, b interface{}) int {
+	if ra, rb := typeRank(a), typeRank(b); ra != rb {
+		if ra < rb {
+			return -1
+		}
+		return 1
+	}
+
+	switch x := a.(type) {
+	case bool:
+		y := b.(bool)
+		switch {
+		case x == y:
+			return 0
+		case !x:
+			return -1
+		}
+		return 1
+	case int64, float64:
+		return compareNumbers(a, b)
+	case time.Time:
+		return x.Compare(b.(time.Time))
+	case string:
+		return strings.Compare(x, b.(string))
+	case []byte:
+		return strings.Compare(string(x), string(b.([]byte)))
+	case []interface{}:
+		y := b.([]interface{})
+		for i := 0; i < len(x) && i < len(y); i++ {
+			if c := compareValues(x[i], y[i]); c != 0 {
+				return c
+			}
+		}
+		return compareNumbers(int64(len(x)), int64(len(y)))
+	case map[string]interface{}:
+		y := b.(map[string]interface{})
+		xKeys, yKeys := sortedKeys(x), sortedKeys(y)
+		for i := 0; i < len(xKeys) && i < len(yKeys); i++ {
+			if c := strings.Compare(xKeys[i], yKeys[i]); c != 0 {
+				return c
+			}
+			if c := compareValues(x[xKeys[i]], y[yKeys[i]]); c != 0 {
+				return c
+			}
+		}
+		return compareNumbers(int64(len(xKeys)), int64(len(yKeys)))
+	}
+	return 0
+}
+
+func compareNumbers(a, b interface{}) int {
+	x, xInt := a.(int64)
+	y, yInt := b.(int64)
+	if xInt && yInt {
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+
+	fx, fy := toFloat(a), toFloat(b)
+	switch {
+	case fx < fy:
+		return -1
+	case fx > fy:
+		return 1
+	}
+	return 0
+}
+
+func toFloat(value interface{}) float64 {
+	if n, ok := value.(int64); ok {
+		return float64(n)
+	}
+	f, _ := value.(float64)
+	return f
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
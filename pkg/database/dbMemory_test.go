@@ -0,0 +1,237 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const memoryTestCollection = "data/user-1/expenses"
+
+func seedMemoryDB(t *testing.T, docs map[string]map[string]interface{}) *MemoryDB {
+	t.Helper()
+	db := InitializeMemoryDB()
+	for id, doc := range docs {
+		require.NoError(t, db.Update(context.Background(), id, doc, memoryTestCollection))
+	}
+	return db
+}
+
+func findIDs(t *testing.T, db *MemoryDB, query *Query) []string {
+	t.Helper()
+	result, err := db.Find(context.Background(), query)
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(result.Documents))
+	for _, doc := range result.Documents {
+		ids = append(ids, doc["id"].(string))
+	}
+	return ids
+}
+
+func TestMemoryDB_Create(t *testing.T) {
+	ctx := context.Background()
+	db := InitializeMemoryDB()
+
+	b, err := db.Create(ctx, map[string]interface{}{"Category": "housing", "Amount": 1200}, memoryTestCollection)
+	require.NoError(t, err)
+
+	var created map[string]interface{}
+	require.NoError(t, json.Unmarshal(b, &created))
+	id, _ := created["id"].(string)
+	assert.Len(t, id, 20)
+
+	result, err := db.Find(ctx, NewQuery(memoryTestCollection).Where("Category", FilterEquals, "housing"))
+	require.NoError(t, err)
+	require.Len(t, result.Documents, 1)
+	doc := result.Documents[0]
+	assert.Equal(t, id, doc["id"])
+	assert.Equal(t, int64(1200), doc["Amount"])
+	assert.IsType(t, time.Time{}, doc["updatedAt"], "the server timestamp is resolved")
+
+	t.Run("collections are isolated by path", func(t *testing.T) {
+		result, err := db.Find(ctx, NewQuery("data/user-2/expenses"))
+		require.NoError(t, err)
+		assert.Empty(t, result.Documents)
+	})
+
+	t.Run("invalid path", func(t *testing.T) {
+		_, err := db.Create(ctx, map[string]interface{}{"Amount": 1}, "data/user-1")
+		assert.EqualError(t, err, `invalid collection path "data/user-1"`)
+	})
+
+	t.Run("missing authorization", func(t *testing.T) {
+		ctx := context.WithValue(ctx, "Authorization", "")
+		_, err := db.Create(ctx, map[string]interface{}{"Amount": 1}, memoryTestCollection)
+		assert.EqualError(t, err, "authorization token is nil")
+	})
+}
+
+func TestMemoryDB_UpdateAndDelete(t *testing.T) {
+	ctx := context.Background()
+	db := seedMemoryDB(t, map[string]map[string]interface{}{
+		"e-1": {"Category": "housing", "Amount": 100.0, "Meta": map[string]interface{}{"a": 1, "b": 2}},
+	})
+
+	require.NoError(t, db.Update(ctx, "e-1", map[string]interface{}{"Amount": 150.0, "Meta": map[string]interface{}{"b": 3}}, memoryTestCollection))
+
+	result, err := db.Find(ctx, NewQuery(memoryTestCollection))
+	require.NoError(t, err)
+	require.Len(t, result.Documents, 1)
+	doc := result.Documents[0]
+	assert.Equal(t, "housing", doc["Category"], "fields missing from the update are kept")
+	assert.Equal(t, 150.0, doc["Amount"])
+	assert.Equal(t, map[string]interface{}{"a": int64(1), "b": int64(3)}, doc["Meta"])
+
+	// The stored document is a copy.
+	doc["Category"] = "changed"
+	assert.Equal(t, []string{"e-1"}, findIDs(t, db, NewQuery(memoryTestCollection).Where("Category", FilterEquals, "housing")))
+	assert.Equal(t, []string{"e-1"}, findIDs(t, db, NewQuery(memoryTestCollection).WhereID("e-1")))
+	assert.Empty(t, findIDs(t, db, NewQuery(memoryTestCollection).WhereID("e-2")))
+
+	require.NoError(t, db.Delete(ctx, "e-1", memoryTestCollection))
+	require.NoError(t, db.Delete(ctx, "e-1", memoryTestCollection), "deleting a missing document is not an error")
+	assert.Empty(t, findIDs(t, db, NewQuery(memoryTestCollection)))
+}
+
+func TestMemoryDB_Find(t *testing.T) {
+	db := seedMemoryDB(t, map[string]map[string]interface{}{
+		"a": {"Category": "housing", "Amount": 1200.0, "DueDate": "2025-03-05T00:00:00Z", "Tags": []string{"fixed"}},
+		"b": {"Category": "food", "Amount": 300.0, "DueDate": "2025-03-10T00:00:00Z", "Tags": []string{"market", "weekly"}},
+		"c": {"Category": "food", "Amount": 80.5, "DueDate": "2025-04-01T00:00:00Z"},
+		"d": {"Category": "leisure", "Amount": nil, "DueDate": "2025-03-20T00:00:00Z"},
+	})
+	base := func() *Query { return NewQuery(memoryTestCollection) }
+
+	tests := []struct {
+		name  string
+		query *Query
+		want  []string
+	}{
+		{name: "all by id", query: base(), want: []string{"a", "b", "c", "d"}},
+		{name: "equal", query: base().Where("Category", FilterEquals, "food"), want: []string{"b", "c"}},
+		{name: "not equal skips null", query: base().Where("Amount", FilterNotEquals, 300), want: []string{"a", "c"}},
+		{name: "number types compare", query: base().Where("Amount", FilterGreaterThan, 300), want: []string{"a"}},
+		{name: "range only matches the same type", query: base().Where("Amount", FilterLessOrEqual, "999"), want: []string{}},
+		{name: "date range", query: base().Where("DueDate", FilterGreaterOrEqual, "2025-03-01T00:00:00Z").Where("DueDate", FilterLessOrEqual, "2025-03-31T24:00:00Z"), want: []string{"a", "b", "d"}},
+		{name: "in", query: base().Where("Category", FilterIn, []string{"housing", "leisure"}), want: []string{"a", "d"}},
+		{name: "array contains", query: base().Where("Tags", FilterArrayContains, "weekly"), want: []string{"b"}},
+		{name: "array contains any", query: base().Where("Tags", FilterArrayContainsAny, []string{"fixed", "market"}), want: []string{"a", "b"}},
+		{name: "missing field", query: base().Where("Tags", FilterNotEquals, "x"), want: []string{"a", "b"}},
+		{name: "order by", query: base().OrderBy("DueDate", Descending), want: []string{"c", "d", "b", "a"}},
+		{name: "order by then id", query: base().OrderBy("Category", Ascending), want: []string{"b", "c", "a", "d"}},
+		{name: "offset and limit", query: base().OrderBy("DueDate", Ascending).Offset(1).Limit(2), want: []string{"b", "d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, findIDs(t, db, tt.query))
+		})
+	}
+
+	t.Run("pages with a cursor", func(t *testing.T) {
+		var ids []string
+		cursor := ""
+		for pages := 0; pages < 10; pages++ {
+			result, err := db.Find(context.Background(), base().OrderBy("Category", Descending).Limit(3).StartAfter(cursor))
+			require.NoError(t, err)
+			for _, doc := range result.Documents {
+				ids = append(ids, doc["id"].(string))
+			}
+			if result.NextCursor == "" {
+				break
+			}
+			cursor = result.NextCursor
+		}
+		assert.Equal(t, []string{"d", "a", "c", "b"}, ids)
+	})
+
+	t.Run("select", func(t *testing.T) {
+		result, err := db.Find(context.Background(), base().Where("Category", FilterEquals, "housing").Select("Amount"))
+		require.NoError(t, err)
+		assert.Equal(t, []map[string]interface{}{{"id": "a", "Amount": 1200.0}}, result.Documents)
+	})
+
+	t.Run("cursor of another query", func(t *testing.T) {
+		cursor, err := encodeCursor([]interface{}{"food"}, "b")
+		require.NoError(t, err)
+		_, err = db.Find(context.Background(), base().StartAfter(cursor))
+		assert.EqualError(t, err, "invalid cursor: it belongs to a query with other orders")
+	})
+}
+
+func TestMemoryDB_Batch(t *testing.T) {
+	ctx := context.Background()
+	db := seedMemoryDB(t, map[string]map[string]interface{}{"a": {"Amount": 1.0}})
+
+	err := db.Batch(ctx, func(ctx context.Context) error {
+		require.NoError(t, db.Update(ctx, "b", map[string]interface{}{"Amount": 2.0}, memoryTestCollection))
+		require.NoError(t, db.Delete(ctx, "a", memoryTestCollection))
+		assert.Equal(t, []string{"a"}, findIDs(t, db, NewQuery(memoryTestCollection)), "pending writes are not visible")
+		return errors.New("failed")
+	})
+	assert.EqualError(t, err, "failed")
+	assert.Equal(t, []string{"a"}, findIDs(t, db, NewQuery(memoryTestCollection)), "nothing is written when the batch fails")
+
+	err = db.Batch(ctx, func(ctx context.Context) error {
+		if err := db.Update(ctx, "b", map[string]interface{}{"Amount": 2.0}, memoryTestCollection); err != nil {
+			return err
+		}
+		return db.Delete(ctx, "a", memoryTestCollection)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b"}, findIDs(t, db, NewQuery(memoryTestCollection)))
+}
+
+func TestMemoryDB_RunTransaction(t *testing.T) {
+	ctx := context.Background()
+	db := seedMemoryDB(t, map[string]map[string]interface{}{"a": {"Amount": 1.0}})
+
+	err := db.RunTransaction(ctx, func(ctx context.Context) error {
+		result, err := db.Find(ctx, NewQuery(memoryTestCollection))
+		if err != nil {
+			return err
+		}
+		amount := result.Documents[0]["Amount"].(float64)
+		if err := db.Update(ctx, "a", map[string]interface{}{"Amount": amount + 1}, memoryTestCollection); err != nil {
+			return err
+		}
+
+		// Nested units of work join the transaction.
+		return db.Batch(ctx, func(ctx context.Context) error {
+			_, err := db.Find(ctx, NewQuery(memoryTestCollection))
+			return err
+		})
+	})
+	assert.EqualError(t, err, "read after write in transaction")
+
+	err = db.RunTransaction(ctx, func(ctx context.Context) error {
+		return db.Update(ctx, "a", map[string]interface{}{"Amount": 5.0}, memoryTestCollection)
+	})
+	require.NoError(t, err)
+
+	result, err := db.Find(ctx, NewQuery(memoryTestCollection))
+	require.NoError(t, err)
+	assert.Equal(t, 5.0, result.Documents[0]["Amount"])
+}
+
+func TestInitializeDatabase(t *testing.T) {
+	db, err := InitializeDatabase(DatabaseConfig{Driver: DriverMemory}, FirebaseConfig{})
+	require.NoError(t, err)
+	assert.IsType(t, &MemoryDB{}, db)
+
+	_, err = InitializeDatabase(DatabaseConfig{Driver: "postgres"}, FirebaseConfig{})
+	assert.EqualError(t, err, `unknown database driver "postgres"`)
+
+	config := DatabaseConfig{Driver: DriverSQLite}
+	if sqliteAvailable {
+		assert.NoError(t, config.Validate())
+	} else {
+		assert.ErrorIs(t, config.Validate(), errSQLiteWithoutCgo)
+	}
+}
@@ -0,0 +1,549 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+const (
+	// mongoParentField holds the path of the document owning the collection of a document,
+	// "data/{userID}" for the collections of a user.
+	mongoParentField = "_parent"
+	// mongoIDField holds the full path of the document, which keeps the IDs of the documents
+	// of different parents apart in the same Mongo collection.
+	mongoIDField = "_id"
+)
+
+// MongoDocumentDB implements FirebaseDBInterface on MongoDB, so the repositories run without
+// GCP. A collection path such as data/{userID}/expenses is stored in the Mongo collection
+// named by its last segment ("expenses"), and each document records the path of its parent
+// ("data/{userID}") in the _parent field every query is scoped by.
+//
+// Transactions and batches run in Mongo transactions, which need a replica set; a single-node
+// replica set is enough.
+type MongoDocumentDB struct {
+	client   *mongo.Client
+	database *mongo.Database
+	indexed  sync.Map
+}
+
+// InitializeMongoDocumentDB connects to the database named by the config. The collection name
+// of the config is not used: the collections come from the paths of the requests.
+func InitializeMongoDocumentDB(config MongoConfig) (FirebaseDBInterface, error) {
+	if config.ConnectionString == "" {
+		return nil, fmt.Errorf("ConnectionString is required for MongoDB")
+	}
+	if config.DatabaseName == "" {
+		return nil, fmt.Errorf("DatabaseName is required for MongoDB")
+	}
+
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(config.ConnectionString))
+	if err != nil {
+		return nil, fmt.Errorf("mongo.Connect: %w", err)
+	}
+
+	pingCtx, cancelPing := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelPing()
+	if err := client.Ping(pingCtx, readpref.Primary()); err != nil {
+		client.Disconnect(context.Background())
+		return nil, fmt.Errorf("MongoDB ping failed: %w", err)
+	}
+
+	log.Printf("Successfully connected to MongoDB, database: %s", config.DatabaseName)
+	return &MongoDocumentDB{
+		client:   client,
+		database: client.Database(config.DatabaseName),
+	}, nil
+}
+
+type mongoWorkKey struct{}
+
+// mongoWork is the transaction or the batch a context runs in on a MongoDocumentDB. In a
+// transaction the context carries the Mongo session and the writes run at once; a batch
+// keeps them until fn returns.
+type mongoWork struct {
+	db     *MongoDocumentDB
+	tx     bool
+	writes []func(ctx context.Context) error
+}
+
+func (db *MongoDocumentDB) workFrom(ctx context.Context) *mongoWork {
+	work, _ := ctx.Value(mongoWorkKey{}).(*mongoWork)
+	if work == nil || work.db != db {
+		return nil
+	}
+	return work
+}
+
+func (db *MongoDocumentDB) write(ctx context.Context, op func(ctx context.Context) error) error {
+	if work := db.workFrom(ctx); work != nil && !work.tx {
+		if len(work.writes) == maxUnitOfWorkWrites {
+			return fmt.Errorf("batch exceeds the limit of %d writes", maxUnitOfWorkWrites)
+		}
+		work.writes = append(work.writes, op)
+		return nil
+	}
+	return op(ctx)
+}
+
+// Create stores the data under a generated ID and returns it with the "id" added.
+func (db *MongoDocumentDB) Create(ctx context.Context, data interface{}, collection string) ([]byte, error) {
+	if err := db.validate(ctx, collection); err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, errors.New("data is nil")
+	}
+
+	// Same timestamps as FirebaseDB.Create.
+	mapData, isMap := data.(map[string]interface{})
+	if isMap {
+		if _, exists := mapData["createdAt"]; !exists {
+			mapData["updatedAt"] = firestore.ServerTimestamp
+		}
+	}
+
+	doc, err := toDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	doc = resolveSentinels(doc, mongoNow())
+
+	id, err := newDocumentID()
+	if err != nil {
+		return nil, err
+	}
+	name, parent := splitCollectionPath(collection)
+	doc[mongoIDField] = collection + "/" + id
+	doc[mongoParentField] = parent
+
+	err = db.write(ctx, func(ctx context.Context) error {
+		_, err := db.collection(name).InsertOne(ctx, doc)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if isMap {
+		mapData["id"] = id
+		data = mapData
+	}
+	return json.Marshal(data)
+}
+
+// Update merges the data into the document, creating it when it does not exist, as
+// firestore.MergeAll does.
+func (db *MongoDocumentDB) Update(ctx context.Context, id string, data interface{}, collection string) error {
+	if id == "" {
+		return fmt.Errorf("id is empty")
+	}
+	if err := db.validate(ctx, collection); err != nil {
+		return err
+	}
+	if data == nil {
+		return errors.New("data is nil")
+	}
+
+	if mapData, ok := data.(map[string]interface{}); ok {
+		if _, exists := mapData["updatedAt"]; !exists {
+			mapData["updatedAt"] = firestore.ServerTimestamp
+		}
+	}
+
+	doc, err := toDocument(data)
+	if err != nil {
+		return err
+	}
+
+	name, parent := splitCollectionPath(collection)
+	update := mongoUpdate(doc, mongoNow())
+	update[mongoSetOnInsert] = bson.M{mongoParentField: parent}
+
+	return db.write(ctx, func(ctx context.Context) error {
+		_, err := db.collection(name).UpdateOne(ctx, bson.M{mongoIDField: collection + "/" + id}, update, options.Update().SetUpsert(true))
+		return err
+	})
+}
+
+// Delete removes the document; deleting a missing document is not an error.
+func (db *MongoDocumentDB) Delete(ctx context.Context, id, collection string) error {
+	if id == "" {
+		return fmt.Errorf("id is empty")
+	}
+	if err := db.validate(ctx, collection); err != nil {
+		return err
+	}
+
+	name, _ := splitCollectionPath(collection)
+	return db.write(ctx, func(ctx context.Context) error {
+		_, err := db.collection(name).DeleteOne(ctx, bson.M{mongoIDField: collection + "/" + id})
+		return err
+	})
+}
+
+// Find runs the query on its collection with the ordering and cursors of FirebaseDB.Find.
+// Inside RunTransaction the documents are read in the transaction.
+func (db *MongoDocumentDB) Find(ctx context.Context, query *Query) (*QueryResult, error) {
+	if query == nil {
+		return nil, errors.New("query is nil")
+	}
+	if err := db.validate(ctx, query.collection); err != nil {
+		return nil, err
+	}
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	name, parent := splitCollectionPath(query.collection)
+	filter, err := mongoFilter(parent, query)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().SetSort(mongoSort(query))
+	if fields := query.selectedFields(); fields != nil {
+		projection := bson.M{}
+		for _, field := range fields {
+			projection[field] = 1
+		}
+		opts.SetProjection(projection)
+	}
+	if query.offset > 0 {
+		opts.SetSkip(int64(query.offset))
+	}
+	if query.limit > 0 {
+		opts.SetLimit(int64(query.limit + 1))
+	}
+
+	cursor, err := db.collection(name).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("MongoDB Find error: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	result := &QueryResult{}
+	var last map[string]interface{}
+	for cursor.Next(ctx) {
+		var raw bson.M
+		if err := cursor.Decode(&raw); err != nil {
+			return nil, err
+		}
+
+		if query.limit > 0 && len(result.Documents) == query.limit {
+			result.NextCursor, err = encodeCursor(orderValues(last, query.orders), last["id"].(string))
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+
+		data, _ := fromBSON(raw).(map[string]interface{})
+		path, _ := data[mongoIDField].(string)
+		delete(data, mongoIDField)
+		delete(data, mongoParentField)
+		data["id"] = strings.TrimPrefix(path, query.collection+"/")
+		result.Documents = append(result.Documents, data)
+		last = data
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("MongoDB cursor error: %w", err)
+	}
+
+	return result, nil
+}
+
+// RunTransaction runs fn in a Mongo transaction. The calls made with the context given to fn
+// take part in it, and Mongo may run fn again on a transient error, so fn must leave side
+// effects to the caller. A transaction started inside another transaction or batch joins it.
+func (db *MongoDocumentDB) RunTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if fn == nil {
+		return errors.New("transaction function is nil")
+	}
+	if db.workFrom(ctx) != nil {
+		return fn(ctx)
+	}
+
+	return db.withTransaction(ctx, func(ctx context.Context) error {
+		return fn(context.WithValue(ctx, mongoWorkKey{}, &mongoWork{db: db, tx: true}))
+	})
+}
+
+// Batch runs fn once and commits the Create, Update and Delete calls made with its context
+// together in a Mongo transaction when it returns nil. Reads are not isolated and do not see
+// the pending writes. A batch started inside another transaction or batch joins it.
+func (db *MongoDocumentDB) Batch(ctx context.Context, fn func(ctx context.Context) error) error {
+	if fn == nil {
+		return errors.New("batch function is nil")
+	}
+	if db.workFrom(ctx) != nil {
+		return fn(ctx)
+	}
+	if db.client == nil {
+		return errors.New("mongo client not initialized. Call Connect first")
+	}
+
+	work := &mongoWork{db: db}
+	if err := fn(context.WithValue(ctx, mongoWorkKey{}, work)); err != nil {
+		return err
+	}
+	if len(work.writes) == 0 {
+		return nil
+	}
+
+	return db.withTransaction(ctx, func(ctx context.Context) error {
+		for _, write := range work.writes {
+			if err := write(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Close terminates the MongoDB connection.
+func (db *MongoDocumentDB) Close() error {
+	if db.client == nil {
+		return errors.New("MongoDB client not initialized or already closed")
+	}
+	if err := db.client.Disconnect(context.Background()); err != nil {
+		return fmt.Errorf("error disconnecting MongoDB client: %w", err)
+	}
+	db.client = nil
+	db.database = nil
+	log.Println("MongoDB connection closed.")
+	return nil
+}
+
+func (db *MongoDocumentDB) withTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if db.client == nil {
+		return errors.New("mongo client not initialized. Call Connect first")
+	}
+
+	session, err := db.client.StartSession()
+	if err != nil {
+		return fmt.Errorf("MongoDB StartSession error: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(ctx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(ctx)
+	})
+	return err
+}
+
+// collection returns the Mongo collection, creating the index on the parent path the first
+// time it is used.
+func (db *MongoDocumentDB) collection(name string) *mongo.Collection {
+	coll := db.database.Collection(name)
+	if _, loaded := db.indexed.LoadOrStore(name, true); !loaded {
+		// Outside the request context: indexes cannot be created inside a transaction.
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: mongoParentField, Value: 1}}})
+		if err != nil {
+			log.Printf("failed to create the %s index on %s: %v", mongoParentField, name, err)
+			db.indexed.Delete(name)
+		}
+	}
+	return coll
+}
+
+func (db *MongoDocumentDB) validate(ctx context.Context, collection string) error {
+	if db.client == nil {
+		return errors.New("mongo client not initialized. Call Connect first")
+	}
+	return validateDocumentRequest(ctx, collection)
+}
+
+// mongoNow is the time server timestamps are set to, at the millisecond precision of BSON dates.
+func mongoNow() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
+
+const (
+	mongoSet         = "$set"
+	mongoUnset       = "$unset"
+	mongoSetOnInsert = "$setOnInsert"
+)
+
+// mongoUpdate turns a document into a Mongo update merging it into the stored one: nested maps
+// are set field by field, server timestamps are set to now and deleted fields are unset.
+func mongoUpdate(doc map[string]interface{}, now time.Time) bson.M {
+	set, unset := bson.M{}, bson.M{}
+	flattenMongoUpdate("", doc, now, set, unset)
+
+	update := bson.M{}
+	if len(set) > 0 {
+		update[mongoSet] = set
+	}
+	if len(unset) > 0 {
+		update[mongoUnset] = unset
+	}
+	return update
+}
+
+func flattenMongoUpdate(prefix string, doc map[string]interface{}, now time.Time, set, unset bson.M) {
+	for key, value := range doc {
+		path := prefix + key
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			flattenMongoUpdate(path+".", nested, now, set, unset)
+			continue
+		}
+
+		switch value {
+		case firestore.ServerTimestamp:
+			set[path] = now
+		case firestore.Delete:
+			unset[path] = ""
+		default:
+			set[path] = value
+		}
+	}
+}
+
+// mongoFilter translates the conditionals and the cursor of the query into a filter on the
+// documents of the parent. As in Firestore, documents missing an order field are left out.
+func mongoFilter(parent string, query *Query) (bson.M, error) {
+	clauses := bson.A{bson.M{mongoParentField: parent}}
+	if query.id != "" {
+		clauses = append(clauses, bson.M{mongoIDField: query.collection + "/" + query.id})
+	}
+
+	for _, cond := range query.conditionals {
+		value, err := normalizeValue(cond.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", cond.Field, err)
+		}
+		clauses = append(clauses, mongoCondition(cond.Field, cond.Filter, value))
+	}
+
+	for _, order := range query.orders {
+		clauses = append(clauses, bson.M{order.Field: bson.M{"$exists": true}})
+	}
+
+	if query.cursor != "" {
+		values, id, err := decodeCursor(query.cursor, len(query.orders))
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, mongoStartAfter(query, values, query.collection+"/"+id))
+	}
+
+	return bson.M{"$and": clauses}, nil
+}
+
+func mongoCondition(field string, filter Filter, value interface{}) bson.M {
+	switch filter {
+	case FilterNotEquals:
+		// Firestore leaves out the documents where the field is null or missing.
+		return bson.M{field: bson.M{"$nin": bson.A{value, nil}}}
+	case FilterGreaterThan:
+		return bson.M{field: bson.M{"$gt": value}}
+	case FilterLessThan:
+		return bson.M{field: bson.M{"$lt": value}}
+	case FilterGreaterOrEqual:
+		return bson.M{field: bson.M{"$gte": value}}
+	case FilterLessOrEqual:
+		return bson.M{field: bson.M{"$lte": value}}
+	case FilterIn:
+		return bson.M{field: bson.M{"$in": value}}
+	case FilterArrayContains:
+		return bson.M{field: bson.M{"$elemMatch": bson.M{"$eq": value}}}
+	case FilterArrayContainsAny:
+		return bson.M{field: bson.M{"$elemMatch": bson.M{"$in": value}}}
+	}
+	return bson.M{field: bson.M{"$eq": value}}
+}
+
+// mongoStartAfter keeps the documents sorted after the cursor: those past it on the first
+// order field, or equal to it there and past it on the next one, down to the document path.
+func mongoStartAfter(query *Query, values []interface{}, path string) bson.M {
+	keys := mongoSortKeys(query)
+	values = append(values, path)
+
+	branches := bson.A{}
+	for i, key := range keys {
+		branch := bson.A{}
+		for j := 0; j < i; j++ {
+			branch = append(branch, bson.M{keys[j].Field: bson.M{"$eq": values[j]}})
+		}
+		op := "$gt"
+		if key.Direction == Descending {
+			op = "$lt"
+		}
+		branch = append(branch, bson.M{key.Field: bson.M{op: values[i]}})
+		branches = append(branches, bson.M{"$and": branch})
+	}
+	return bson.M{"$or": branches}
+}
+
+// mongoSortKeys returns the order fields followed by the document path, in the direction of
+// the last order.
+func mongoSortKeys(query *Query) []Order {
+	keys := make([]Order, 0, len(query.orders)+1)
+	keys = append(keys, query.orders...)
+	return append(keys, Order{Field: mongoIDField, Direction: query.direction()})
+}
+
+func mongoSort(query *Query) bson.D {
+	sort := bson.D{}
+	for _, order := range mongoSortKeys(query) {
+		direction := 1
+		if order.Direction == Descending {
+			direction = -1
+		}
+		sort = append(sort, bson.E{Key: order.Field, Value: direction})
+	}
+	return sort
+}
+
+// fromBSON converts a decoded value to the types FirebaseDB returns.
+func fromBSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case primitive.M:
+		return fromBSON(map[string]interface{}(v))
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[key] = fromBSON(item)
+		}
+		return m
+	case primitive.D:
+		m := make(map[string]interface{}, len(v))
+		for _, item := range v {
+			m[item.Key] = fromBSON(item.Value)
+		}
+		return m
+	case primitive.A:
+		return fromBSON([]interface{}(v))
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = fromBSON(item)
+		}
+		return list
+	case primitive.DateTime:
+		return v.Time().UTC()
+	case primitive.Binary:
+		return v.Data
+	case primitive.ObjectID:
+		return v.Hex()
+	case int32:
+		return int64(v)
+	}
+	return value
+}
@@ -0,0 +1,199 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSplitCollectionPath(t *testing.T) {
+	name, parent := splitCollectionPath("data/user-1/expenses")
+	assert.Equal(t, "expenses", name)
+	assert.Equal(t, "data/user-1", parent)
+
+	name, parent = splitCollectionPath("support")
+	assert.Equal(t, "support", name)
+	assert.Equal(t, "", parent)
+}
+
+func TestMongoFilter(t *testing.T) {
+	query := NewQuery("data/user-1/expenses").
+		Where("Category", FilterIn, []string{"housing", "food"}).
+		Where("Amount", FilterNotEquals, 10).
+		Where("Tags", FilterArrayContains, "fixed").
+		Where("DueDate", FilterGreaterOrEqual, "2025-03-01T00:00:00Z").
+		OrderBy("DueDate", Descending)
+
+	filter, err := mongoFilter("data/user-1", query)
+	require.NoError(t, err)
+	assert.Equal(t, bson.M{"$and": bson.A{
+		bson.M{"_parent": "data/user-1"},
+		bson.M{"Category": bson.M{"$in": []interface{}{"housing", "food"}}},
+		bson.M{"Amount": bson.M{"$nin": bson.A{int64(10), nil}}},
+		bson.M{"Tags": bson.M{"$elemMatch": bson.M{"$eq": "fixed"}}},
+		bson.M{"DueDate": bson.M{"$gte": "2025-03-01T00:00:00Z"}},
+		bson.M{"DueDate": bson.M{"$exists": true}},
+	}}, filter)
+
+	assert.Equal(t, bson.D{{Key: "DueDate", Value: -1}, {Key: "_id", Value: -1}}, mongoSort(query))
+
+	t.Run("start after the cursor", func(t *testing.T) {
+		cursor, err := encodeCursor([]interface{}{"2025-03-10T00:00:00Z"}, "e-1")
+		require.NoError(t, err)
+
+		filter, err := mongoFilter("data/user-1", NewQuery("data/user-1/expenses").OrderBy("DueDate", Ascending).StartAfter(cursor))
+		require.NoError(t, err)
+		clauses := filter["$and"].(bson.A)
+		assert.Equal(t, bson.M{"$or": bson.A{
+			bson.M{"$and": bson.A{
+				bson.M{"DueDate": bson.M{"$gt": "2025-03-10T00:00:00Z"}},
+			}},
+			bson.M{"$and": bson.A{
+				bson.M{"DueDate": bson.M{"$eq": "2025-03-10T00:00:00Z"}},
+				bson.M{"_id": bson.M{"$gt": "data/user-1/expenses/e-1"}},
+			}},
+		}}, clauses[len(clauses)-1])
+	})
+
+	t.Run("by document ID", func(t *testing.T) {
+		filter, err := mongoFilter("data/user-1", NewQuery("data/user-1/expenses").WhereID("e-1"))
+		require.NoError(t, err)
+		assert.Equal(t, bson.M{"$and": bson.A{
+			bson.M{"_parent": "data/user-1"},
+			bson.M{"_id": "data/user-1/expenses/e-1"},
+		}}, filter)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		_, err := mongoFilter("data/user-1", NewQuery("data/user-1/expenses").StartAfter("not a cursor"))
+		assert.Error(t, err)
+	})
+}
+
+func TestMongoUpdate(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	update := mongoUpdate(map[string]interface{}{
+		"Amount":    10.5,
+		"Meta":      map[string]interface{}{"a": int64(1), "b": firestore.Delete},
+		"Empty":     map[string]interface{}{},
+		"updatedAt": firestore.ServerTimestamp,
+	}, now)
+
+	assert.Equal(t, bson.M{
+		"$set": bson.M{
+			"Amount":    10.5,
+			"Meta.a":    int64(1),
+			"Empty":     map[string]interface{}{},
+			"updatedAt": now,
+		},
+		"$unset": bson.M{"Meta.b": ""},
+	}, update)
+}
+
+func TestFromBSON(t *testing.T) {
+	date := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	value := fromBSON(bson.M{
+		"_id":       "data/user-1/expenses/e-1",
+		"Amount":    int32(3),
+		"CreatedAt": primitive.NewDateTimeFromTime(date),
+		"Tags":      bson.A{"a", bson.D{{Key: "k", Value: "v"}}},
+		"Nested":    bson.M{"n": int32(1)},
+	})
+
+	assert.Equal(t, map[string]interface{}{
+		"_id":       "data/user-1/expenses/e-1",
+		"Amount":    int64(3),
+		"CreatedAt": date,
+		"Tags":      []interface{}{"a", map[string]interface{}{"k": "v"}},
+		"Nested":    map[string]interface{}{"n": int64(1)},
+	}, value)
+}
+
+func TestMongoDocumentDB_Unconnected(t *testing.T) {
+	db := &MongoDocumentDB{}
+	ctx := context.Background()
+
+	_, err := db.Find(ctx, NewQuery("data/user-1/expenses"))
+	assert.EqualError(t, err, "mongo client not initialized. Call Connect first")
+	assert.EqualError(t, db.Batch(ctx, func(ctx context.Context) error { return nil }), "mongo client not initialized. Call Connect first")
+	assert.EqualError(t, db.RunTransaction(ctx, nil), "transaction function is nil")
+
+	_, err = InitializeMongoDocumentDB(MongoConfig{ConnectionString: "mongodb://localhost:27017"})
+	assert.EqualError(t, err, "DatabaseName is required for MongoDB")
+}
+
+func TestMongoDocumentDB_Write(t *testing.T) {
+	db := &MongoDocumentDB{}
+	work := &mongoWork{db: db}
+	ctx := context.WithValue(context.Background(), mongoWorkKey{}, work)
+	for i := 0; i < maxUnitOfWorkWrites; i++ {
+		require.NoError(t, db.write(ctx, func(ctx context.Context) error { return nil }))
+	}
+
+	assert.Len(t, work.writes, maxUnitOfWorkWrites)
+	assert.EqualError(t, db.write(ctx, func(ctx context.Context) error { return nil }), "batch exceeds the limit of 500 writes")
+}
+
+// TestMongoDocumentDB runs against the database of the MONGO_* variables, which must be a
+// replica set for the batch.
+func TestMongoDocumentDB(t *testing.T) {
+	cfg, ok := getTestMongoConfig()
+	if !ok {
+		t.Skip("MONGO_CONNECTION_STRING, MONGO_DATABASE_NAME, or MONGO_COLLECTION_NAME not set, skipping MongoDocumentDB test.")
+	}
+
+	db, err := InitializeMongoDocumentDB(cfg)
+	require.NoError(t, err)
+	defer db.(*MongoDocumentDB).Close()
+
+	ctx := context.Background()
+	collection := "data/test-" + primitive.NewObjectID().Hex() + "/expenses"
+
+	var ids []string
+	err = db.Batch(ctx, func(ctx context.Context) error {
+		for _, amount := range []float64{30, 10, 20} {
+			b, err := db.Create(ctx, map[string]interface{}{"Amount": amount, "Category": "food"}, collection)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, string(b))
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, ids, 3)
+
+	var amounts []interface{}
+	cursor := ""
+	for {
+		result, err := db.Find(ctx, NewQuery(collection).OrderBy("Amount", Ascending).Limit(2).StartAfter(cursor))
+		require.NoError(t, err)
+		for _, doc := range result.Documents {
+			amounts = append(amounts, doc["Amount"])
+			assert.NotEmpty(t, doc["id"])
+			assert.IsType(t, time.Time{}, doc["updatedAt"])
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		cursor = result.NextCursor
+	}
+	assert.Equal(t, []interface{}{10.0, 20.0, 30.0}, amounts)
+
+	require.NoError(t, db.Update(ctx, "fixed", map[string]interface{}{"Amount": 5.0}, collection))
+	result, err := db.Find(ctx, NewQuery(collection).Where("Amount", FilterLessThan, 10))
+	require.NoError(t, err)
+	require.Len(t, result.Documents, 1)
+	assert.Equal(t, "fixed", result.Documents[0]["id"])
+
+	require.NoError(t, db.Delete(ctx, "fixed", collection))
+	result, err = db.Find(ctx, NewQuery(collection).Where("Amount", FilterLessThan, 10))
+	require.NoError(t, err)
+	assert.Empty(t, result.Documents)
+}
@@ -0,0 +1,458 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLiteDB implements FirebaseDBInterface on an embedded SQLite file, for single-user
+// deployments: the whole data is one file that can be copied for backup while the server is
+// stopped. Documents are stored as JSON in the documents table, one row per document keyed
+// by its collection path, with the user ID of data/{userID}/... paths and the date field of
+// the collection in indexed columns. Queries read the documents of the collection, narrowed
+// by date when filtered on it, and apply the remaining clauses like MemoryDB.
+//
+// The driver uses cgo: the server must be built with CGO_ENABLED=1.
+type SQLiteDB struct {
+	db *sql.DB
+}
+
+// errSQLiteWithoutCgo is returned when the sqlite driver is picked on a server built without
+// cgo, where go-sqlite3 only fails on the first query.
+var errSQLiteWithoutCgo = errors.New("database driver \"sqlite\" requires a server built with CGO_ENABLED=1, see docs/BUILD.md")
+
+// sqliteDateFields is the field stored in the date column of each collection, the one its
+// listings filter by.
+var sqliteDateFields = map[string]string{
+	"expenses":             "DueDate",
+	"incomes":              "ReceiptDate",
+	"transfers":            "transferDate",
+	"credit-card-invoices": "dueDate",
+}
+
+// sqliteMigrations are applied in order on startup; the version of each is its position
+// plus one. Append new migrations, never edit applied ones.
+var sqliteMigrations = []string{
+	`CREATE TABLE documents (
+		collection TEXT NOT NULL,
+		id         TEXT NOT NULL,
+		user_id    TEXT NOT NULL DEFAULT '',
+		date       TEXT,
+		data       TEXT NOT NULL,
+		PRIMARY KEY (collection, id)
+	);
+	CREATE INDEX documents_user_id ON documents (user_id);
+	CREATE INDEX documents_collection_date ON documents (collection, date);`,
+}
+
+// InitializeSQLiteDB opens the database file, creating it when missing, and migrates its schema.
+func InitializeSQLiteDB(config SQLiteConfig) (FirebaseDBInterface, error) {
+	if !sqliteAvailable {
+		return nil, errSQLiteWithoutCgo
+	}
+	if config.Path == "" {
+		return nil, fmt.Errorf("Path is required for SQLite")
+	}
+
+	db, err := sql.Open("sqlite3", "file:"+config.Path+"?_busy_timeout=5000")
+	if err != nil {
+		return nil, fmt.Errorf("sql.Open: %w", err)
+	}
+	// A single connection serializes the writes, which SQLite does not run concurrently.
+	db.SetMaxOpenConns(1)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("SQLite open failed: %w", err)
+	}
+
+	sdb := &SQLiteDB{db: db}
+	if err := sdb.migrate(context.Background()); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	log.Printf("Successfully opened SQLite database: %s", config.Path)
+	return sdb, nil
+}
+
+// migrate applies the migrations the database has not run yet.
+func (db *SQLiteDB) migrate(ctx context.Context) error {
+	_, err := db.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at TEXT NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	var version int
+	if err := db.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return fmt.Errorf("failed to read the schema version: %w", err)
+	}
+
+	for i := version; i < len(sqliteMigrations); i++ {
+		err := db.inTx(ctx, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, sqliteMigrations[i]); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`, i+1, time.Now().UTC().Format(time.RFC3339))
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %d: %w", i+1, err)
+		}
+		log.Printf("Applied SQLite migration %d", i+1)
+	}
+	return nil
+}
+
+type sqliteWorkKey struct{}
+
+// sqliteWork is the transaction or the batch a context runs in on a SQLiteDB. A transaction
+// runs its reads and writes in tx; a batch keeps the writes until fn returns.
+type sqliteWork struct {
+	db     *SQLiteDB
+	tx     *sql.Tx
+	writes []func(tx *sql.Tx) error
+}
+
+func (db *SQLiteDB) workFrom(ctx context.Context) *sqliteWork {
+	work, _ := ctx.Value(sqliteWorkKey{}).(*sqliteWork)
+	if work == nil || work.db != db {
+		return nil
+	}
+	return work
+}
+
+// write runs op in the transaction of the context, keeps it for the batch of the context, or
+// runs it in a transaction of its own.
+func (db *SQLiteDB) write(ctx context.Context, op func(tx *sql.Tx) error) error {
+	if work := db.workFrom(ctx); work != nil {
+		if work.tx != nil {
+			return op(work.tx)
+		}
+		if len(work.writes) == maxUnitOfWorkWrites {
+			return fmt.Errorf("batch exceeds the limit of %d writes", maxUnitOfWorkWrites)
+		}
+		work.writes = append(work.writes, op)
+		return nil
+	}
+	return db.inTx(ctx, op)
+}
+
+func (db *SQLiteDB) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// Create stores the data under a generated ID and returns it with the "id" added.
+func (db *SQLiteDB) Create(ctx context.Context, data interface{}, collection string) ([]byte, error) {
+	if err := db.validate(ctx, collection); err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, errors.New("data is nil")
+	}
+
+	// Same timestamps as FirebaseDB.Create.
+	mapData, isMap := data.(map[string]interface{})
+	if isMap {
+		if _, exists := mapData["createdAt"]; !exists {
+			mapData["updatedAt"] = firestore.ServerTimestamp
+		}
+	}
+
+	doc, err := toDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := newDocumentID()
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.write(ctx, func(tx *sql.Tx) error {
+		return putSQLiteDocument(tx, collection, id, resolveSentinels(doc, sqliteNow()))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if isMap {
+		mapData["id"] = id
+		data = mapData
+	}
+	return json.Marshal(data)
+}
+
+// Update merges the data into the document, creating it when it does not exist, as
+// firestore.MergeAll does.
+func (db *SQLiteDB) Update(ctx context.Context, id string, data interface{}, collection string) error {
+	if id == "" {
+		return fmt.Errorf("id is empty")
+	}
+	if err := db.validate(ctx, collection); err != nil {
+		return err
+	}
+	if data == nil {
+		return errors.New("data is nil")
+	}
+
+	if mapData, ok := data.(map[string]interface{}); ok {
+		if _, exists := mapData["updatedAt"]; !exists {
+			mapData["updatedAt"] = firestore.ServerTimestamp
+		}
+	}
+
+	doc, err := toDocument(data)
+	if err != nil {
+		return err
+	}
+
+	return db.write(ctx, func(tx *sql.Tx) error {
+		merged := copyValue(doc).(map[string]interface{})
+
+		var raw string
+		err := tx.QueryRow(`SELECT data FROM documents WHERE collection = ? AND id = ?`, collection, id).Scan(&raw)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+		case err != nil:
+			return err
+		default:
+			existing, err := decodeSQLiteDocument(raw)
+			if err != nil {
+				return err
+			}
+			merged = mergeDocument(existing, merged)
+		}
+		return putSQLiteDocument(tx, collection, id, resolveSentinels(merged, sqliteNow()))
+	})
+}
+
+// Delete removes the document; deleting a missing document is not an error.
+func (db *SQLiteDB) Delete(ctx context.Context, id, collection string) error {
+	if id == "" {
+		return fmt.Errorf("id is empty")
+	}
+	if err := db.validate(ctx, collection); err != nil {
+		return err
+	}
+
+	return db.write(ctx, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`DELETE FROM documents WHERE collection = ? AND id = ?`, collection, id)
+		return err
+	})
+}
+
+// Find runs the query like FirebaseDB.Find. Inside RunTransaction the documents are read in
+// the transaction.
+func (db *SQLiteDB) Find(ctx context.Context, query *Query) (*QueryResult, error) {
+	if query == nil {
+		return nil, errors.New("query is nil")
+	}
+	if err := db.validate(ctx, query.collection); err != nil {
+		return nil, err
+	}
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	statement, args := sqliteSelect(query)
+
+	var rows *sql.Rows
+	var err error
+	if work := db.workFrom(ctx); work != nil && work.tx != nil {
+		rows, err = work.tx.QueryContext(ctx, statement, args...)
+	} else {
+		rows, err = db.db.QueryContext(ctx, statement, args...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("SQLite query error: %w", err)
+	}
+	defer rows.Close()
+
+	var docs []storedDocument
+	for rows.Next() {
+		var id, raw string
+		if err := rows.Scan(&id, &raw); err != nil {
+			return nil, err
+		}
+		data, err := decodeSQLiteDocument(raw)
+		if err != nil {
+			return nil, fmt.Errorf("document %s: %w", id, err)
+		}
+		docs = append(docs, storedDocument{id: id, data: data})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return queryDocuments(query, docs)
+}
+
+// RunTransaction runs fn in a SQLite transaction and commits its writes when it returns nil.
+// Calls made inside fn with another context than the one it receives block until the
+// transaction ends.
+func (db *SQLiteDB) RunTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if fn == nil {
+		return errors.New("transaction function is nil")
+	}
+	if db.workFrom(ctx) != nil {
+		return fn(ctx)
+	}
+	if db.db == nil {
+		return errors.New("sqlite database not initialized or already closed")
+	}
+
+	return db.inTx(ctx, func(tx *sql.Tx) error {
+		return fn(context.WithValue(ctx, sqliteWorkKey{}, &sqliteWork{db: db, tx: tx}))
+	})
+}
+
+// Batch runs fn and commits its writes together when it returns nil. Reads inside fn do not
+// see the pending writes.
+func (db *SQLiteDB) Batch(ctx context.Context, fn func(ctx context.Context) error) error {
+	if fn == nil {
+		return errors.New("batch function is nil")
+	}
+	if db.workFrom(ctx) != nil {
+		return fn(ctx)
+	}
+	if db.db == nil {
+		return errors.New("sqlite database not initialized or already closed")
+	}
+
+	work := &sqliteWork{db: db}
+	if err := fn(context.WithValue(ctx, sqliteWorkKey{}, work)); err != nil {
+		return err
+	}
+	if len(work.writes) == 0 {
+		return nil
+	}
+
+	return db.inTx(ctx, func(tx *sql.Tx) error {
+		for _, write := range work.writes {
+			if err := write(tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Close closes the database file.
+func (db *SQLiteDB) Close() error {
+	if db.db == nil {
+		return errors.New("sqlite database not initialized or already closed")
+	}
+	if err := db.db.Close(); err != nil {
+		return fmt.Errorf("error closing SQLite database: %w", err)
+	}
+	db.db = nil
+	log.Println("SQLite database closed.")
+	return nil
+}
+
+func (db *SQLiteDB) validate(ctx context.Context, collection string) error {
+	if db.db == nil {
+		return errors.New("sqlite database not initialized or already closed")
+	}
+	return validateDocumentRequest(ctx, collection)
+}
+
+// sqliteNow is the time server timestamps are set to.
+func sqliteNow() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// sqliteSelect reads the documents of the collection of the query. The filters on the date
+// field of the collection narrow the rows through the date column; queryDocuments applies
+// every filter again.
+func sqliteSelect(query *Query) (string, []interface{}) {
+	statement := `SELECT id, data FROM documents WHERE collection = ?`
+	args := []interface{}{query.collection}
+	if query.id != "" {
+		statement += ` AND id = ?`
+		args = append(args, query.id)
+	}
+
+	name, _ := splitCollectionPath(query.collection)
+	dateField, ok := sqliteDateFields[name]
+	if !ok {
+		return statement, args
+	}
+
+	for _, cond := range query.conditionals {
+		value, isString := cond.Value.(string)
+		if cond.Field != dateField || !isString {
+			continue
+		}
+		switch cond.Filter {
+		case FilterEquals, FilterGreaterThan, FilterLessThan, FilterGreaterOrEqual, FilterLessOrEqual:
+			op := string(cond.Filter)
+			if cond.Filter == FilterEquals {
+				op = "="
+			}
+			statement += ` AND date ` + op + ` ?`
+			args = append(args, value)
+		}
+	}
+	return statement, args
+}
+
+// putSQLiteDocument stores the document, replacing the one with the same ID.
+func putSQLiteDocument(tx *sql.Tx, collection, id string, doc map[string]interface{}) error {
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	var userID string
+	if segments := strings.Split(collection, "/"); len(segments) > 2 && segments[0] == "data" {
+		userID = segments[1]
+	}
+
+	var date interface{}
+	name, _ := splitCollectionPath(collection)
+	if field, ok := sqliteDateFields[name]; ok {
+		switch v := doc[field].(type) {
+		case string:
+			date = v
+		case time.Time:
+			date = v.Format(time.RFC3339Nano)
+		}
+	}
+
+	_, err = tx.Exec(`INSERT INTO documents (collection, id, user_id, date, data) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (collection, id) DO UPDATE SET user_id = excluded.user_id, date = excluded.date, data = excluded.data`,
+		collection, id, userID, date, string(raw))
+	return err
+}
+
+// decodeSQLiteDocument reads a stored document. As after a JSON round trip, numbers come
+// back as float64 and timestamps as RFC 3339 strings.
+func decodeSQLiteDocument(raw string) (map[string]interface{}, error) {
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,6 @@
+//go:build cgo
+
+package database
+
+// sqliteAvailable reports whether the SQLite driver was built; it needs cgo.
+const sqliteAvailable = true
@@ -0,0 +1,167 @@
+//go:build cgo
+
+package database
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSQLiteDB(t *testing.T, path string) *SQLiteDB {
+	t.Helper()
+	db, err := InitializeSQLiteDB(SQLiteConfig{Path: path})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.(*SQLiteDB).Close() })
+	return db.(*SQLiteDB)
+}
+
+func TestSQLiteDB_Migrate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dashfin.db")
+	db := newTestSQLiteDB(t, path)
+	require.NoError(t, db.Update(context.Background(), "e-1", map[string]interface{}{"Amount": 1.0}, memoryTestCollection))
+	require.NoError(t, db.Close())
+
+	// Reopening the file keeps the documents and applies no migration twice.
+	db = newTestSQLiteDB(t, path)
+	var version, migrations int
+	require.NoError(t, db.db.QueryRow(`SELECT MAX(version), COUNT(*) FROM schema_migrations`).Scan(&version, &migrations))
+	assert.Equal(t, len(sqliteMigrations), version)
+	assert.Equal(t, len(sqliteMigrations), migrations)
+
+	result, err := db.Find(context.Background(), NewQuery(memoryTestCollection))
+	require.NoError(t, err)
+	require.Len(t, result.Documents, 1)
+	assert.Equal(t, "e-1", result.Documents[0]["id"])
+
+	_, err = InitializeSQLiteDB(SQLiteConfig{})
+	assert.EqualError(t, err, "Path is required for SQLite")
+}
+
+func TestSQLiteDB_Documents(t *testing.T) {
+	ctx := context.Background()
+	db := newTestSQLiteDB(t, filepath.Join(t.TempDir(), "dashfin.db"))
+
+	_, err := db.Create(ctx, map[string]interface{}{"Category": "housing", "Amount": 1200.0, "DueDate": "2025-03-05T00:00:00Z"}, memoryTestCollection)
+	require.NoError(t, err)
+	for id, doc := range map[string]map[string]interface{}{
+		"b": {"Category": "food", "Amount": 300.5, "DueDate": "2025-03-31T18:30:00Z", "Meta": map[string]interface{}{"a": 1, "b": 2}},
+		"c": {"Category": "food", "Amount": 80.5, "DueDate": "2025-04-01T00:00:00Z"},
+	} {
+		require.NoError(t, db.Update(ctx, id, doc, memoryTestCollection))
+	}
+	require.NoError(t, db.Update(ctx, "b", map[string]interface{}{"Meta": map[string]interface{}{"b": 3}}, memoryTestCollection))
+	require.NoError(t, db.Update(ctx, "x", map[string]interface{}{"Category": "other"}, "data/user-2/expenses"))
+
+	t.Run("indexed columns", func(t *testing.T) {
+		var userID, date string
+		require.NoError(t, db.db.QueryRow(`SELECT user_id, date FROM documents WHERE collection = ? AND id = ?`, memoryTestCollection, "b").Scan(&userID, &date))
+		assert.Equal(t, "user-1", userID)
+		assert.Equal(t, "2025-03-31T18:30:00Z", date)
+
+		statement, args := sqliteSelect(NewQuery(memoryTestCollection).
+			Where("DueDate", FilterGreaterOrEqual, "2025-03-01T00:00:00Z").
+			Where("Category", FilterEquals, "food"))
+		assert.Equal(t, `SELECT id, data FROM documents WHERE collection = ? AND date >= ?`, statement)
+		assert.Equal(t, []interface{}{memoryTestCollection, "2025-03-01T00:00:00Z"}, args)
+
+		statement, args = sqliteSelect(NewQuery(memoryTestCollection).WhereID("b"))
+		assert.Equal(t, `SELECT id, data FROM documents WHERE collection = ? AND id = ?`, statement)
+		assert.Equal(t, []interface{}{memoryTestCollection, "b"}, args)
+	})
+
+	t.Run("date range", func(t *testing.T) {
+		result, err := db.Find(ctx, NewQuery(memoryTestCollection).
+			Where("DueDate", FilterGreaterOrEqual, "2025-03-01T00:00:00Z").
+			Where("DueDate", FilterLessOrEqual, "2025-03-31T24:00:00Z").
+			Where("Category", FilterEquals, "food"))
+		require.NoError(t, err)
+		require.Len(t, result.Documents, 1)
+		doc := result.Documents[0]
+		assert.Equal(t, "b", doc["id"])
+		assert.Equal(t, 300.5, doc["Amount"])
+		assert.Equal(t, map[string]interface{}{"a": 1.0, "b": 3.0}, doc["Meta"], "updates are merged")
+		assert.NotEmpty(t, doc["updatedAt"])
+	})
+
+	t.Run("pages", func(t *testing.T) {
+		var amounts []interface{}
+		cursor := ""
+		for {
+			result, err := db.Find(ctx, NewQuery(memoryTestCollection).OrderBy("DueDate", Descending).Limit(2).StartAfter(cursor))
+			require.NoError(t, err)
+			for _, doc := range result.Documents {
+				amounts = append(amounts, doc["Amount"])
+			}
+			if result.NextCursor == "" {
+				break
+			}
+			cursor = result.NextCursor
+		}
+		assert.Equal(t, []interface{}{80.5, 300.5, 1200.0}, amounts)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, db.Delete(ctx, "c", memoryTestCollection))
+		require.NoError(t, db.Delete(ctx, "c", memoryTestCollection))
+		result, err := db.Find(ctx, NewQuery(memoryTestCollection))
+		require.NoError(t, err)
+		assert.Len(t, result.Documents, 2)
+	})
+}
+
+func TestSQLiteDB_UnitOfWork(t *testing.T) {
+	ctx := context.Background()
+	db := newTestSQLiteDB(t, filepath.Join(t.TempDir(), "dashfin.db"))
+	require.NoError(t, db.Update(ctx, "a", map[string]interface{}{"Amount": 1.0}, memoryTestCollection))
+
+	err := db.Batch(ctx, func(ctx context.Context) error {
+		require.NoError(t, db.Update(ctx, "b", map[string]interface{}{"Amount": 2.0}, memoryTestCollection))
+		require.NoError(t, db.Delete(ctx, "a", memoryTestCollection))
+		return errors.New("failed")
+	})
+	assert.EqualError(t, err, "failed")
+
+	err = db.RunTransaction(ctx, func(ctx context.Context) error {
+		result, err := db.Find(ctx, NewQuery(memoryTestCollection))
+		if err != nil {
+			return err
+		}
+		amount := result.Documents[0]["Amount"].(float64)
+		if err := db.Update(ctx, "a", map[string]interface{}{"Amount": amount + 1}, memoryTestCollection); err != nil {
+			return err
+		}
+		return errors.New("rolled back")
+	})
+	assert.EqualError(t, err, "rolled back")
+
+	result, err := db.Find(ctx, NewQuery(memoryTestCollection))
+	require.NoError(t, err)
+	require.Len(t, result.Documents, 1)
+	assert.Equal(t, 1.0, result.Documents[0]["Amount"], "nothing is written when the batch or the transaction fails")
+
+	err = db.Batch(ctx, func(ctx context.Context) error {
+		if err := db.Update(ctx, "b", map[string]interface{}{"Amount": 2.0}, memoryTestCollection); err != nil {
+			return err
+		}
+		return db.Delete(ctx, "a", memoryTestCollection)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b"}, sqliteIDs(t, db))
+}
+
+func sqliteIDs(t *testing.T, db *SQLiteDB) []string {
+	t.Helper()
+	result, err := db.Find(context.Background(), NewQuery(memoryTestCollection))
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(result.Documents))
+	for _, doc := range result.Documents {
+		ids = append(ids, doc["id"].(string))
+	}
+	return ids
+}
@@ -0,0 +1,277 @@
+package database
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// Direction is the order of an OrderBy clause.
+type Direction string
+
+const (
+	Ascending  Direction = "asc"
+	Descending Direction = "desc"
+)
+
+// Order sorts the documents of a query by a field.
+type Order struct {
+	Field     string
+	Direction Direction
+}
+
+// Query describes a read on a collection independently of the database backend. Build it with
+// NewQuery and the chained methods, and run it with FirebaseDBInterface.Find or FindAs.
+//
+// Documents sharing the values of the OrderBy fields are ordered by their ID, so pages read
+// through Limit and StartAfter keep a stable order.
+type Query struct {
+	collection   string
+	id           string
+	conditionals []Conditional
+	orders       []Order
+	limit        int
+	offset       int
+	cursor       string
+	fields       []string
+}
+
+// NewQuery starts a query on the collection returning every document.
+func NewQuery(collection string) *Query {
+	return &Query{collection: collection}
+}
+
+// Collection returns the collection the query reads.
+func (q *Query) Collection() string {
+	return q.collection
+}
+
+// WhereID keeps the document with the ID, read directly instead of through a field filter.
+func (q *Query) WhereID(id string) *Query {
+	q.id = id
+	return q
+}
+
+// Where keeps the documents whose field matches the value with the filter.
+func (q *Query) Where(field string, filter Filter, value interface{}) *Query {
+	q.conditionals = append(q.conditionals, Conditional{Field: field, Value: value, Filter: filter})
+	return q
+}
+
+// WhereConditionals adds every conditional to the query.
+func (q *Query) WhereConditionals(conditionals ...Conditional) *Query {
+	q.conditionals = append(q.conditionals, conditionals...)
+	return q
+}
+
+// WhereEqual keeps the documents whose fields equal every value of the map.
+func (q *Query) WhereEqual(filters map[string]interface{}) *Query {
+	fields := make([]string, 0, len(filters))
+	for field := range filters {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		q.Where(field, FilterEquals, filters[field])
+	}
+	return q
+}
+
+// OrderBy sorts the documents by the field, after the orders added before.
+func (q *Query) OrderBy(field string, direction Direction) *Query {
+	q.orders = append(q.orders, Order{Field: field, Direction: direction})
+	return q
+}
+
+// Limit returns at most n documents; zero means no limit. When more documents are left, the
+// result carries the cursor of the next page.
+func (q *Query) Limit(n int) *Query {
+	q.limit = n
+	return q
+}
+
+// Offset skips the first n documents.
+func (q *Query) Offset(n int) *Query {
+	q.offset = n
+	return q
+}
+
+// StartAfter continues from the NextCursor of a previous result of the same query.
+func (q *Query) StartAfter(cursor string) *Query {
+	q.cursor = cursor
+	return q
+}
+
+// Select returns only the fields, besides the "id" and the fields the query is ordered by.
+func (q *Query) Select(fields ...string) *Query {
+	q.fields = append(q.fields, fields...)
+	return q
+}
+
+// Validate checks the clauses of the query before it runs.
+func (q *Query) Validate() error {
+	if q.collection == "" {
+		return errors.New("collection is empty")
+	}
+	for _, cond := range q.conditionals {
+		if err := cond.validate(); err != nil {
+			return err
+		}
+	}
+	for _, order := range q.orders {
+		if order.Field == "" {
+			return errors.New("field in order cannot be empty")
+		}
+		if order.Direction != Ascending && order.Direction != Descending {
+			return fmt.Errorf("invalid order direction: %s", order.Direction)
+		}
+	}
+	if q.limit < 0 {
+		return errors.New("limit cannot be negative")
+	}
+	if q.offset < 0 {
+		return errors.New("offset cannot be negative")
+	}
+	for _, field := range q.fields {
+		if field == "" {
+			return errors.New("selected field cannot be empty")
+		}
+	}
+	return nil
+}
+
+// direction returns the direction of the last order, which also orders the document IDs.
+func (q *Query) direction() Direction {
+	if len(q.orders) == 0 {
+		return Ascending
+	}
+	return q.orders[len(q.orders)-1].Direction
+}
+
+// selectedFields returns the fields to project, with the order fields the cursor is built from.
+func (q *Query) selectedFields() []string {
+	if len(q.fields) == 0 {
+		return nil
+	}
+
+	fields := append([]string(nil), q.fields...)
+	for _, order := range q.orders {
+		found := false
+		for _, field := range fields {
+			found = found || field == order.Field
+		}
+		if !found {
+			fields = append(fields, order.Field)
+		}
+	}
+	return fields
+}
+
+// QueryResult holds the documents found, each with its "id", and the cursor of the next page
+// when the query had a limit and documents were left.
+type QueryResult struct {
+	Documents  []map[string]interface{}
+	NextCursor string
+}
+
+// Decode unmarshals the documents into v, a pointer to a slice, through their JSON form.
+func (r *QueryResult) Decode(v interface{}) error {
+	b, err := json.Marshal(r.Documents)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// FindAs runs the query and decodes the documents found into values of type T.
+func FindAs[T any](ctx context.Context, db FirebaseDBInterface, query *Query) ([]T, error) {
+	result, err := db.Find(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []T
+	if err := result.Decode(&items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// queryCursor is the position after the last document of a page: the values of its order
+// fields and its ID.
+type queryCursor struct {
+	Values []cursorValue `json:"v"`
+	ID     string        `json:"id"`
+}
+
+// cursorValue tells a timestamp apart, which JSON would otherwise turn into a string.
+type cursorValue struct {
+	Value interface{} `json:"v"`
+	Time  bool        `json:"t,omitempty"`
+}
+
+func encodeCursor(values []interface{}, id string) (string, error) {
+	cursor := queryCursor{Values: make([]cursorValue, 0, len(values)), ID: id}
+	for _, value := range values {
+		if t, ok := value.(time.Time); ok {
+			cursor.Values = append(cursor.Values, cursorValue{Value: t.Format(time.RFC3339Nano), Time: true})
+			continue
+		}
+		cursor.Values = append(cursor.Values, cursorValue{Value: value})
+	}
+
+	b, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// decodeCursor reads a cursor of a query ordered by the given number of fields.
+func decodeCursor(encoded string, orders int) ([]interface{}, string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	var cursor queryCursor
+	if err := json.Unmarshal(b, &cursor); err != nil {
+		return nil, "", fmt.Errorf("invalid cursor: %w", err)
+	}
+	if cursor.ID == "" {
+		return nil, "", errors.New("invalid cursor: document id is empty")
+	}
+	if len(cursor.Values) != orders {
+		return nil, "", errors.New("invalid cursor: it belongs to a query with other orders")
+	}
+
+	values := make([]interface{}, 0, len(cursor.Values))
+	for _, value := range cursor.Values {
+		if !value.Time {
+			values = append(values, value.Value)
+			continue
+		}
+		s, _ := value.Value.(string)
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid cursor: %w", err)
+		}
+		values = append(values, t)
+	}
+	return values, cursor.ID, nil
+}
+
+// isList tells whether the value of an "in" or "array-contains-any" filter is a slice or array.
+func isList(value interface{}) (int, bool) {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return 0, false
+	}
+	return v.Len(), true
+}
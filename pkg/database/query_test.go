@@ -0,0 +1,91 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuery_Builder(t *testing.T) {
+	q := NewQuery("data/user-1/expenses").
+		WhereEqual(map[string]interface{}{"Status": "paid", "Category": "housing"}).
+		Where("DueDate", FilterGreaterOrEqual, "2025-03-01T00:00:00Z").
+		OrderBy("DueDate", Descending).
+		Limit(20).
+		Offset(5).
+		Select("Amount")
+
+	require.NoError(t, q.Validate())
+	assert.Equal(t, "data/user-1/expenses", q.Collection())
+	assert.Equal(t, []Conditional{
+		{Field: "Category", Value: "housing", Filter: FilterEquals},
+		{Field: "Status", Value: "paid", Filter: FilterEquals},
+		{Field: "DueDate", Value: "2025-03-01T00:00:00Z", Filter: FilterGreaterOrEqual},
+	}, q.conditionals)
+	assert.Equal(t, Descending, q.direction())
+	assert.Equal(t, []string{"Amount", "DueDate"}, q.selectedFields())
+}
+
+func TestQuery_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   *Query
+		wantErr string
+	}{
+		{name: "empty collection", query: NewQuery(""), wantErr: "collection is empty"},
+		{name: "invalid filter", query: NewQuery("c").Where("Category", "like", "x"), wantErr: "invalid filter type"},
+		{name: "array contains any without a list", query: NewQuery("c").Where("Tags", FilterArrayContainsAny, "x"), wantErr: "must be a list"},
+		{name: "invalid direction", query: NewQuery("c").OrderBy("DueDate", "up"), wantErr: "invalid order direction"},
+		{name: "negative limit", query: NewQuery("c").Limit(-1), wantErr: "limit cannot be negative"},
+		{name: "negative offset", query: NewQuery("c").Offset(-1), wantErr: "offset cannot be negative"},
+		{name: "empty selected field", query: NewQuery("c").Select(""), wantErr: "selected field cannot be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorContains(t, tt.query.Validate(), tt.wantErr)
+		})
+	}
+}
+
+func TestQueryResult_Decode(t *testing.T) {
+	type transfer struct {
+		ID     string  `json:"id"`
+		Amount float64 `json:"amount"`
+	}
+
+	result := &QueryResult{Documents: []map[string]interface{}{{"id": "t-1", "amount": 10.5}}}
+	var transfers []transfer
+	require.NoError(t, result.Decode(&transfers))
+	assert.Equal(t, []transfer{{ID: "t-1", Amount: 10.5}}, transfers)
+
+	var empty []transfer
+	require.NoError(t, (&QueryResult{}).Decode(&empty))
+	assert.Nil(t, empty)
+}
+
+func TestQueryCursor_RoundTrip(t *testing.T) {
+	dueDate := time.Date(2025, 3, 5, 10, 30, 0, 0, time.UTC)
+	values := []interface{}{"2025-03-05T00:00:00Z", dueDate, 120.5}
+
+	encoded, err := encodeCursor(values, "doc-1")
+	require.NoError(t, err)
+
+	decoded, id, err := decodeCursor(encoded, len(values))
+	require.NoError(t, err)
+	assert.Equal(t, values, decoded)
+	assert.Equal(t, "doc-1", id)
+
+	_, _, err = decodeCursor(encoded, 1)
+	assert.ErrorContains(t, err, "invalid cursor")
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	for _, cursor := range []string{"not base64!", "bm90IGpzb24", "eyJ2IjpbXX0"} {
+		_, _, err := decodeCursor(cursor, 0)
+		require.Error(t, err, cursor)
+		assert.Contains(t, err.Error(), "invalid cursor")
+	}
+}
@@ -1,5 +1,7 @@
 package database
 
+import "fmt"
+
 // FirebaseConfig holds the configuration for Firebase connection
 type FirebaseConfig struct {
 	ProjectID string `json:"project_id" yaml:"projectId"`
@@ -57,3 +59,49 @@ type DatabaseService interface {
 // General validation logic (if any) related to the interface can be added here.
 // For example, functions to validate config structs, though specific validation
 // might be better handled within the Connect methods of the implementations.
+
+const (
+	DriverFirestore = "firestore"
+	// DriverMemory keeps the documents in memory, for tests and local runs without credentials.
+	DriverMemory = "memory"
+	// DriverMongo stores the documents in MongoDB, for deployments without GCP.
+	DriverMongo = "mongo"
+	// DriverSQLite stores the documents in an embedded SQLite file, for single-user deployments.
+	DriverSQLite = "sqlite"
+)
+
+// DatabaseConfig selects the backend the repositories use.
+type DatabaseConfig struct {
+	// Driver is DriverFirestore, the default, DriverMemory, DriverMongo or DriverSQLite.
+	Driver string `json:"driver" yaml:"driver"`
+	// Mongo is the connection of DriverMongo.
+	Mongo MongoConfig `json:"mongo" yaml:"mongo"`
+	// SQLite is the database file of DriverSQLite.
+	SQLite SQLiteConfig `json:"sqlite" yaml:"sqlite"`
+}
+
+// Validate defaults the driver and checks it is known. The fields of each backend are checked
+// when it connects.
+func (c *DatabaseConfig) Validate() error {
+	if c.Driver == "" {
+		c.Driver = DriverFirestore
+	}
+
+	switch c.Driver {
+	case DriverFirestore, DriverMemory, DriverMongo:
+		return nil
+	case DriverSQLite:
+		if !sqliteAvailable {
+			return errSQLiteWithoutCgo
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown database driver %q", c.Driver)
+	}
+}
+
+// SQLiteConfig holds the configuration of the SQLite database.
+type SQLiteConfig struct {
+	// Path is the database file, created on first use.
+	Path string `json:"path" yaml:"path"`
+}
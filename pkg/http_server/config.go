@@ -61,7 +61,7 @@ func NewRestApi(fields RestAPIConfig) (*RestAPI, error) {
 
 func (api *RestAPIConfig) Validate() error {
 
-	if api.Port == "" {
+	if api.Port == nil || api.Port == "" {
 		api.Port = "8080"
 	}
 	if api.Host == "" {
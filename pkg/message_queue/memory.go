@@ -0,0 +1,374 @@
+package message_queue
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+)
+
+// MemoryQueue implementação da interface MessageQueue sem broker, para execução local e testes.
+// Segue a topologia da configuração como o RabbitMQ: as exchanges direct, topic e fanout
+// roteiam pelas route keys das queues, as mensagens ficam na queue até serem consumidas e
+// uma mensagem rejeitada pelo handler vai para a dead letter da queue.
+type MemoryQueue struct {
+	config    Config
+	exchanges map[string]*ExchangeConfig
+	queues    map[string]*QueueConfig
+
+	mu       sync.Mutex
+	routes   map[string]*memoryExchange
+	declared map[string]*memoryQueue
+	closed   chan struct{}
+}
+
+// QueueStats contadores de uma queue da MemoryQueue
+type QueueStats struct {
+	Ready   int // Mensagens aguardando um consumer
+	Unacked int // Mensagens entregues ainda em processamento
+	Acked   int
+	Nacked  int
+}
+
+type memoryMessage struct {
+	body     []byte
+	traceID  string
+	routeKey string
+}
+
+type memoryBinding struct {
+	queue    string
+	routeKey string
+}
+
+type memoryExchange struct {
+	kind     string
+	bindings []memoryBinding
+}
+
+type memoryQueue struct {
+	messages   []memoryMessage
+	ready      chan struct{}
+	deadLetter DeadLetterConfig
+	stats      QueueStats
+}
+
+// NewMemoryQueue cria uma nova instância da fila em memória
+func NewMemoryQueue(config Config) (MessageQueue, error) {
+	log.Println("Initializing in-memory message queue")
+	mq := &MemoryQueue{
+		config:    config,
+		exchanges: make(map[string]*ExchangeConfig),
+		queues:    make(map[string]*QueueConfig),
+		routes:    make(map[string]*memoryExchange),
+		declared:  make(map[string]*memoryQueue),
+		closed:    make(chan struct{}),
+	}
+
+	for i := range config.MessageQueues {
+		exchange := &config.MessageQueues[i]
+		mq.exchanges[exchange.Name] = exchange
+
+		for j := range exchange.Queues {
+			queue := &exchange.Queues[j]
+			mq.queues[queue.Name] = queue
+		}
+	}
+
+	return mq, nil
+}
+
+// Setup declara as exchanges, queues e dead letters. As mensagens das queues já declaradas
+// são mantidas.
+func (mq *MemoryQueue) Setup() error {
+	mq.mu.Lock()
+	defer mq.mu.Unlock()
+
+	routes := make(map[string]*memoryExchange)
+	for _, exchange := range mq.config.MessageQueues {
+		exchangeType := exchange.Type
+		if exchangeType == "" {
+			exchangeType = "direct"
+		}
+		if exchangeType != "direct" && exchangeType != "topic" && exchangeType != "fanout" {
+			return fmt.Errorf("failed to declare exchange %s: type %s is not supported in memory", exchange.Name, exchangeType)
+		}
+		if declared, exists := routes[exchange.Name]; exists {
+			declared.kind = exchangeType
+		} else {
+			routes[exchange.Name] = &memoryExchange{kind: exchangeType}
+		}
+
+		for _, queue := range exchange.Queues {
+			if queue.DeadLetter.Exchange != "" {
+				if _, exists := routes[queue.DeadLetter.Exchange]; !exists {
+					routes[queue.DeadLetter.Exchange] = &memoryExchange{kind: "direct"}
+				}
+				if queue.DeadLetter.Queue != "" {
+					mq.declare(queue.DeadLetter.Queue, DeadLetterConfig{})
+					dlx := routes[queue.DeadLetter.Exchange]
+					dlx.bindings = append(dlx.bindings, memoryBinding{queue: queue.DeadLetter.Queue, routeKey: queue.DeadLetter.RouteKey})
+				}
+			}
+		}
+
+		for _, queue := range exchange.Queues {
+			mq.declare(queue.Name, queue.DeadLetter)
+			for _, routeKey := range queue.getRouteKeys() {
+				routes[exchange.Name].bindings = append(routes[exchange.Name].bindings, memoryBinding{queue: queue.Name, routeKey: routeKey})
+			}
+		}
+	}
+
+	mq.routes = routes
+	return nil
+}
+
+// declare cria a queue se ela ainda não existe. Deve ser chamado com mq.mu travado.
+func (mq *MemoryQueue) declare(name string, deadLetter DeadLetterConfig) {
+	queue, exists := mq.declared[name]
+	if !exists {
+		queue = &memoryQueue{ready: make(chan struct{}, 1)}
+		mq.declared[name] = queue
+	}
+	queue.deadLetter = deadLetter
+}
+
+// Publisher publica uma mensagem na queue especificada usando a primeira route key dela
+func (mq *MemoryQueue) Publisher(exchangeName, queueName string, message []byte, traceID string) error {
+	queueConfig, exists := mq.queues[queueName]
+	if !exists {
+		return fmt.Errorf("queue %s not found in configuration", queueName)
+	}
+
+	publisherConfig := queueConfig.Publisher
+	if !publisherConfig.Mandatory && !publisherConfig.Immediate {
+		publisherConfig = mq.config.DefaultPublisher
+	}
+
+	return mq.publish(exchangeName, queueConfig.getRouteKeys()[0], message, traceID, publisherConfig)
+}
+
+// PublisherWithRouteKey publica mensagem usando route key específica
+func (mq *MemoryQueue) PublisherWithRouteKey(exchangeName, routeKey string, message []byte, traceID string) error {
+	return mq.publish(exchangeName, routeKey, message, traceID, mq.config.DefaultPublisher)
+}
+
+// publish entrega a mensagem às queues ligadas à exchange pela route key. Sem nenhuma queue
+// a mensagem é descartada, ou recusada quando o publisher é mandatory.
+func (mq *MemoryQueue) publish(exchangeName, routeKey string, message []byte, traceID string, publisherConfig PublisherConfig) error {
+	if mq.isClosed() {
+		return fmt.Errorf("not connected to the in-memory message queue")
+	}
+	if _, exists := mq.exchanges[exchangeName]; !exists {
+		return fmt.Errorf("exchange %s not found in configuration", exchangeName)
+	}
+
+	mq.mu.Lock()
+	defer mq.mu.Unlock()
+
+	body := make([]byte, len(message))
+	copy(body, message)
+
+	routed := mq.route(exchangeName, memoryMessage{body: body, traceID: traceID, routeKey: routeKey})
+	if !routed && publisherConfig.Mandatory {
+		return fmt.Errorf("message to exchange %s with route key %s was not routed to any queue", exchangeName, routeKey)
+	}
+	return nil
+}
+
+// route coloca a mensagem em cada queue que a recebe, uma vez por queue. Deve ser chamado
+// com mq.mu travado.
+func (mq *MemoryQueue) route(exchangeName string, msg memoryMessage) bool {
+	exchange, exists := mq.routes[exchangeName]
+	if !exists {
+		return false
+	}
+
+	delivered := make(map[string]bool)
+	for _, binding := range exchange.bindings {
+		if delivered[binding.queue] || !exchange.matches(binding.routeKey, msg.routeKey) {
+			continue
+		}
+		delivered[binding.queue] = true
+		mq.declared[binding.queue].push(msg)
+	}
+	return len(delivered) > 0
+}
+
+func (e *memoryExchange) matches(bindingKey, routeKey string) bool {
+	switch e.kind {
+	case "fanout":
+		return true
+	case "topic":
+		return topicMatch(strings.Split(bindingKey, "."), strings.Split(routeKey, "."))
+	default:
+		return bindingKey == routeKey
+	}
+}
+
+// topicMatch compara as palavras da route key com o padrão do binding, em que * substitui
+// exatamente uma palavra e # zero ou mais.
+func topicMatch(pattern, words []string) bool {
+	if len(pattern) == 0 {
+		return len(words) == 0
+	}
+
+	switch pattern[0] {
+	case "#":
+		for i := 0; i <= len(words); i++ {
+			if topicMatch(pattern[1:], words[i:]) {
+				return true
+			}
+		}
+		return false
+	case "*":
+		return len(words) > 0 && topicMatch(pattern[1:], words[1:])
+	default:
+		return len(words) > 0 && pattern[0] == words[0] && topicMatch(pattern[1:], words[1:])
+	}
+}
+
+func (q *memoryQueue) push(msg memoryMessage) {
+	q.messages = append(q.messages, msg)
+	q.stats.Ready++
+	q.signal()
+}
+
+// signal acorda um consumer da queue, sem bloquear quando um já foi avisado
+func (q *memoryQueue) signal() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// Consumer consome mensagens da queue especificada até o cancelamento do context ou o
+// fechamento da fila. A mensagem é confirmada quando o handler não retorna erro; caso
+// contrário é rejeitada e enviada para a dead letter, se configurada.
+func (mq *MemoryQueue) Consumer(ctx context.Context, exchangeName, queueName string, handler func([]byte, string) error) error {
+	queueConfig, exists := mq.queues[queueName]
+	if !exists {
+		return fmt.Errorf("queue %s not found in configuration", queueName)
+	}
+
+	mq.mu.Lock()
+	queue, declared := mq.declared[queueName]
+	mq.mu.Unlock()
+	if !declared {
+		return fmt.Errorf("queue %s not declared, call Setup first", queueName)
+	}
+
+	autoAck := queueConfig.Consumer.AutoAck
+	if queueConfig.Consumer.Tag == "" && !autoAck && !queueConfig.Consumer.Exclusive &&
+		!queueConfig.Consumer.NoLocal && !queueConfig.Consumer.NoWait && queueConfig.Consumer.Args == nil {
+		autoAck = mq.config.DefaultConsumer.AutoAck
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		msg, ok := mq.next(queue, autoAck)
+		if !ok {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-mq.closed:
+				return nil
+			case <-queue.ready:
+			}
+			continue
+		}
+
+		err := handler(msg.body, msg.traceID)
+		if err != nil {
+			log.Printf("Message processing failed: %v", err)
+		}
+		if !autoAck {
+			mq.settle(queue, msg, err == nil)
+		}
+	}
+}
+
+// next retira a próxima mensagem da queue
+func (mq *MemoryQueue) next(queue *memoryQueue, autoAck bool) (memoryMessage, bool) {
+	mq.mu.Lock()
+	defer mq.mu.Unlock()
+
+	if len(queue.messages) == 0 {
+		return memoryMessage{}, false
+	}
+
+	msg := queue.messages[0]
+	queue.messages[0] = memoryMessage{}
+	queue.messages = queue.messages[1:]
+	queue.stats.Ready--
+	if autoAck {
+		queue.stats.Acked++
+	} else {
+		queue.stats.Unacked++
+	}
+
+	// Outro consumer pode seguir com as mensagens restantes
+	if len(queue.messages) > 0 {
+		queue.signal()
+	}
+	return msg, true
+}
+
+// settle confirma (ack) ou rejeita (nack sem requeue) uma mensagem entregue. A mensagem
+// rejeitada é publicada na exchange de dead letter com a route key dela, ou com a route key
+// original quando a dead letter não define uma.
+func (mq *MemoryQueue) settle(queue *memoryQueue, msg memoryMessage, ack bool) {
+	mq.mu.Lock()
+	defer mq.mu.Unlock()
+
+	queue.stats.Unacked--
+	if ack {
+		queue.stats.Acked++
+		return
+	}
+
+	queue.stats.Nacked++
+	if queue.deadLetter.Exchange == "" {
+		return
+	}
+	if queue.deadLetter.RouteKey != "" {
+		msg.routeKey = queue.deadLetter.RouteKey
+	}
+	mq.route(queue.deadLetter.Exchange, msg)
+}
+
+// Stats retorna os contadores da queue, inclusive das dead letter queues
+func (mq *MemoryQueue) Stats(queueName string) QueueStats {
+	mq.mu.Lock()
+	defer mq.mu.Unlock()
+
+	if queue, exists := mq.declared[queueName]; exists {
+		return queue.stats
+	}
+	return QueueStats{}
+}
+
+// Close encerra os consumers. As mensagens publicadas depois são recusadas.
+func (mq *MemoryQueue) Close() error {
+	mq.mu.Lock()
+	defer mq.mu.Unlock()
+
+	if !mq.isClosed() {
+		close(mq.closed)
+	}
+	return nil
+}
+
+func (mq *MemoryQueue) isClosed() bool {
+	select {
+	case <-mq.closed:
+		return true
+	default:
+		return false
+	}
+}
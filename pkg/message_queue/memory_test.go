@@ -0,0 +1,154 @@
+package message_queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMemoryQueue(t *testing.T) *MemoryQueue {
+	t.Helper()
+	mq, err := InitializeMessageQueue(Config{
+		Driver: DriverMemory,
+		MessageQueues: []ExchangeConfig{
+			{
+				Name: "dashfin_finance",
+				Type: "topic",
+				Queues: []QueueConfig{
+					{
+						Name:      "expense_record",
+						RouteKeys: []string{"expense.record.*", "expense.nfce.#"},
+						DeadLetter: DeadLetterConfig{
+							Exchange: "dashfin_finance_dlx",
+							Queue:    "expense_record_dlq",
+							RouteKey: "expense.failed",
+						},
+					},
+					{Name: "income_record", RouteKey: "income.record.*"},
+					{Name: "audit", RouteKey: "#"},
+				},
+			},
+			{
+				Name:   "notifications",
+				Type:   "fanout",
+				Queues: []QueueConfig{{Name: "email"}, {Name: "push"}},
+			},
+			{
+				Name:   "reports",
+				Queues: []QueueConfig{{Name: "monthly_report", RouteKey: "report.monthly"}},
+			},
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { mq.Close() })
+	return mq.(*MemoryQueue)
+}
+
+func TestTopicMatch(t *testing.T) {
+	tests := []struct {
+		pattern, key string
+		want         bool
+	}{
+		{"expense.record.*", "expense.record.create", true},
+		{"expense.record.*", "expense.record", false},
+		{"expense.record.*", "expense.record.create.batch", false},
+		{"expense.#", "expense", true},
+		{"expense.#", "expense.nfce.import", true},
+		{"#.import", "expense.nfce.import", true},
+		{"*.record.#", "income.record.update", true},
+		{"#", "", true},
+		{"income.record.*", "expense.record.create", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, (&memoryExchange{kind: "topic"}).matches(tt.pattern, tt.key), "%s ~ %s", tt.pattern, tt.key)
+	}
+}
+
+func TestMemoryQueue_Routing(t *testing.T) {
+	mq := newTestMemoryQueue(t)
+
+	require.NoError(t, mq.PublisherWithRouteKey("dashfin_finance", "expense.record.create", []byte(`{}`), ""))
+	require.NoError(t, mq.PublisherWithRouteKey("dashfin_finance", "expense.nfce.import", []byte(`{}`), ""))
+	require.NoError(t, mq.PublisherWithRouteKey("dashfin_finance", "income.record.delete", []byte(`{}`), ""))
+	require.NoError(t, mq.PublisherWithRouteKey("notifications", "anything", []byte(`{}`), ""))
+	require.NoError(t, mq.Publisher("reports", "monthly_report", []byte(`{}`), ""))
+	require.NoError(t, mq.PublisherWithRouteKey("reports", "report.yearly", []byte(`{}`), ""), "unrouted messages are dropped")
+
+	for queue, ready := range map[string]int{
+		"expense_record": 2,
+		"income_record":  1,
+		"audit":          3,
+		"email":          1,
+		"push":           1,
+		"monthly_report": 1,
+	} {
+		assert.Equal(t, ready, mq.Stats(queue).Ready, queue)
+	}
+
+	assert.EqualError(t, mq.PublisherWithRouteKey("unknown", "key", nil, ""), "exchange unknown not found in configuration")
+	assert.EqualError(t, mq.Publisher("reports", "unknown", nil, ""), "queue unknown not found in configuration")
+
+	mq.config.DefaultPublisher.Mandatory = true
+	assert.EqualError(t, mq.PublisherWithRouteKey("reports", "report.yearly", nil, ""), "message to exchange reports with route key report.yearly was not routed to any queue")
+}
+
+func TestMemoryQueue_Consumer(t *testing.T) {
+	mq := newTestMemoryQueue(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	var received []string
+	consumerDone := make(chan error, 1)
+	go func() {
+		consumerDone <- mq.Consumer(ctx, "dashfin_finance", "expense_record", func(body []byte, traceID string) error {
+			mu.Lock()
+			received = append(received, traceID)
+			mu.Unlock()
+			if string(body) == "fail" {
+				return errors.New("failed")
+			}
+			return nil
+		})
+	}()
+
+	require.NoError(t, mq.PublisherWithRouteKey("dashfin_finance", "expense.record.create", []byte("ok"), "trace-1"))
+	require.NoError(t, mq.PublisherWithRouteKey("dashfin_finance", "expense.record.update", []byte("fail"), "trace-2"))
+	require.NoError(t, mq.Publisher("dashfin_finance", "expense_record", []byte("ok"), "trace-3"))
+
+	require.Eventually(t, func() bool {
+		return mq.Stats("expense_record").Acked+mq.Stats("expense_record").Nacked == 3
+	}, time.Second, time.Millisecond)
+
+	mu.Lock()
+	assert.Equal(t, []string{"trace-1", "trace-2", "trace-3"}, received)
+	mu.Unlock()
+	assert.Equal(t, QueueStats{Acked: 2, Nacked: 1}, mq.Stats("expense_record"))
+	assert.Equal(t, QueueStats{Ready: 1}, mq.Stats("expense_record_dlq"), "the rejected message is dead lettered")
+
+	cancel()
+	assert.ErrorIs(t, <-consumerDone, context.Canceled)
+}
+
+func TestMemoryQueue_Close(t *testing.T) {
+	mq := newTestMemoryQueue(t)
+
+	consumerDone := make(chan error, 1)
+	go func() {
+		consumerDone <- mq.Consumer(context.Background(), "dashfin_finance", "income_record", func([]byte, string) error { return nil })
+	}()
+
+	require.NoError(t, mq.Close())
+	assert.NoError(t, <-consumerDone)
+	assert.Error(t, mq.PublisherWithRouteKey("dashfin_finance", "income.record.create", nil, ""))
+
+	_, err := NewMemoryQueue(Config{MessageQueues: []ExchangeConfig{{Name: "legacy", Type: "headers"}}})
+	require.NoError(t, err)
+	_, err = InitializeMessageQueue(Config{Driver: DriverMemory, MessageQueues: []ExchangeConfig{{Name: "legacy", Type: "headers"}}})
+	assert.EqualError(t, err, "failed to declare exchange legacy: type headers is not supported in memory")
+}
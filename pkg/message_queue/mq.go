@@ -2,8 +2,11 @@ package message_queue
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/streadway/amqp"
@@ -55,12 +58,21 @@ type ExchangeConfig struct {
 	Queues  []QueueConfig `yaml:"queues" json:"queues"`
 }
 
+const (
+	// DriverRabbitMQ usa o broker do RabbitMQ. É o padrão.
+	DriverRabbitMQ = "rabbitmq"
+	// DriverMemory mantém as mensagens no processo, para testes e execução local sem broker.
+	DriverMemory = "memory"
+)
+
 // Config estrutura principal de configuração para RabbitMQ
 type Config struct {
+	Driver           string           `yaml:"driver" json:"driver"` // DriverRabbitMQ (padrão) ou DriverMemory
 	URL              string           `yaml:"url" json:"url"`
 	MessageQueues    []ExchangeConfig `yaml:"message_queues" json:"message_queues"`
 	DefaultConsumer  ConsumerConfig   `yaml:"default_consumer" json:"default_consumer"`
 	DefaultPublisher PublisherConfig  `yaml:"default_publisher" json:"default_publisher"`
+	PublishTimeout   int              `yaml:"publish_timeout" json:"publish_timeout"` // Segundos de espera pela confirmação do broker (padrão 5)
 }
 
 // MessageQueue interface principal da biblioteca
@@ -72,15 +84,94 @@ type MessageQueue interface {
 	Setup() error
 }
 
-// RabbitMQ implementação da interface MessageQueue
+// Validate define o driver padrão e verifica os campos que ele exige
+func (c *Config) Validate() error {
+	if c.Driver == "" {
+		c.Driver = DriverRabbitMQ
+	}
+	if c.PublishTimeout < 0 {
+		return fmt.Errorf("publish_timeout must not be negative")
+	}
+
+	switch c.Driver {
+	case DriverRabbitMQ:
+		if c.URL == "" {
+			return fmt.Errorf("url is required for rabbitmq")
+		}
+	case DriverMemory:
+	default:
+		return fmt.Errorf("unknown message queue driver %q", c.Driver)
+	}
+	return nil
+}
+
+// InitializeMessageQueue cria a fila selecionada pela configuração e declara a topologia
+func InitializeMessageQueue(config Config) (MessageQueue, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	var mq MessageQueue
+	var err error
+	if config.Driver == DriverMemory {
+		mq, err = NewMemoryQueue(config)
+	} else {
+		mq, err = NewRabbitMQ(config)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mq.Setup(); err != nil {
+		mq.Close()
+		return nil, err
+	}
+	return mq, nil
+}
+
+// Intervalos da reconexão e espera padrão da confirmação do broker
+const (
+	reconnectInitialDelay = time.Second
+	reconnectMaxDelay     = 30 * time.Second
+	publishConfirmTimeout = 5 * time.Second
+)
+
+var errClosed = errors.New("RabbitMQ connection closed")
+
+// RabbitMQ implementação da interface MessageQueue. A conexão é refeita com backoff quando
+// cai: a topologia do Setup é declarada de novo e os consumers ativos voltam a consumir.
 type RabbitMQ struct {
-	config      Config
-	conn        *amqp.Connection
-	channel     *amqp.Channel
-	closeChan   chan *amqp.Error
+	config    Config
+	exchanges map[string]*ExchangeConfig
+	queues    map[string]*QueueConfig
+
+	mu          sync.RWMutex
+	current     *rabbitChannel
 	isConnected bool
-	exchanges   map[string]*ExchangeConfig
-	queues      map[string]*QueueConfig
+	isSetup     bool
+	connected   chan struct{} // Fechado quando uma nova conexão fica disponível
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// rabbitChannel conexão e canal em uso, com as notificações do modo confirm
+type rabbitChannel struct {
+	conn      *amqp.Connection
+	channel   *amqp.Channel
+	confirms  chan amqp.Confirmation
+	returns   chan amqp.Return
+	publishMu sync.Mutex
+	published uint64 // Delivery tag da última mensagem publicada, protegido por publishMu
+
+	waitersMu sync.Mutex
+	waiters   map[uint64]chan publishResult // Publishers aguardando a confirmação, por delivery tag
+}
+
+// publishResult resultado de uma publicação entregue pelo dispatchConfirms
+type publishResult struct {
+	ack      bool
+	returned *amqp.Return
 }
 
 // NewRabbitMQ cria uma nova instância do RabbitMQ
@@ -88,9 +179,10 @@ func NewRabbitMQ(config Config) (MessageQueue, error) {
 	log.Println("Initializing RabbitMQ")
 	mq := &RabbitMQ{
 		config:    config,
-		closeChan: make(chan *amqp.Error),
 		exchanges: make(map[string]*ExchangeConfig),
 		queues:    make(map[string]*QueueConfig),
+		connected: make(chan struct{}),
+		done:      make(chan struct{}),
 	}
 
 	// Mapeia exchanges e queues para acesso rápido
@@ -112,7 +204,8 @@ func NewRabbitMQ(config Config) (MessageQueue, error) {
 	return mq, nil
 }
 
-// connect estabelece conexão com RabbitMQ
+// connect estabelece conexão com RabbitMQ, coloca o canal em modo confirm e, depois do
+// Setup, declara a topologia antes de liberar a conexão para publishers e consumers
 func (mq *RabbitMQ) connect() error {
 	conn, err := amqp.Dial(mq.config.URL)
 	if err != nil {
@@ -125,16 +218,132 @@ func (mq *RabbitMQ) connect() error {
 		return err
 	}
 
-	mq.conn = conn
-	mq.channel = channel
+	if err := channel.Confirm(false); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+
+	current := &rabbitChannel{
+		conn:    conn,
+		channel: channel,
+		// Uma confirmação ou devolução não lida bloqueia a conexão; o dispatchConfirms
+		// consome as duas, inclusive as das mensagens cuja confirmação expirou
+		confirms: channel.NotifyPublish(make(chan amqp.Confirmation, 64)),
+		returns:  channel.NotifyReturn(make(chan amqp.Return, 64)),
+		waiters:  make(map[uint64]chan publishResult),
+	}
+	go current.dispatchConfirms()
+
+	mq.mu.Lock()
+	defer mq.mu.Unlock()
+
+	// O Close pode ter ocorrido durante a reconexão
+	select {
+	case <-mq.done:
+		conn.Close()
+		return errClosed
+	default:
+	}
+
+	if mq.isSetup {
+		if err := mq.declareTopology(channel); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+
+	mq.current = current
 	mq.isConnected = true
+	close(mq.connected)
 
-	// Monitora a conexão
-	mq.conn.NotifyClose(mq.closeChan)
+	// Monitora a conexão e o canal
+	go mq.watch(current, conn.NotifyClose(make(chan *amqp.Error, 1)), channel.NotifyClose(make(chan *amqp.Error, 1)))
 
 	return nil
 }
 
+// watch aguarda a queda da conexão ou do canal e reconecta até conseguir ou até o Close
+func (mq *RabbitMQ) watch(current *rabbitChannel, connClose, channelClose chan *amqp.Error) {
+	var reason *amqp.Error
+	select {
+	case <-mq.done:
+		return
+	case reason = <-connClose:
+	case reason = <-channelClose:
+	}
+
+	select {
+	case <-mq.done:
+		return
+	default:
+	}
+
+	log.Printf("RabbitMQ connection lost: %v", reason)
+
+	mq.mu.Lock()
+	mq.isConnected = false
+	mq.current = nil
+	mq.connected = make(chan struct{})
+	mq.mu.Unlock()
+
+	// O canal pode ter caído com a conexão ainda aberta
+	current.conn.Close()
+
+	for attempt := 0; ; attempt++ {
+		select {
+		case <-mq.done:
+			return
+		case <-time.After(reconnectDelay(attempt)):
+		}
+
+		if err := mq.connect(); err != nil {
+			if errors.Is(err, errClosed) {
+				return
+			}
+			log.Printf("RabbitMQ reconnection attempt %d failed: %v", attempt+1, err)
+			continue
+		}
+
+		log.Println("RabbitMQ reconnected")
+		return
+	}
+}
+
+// reconnectDelay retorna a espera antes da tentativa de reconexão, dobrando a cada
+// tentativa até reconnectMaxDelay
+func reconnectDelay(attempt int) time.Duration {
+	delay := reconnectInitialDelay
+	for i := 0; i < attempt && delay < reconnectMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > reconnectMaxDelay {
+		return reconnectMaxDelay
+	}
+	return delay
+}
+
+// waitChannel retorna o canal da conexão atual, diferente de stale, aguardando a reconexão
+// quando necessário. Retorna nil quando o context termina ou a fila é fechada.
+func (mq *RabbitMQ) waitChannel(ctx context.Context, stale *amqp.Channel) *amqp.Channel {
+	for {
+		mq.mu.RLock()
+		current, connected := mq.current, mq.connected
+		mq.mu.RUnlock()
+
+		if current != nil && current.channel != stale {
+			return current.channel
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-mq.done:
+			return nil
+		case <-connected:
+		}
+	}
+}
+
 // getRouteKeys retorna as route keys de uma queue (suporta tanto RouteKey quanto RouteKeys)
 func (q *QueueConfig) getRouteKeys() []string {
 	if len(q.RouteKeys) > 0 {
@@ -146,12 +355,26 @@ func (q *QueueConfig) getRouteKeys() []string {
 	return []string{""} // Para fanout exchanges
 }
 
-// Setup configura todas as exchanges, queues e dead letters
+// Setup configura todas as exchanges, queues e dead letters. A configuração é refeita a
+// cada reconexão.
 func (mq *RabbitMQ) Setup() error {
+	mq.mu.Lock()
+	defer mq.mu.Unlock()
+
 	if !mq.isConnected {
 		return fmt.Errorf("not connected to RabbitMQ")
 	}
 
+	if err := mq.declareTopology(mq.current.channel); err != nil {
+		return err
+	}
+
+	mq.isSetup = true
+	return nil
+}
+
+// declareTopology declara as exchanges, queues e dead letters da configuração no canal
+func (mq *RabbitMQ) declareTopology(channel *amqp.Channel) error {
 	// Declara todas as exchanges
 	for _, exchange := range mq.config.MessageQueues {
 		exchangeType := exchange.Type
@@ -159,7 +382,7 @@ func (mq *RabbitMQ) Setup() error {
 			exchangeType = "direct"
 		}
 
-		err := mq.channel.ExchangeDeclare(
+		err := channel.ExchangeDeclare(
 			exchange.Name,
 			exchangeType,
 			exchange.Durable,
@@ -175,7 +398,7 @@ func (mq *RabbitMQ) Setup() error {
 		// Declara dead letter exchanges para cada queue
 		for _, queue := range exchange.Queues {
 			if queue.DeadLetter.Exchange != "" {
-				err = mq.channel.ExchangeDeclare(
+				err = channel.ExchangeDeclare(
 					queue.DeadLetter.Exchange,
 					"direct",
 					true,
@@ -190,7 +413,7 @@ func (mq *RabbitMQ) Setup() error {
 
 				// Declara dead letter queue
 				if queue.DeadLetter.Queue != "" {
-					_, err = mq.channel.QueueDeclare(
+					_, err = channel.QueueDeclare(
 						queue.DeadLetter.Queue,
 						true,
 						false,
@@ -203,7 +426,7 @@ func (mq *RabbitMQ) Setup() error {
 					}
 
 					// Bind dead letter queue
-					err = mq.channel.QueueBind(
+					err = channel.QueueBind(
 						queue.DeadLetter.Queue,
 						queue.DeadLetter.RouteKey,
 						queue.DeadLetter.Exchange,
@@ -235,7 +458,7 @@ func (mq *RabbitMQ) Setup() error {
 			}
 
 			// Declara queue
-			_, err = mq.channel.QueueDeclare(
+			_, err = channel.QueueDeclare(
 				queue.Name,
 				queue.Durable,
 				queue.AutoDelete,
@@ -250,7 +473,7 @@ func (mq *RabbitMQ) Setup() error {
 			// Bind queue ao exchange com múltiplas route keys
 			routeKeys := queue.getRouteKeys()
 			for _, routeKey := range routeKeys {
-				err = mq.channel.QueueBind(
+				err = channel.QueueBind(
 					queue.Name,
 					routeKey,
 					exchange.Name,
@@ -270,10 +493,6 @@ func (mq *RabbitMQ) Setup() error {
 
 // Publisher publica uma mensagem na queue especificada
 func (mq *RabbitMQ) Publisher(exchangeName, queueName string, message []byte, traceID string) error {
-	if !mq.isConnected {
-		return fmt.Errorf("not connected to RabbitMQ")
-	}
-
 	// Busca configuração da queue
 	queueConfig, exists := mq.queues[queueName]
 	if !exists {
@@ -296,33 +515,11 @@ func (mq *RabbitMQ) Publisher(exchangeName, queueName string, message []byte, tr
 	routeKeys := queueConfig.getRouteKeys()
 	routeKey := routeKeys[0] // Usa a primeira route key por padrão
 
-	// Cria headers incluindo X-TRACE-ID
-	headers := amqp.Table{}
-	if traceID != "" {
-		headers["X-TRACE-ID"] = traceID
-	}
-
-	return mq.channel.Publish(
-		exchangeConfig.Name,
-		routeKey,
-		publisherConfig.Mandatory,
-		publisherConfig.Immediate,
-		amqp.Publishing{
-			ContentType:  "application/json",
-			Body:         message,
-			DeliveryMode: amqp.Persistent,
-			Timestamp:    time.Now(),
-			Headers:      headers,
-		},
-	)
+	return mq.publish(exchangeConfig.Name, routeKey, publisherConfig, message, traceID)
 }
 
 // PublisherWithRouteKey publica mensagem usando route key específica
 func (mq *RabbitMQ) PublisherWithRouteKey(exchangeName, routeKey string, message []byte, traceID string) error {
-	if !mq.isConnected {
-		return fmt.Errorf("not connected to RabbitMQ")
-	}
-
 	// Busca configuração da exchange
 	exchangeConfig, exists := mq.exchanges[exchangeName]
 	if !exists {
@@ -330,7 +527,20 @@ func (mq *RabbitMQ) PublisherWithRouteKey(exchangeName, routeKey string, message
 	}
 
 	// Usa configuração padrão do publisher
-	publisherConfig := mq.config.DefaultPublisher
+	return mq.publish(exchangeConfig.Name, routeKey, mq.config.DefaultPublisher, message, traceID)
+}
+
+// publish publica a mensagem e aguarda a confirmação do broker. Retorna erro quando o broker
+// rejeita a mensagem, não confirma a tempo ou a devolve por não ter queue de destino
+// (publisher mandatory). O publishMu protege só a publicação e o delivery tag, então
+// publishers concorrentes aguardam as confirmações em paralelo.
+func (mq *RabbitMQ) publish(exchangeName, routeKey string, publisherConfig PublisherConfig, message []byte, traceID string) error {
+	mq.mu.RLock()
+	current := mq.current
+	mq.mu.RUnlock()
+	if current == nil {
+		return fmt.Errorf("not connected to RabbitMQ")
+	}
 
 	// Cria headers incluindo X-TRACE-ID
 	headers := amqp.Table{}
@@ -338,27 +548,145 @@ func (mq *RabbitMQ) PublisherWithRouteKey(exchangeName, routeKey string, message
 		headers["X-TRACE-ID"] = traceID
 	}
 
-	return mq.channel.Publish(
-		exchangeConfig.Name,
-		routeKey,
-		publisherConfig.Mandatory,
-		publisherConfig.Immediate,
-		amqp.Publishing{
-			ContentType:  "application/json",
-			Body:         message,
-			DeliveryMode: amqp.Persistent,
-			Timestamp:    time.Now(),
-			Headers:      headers,
-		},
-	)
+	waiter, deliveryTag, err := current.publish(exchangeName, routeKey, publisherConfig, amqp.Publishing{
+		ContentType:  "application/json",
+		Body:         message,
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    time.Now(),
+		Headers:      headers,
+	})
+	if err != nil {
+		return err
+	}
+
+	timeout := time.NewTimer(mq.publishTimeout())
+	defer timeout.Stop()
+
+	select {
+	case result, ok := <-waiter:
+		if !ok {
+			return fmt.Errorf("connection closed before RabbitMQ confirmed the message to exchange %s with route key %s", exchangeName, routeKey)
+		}
+		if !result.ack {
+			return fmt.Errorf("RabbitMQ rejected the message to exchange %s with route key %s", exchangeName, routeKey)
+		}
+		if result.returned != nil {
+			return fmt.Errorf("message to exchange %s with route key %s was returned: %s", exchangeName, routeKey, result.returned.ReplyText)
+		}
+		return nil
+	case <-timeout.C:
+		// A confirmação que chegar depois é descartada pelo dispatchConfirms
+		current.removeWaiter(deliveryTag)
+		return fmt.Errorf("timed out waiting for RabbitMQ to confirm the message to exchange %s with route key %s", exchangeName, routeKey)
+	}
 }
 
-// Consumer consome mensagens da queue especificada
-func (mq *RabbitMQ) Consumer(ctx context.Context, exchangeName, queueName string, handler func([]byte, string) error) error {
-	if !mq.isConnected {
-		return fmt.Errorf("not connected to RabbitMQ")
+// publish publica a mensagem no canal e registra quem aguarda a confirmação. O MessageId
+// leva o delivery tag para associar uma devolução à mensagem publicada.
+func (c *rabbitChannel) publish(exchangeName, routeKey string, publisherConfig PublisherConfig, msg amqp.Publishing) (chan publishResult, uint64, error) {
+	c.publishMu.Lock()
+	defer c.publishMu.Unlock()
+
+	deliveryTag := c.published + 1
+	msg.MessageId = strconv.FormatUint(deliveryTag, 10)
+
+	// Registrado antes da publicação: a confirmação pode chegar antes do Publish retornar
+	waiter := make(chan publishResult, 1)
+	c.waitersMu.Lock()
+	c.waiters[deliveryTag] = waiter
+	c.waitersMu.Unlock()
+
+	err := c.channel.Publish(exchangeName, routeKey, publisherConfig.Mandatory, publisherConfig.Immediate, msg)
+	if err != nil {
+		c.removeWaiter(deliveryTag)
+		return nil, 0, err
+	}
+	c.published = deliveryTag
+
+	return waiter, deliveryTag, nil
+}
+
+// removeWaiter descarta quem aguardava a confirmação do delivery tag
+func (c *rabbitChannel) removeWaiter(deliveryTag uint64) {
+	c.waitersMu.Lock()
+	delete(c.waiters, deliveryTag)
+	c.waitersMu.Unlock()
+}
+
+// dispatchConfirms entrega cada confirmação do canal a quem aguarda o delivery tag,
+// junto com a devolução da mensagem, se houver. Confirmações sem ninguém aguardando são
+// descartadas. Quando o canal fecha, os publishers que ainda aguardam são liberados.
+func (c *rabbitChannel) dispatchConfirms() {
+	returns := c.returns
+	returned := make(map[uint64]amqp.Return)
+	keepReturn := func(r amqp.Return) {
+		if tag, err := strconv.ParseUint(r.MessageId, 10, 64); err == nil {
+			returned[tag] = r
+		}
 	}
 
+	for {
+		select {
+		case r, ok := <-returns:
+			if !ok {
+				returns = nil
+				continue
+			}
+			keepReturn(r)
+		case confirm, ok := <-c.confirms:
+			if !ok {
+				c.waitersMu.Lock()
+				for tag, waiter := range c.waiters {
+					close(waiter)
+					delete(c.waiters, tag)
+				}
+				c.waitersMu.Unlock()
+				return
+			}
+
+			// O broker envia a devolução antes da confirmação
+			for drained := returns == nil; !drained; {
+				select {
+				case r, ok := <-returns:
+					if !ok {
+						returns = nil
+						drained = true
+						continue
+					}
+					keepReturn(r)
+				default:
+					drained = true
+				}
+			}
+
+			result := publishResult{ack: confirm.Ack}
+			if r, ok := returned[confirm.DeliveryTag]; ok {
+				result.returned = &r
+				delete(returned, confirm.DeliveryTag)
+			}
+
+			c.waitersMu.Lock()
+			waiter, ok := c.waiters[confirm.DeliveryTag]
+			delete(c.waiters, confirm.DeliveryTag)
+			c.waitersMu.Unlock()
+			if ok {
+				waiter <- result
+			}
+		}
+	}
+}
+
+// publishTimeout retorna a espera pela confirmação do broker configurada em PublishTimeout
+func (mq *RabbitMQ) publishTimeout() time.Duration {
+	if mq.config.PublishTimeout > 0 {
+		return time.Duration(mq.config.PublishTimeout) * time.Second
+	}
+	return publishConfirmTimeout
+}
+
+// Consumer consome mensagens da queue especificada até o cancelamento do context ou o Close.
+// Com a conexão caída, inclusive ao ser chamado, o consumer aguarda a reconexão para consumir.
+func (mq *RabbitMQ) Consumer(ctx context.Context, exchangeName, queueName string, handler func([]byte, string) error) error {
 	// Busca configuração da queue
 	queueConfig, exists := mq.queues[queueName]
 	if !exists {
@@ -372,31 +700,47 @@ func (mq *RabbitMQ) Consumer(ctx context.Context, exchangeName, queueName string
 		consumerConfig = mq.config.DefaultConsumer
 	}
 
-	msgs, err := mq.channel.Consume(
-		queueConfig.Name,
-		consumerConfig.Tag,
-		consumerConfig.AutoAck,
-		consumerConfig.Exclusive,
-		consumerConfig.NoLocal,
-		consumerConfig.NoWait,
-		consumerConfig.Args,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to register consumer: %w", err)
+	var channel *amqp.Channel
+	for {
+		channel = mq.waitChannel(ctx, channel)
+		if channel == nil {
+			// Encerrado pelo context ou pelo Close
+			return ctx.Err()
+		}
+
+		msgs, err := channel.Consume(
+			queueConfig.Name,
+			consumerConfig.Tag,
+			consumerConfig.AutoAck,
+			consumerConfig.Exclusive,
+			consumerConfig.NoLocal,
+			consumerConfig.NoWait,
+			consumerConfig.Args,
+		)
+		if err != nil {
+			// Um canal em queda será trocado na reconexão; outros erros são da configuração
+			if !errors.Is(err, amqp.ErrClosed) {
+				return fmt.Errorf("failed to register consumer: %w", err)
+			}
+			continue
+		}
+
+		if err := mq.deliver(ctx, msgs, consumerConfig, handler); err != nil {
+			return err
+		}
+		log.Printf("Consumer of queue %s lost its channel, waiting for RabbitMQ to reconnect", queueConfig.Name)
 	}
+}
 
+// deliver processa as mensagens até o fechamento do canal, quando retorna nil
+func (mq *RabbitMQ) deliver(ctx context.Context, msgs <-chan amqp.Delivery, consumerConfig ConsumerConfig, handler func([]byte, string) error) error {
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case err := <-mq.closeChan:
-			if err != nil {
-				return fmt.Errorf("connection closed: %w", err)
-			}
-			return nil
 		case msg, ok := <-msgs:
 			if !ok {
-				return fmt.Errorf("message channel closed")
+				return nil
 			}
 
 			// Extrai X-TRACE-ID dos headers
@@ -410,39 +754,44 @@ func (mq *RabbitMQ) Consumer(ctx context.Context, exchangeName, queueName string
 			}
 
 			err := handler(msg.Body, traceID)
+			if err != nil {
+				log.Printf("Message processing failed: %v", err)
+			}
+			if consumerConfig.AutoAck {
+				continue
+			}
 			if err != nil {
 				// Rejeita mensagem e envia para dead letter se configurado
 				msg.Nack(false, false)
-				log.Printf("Message processing failed: %v", err)
 			} else {
 				// Confirma processamento da mensagem
-				if !consumerConfig.AutoAck {
-					msg.Ack(false)
-				}
+				msg.Ack(false)
 			}
 		}
 	}
 }
 
-// Close fecha a conexão com RabbitMQ
+// Close fecha a conexão com RabbitMQ e encerra a reconexão e os consumers
 func (mq *RabbitMQ) Close() error {
-	if !mq.isConnected {
-		return nil
-	}
+	mq.closeOnce.Do(func() { close(mq.done) })
 
+	mq.mu.Lock()
+	current := mq.current
+	mq.current = nil
 	mq.isConnected = false
+	mq.mu.Unlock()
 
-	if mq.channel != nil {
-		if err := mq.channel.Close(); err != nil {
-			log.Printf("Error closing channel: %v", err)
-		}
+	if current == nil {
+		return nil
 	}
 
-	if mq.conn != nil {
-		if err := mq.conn.Close(); err != nil {
-			log.Printf("Error closing connection: %v", err)
-			return err
-		}
+	if err := current.channel.Close(); err != nil {
+		log.Printf("Error closing channel: %v", err)
+	}
+
+	if err := current.conn.Close(); err != nil {
+		log.Printf("Error closing connection: %v", err)
+		return err
 	}
 
 	return nil
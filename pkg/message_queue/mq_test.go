@@ -0,0 +1,123 @@
+package message_queue
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconnectDelay(t *testing.T) {
+	assert.Equal(t, time.Second, reconnectDelay(0))
+	assert.Equal(t, 2*time.Second, reconnectDelay(1))
+	assert.Equal(t, 16*time.Second, reconnectDelay(4))
+	assert.Equal(t, 30*time.Second, reconnectDelay(5))
+	assert.Equal(t, 30*time.Second, reconnectDelay(100))
+}
+
+func TestRabbitMQ_PublishTimeout(t *testing.T) {
+	assert.Equal(t, publishConfirmTimeout, (&RabbitMQ{}).publishTimeout())
+	assert.Equal(t, 30*time.Second, (&RabbitMQ{config: Config{PublishTimeout: 30}}).publishTimeout())
+
+	config := Config{URL: "amqp://localhost", PublishTimeout: -1}
+	assert.EqualError(t, config.Validate(), "publish_timeout must not be negative")
+}
+
+func TestRabbitChannel_DispatchConfirms(t *testing.T) {
+	current := &rabbitChannel{
+		confirms: make(chan amqp.Confirmation, 4),
+		returns:  make(chan amqp.Return, 4),
+		waiters:  make(map[uint64]chan publishResult),
+	}
+	first, second, third := make(chan publishResult, 1), make(chan publishResult, 1), make(chan publishResult, 1)
+	current.waiters[1], current.waiters[2], current.waiters[3] = first, second, third
+	current.removeWaiter(4)
+
+	done := make(chan struct{})
+	go func() {
+		current.dispatchConfirms()
+		close(done)
+	}()
+
+	// The confirm of a timed out message is dropped without blocking the others
+	current.confirms <- amqp.Confirmation{DeliveryTag: 4, Ack: true}
+	current.returns <- amqp.Return{MessageId: "2", ReplyText: "NO_ROUTE"}
+	current.confirms <- amqp.Confirmation{DeliveryTag: 2, Ack: true}
+	current.confirms <- amqp.Confirmation{DeliveryTag: 1, Ack: false}
+
+	result := <-second
+	assert.True(t, result.ack)
+	require.NotNil(t, result.returned)
+	assert.Equal(t, "NO_ROUTE", result.returned.ReplyText)
+	assert.Equal(t, publishResult{ack: false}, <-first)
+
+	close(current.confirms)
+	<-done
+	_, ok := <-third
+	assert.False(t, ok)
+	assert.Empty(t, current.waiters)
+}
+
+// TestRabbitMQ_Reconnect runs against the broker of RABBITMQ_URL.
+func TestRabbitMQ_Reconnect(t *testing.T) {
+	url := os.Getenv("RABBITMQ_URL")
+	if url == "" {
+		t.Skip("RABBITMQ_URL not set, skipping RabbitMQ test.")
+	}
+
+	suffix := fmt.Sprint(time.Now().UnixNano())
+	exchange, queue := "test_exchange_"+suffix, "test_queue_"+suffix
+	mq, err := InitializeMessageQueue(Config{
+		URL: url,
+		MessageQueues: []ExchangeConfig{{
+			Name:   exchange,
+			Type:   "topic",
+			Queues: []QueueConfig{{Name: queue, AutoDelete: true, RouteKey: "test.*"}},
+		}},
+	})
+	require.NoError(t, err)
+	defer mq.Close()
+	rabbit := mq.(*RabbitMQ)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	received := make(chan string, 10)
+	consumerDone := make(chan error, 1)
+	go func() {
+		consumerDone <- mq.Consumer(ctx, exchange, queue, func(body []byte, traceID string) error {
+			received <- string(body)
+			return nil
+		})
+	}()
+
+	require.NoError(t, mq.PublisherWithRouteKey(exchange, "test.before", []byte("before"), ""))
+	assert.Equal(t, "before", <-received)
+
+	// Drop the connection as a broker restart would
+	rabbit.mu.RLock()
+	conn := rabbit.current.conn
+	rabbit.mu.RUnlock()
+	require.NoError(t, conn.Close())
+
+	require.Eventually(t, func() bool {
+		return mq.PublisherWithRouteKey(exchange, "test.after", []byte("after"), "") == nil
+	}, 10*time.Second, 100*time.Millisecond)
+	select {
+	case body := <-received:
+		assert.Equal(t, "after", body, "the consumer is registered again")
+	case <-time.After(5 * time.Second):
+		t.Fatal("the consumer did not receive the message published after the reconnection")
+	}
+
+	rabbit.config.DefaultPublisher.Mandatory = true
+	err = mq.PublisherWithRouteKey(exchange, "unrouted.key", []byte("lost"), "")
+	assert.ErrorContains(t, err, "was returned: NO_ROUTE")
+
+	cancel()
+	assert.ErrorIs(t, <-consumerDone, context.Canceled)
+}